@@ -26,6 +26,7 @@ import (
 )
 
 // ClusterHealthParameters are the configurable fields of a ClusterHealth.
+// +kubebuilder:validation:XValidation:rule="(has(self.clientConfiguration) ? 1 : 0) + (has(self.clientConfigurationSecretRef) ? 1 : 0) + ((has(self.secretsRef) || has(self.secretsSelector)) ? 1 : 0) == 1",message="exactly one of clientConfiguration, clientConfigurationSecretRef or secretsRef/secretsSelector must be set"
 type ClusterHealthParameters struct {
 	// Endpoints are Talos API endpoints used by the health check client.
 	// Use at least one reachable control-plane endpoint.
@@ -45,8 +46,25 @@ type ClusterHealthParameters struct {
 	// +optional
 	SkipKubernetesChecks *bool `json:"skipKubernetesChecks,omitempty"`
 
-	// ClientConfiguration contains Talos client credentials.
-	ClientConfiguration ClientConfiguration `json:"clientConfiguration"`
+	// ClientConfiguration holds the Talos API client credentials inline.
+	// Prefer clientConfigurationSecretRef or secretsRef to keep the client
+	// key out of the resource.
+	// +optional
+	ClientConfiguration *ClientConfiguration `json:"clientConfiguration,omitempty"`
+	// ClientConfigurationSecretRef references a Secret holding the Talos API
+	// client credentials under the client_configuration key, or the
+	// ca_certificate, client_certificate and client_key keys, such as the
+	// connection secret of a Secrets or ClientCertificate.
+	// +optional
+	ClientConfigurationSecretRef *xpv1.SecretReference `json:"clientConfigurationSecretRef,omitempty"`
+	// SecretsRef references the Secrets whose admin client credentials are
+	// used.
+	// +optional
+	SecretsRef *xpv1.Reference `json:"secretsRef,omitempty"`
+	// SecretsSelector selects the Secrets whose admin client credentials are
+	// used.
+	// +optional
+	SecretsSelector *xpv1.Selector `json:"secretsSelector,omitempty"`
 }
 
 // ClusterHealthObservation are the observable fields of a ClusterHealth.
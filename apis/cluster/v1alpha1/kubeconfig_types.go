@@ -36,14 +36,38 @@ type ClientConfiguration struct {
 }
 
 // KubeconfigParameters are the configurable fields of a Kubeconfig.
+// +kubebuilder:validation:XValidation:rule="(has(self.clientConfiguration) ? 1 : 0) + (has(self.clientConfigurationSecretRef) ? 1 : 0) + ((has(self.secretsRef) || has(self.secretsSelector)) ? 1 : 0) == 1",message="exactly one of clientConfiguration, clientConfigurationSecretRef or secretsRef/secretsSelector must be set"
 type KubeconfigParameters struct {
 	// Node is the control plane node (required)
 	Node string `json:"node"`
 	// Endpoint is the machine endpoint (optional)
 	// +optional
 	Endpoint *string `json:"endpoint,omitempty"`
-	// ClientConfiguration for authentication
-	ClientConfiguration ClientConfiguration `json:"clientConfiguration"`
+	// Endpoints are Talos API endpoints that calls for the node are routed
+	// through, e.g. control plane nodes whose apid proxies to a node that is
+	// not directly reachable. Any healthy endpoint is used. Endpoints take
+	// precedence over endpoint.
+	// +optional
+	Endpoints []string `json:"endpoints,omitempty"`
+	// ClientConfiguration holds the Talos API client credentials inline.
+	// Prefer clientConfigurationSecretRef or secretsRef to keep the client
+	// key out of the resource.
+	// +optional
+	ClientConfiguration *ClientConfiguration `json:"clientConfiguration,omitempty"`
+	// ClientConfigurationSecretRef references a Secret holding the Talos API
+	// client credentials under the client_configuration key, or the
+	// ca_certificate, client_certificate and client_key keys, such as the
+	// connection secret of a Secrets or ClientCertificate.
+	// +optional
+	ClientConfigurationSecretRef *xpv1.SecretReference `json:"clientConfigurationSecretRef,omitempty"`
+	// SecretsRef references the Secrets whose admin client credentials are
+	// used.
+	// +optional
+	SecretsRef *xpv1.Reference `json:"secretsRef,omitempty"`
+	// SecretsSelector selects the Secrets whose admin client credentials are
+	// used.
+	// +optional
+	SecretsSelector *xpv1.Selector `json:"secretsSelector,omitempty"`
 }
 
 // KubernetesClientConfiguration contains Kubernetes client configuration
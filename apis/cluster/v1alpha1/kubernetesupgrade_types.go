@@ -0,0 +1,189 @@
+/*
+Copyright 2025 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"reflect"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+)
+
+// KubernetesUpgradeParameters are the configurable fields of a KubernetesUpgrade.
+// +kubebuilder:validation:XValidation:rule="(has(self.clientConfiguration) ? 1 : 0) + (has(self.clientConfigurationSecretRef) ? 1 : 0) + ((has(self.secretsRef) || has(self.secretsSelector)) ? 1 : 0) == 1",message="exactly one of clientConfiguration, clientConfigurationSecretRef or secretsRef/secretsSelector must be set"
+type KubernetesUpgradeParameters struct {
+	// Endpoints are Talos API endpoints used to reach the nodes.
+	// Use at least one reachable control-plane endpoint.
+	// +kubebuilder:validation:MinItems=1
+	Endpoints []string `json:"endpoints"`
+
+	// ControlPlaneNodes are the control-plane nodes to upgrade, in rollout order.
+	// +kubebuilder:validation:MinItems=1
+	ControlPlaneNodes []string `json:"controlPlaneNodes"`
+
+	// WorkerNodes are the worker nodes whose kubelets are upgraded, in rollout order.
+	// +optional
+	WorkerNodes []string `json:"workerNodes,omitempty"`
+
+	// TargetVersion is the Kubernetes version to upgrade to, e.g. v1.34.1.
+	// +kubebuilder:validation:Pattern=`^v?\d+\.\d+\.\d+(-[0-9A-Za-z.-]+)?$`
+	TargetVersion string `json:"targetVersion"`
+
+	// SkipKubeletUpgrade only upgrades the control-plane components and leaves
+	// kubelets on their current version.
+	// +optional
+	SkipKubeletUpgrade *bool `json:"skipKubeletUpgrade,omitempty"`
+
+	// ConfigurationApplySelector selects the ConfigurationApplies that manage
+	// the nodes. Before a component is upgraded on a node, its new image is
+	// recorded in spec.forProvider.kubernetesImages of the selected
+	// ConfigurationApply whose node or observed hostname matches, so that
+	// reapplying the node's configuration keeps the upgrade. A node without
+	// a matching ConfigurationApply is not upgraded. Leave unset when the
+	// nodes are not managed by ConfigurationApplies.
+	// +optional
+	ConfigurationApplySelector *xpv1.Selector `json:"configurationApplySelector,omitempty"`
+
+	// ClientConfiguration holds the Talos API client credentials inline.
+	// Prefer clientConfigurationSecretRef or secretsRef to keep the client
+	// key out of the resource.
+	// +optional
+	ClientConfiguration *ClientConfiguration `json:"clientConfiguration,omitempty"`
+	// ClientConfigurationSecretRef references a Secret holding the Talos API
+	// client credentials under the client_configuration key, or the
+	// ca_certificate, client_certificate and client_key keys, such as the
+	// connection secret of a Secrets or ClientCertificate.
+	// +optional
+	ClientConfigurationSecretRef *xpv1.SecretReference `json:"clientConfigurationSecretRef,omitempty"`
+	// SecretsRef references the Secrets whose admin client credentials are
+	// used.
+	// +optional
+	SecretsRef *xpv1.Reference `json:"secretsRef,omitempty"`
+	// SecretsSelector selects the Secrets whose admin client credentials are
+	// used.
+	// +optional
+	SecretsSelector *xpv1.Selector `json:"secretsSelector,omitempty"`
+}
+
+// KubernetesComponentStatus is the observed version of a Kubernetes component
+// on a node.
+type KubernetesComponentStatus struct {
+	// Component is the component name, e.g. kube-apiserver or kubelet.
+	Component string `json:"component"`
+
+	// Node is the node running the component.
+	Node string `json:"node"`
+
+	// Version is the configured version of the component.
+	// +optional
+	Version string `json:"version,omitempty"`
+
+	// Upgraded indicates the component is configured with the target version.
+	Upgraded bool `json:"upgraded"`
+}
+
+// KubernetesUpgradeObservation are the observable fields of a KubernetesUpgrade.
+type KubernetesUpgradeObservation struct {
+	// Phase is the upgrade progress (Pending, Upgrading, Verifying, Completed)
+	// +optional
+	Phase string `json:"phase,omitempty"`
+
+	// CurrentVersion is the oldest Kubernetes version observed across all
+	// components and kubelets.
+	// +optional
+	CurrentVersion string `json:"currentVersion,omitempty"`
+
+	// TargetVersion is the normalized target Kubernetes version.
+	// +optional
+	TargetVersion string `json:"targetVersion,omitempty"`
+
+	// Components is the per-node progress of the control-plane components.
+	// +optional
+	Components []KubernetesComponentStatus `json:"components,omitempty"`
+
+	// Nodes is the per-node progress of the kubelets.
+	// +optional
+	Nodes []KubernetesComponentStatus `json:"nodes,omitempty"`
+
+	// LastStep is the last component and node that was upgraded, e.g.
+	// kube-apiserver/192.168.1.100.
+	// +optional
+	LastStep string `json:"lastStep,omitempty"`
+
+	// LastStepTime is when the last step was applied.
+	// +optional
+	LastStepTime *metav1.Time `json:"lastStepTime,omitempty"`
+
+	// LastMessage summarizes the last health check or upgrade result.
+	// +optional
+	LastMessage string `json:"lastMessage,omitempty"`
+}
+
+// A KubernetesUpgradeSpec defines the desired state of a KubernetesUpgrade.
+type KubernetesUpgradeSpec struct {
+	xpv1.ResourceSpec `json:",inline"`
+	ForProvider       KubernetesUpgradeParameters `json:"forProvider"`
+}
+
+// A KubernetesUpgradeStatus represents the observed state of a KubernetesUpgrade.
+type KubernetesUpgradeStatus struct {
+	xpv1.ResourceStatus `json:",inline"`
+	AtProvider          KubernetesUpgradeObservation `json:"atProvider,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// A KubernetesUpgrade rolls the Kubernetes control plane and kubelets of a
+// Talos cluster to a target version, one node at a time.
+// +kubebuilder:printcolumn:name="READY",type="string",JSONPath=".status.conditions[?(@.type=='Ready')].status"
+// +kubebuilder:printcolumn:name="SYNCED",type="string",JSONPath=".status.conditions[?(@.type=='Synced')].status"
+// +kubebuilder:printcolumn:name="PHASE",type="string",JSONPath=".status.atProvider.phase"
+// +kubebuilder:printcolumn:name="VERSION",type="string",JSONPath=".status.atProvider.currentVersion"
+// +kubebuilder:printcolumn:name="EXTERNAL-NAME",type="string",JSONPath=".metadata.annotations.crossplane\\.io/external-name"
+// +kubebuilder:printcolumn:name="AGE",type="date",JSONPath=".metadata.creationTimestamp"
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:scope=Cluster,categories={crossplane,managed,talos}
+type KubernetesUpgrade struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   KubernetesUpgradeSpec   `json:"spec"`
+	Status KubernetesUpgradeStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// KubernetesUpgradeList contains a list of KubernetesUpgrade.
+type KubernetesUpgradeList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []KubernetesUpgrade `json:"items"`
+}
+
+// KubernetesUpgrade type metadata.
+var (
+	KubernetesUpgradeKind             = reflect.TypeOf(KubernetesUpgrade{}).Name()
+	KubernetesUpgradeGroupKind        = schema.GroupKind{Group: Group, Kind: KubernetesUpgradeKind}.String()
+	KubernetesUpgradeKindAPIVersion   = KubernetesUpgradeKind + "." + SchemeGroupVersion.String()
+	KubernetesUpgradeGroupVersionKind = SchemeGroupVersion.WithKind(KubernetesUpgradeKind)
+)
+
+func init() {
+	SchemeBuilder.Register(&KubernetesUpgrade{}, &KubernetesUpgradeList{})
+}
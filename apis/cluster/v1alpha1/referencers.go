@@ -0,0 +1,58 @@
+/*
+Copyright 2025 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"context"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	machinev1alpha1 "github.com/crossplane-contrib/provider-talos/apis/machine/v1alpha1"
+)
+
+// ResolveReferences of this ClusterHealth.
+func (mg *ClusterHealth) ResolveReferences(ctx context.Context, c client.Reader) error {
+	ref, err := machinev1alpha1.ResolveSecretsReference(ctx, c, mg, mg.Spec.ForProvider.SecretsRef, mg.Spec.ForProvider.SecretsSelector)
+	if err != nil {
+		return err
+	}
+	mg.Spec.ForProvider.SecretsRef = ref
+
+	return nil
+}
+
+// ResolveReferences of this Kubeconfig.
+func (mg *Kubeconfig) ResolveReferences(ctx context.Context, c client.Reader) error {
+	ref, err := machinev1alpha1.ResolveSecretsReference(ctx, c, mg, mg.Spec.ForProvider.SecretsRef, mg.Spec.ForProvider.SecretsSelector)
+	if err != nil {
+		return err
+	}
+	mg.Spec.ForProvider.SecretsRef = ref
+
+	return nil
+}
+
+// ResolveReferences of this KubernetesUpgrade.
+func (mg *KubernetesUpgrade) ResolveReferences(ctx context.Context, c client.Reader) error {
+	ref, err := machinev1alpha1.ResolveSecretsReference(ctx, c, mg, mg.Spec.ForProvider.SecretsRef, mg.Spec.ForProvider.SecretsSelector)
+	if err != nil {
+		return err
+	}
+	mg.Spec.ForProvider.SecretsRef = ref
+
+	return nil
+}
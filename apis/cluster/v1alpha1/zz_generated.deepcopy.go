@@ -9,6 +9,7 @@
 package v1alpha1
 
 import (
+	"github.com/crossplane/crossplane-runtime/apis/common/v1"
 	runtime "k8s.io/apimachinery/pkg/runtime"
 )
 
@@ -132,7 +133,26 @@ func (in *ClusterHealthParameters) DeepCopyInto(out *ClusterHealthParameters) {
 		*out = new(bool)
 		**out = **in
 	}
-	out.ClientConfiguration = in.ClientConfiguration
+	if in.ClientConfiguration != nil {
+		in, out := &in.ClientConfiguration, &out.ClientConfiguration
+		*out = new(ClientConfiguration)
+		**out = **in
+	}
+	if in.ClientConfigurationSecretRef != nil {
+		in, out := &in.ClientConfigurationSecretRef, &out.ClientConfigurationSecretRef
+		*out = new(v1.SecretReference)
+		**out = **in
+	}
+	if in.SecretsRef != nil {
+		in, out := &in.SecretsRef, &out.SecretsRef
+		*out = new(v1.Reference)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.SecretsSelector != nil {
+		in, out := &in.SecretsSelector, &out.SecretsSelector
+		*out = new(v1.Selector)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterHealthParameters.
@@ -266,7 +286,31 @@ func (in *KubeconfigParameters) DeepCopyInto(out *KubeconfigParameters) {
 		*out = new(string)
 		**out = **in
 	}
-	out.ClientConfiguration = in.ClientConfiguration
+	if in.Endpoints != nil {
+		in, out := &in.Endpoints, &out.Endpoints
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.ClientConfiguration != nil {
+		in, out := &in.ClientConfiguration, &out.ClientConfiguration
+		*out = new(ClientConfiguration)
+		**out = **in
+	}
+	if in.ClientConfigurationSecretRef != nil {
+		in, out := &in.ClientConfigurationSecretRef, &out.ClientConfigurationSecretRef
+		*out = new(v1.SecretReference)
+		**out = **in
+	}
+	if in.SecretsRef != nil {
+		in, out := &in.SecretsRef, &out.SecretsRef
+		*out = new(v1.Reference)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.SecretsSelector != nil {
+		in, out := &in.SecretsSelector, &out.SecretsSelector
+		*out = new(v1.Selector)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KubeconfigParameters.
@@ -327,3 +371,200 @@ func (in *KubernetesClientConfiguration) DeepCopy() *KubernetesClientConfigurati
 	in.DeepCopyInto(out)
 	return out
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KubernetesComponentStatus) DeepCopyInto(out *KubernetesComponentStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KubernetesComponentStatus.
+func (in *KubernetesComponentStatus) DeepCopy() *KubernetesComponentStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(KubernetesComponentStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KubernetesUpgrade) DeepCopyInto(out *KubernetesUpgrade) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KubernetesUpgrade.
+func (in *KubernetesUpgrade) DeepCopy() *KubernetesUpgrade {
+	if in == nil {
+		return nil
+	}
+	out := new(KubernetesUpgrade)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *KubernetesUpgrade) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KubernetesUpgradeList) DeepCopyInto(out *KubernetesUpgradeList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]KubernetesUpgrade, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KubernetesUpgradeList.
+func (in *KubernetesUpgradeList) DeepCopy() *KubernetesUpgradeList {
+	if in == nil {
+		return nil
+	}
+	out := new(KubernetesUpgradeList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *KubernetesUpgradeList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KubernetesUpgradeObservation) DeepCopyInto(out *KubernetesUpgradeObservation) {
+	*out = *in
+	if in.Components != nil {
+		in, out := &in.Components, &out.Components
+		*out = make([]KubernetesComponentStatus, len(*in))
+		copy(*out, *in)
+	}
+	if in.Nodes != nil {
+		in, out := &in.Nodes, &out.Nodes
+		*out = make([]KubernetesComponentStatus, len(*in))
+		copy(*out, *in)
+	}
+	if in.LastStepTime != nil {
+		in, out := &in.LastStepTime, &out.LastStepTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KubernetesUpgradeObservation.
+func (in *KubernetesUpgradeObservation) DeepCopy() *KubernetesUpgradeObservation {
+	if in == nil {
+		return nil
+	}
+	out := new(KubernetesUpgradeObservation)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KubernetesUpgradeParameters) DeepCopyInto(out *KubernetesUpgradeParameters) {
+	*out = *in
+	if in.Endpoints != nil {
+		in, out := &in.Endpoints, &out.Endpoints
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.ControlPlaneNodes != nil {
+		in, out := &in.ControlPlaneNodes, &out.ControlPlaneNodes
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.WorkerNodes != nil {
+		in, out := &in.WorkerNodes, &out.WorkerNodes
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.SkipKubeletUpgrade != nil {
+		in, out := &in.SkipKubeletUpgrade, &out.SkipKubeletUpgrade
+		*out = new(bool)
+		**out = **in
+	}
+	if in.ConfigurationApplySelector != nil {
+		in, out := &in.ConfigurationApplySelector, &out.ConfigurationApplySelector
+		*out = new(v1.Selector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ClientConfiguration != nil {
+		in, out := &in.ClientConfiguration, &out.ClientConfiguration
+		*out = new(ClientConfiguration)
+		**out = **in
+	}
+	if in.ClientConfigurationSecretRef != nil {
+		in, out := &in.ClientConfigurationSecretRef, &out.ClientConfigurationSecretRef
+		*out = new(v1.SecretReference)
+		**out = **in
+	}
+	if in.SecretsRef != nil {
+		in, out := &in.SecretsRef, &out.SecretsRef
+		*out = new(v1.Reference)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.SecretsSelector != nil {
+		in, out := &in.SecretsSelector, &out.SecretsSelector
+		*out = new(v1.Selector)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KubernetesUpgradeParameters.
+func (in *KubernetesUpgradeParameters) DeepCopy() *KubernetesUpgradeParameters {
+	if in == nil {
+		return nil
+	}
+	out := new(KubernetesUpgradeParameters)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KubernetesUpgradeSpec) DeepCopyInto(out *KubernetesUpgradeSpec) {
+	*out = *in
+	in.ResourceSpec.DeepCopyInto(&out.ResourceSpec)
+	in.ForProvider.DeepCopyInto(&out.ForProvider)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KubernetesUpgradeSpec.
+func (in *KubernetesUpgradeSpec) DeepCopy() *KubernetesUpgradeSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(KubernetesUpgradeSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KubernetesUpgradeStatus) DeepCopyInto(out *KubernetesUpgradeStatus) {
+	*out = *in
+	in.ResourceStatus.DeepCopyInto(&out.ResourceStatus)
+	in.AtProvider.DeepCopyInto(&out.AtProvider)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KubernetesUpgradeStatus.
+func (in *KubernetesUpgradeStatus) DeepCopy() *KubernetesUpgradeStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(KubernetesUpgradeStatus)
+	in.DeepCopyInto(out)
+	return out
+}
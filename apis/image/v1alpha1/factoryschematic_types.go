@@ -20,22 +20,129 @@ import (
 	"reflect"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 
 	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
 )
 
 // FactorySchematicParameters are the configurable fields of a FactorySchematic.
+// The schematic is either given as YAML in schematic or by the typed fields.
+// +kubebuilder:validation:XValidation:rule="!has(self.schematic) || !(has(self.systemExtensions) || has(self.extraKernelArgs) || has(self.meta) || has(self.overlay) || has(self.secureboot))",message="schematic cannot be combined with systemExtensions, extraKernelArgs, meta, overlay or secureboot"
 type FactorySchematicParameters struct {
 	// Schematic is the YAML configuration for image customization (optional)
 	// +optional
 	Schematic *string `json:"schematic,omitempty"`
+	// SystemExtensions are the official system extensions included in the
+	// image, e.g. siderolabs/gvisor.
+	// +kubebuilder:validation:items:Pattern=`^siderolabs/[a-z0-9]([a-z0-9-]*[a-z0-9])?$`
+	// +listType=set
+	// +optional
+	SystemExtensions []string `json:"systemExtensions,omitempty"`
+	// ExtraKernelArgs are appended to the kernel command line.
+	// +kubebuilder:validation:items:Pattern=`^\S+$`
+	// +optional
+	ExtraKernelArgs []string `json:"extraKernelArgs,omitempty"`
+	// Meta values are written to the META partition of the image.
+	// +listType=map
+	// +listMapKey=key
+	// +optional
+	Meta []SchematicMetaValue `json:"meta,omitempty"`
+	// Overlay selects an overlay, e.g. for single-board computers.
+	// +optional
+	Overlay *SchematicOverlay `json:"overlay,omitempty"`
+	// SecureBoot customizes SecureBoot images.
+	// +optional
+	SecureBoot *SchematicSecureBoot `json:"secureboot,omitempty"`
+
+	// Offline computes the schematic ID locally, the way the Image Factory
+	// does, instead of uploading the schematic. Use it where the factory is
+	// not reachable and its images are mirrored ahead of time.
+	// +optional
+	Offline *bool `json:"offline,omitempty"`
+
+	// TalosVersion is the Talos version of the published artifact
+	// references, e.g. v1.11.0. No artifacts are published without it.
+	// +optional
+	TalosVersion *string `json:"talosVersion,omitempty"`
+	// Platform is the platform of the published installer and disk image,
+	// e.g. metal, aws or nocloud.
+	// +kubebuilder:default=metal
+	// +optional
+	Platform *string `json:"platform,omitempty"`
+	// Arch is the architecture of the published boot media.
+	// +kubebuilder:validation:Enum=amd64;arm64
+	// +kubebuilder:default=amd64
+	// +optional
+	Arch *string `json:"arch,omitempty"`
+}
+
+// A SchematicMetaValue is a value written to the META partition.
+type SchematicMetaValue struct {
+	// Key is the META key.
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=255
+	Key int `json:"key"`
+	// Value is the META value.
+	Value string `json:"value"`
+}
+
+// A SchematicOverlay selects an overlay image and its options.
+type SchematicOverlay struct {
+	// Name is the overlay name, e.g. rpi_generic.
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+	// Image is the overlay image, e.g. siderolabs/sbc-raspberrypi.
+	// +kubebuilder:validation:MinLength=1
+	Image string `json:"image"`
+	// Options are passed to the overlay installer.
+	// +kubebuilder:pruning:PreserveUnknownFields
+	// +kubebuilder:validation:Type=object
+	// +optional
+	Options *runtime.RawExtension `json:"options,omitempty"`
+}
+
+// SchematicSecureBoot customizes SecureBoot images.
+type SchematicSecureBoot struct {
+	// IncludeWellKnownCertificates enrolls the well-known UEFI certificates
+	// alongside the SecureBoot signing certificate.
+	// +optional
+	IncludeWellKnownCertificates bool `json:"includeWellKnownCertificates,omitempty"`
 }
 
 // FactorySchematicObservation are the observable fields of a FactorySchematic.
 type FactorySchematicObservation struct {
 	// ID is the unique schematic identifier
 	ID string `json:"id,omitempty"`
+	// SpecHash is the locally computed hash of the schematic that was last
+	// uploaded. It detects changes to the schematic independently of the ID
+	// the Image Factory assigned.
+	// +optional
+	SpecHash string `json:"specHash,omitempty"`
+	// Artifacts are the Image Factory artifacts of the schematic for the
+	// requested talosVersion, platform and arch.
+	// +optional
+	Artifacts *FactorySchematicArtifacts `json:"artifacts,omitempty"`
+}
+
+// FactorySchematicArtifacts are references to the Image Factory artifacts of
+// a schematic.
+type FactorySchematicArtifacts struct {
+	// TalosVersion is the Talos version of the artifacts.
+	TalosVersion string `json:"talosVersion,omitempty"`
+	// InstallerImage is the installer image, e.g. for machine.install.image
+	// or an Upgrade.
+	InstallerImage string `json:"installerImage,omitempty"`
+	// SecureBootInstallerImage is the SecureBoot installer image.
+	SecureBootInstallerImage string `json:"secureBootInstallerImage,omitempty"`
+	// ISO is the URL of the ISO image.
+	ISO string `json:"iso,omitempty"`
+	// DiskImage is the URL of the raw disk image.
+	DiskImage string `json:"diskImage,omitempty"`
+	// Kernel is the URL of the PXE kernel.
+	Kernel string `json:"kernel,omitempty"`
+	// Initramfs is the URL of the PXE initramfs.
+	Initramfs string `json:"initramfs,omitempty"`
 }
 
 // A FactorySchematicSpec defines the desired state of a FactorySchematic.
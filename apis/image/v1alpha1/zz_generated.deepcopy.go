@@ -9,7 +9,7 @@
 package v1alpha1
 
 import (
-	runtime "k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime"
 )
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
@@ -39,6 +39,21 @@ func (in *FactorySchematic) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FactorySchematicArtifacts) DeepCopyInto(out *FactorySchematicArtifacts) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FactorySchematicArtifacts.
+func (in *FactorySchematicArtifacts) DeepCopy() *FactorySchematicArtifacts {
+	if in == nil {
+		return nil
+	}
+	out := new(FactorySchematicArtifacts)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *FactorySchematicList) DeepCopyInto(out *FactorySchematicList) {
 	*out = *in
@@ -74,6 +89,11 @@ func (in *FactorySchematicList) DeepCopyObject() runtime.Object {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *FactorySchematicObservation) DeepCopyInto(out *FactorySchematicObservation) {
 	*out = *in
+	if in.Artifacts != nil {
+		in, out := &in.Artifacts, &out.Artifacts
+		*out = new(FactorySchematicArtifacts)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FactorySchematicObservation.
@@ -94,6 +114,51 @@ func (in *FactorySchematicParameters) DeepCopyInto(out *FactorySchematicParamete
 		*out = new(string)
 		**out = **in
 	}
+	if in.SystemExtensions != nil {
+		in, out := &in.SystemExtensions, &out.SystemExtensions
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.ExtraKernelArgs != nil {
+		in, out := &in.ExtraKernelArgs, &out.ExtraKernelArgs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Meta != nil {
+		in, out := &in.Meta, &out.Meta
+		*out = make([]SchematicMetaValue, len(*in))
+		copy(*out, *in)
+	}
+	if in.Overlay != nil {
+		in, out := &in.Overlay, &out.Overlay
+		*out = new(SchematicOverlay)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.SecureBoot != nil {
+		in, out := &in.SecureBoot, &out.SecureBoot
+		*out = new(SchematicSecureBoot)
+		**out = **in
+	}
+	if in.Offline != nil {
+		in, out := &in.Offline, &out.Offline
+		*out = new(bool)
+		**out = **in
+	}
+	if in.TalosVersion != nil {
+		in, out := &in.TalosVersion, &out.TalosVersion
+		*out = new(string)
+		**out = **in
+	}
+	if in.Platform != nil {
+		in, out := &in.Platform, &out.Platform
+		*out = new(string)
+		**out = **in
+	}
+	if in.Arch != nil {
+		in, out := &in.Arch, &out.Arch
+		*out = new(string)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FactorySchematicParameters.
@@ -127,7 +192,7 @@ func (in *FactorySchematicSpec) DeepCopy() *FactorySchematicSpec {
 func (in *FactorySchematicStatus) DeepCopyInto(out *FactorySchematicStatus) {
 	*out = *in
 	in.ResourceStatus.DeepCopyInto(&out.ResourceStatus)
-	out.AtProvider = in.AtProvider
+	in.AtProvider.DeepCopyInto(&out.AtProvider)
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FactorySchematicStatus.
@@ -139,3 +204,53 @@ func (in *FactorySchematicStatus) DeepCopy() *FactorySchematicStatus {
 	in.DeepCopyInto(out)
 	return out
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SchematicMetaValue) DeepCopyInto(out *SchematicMetaValue) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SchematicMetaValue.
+func (in *SchematicMetaValue) DeepCopy() *SchematicMetaValue {
+	if in == nil {
+		return nil
+	}
+	out := new(SchematicMetaValue)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SchematicOverlay) DeepCopyInto(out *SchematicOverlay) {
+	*out = *in
+	if in.Options != nil {
+		in, out := &in.Options, &out.Options
+		*out = new(runtime.RawExtension)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SchematicOverlay.
+func (in *SchematicOverlay) DeepCopy() *SchematicOverlay {
+	if in == nil {
+		return nil
+	}
+	out := new(SchematicOverlay)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SchematicSecureBoot) DeepCopyInto(out *SchematicSecureBoot) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SchematicSecureBoot.
+func (in *SchematicSecureBoot) DeepCopy() *SchematicSecureBoot {
+	if in == nil {
+		return nil
+	}
+	out := new(SchematicSecureBoot)
+	in.DeepCopyInto(out)
+	return out
+}
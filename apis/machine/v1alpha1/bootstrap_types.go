@@ -26,14 +26,74 @@ import (
 )
 
 // BootstrapParameters are the configurable fields of a Bootstrap.
+// +kubebuilder:validation:XValidation:rule="(has(self.clientConfiguration) ? 1 : 0) + (has(self.clientConfigurationSecretRef) ? 1 : 0) + ((has(self.secretsRef) || has(self.secretsSelector)) ? 1 : 0) == 1",message="exactly one of clientConfiguration, clientConfigurationSecretRef or secretsRef/secretsSelector must be set"
 type BootstrapParameters struct {
 	// Node is the node to bootstrap (required)
 	Node string `json:"node"`
 	// Endpoint is the machine endpoint (optional)
 	// +optional
 	Endpoint *string `json:"endpoint,omitempty"`
-	// ClientConfiguration for authentication
-	ClientConfiguration ClientConfiguration `json:"clientConfiguration"`
+	// Endpoints are Talos API endpoints that calls for the node are routed
+	// through, e.g. control plane nodes whose apid proxies to a node that is
+	// not directly reachable. Any healthy endpoint is used. Endpoints take
+	// precedence over endpoint.
+	// +optional
+	Endpoints []string `json:"endpoints,omitempty"`
+	// ClientConfiguration holds the Talos API client credentials inline.
+	// Prefer clientConfigurationSecretRef or secretsRef to keep the client
+	// key out of the resource.
+	// +optional
+	ClientConfiguration *ClientConfiguration `json:"clientConfiguration,omitempty"`
+	// ClientConfigurationSecretRef references a Secret holding the Talos API
+	// client credentials under the client_configuration key, or the
+	// ca_certificate, client_certificate and client_key keys, such as the
+	// connection secret of a Secrets or ClientCertificate.
+	// +optional
+	ClientConfigurationSecretRef *xpv1.SecretReference `json:"clientConfigurationSecretRef,omitempty"`
+	// SecretsRef references the Secrets whose admin client credentials are
+	// used.
+	// +optional
+	SecretsRef *xpv1.Reference `json:"secretsRef,omitempty"`
+	// SecretsSelector selects the Secrets whose admin client credentials are
+	// used.
+	// +optional
+	SecretsSelector *xpv1.Selector `json:"secretsSelector,omitempty"`
+	// Recovery restores etcd from a snapshot instead of bootstrapping an
+	// empty cluster. Use it to recover from the loss of all control plane
+	// nodes; the node must be waiting for bootstrap.
+	// +optional
+	Recovery *BootstrapRecovery `json:"recovery,omitempty"`
+}
+
+// BootstrapRecovery selects the etcd snapshot a cluster is recovered from.
+// Exactly one source must be set.
+// +kubebuilder:validation:XValidation:rule="(has(self.snapshotSecretRef) ? 1 : 0) + (has(self.etcdSnapshotRef) ? 1 : 0) + (has(self.dataDirectory) ? 1 : 0) == 1",message="exactly one of snapshotSecretRef, etcdSnapshotRef or dataDirectory must be set"
+type BootstrapRecovery struct {
+	// SnapshotSecretRef references a Secret key holding an etcd snapshot.
+	// +optional
+	SnapshotSecretRef *xpv1.SecretKeySelector `json:"snapshotSecretRef,omitempty"`
+	// EtcdSnapshotRef references an EtcdSnapshot whose last snapshot is
+	// restored.
+	// +optional
+	EtcdSnapshotRef *xpv1.Reference `json:"etcdSnapshotRef,omitempty"`
+	// DataDirectory recovers from the etcd database left in a node's data
+	// directory. The hash check is always skipped for this source as the
+	// database was not taken as a snapshot.
+	// +optional
+	DataDirectory *BootstrapRecoveryDataDirectory `json:"dataDirectory,omitempty"`
+	// SkipHashCheck skips the snapshot integrity check, e.g. for a database
+	// copied directly from an etcd data directory.
+	// +optional
+	SkipHashCheck *bool `json:"skipHashCheck,omitempty"`
+}
+
+// BootstrapRecoveryDataDirectory copies /var/lib/etcd/member/snap/db from a
+// node, e.g. a surviving control plane node whose etcd no longer has quorum.
+type BootstrapRecoveryDataDirectory struct {
+	// Node is the node to copy the database from. Defaults to the node being
+	// bootstrapped.
+	// +optional
+	Node *string `json:"node,omitempty"`
 }
 
 // BootstrapObservation are the observable fields of a Bootstrap.
@@ -42,6 +102,16 @@ type BootstrapObservation struct {
 	Bootstrapped bool `json:"bootstrapped,omitempty"`
 	// BootstrapTime is the timestamp when bootstrap completed
 	BootstrapTime *metav1.Time `json:"bootstrapTime,omitempty"`
+	// RecoverySource describes the etcd snapshot the cluster was recovered
+	// from, e.g. secret:namespace/name/key, etcdSnapshot:name or dataDirectory.
+	// +optional
+	RecoverySource string `json:"recoverySource,omitempty"`
+	// RecoverySnapshot is the location of the restored snapshot.
+	// +optional
+	RecoverySnapshot string `json:"recoverySnapshot,omitempty"`
+	// RecoveryTime is when the snapshot was uploaded and etcd was recovered.
+	// +optional
+	RecoveryTime *metav1.Time `json:"recoveryTime,omitempty"`
 }
 
 // A BootstrapSpec defines the desired state of a Bootstrap.
@@ -0,0 +1,114 @@
+/*
+Copyright 2025 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"reflect"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+)
+
+// ClientCertificateParameters are the configurable fields of a
+// ClientCertificate.
+type ClientCertificateParameters struct {
+	// SecretsRef references the Secrets whose OS CA issues the certificate.
+	SecretsRef xpv1.Reference `json:"secretsRef"`
+	// Roles are the Talos API roles granted to the certificate.
+	// +kubebuilder:validation:MinItems=1
+	// +kubebuilder:validation:items:Enum="os:admin";"os:operator";"os:reader";"os:etcd:backup";"os:impersonator"
+	Roles []string `json:"roles"`
+	// TTL is the validity of issued certificates. Defaults to one year.
+	// +optional
+	TTL *metav1.Duration `json:"ttl,omitempty"`
+	// RenewBefore is how long before expiry the certificate is reissued.
+	// Defaults to 30 days, or a third of ttl if that is shorter.
+	// +optional
+	RenewBefore *metav1.Duration `json:"renewBefore,omitempty"`
+}
+
+// ClientCertificateObservation are the observable fields of a
+// ClientCertificate.
+type ClientCertificateObservation struct {
+	// Roles are the Talos API roles of the published certificate.
+	// +optional
+	Roles []string `json:"roles,omitempty"`
+	// NotAfter is when the published certificate expires.
+	// +optional
+	NotAfter *metav1.Time `json:"notAfter,omitempty"`
+	// RenewalTime is when the certificate is due to be reissued.
+	// +optional
+	RenewalTime *metav1.Time `json:"renewalTime,omitempty"`
+	// IssuedTime is when the certificate was last issued.
+	// +optional
+	IssuedTime *metav1.Time `json:"issuedTime,omitempty"`
+}
+
+// A ClientCertificateSpec defines the desired state of a ClientCertificate.
+type ClientCertificateSpec struct {
+	xpv1.ResourceSpec `json:",inline"`
+	ForProvider       ClientCertificateParameters `json:"forProvider"`
+}
+
+// A ClientCertificateStatus represents the observed state of a
+// ClientCertificate.
+type ClientCertificateStatus struct {
+	xpv1.ResourceStatus `json:",inline"`
+	AtProvider          ClientCertificateObservation `json:"atProvider,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// A ClientCertificate issues a Talos API client certificate with a limited set
+// of roles and publishes it as a talosconfig.
+// +kubebuilder:printcolumn:name="READY",type="string",JSONPath=".status.conditions[?(@.type=='Ready')].status"
+// +kubebuilder:printcolumn:name="SYNCED",type="string",JSONPath=".status.conditions[?(@.type=='Synced')].status"
+// +kubebuilder:printcolumn:name="ROLES",type="string",JSONPath=".status.atProvider.roles"
+// +kubebuilder:printcolumn:name="EXPIRY",type="string",JSONPath=".status.atProvider.notAfter"
+// +kubebuilder:printcolumn:name="AGE",type="date",JSONPath=".metadata.creationTimestamp"
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:scope=Cluster,categories={crossplane,managed,talos}
+type ClientCertificate struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   ClientCertificateSpec   `json:"spec"`
+	Status ClientCertificateStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// ClientCertificateList contains a list of ClientCertificate
+type ClientCertificateList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []ClientCertificate `json:"items"`
+}
+
+// ClientCertificate type metadata.
+var (
+	ClientCertificateKind             = reflect.TypeOf(ClientCertificate{}).Name()
+	ClientCertificateGroupKind        = schema.GroupKind{Group: Group, Kind: ClientCertificateKind}.String()
+	ClientCertificateKindAPIVersion   = ClientCertificateKind + "." + SchemeGroupVersion.String()
+	ClientCertificateGroupVersionKind = SchemeGroupVersion.WithKind(ClientCertificateKind)
+)
+
+func init() {
+	SchemeBuilder.Register(&ClientCertificate{}, &ClientCertificateList{})
+}
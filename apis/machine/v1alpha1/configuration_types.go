@@ -58,6 +58,10 @@ type ConfigurationObservation struct {
 	MachineConfigurationHash string `json:"machineConfigurationHash,omitempty"`
 	// GeneratedTime is when the configuration was generated
 	GeneratedTime *metav1.Time `json:"generatedTime,omitempty"`
+	// MachineSecretsHash identifies the machine secrets and accepted CAs of
+	// the referenced Secrets the configuration was generated from.
+	// +optional
+	MachineSecretsHash string `json:"machineSecretsHash,omitempty"`
 }
 
 // A ConfigurationSpec defines the desired state of a Configuration.
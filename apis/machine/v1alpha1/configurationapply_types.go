@@ -27,12 +27,20 @@ import (
 
 // ConfigurationApplyParameters are the configurable fields of a ConfigurationApply.
 // +kubebuilder:validation:XValidation:rule="has(self.machineConfigurationRef) || has(self.machineConfiguration)",message="machineConfigurationRef or machineConfiguration must be set"
+// +kubebuilder:validation:XValidation:rule="(has(self.clientConfiguration) ? 1 : 0) + (has(self.clientConfigurationSecretRef) ? 1 : 0) + ((has(self.secretsRef) || has(self.secretsSelector)) ? 1 : 0) <= 1",message="at most one of clientConfiguration, clientConfigurationSecretRef or secretsRef/secretsSelector may be set"
 type ConfigurationApplyParameters struct {
 	// Node is the target machine identifier (required)
 	Node string `json:"node"`
-	// Endpoint is the machine endpoint (optional)
+	// Endpoint is the machine endpoint (optional). Machines in maintenance
+	// mode are always reached directly through it, as their insecure API
+	// cannot be proxied.
 	// +optional
 	Endpoint *string `json:"endpoint,omitempty"`
+	// Endpoints are Talos API endpoints that authenticated calls for the node
+	// are routed through, e.g. control plane nodes whose apid proxies to a
+	// node that is not directly reachable. Any healthy endpoint is used.
+	// +optional
+	Endpoints []string `json:"endpoints,omitempty"`
 	// ApplyMode is the configuration application mode (optional)
 	// +optional
 	// +kubebuilder:validation:Enum=auto;reboot;no_reboot;staged
@@ -43,14 +51,71 @@ type ConfigurationApplyParameters struct {
 	// MachineConfigurationRef references raw Talos machine configuration bytes in a Secret.
 	// +optional
 	MachineConfigurationRef *SecretKeyReference `json:"machineConfigurationRef,omitempty"`
-	// ConfigPatches is a list of configuration modifications (optional)
+	// ConfigPatches are strategic merge or JSON6902 patches applied on top of
+	// the resolved machine configuration before it is sent to the node (optional)
 	// +optional
 	ConfigPatches []string `json:"configPatches,omitempty"`
-	// OnDestroy configuration for machine reset during destruction (optional)
+	// KubernetesImages are Kubernetes component images rolled out to the node
+	// by a KubernetesUpgrade. Each image replaces the one in the machine
+	// configuration unless that one is already on the same or a newer
+	// version, so applying the configuration does not revert the upgrade.
+	// +optional
+	KubernetesImages *KubernetesImages `json:"kubernetesImages,omitempty"`
+	// OnDestroy controls what happens to the node when the ConfigurationApply is
+	// deleted (optional). "none" (the default) leaves the node untouched.
+	// "reset" gracefully leaves etcd and wipes the system disk. "wipe" wipes the
+	// STATE and EPHEMERAL partitions without leaving etcd. "maintenance" wipes
+	// the STATE partition so the node reboots into maintenance mode. Deletion
+	// completes once the node is back in maintenance mode.
 	// +optional
+	// +kubebuilder:validation:Enum=none;reset;wipe;maintenance
 	OnDestroy *string `json:"onDestroy,omitempty"`
-	// ClientConfiguration for authentication
-	ClientConfiguration ClientConfiguration `json:"clientConfiguration"`
+	// OnDestroyTimeout is how long deletion waits for the node to be reset
+	// and return to maintenance mode, measured from the reset request, or from
+	// the deletion if the node could not be reset. Once it has passed the
+	// node is given up on and the ConfigurationApply is deleted. Defaults to
+	// 30m.
+	// +optional
+	OnDestroyTimeout *metav1.Duration `json:"onDestroyTimeout,omitempty"`
+	// ClientConfiguration holds the Talos API client credentials inline.
+	// Machines in maintenance mode are reached without credentials when no
+	// credentials are set. Prefer clientConfigurationSecretRef or secretsRef to keep the client
+	// key out of the resource.
+	// +optional
+	ClientConfiguration *ClientConfiguration `json:"clientConfiguration,omitempty"`
+	// ClientConfigurationSecretRef references a Secret holding the Talos API
+	// client credentials under the client_configuration key, or the
+	// ca_certificate, client_certificate and client_key keys, such as the
+	// connection secret of a Secrets or ClientCertificate.
+	// +optional
+	ClientConfigurationSecretRef *xpv1.SecretReference `json:"clientConfigurationSecretRef,omitempty"`
+	// SecretsRef references the Secrets whose admin client credentials are
+	// used.
+	// +optional
+	SecretsRef *xpv1.Reference `json:"secretsRef,omitempty"`
+	// SecretsSelector selects the Secrets whose admin client credentials are
+	// used.
+	// +optional
+	SecretsSelector *xpv1.Selector `json:"secretsSelector,omitempty"`
+}
+
+// KubernetesImages are the images of the Kubernetes components of a node.
+type KubernetesImages struct {
+	// APIServer is the kube-apiserver image.
+	// +optional
+	APIServer *string `json:"apiServer,omitempty"`
+	// ControllerManager is the kube-controller-manager image.
+	// +optional
+	ControllerManager *string `json:"controllerManager,omitempty"`
+	// Scheduler is the kube-scheduler image.
+	// +optional
+	Scheduler *string `json:"scheduler,omitempty"`
+	// Proxy is the kube-proxy image.
+	// +optional
+	Proxy *string `json:"proxy,omitempty"`
+	// Kubelet is the kubelet image.
+	// +optional
+	Kubelet *string `json:"kubelet,omitempty"`
 }
 
 // SecretKeyReference identifies a key in a Kubernetes Secret.
@@ -69,10 +134,39 @@ type ConfigurationApplyObservation struct {
 	Applied bool `json:"applied,omitempty"`
 	// LastAppliedTime is the timestamp of the last successful application
 	LastAppliedTime *metav1.Time `json:"lastAppliedTime,omitempty"`
-	// MachineState indicates the current state of the machine (MaintenanceMode, Configured, Unreachable)
+	// MachineState indicates the current state of the machine (MaintenanceMode, Configured, Unreachable, Resetting)
 	MachineState string `json:"machineState,omitempty"`
 	// LastStateCheck is the timestamp of the last state verification
 	LastStateCheck *metav1.Time `json:"lastStateCheck,omitempty"`
+	// TalosVersion is the Talos version running on the node
+	// +optional
+	TalosVersion string `json:"talosVersion,omitempty"`
+	// MachineStage is the stage the node reports, e.g. booting, installing, maintenance,
+	// running, rebooting, upgrading or resetting. It is kept while the node is unreachable.
+	// +optional
+	MachineStage string `json:"machineStage,omitempty"`
+	// Hostname is the hostname of the node
+	// +optional
+	Hostname string `json:"hostname,omitempty"`
+	// NodeUUID is the system UUID of the node
+	// +optional
+	NodeUUID string `json:"nodeUUID,omitempty"`
+	// ResetTime is when the onDestroy reset was requested
+	ResetTime *metav1.Time `json:"resetTime,omitempty"`
+	// DesiredConfigHash is the SHA-256 hash of the normalized desired machine configuration
+	DesiredConfigHash string `json:"desiredConfigHash,omitempty"`
+	// AppliedConfigHash is the SHA-256 hash of the normalized machine configuration running on the node
+	AppliedConfigHash string `json:"appliedConfigHash,omitempty"`
+	// SourceConfigHash is the SHA-256 hash of the machine configuration read from
+	// machineConfigurationRef when the configuration hashes were last observed
+	// +optional
+	SourceConfigHash string `json:"sourceConfigHash,omitempty"`
+	// ConfigDrift lists the configuration paths that differ between the desired and running configuration
+	// +optional
+	ConfigDrift []string `json:"configDrift,omitempty"`
+	// Disks is the disk inventory of the node, refreshed whenever the node is reachable
+	// +optional
+	Disks []MachineDisk `json:"disks,omitempty"`
 }
 
 // A ConfigurationApplySpec defines the desired state of a ConfigurationApply.
@@ -92,8 +186,14 @@ type ConfigurationApplyStatus struct {
 // A ConfigurationApply applies machine configuration to Talos nodes.
 // +kubebuilder:printcolumn:name="READY",type="string",JSONPath=".status.conditions[?(@.type=='Ready')].status"
 // +kubebuilder:printcolumn:name="SYNCED",type="string",JSONPath=".status.conditions[?(@.type=='Synced')].status"
+// +kubebuilder:printcolumn:name="STATE",type="string",JSONPath=".status.atProvider.machineState"
+// +kubebuilder:printcolumn:name="STAGE",type="string",JSONPath=".status.atProvider.machineStage"
+// +kubebuilder:printcolumn:name="VERSION",type="string",JSONPath=".status.atProvider.talosVersion"
+// +kubebuilder:printcolumn:name="HOSTNAME",type="string",JSONPath=".status.atProvider.hostname"
 // +kubebuilder:printcolumn:name="EXTERNAL-NAME",type="string",JSONPath=".metadata.annotations.crossplane\\.io/external-name"
 // +kubebuilder:printcolumn:name="AGE",type="date",JSONPath=".metadata.creationTimestamp"
+// +kubebuilder:printcolumn:name="UUID",type="string",JSONPath=".status.atProvider.nodeUUID",priority=1
+// +kubebuilder:printcolumn:name="CONFIG-HASH",type="string",JSONPath=".status.atProvider.appliedConfigHash",priority=1
 // +kubebuilder:subresource:status
 // +kubebuilder:resource:scope=Cluster,categories={crossplane,managed,talos}
 type ConfigurationApply struct {
@@ -131,6 +231,12 @@ type MachineConfigurationSpec struct {
 
 	// Cluster configuration
 	Cluster ClusterSpec `json:"cluster"`
+
+	// ValidationMode is the Talos runtime mode the generated configuration is
+	// validated for before it is applied (optional, defaults to metal)
+	// +optional
+	// +kubebuilder:validation:Enum=metal;cloud;container
+	ValidationMode *string `json:"validationMode,omitempty"`
 }
 
 // MachineSpec defines machine-specific configuration
@@ -185,12 +291,23 @@ type ClusterSpec struct {
 	// CA defines the Kubernetes CA configuration (optional)
 	// +optional
 	CA *CASpec `json:"ca,omitempty"`
+
+	// SecretboxEncryptionSecret is the key used to encrypt Kubernetes secrets at rest (optional)
+	// +optional
+	SecretboxEncryptionSecret *string `json:"secretboxEncryptionSecret,omitempty"`
 }
 
 // InstallSpec defines installation configuration
+// +kubebuilder:validation:XValidation:rule="has(self.disk) != has(self.diskSelector)",message="exactly one of disk or diskSelector must be set"
 type InstallSpec struct {
-	// Disk is the target disk for installation
-	Disk string `json:"disk"`
+	// Disk is the device path of the target disk for installation, e.g. /dev/sda
+	// +optional
+	Disk string `json:"disk,omitempty"`
+
+	// DiskSelector selects the target disk for installation by its
+	// properties. Talos installs to the first disk matching all set fields.
+	// +optional
+	DiskSelector *InstallDiskSelector `json:"diskSelector,omitempty"`
 
 	// Image is the Talos installer image
 	Image string `json:"image"`
@@ -200,6 +317,49 @@ type InstallSpec struct {
 	Wipe *bool `json:"wipe,omitempty"`
 }
 
+// InstallDiskSelector selects the install disk, mirroring the Talos
+// machine.install.diskSelector. String fields other than size support glob
+// patterns, e.g. WDC*.
+type InstallDiskSelector struct {
+	// Size matches the disk size, e.g. "4GB", ">= 1TB" or "<= 2TB"
+	// +kubebuilder:validation:Pattern=`^\s*(>=|<=|>|<|==)?\s*[0-9]+(\.[0-9]+)?\s*[a-zA-Z]*\s*$`
+	// +optional
+	Size *string `json:"size,omitempty"`
+
+	// Name matches the disk name from /sys/block/<dev>/device/name
+	// +optional
+	Name *string `json:"name,omitempty"`
+
+	// Model matches the disk model
+	// +optional
+	Model *string `json:"model,omitempty"`
+
+	// Serial matches the disk serial number
+	// +optional
+	Serial *string `json:"serial,omitempty"`
+
+	// Modalias matches the disk modalias
+	// +optional
+	Modalias *string `json:"modalias,omitempty"`
+
+	// UUID matches the disk UUID
+	// +optional
+	UUID *string `json:"uuid,omitempty"`
+
+	// WWID matches the disk world wide identifier
+	// +optional
+	WWID *string `json:"wwid,omitempty"`
+
+	// Type matches the disk type
+	// +kubebuilder:validation:Enum=ssd;hdd;nvme;sd
+	// +optional
+	Type *string `json:"type,omitempty"`
+
+	// BusPath matches the disk bus path, e.g. /pci0000:00/*
+	// +optional
+	BusPath *string `json:"busPath,omitempty"`
+}
+
 // ControlPlaneSpec defines control plane configuration
 type ControlPlaneSpec struct {
 	// Endpoint is the control plane endpoint URL
@@ -223,7 +383,161 @@ type ClusterNetworkSpec struct {
 
 // NetworkSpec defines machine network configuration (optional fields)
 type NetworkSpec struct {
-	// Additional network configuration can be added here
+	// Hostname is the machine hostname
+	// +optional
+	Hostname *string `json:"hostname,omitempty"`
+
+	// Nameservers are the DNS servers used by the machine
+	// +optional
+	Nameservers []string `json:"nameservers,omitempty"`
+
+	// Interfaces configures the machine network links
+	// +optional
+	Interfaces []NetworkInterfaceSpec `json:"interfaces,omitempty"`
+
+	// ExtraHostEntries are additional /etc/hosts entries
+	// +optional
+	ExtraHostEntries []ExtraHostEntrySpec `json:"extraHostEntries,omitempty"`
+}
+
+// NetworkInterfaceSpec defines a machine network link
+type NetworkInterfaceSpec struct {
+	// Interface is the link name (e.g., eth0, bond0, br0)
+	Interface string `json:"interface"`
+
+	// Addresses are static addresses in CIDR notation
+	// +optional
+	Addresses []string `json:"addresses,omitempty"`
+
+	// DHCP enables DHCP on the link
+	// +optional
+	DHCP *bool `json:"dhcp,omitempty"`
+
+	// Routes are static routes for the link
+	// +optional
+	Routes []RouteSpec `json:"routes,omitempty"`
+
+	// MTU is the link MTU
+	// +optional
+	// +kubebuilder:validation:Minimum=68
+	MTU *int32 `json:"mtu,omitempty"`
+
+	// VLANs are VLAN links created on top of this link
+	// +optional
+	VLANs []VLANSpec `json:"vlans,omitempty"`
+
+	// Bond turns this link into a bond of other links
+	// +optional
+	Bond *BondSpec `json:"bond,omitempty"`
+
+	// Bridge turns this link into a bridge of other links
+	// +optional
+	Bridge *BridgeSpec `json:"bridge,omitempty"`
+
+	// VIP is a shared virtual IP for the control-plane endpoint
+	// +optional
+	VIP *VIPSpec `json:"vip,omitempty"`
+}
+
+// RouteSpec defines a static route
+type RouteSpec struct {
+	// Network is the route destination in CIDR notation; empty means the default route
+	// +optional
+	Network string `json:"network,omitempty"`
+
+	// Gateway is the route gateway address
+	// +optional
+	Gateway string `json:"gateway,omitempty"`
+
+	// Source is the preferred source address
+	// +optional
+	Source *string `json:"source,omitempty"`
+
+	// Metric is the route metric
+	// +optional
+	// +kubebuilder:validation:Minimum=0
+	Metric *int32 `json:"metric,omitempty"`
+}
+
+// VLANSpec defines a VLAN link
+type VLANSpec struct {
+	// VLANID is the VLAN identifier
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=4094
+	VLANID int32 `json:"vlanId"`
+
+	// Addresses are static addresses in CIDR notation
+	// +optional
+	Addresses []string `json:"addresses,omitempty"`
+
+	// DHCP enables DHCP on the VLAN
+	// +optional
+	DHCP *bool `json:"dhcp,omitempty"`
+
+	// Routes are static routes for the VLAN
+	// +optional
+	Routes []RouteSpec `json:"routes,omitempty"`
+
+	// MTU is the VLAN MTU
+	// +optional
+	// +kubebuilder:validation:Minimum=68
+	MTU *int32 `json:"mtu,omitempty"`
+
+	// VIP is a shared virtual IP for the control-plane endpoint
+	// +optional
+	VIP *VIPSpec `json:"vip,omitempty"`
+}
+
+// BondSpec defines a bond link
+type BondSpec struct {
+	// Interfaces are the links enslaved to the bond
+	// +kubebuilder:validation:MinItems=1
+	Interfaces []string `json:"interfaces"`
+
+	// Mode is the bonding mode
+	// +kubebuilder:validation:Enum=balance-rr;active-backup;balance-xor;broadcast;"802.3ad";balance-tlb;balance-alb
+	Mode string `json:"mode"`
+
+	// LACPRate is the 802.3ad LACPDU rate (slow, fast)
+	// +optional
+	// +kubebuilder:validation:Enum=slow;fast
+	LACPRate *string `json:"lacpRate,omitempty"`
+
+	// XmitHashPolicy is the transmit hash policy (e.g., layer2, layer3+4)
+	// +optional
+	XmitHashPolicy *string `json:"xmitHashPolicy,omitempty"`
+
+	// MIIMon is the MII link monitoring frequency in milliseconds
+	// +optional
+	// +kubebuilder:validation:Minimum=0
+	MIIMon *int32 `json:"miimon,omitempty"`
+}
+
+// BridgeSpec defines a bridge link
+type BridgeSpec struct {
+	// Interfaces are the links attached to the bridge
+	// +kubebuilder:validation:MinItems=1
+	Interfaces []string `json:"interfaces"`
+
+	// STP enables the spanning tree protocol
+	// +optional
+	STP *bool `json:"stp,omitempty"`
+}
+
+// VIPSpec defines a shared virtual IP
+type VIPSpec struct {
+	// IP is the shared virtual IP address
+	IP string `json:"ip"`
+}
+
+// ExtraHostEntrySpec defines an /etc/hosts entry
+type ExtraHostEntrySpec struct {
+	// IP is the host address
+	IP string `json:"ip"`
+
+	// Aliases are the host names resolving to IP
+	// +kubebuilder:validation:MinItems=1
+	Aliases []string `json:"aliases"`
 }
 
 // KubeletSpec defines kubelet configuration (optional fields)
@@ -0,0 +1,162 @@
+/*
+Copyright 2025 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"reflect"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+)
+
+// EtcdMember removal actions.
+const (
+	// EtcdMemberRemovalNone leaves the member in place when the resource is
+	// deleted.
+	EtcdMemberRemovalNone = "None"
+	// EtcdMemberRemovalForget removes the member by ID through Node. Use it
+	// for members whose machine is gone.
+	EtcdMemberRemovalForget = "Forget"
+	// EtcdMemberRemovalLeave asks the member's own node to leave the cluster
+	// gracefully. The node must still be reachable.
+	EtcdMemberRemovalLeave = "Leave"
+)
+
+// EtcdMemberParameters are the configurable fields of an EtcdMember.
+// +kubebuilder:validation:XValidation:rule="(has(self.clientConfiguration) ? 1 : 0) + (has(self.clientConfigurationSecretRef) ? 1 : 0) + ((has(self.secretsRef) || has(self.secretsSelector)) ? 1 : 0) == 1",message="exactly one of clientConfiguration, clientConfigurationSecretRef or secretsRef/secretsSelector must be set"
+type EtcdMemberParameters struct {
+	// Node is the control plane node etcd membership is queried and changed
+	// through (required)
+	Node string `json:"node"`
+	// Endpoint is the machine endpoint (optional)
+	// +optional
+	Endpoint *string `json:"endpoint,omitempty"`
+	// Hostname is the hostname of the etcd member this resource manages.
+	// All members are observed when unset, and the resource is read-only.
+	// +optional
+	Hostname *string `json:"hostname,omitempty"`
+	// RemovalAction is what happens to the member when the resource is
+	// deleted: None keeps it, Forget removes it by ID through Node, and Leave
+	// asks the member's node to leave the cluster.
+	// +kubebuilder:validation:Enum=None;Forget;Leave
+	// +kubebuilder:default=None
+	// +optional
+	RemovalAction *string `json:"removalAction,omitempty"`
+	// ClientConfiguration holds the Talos API client credentials inline.
+	// Prefer clientConfigurationSecretRef or secretsRef to keep the client
+	// key out of the resource.
+	// +optional
+	ClientConfiguration *ClientConfiguration `json:"clientConfiguration,omitempty"`
+	// ClientConfigurationSecretRef references a Secret holding the Talos API
+	// client credentials under the client_configuration key, or the
+	// ca_certificate, client_certificate and client_key keys, such as the
+	// connection secret of a Secrets or ClientCertificate.
+	// +optional
+	ClientConfigurationSecretRef *xpv1.SecretReference `json:"clientConfigurationSecretRef,omitempty"`
+	// SecretsRef references the Secrets whose admin client credentials are
+	// used.
+	// +optional
+	SecretsRef *xpv1.Reference `json:"secretsRef,omitempty"`
+	// SecretsSelector selects the Secrets whose admin client credentials are
+	// used.
+	// +optional
+	SecretsSelector *xpv1.Selector `json:"secretsSelector,omitempty"`
+}
+
+// EtcdMemberInfo describes an etcd cluster member.
+type EtcdMemberInfo struct {
+	// ID is the member ID, hex encoded as printed by talosctl etcd members.
+	ID string `json:"id"`
+	// Hostname is the member's hostname.
+	Hostname string `json:"hostname"`
+	// PeerURLs are the URLs the member serves peer traffic on.
+	// +optional
+	PeerURLs []string `json:"peerURLs,omitempty"`
+	// ClientURLs are the URLs the member serves client traffic on.
+	// +optional
+	ClientURLs []string `json:"clientURLs,omitempty"`
+	// Learner indicates the member is a non-voting learner.
+	// +optional
+	Learner bool `json:"learner,omitempty"`
+}
+
+// EtcdMemberObservation are the observable fields of an EtcdMember.
+type EtcdMemberObservation struct {
+	// Members are all members of the etcd cluster.
+	// +optional
+	Members []EtcdMemberInfo `json:"members,omitempty"`
+	// MemberID is the ID of the member matching hostname, if it is present.
+	// +optional
+	MemberID string `json:"memberID,omitempty"`
+	// Present indicates a member matching hostname is in the cluster.
+	// +optional
+	Present bool `json:"present,omitempty"`
+}
+
+// An EtcdMemberSpec defines the desired state of an EtcdMember.
+type EtcdMemberSpec struct {
+	xpv1.ResourceSpec `json:",inline"`
+	ForProvider       EtcdMemberParameters `json:"forProvider"`
+}
+
+// An EtcdMemberStatus represents the observed state of an EtcdMember.
+type EtcdMemberStatus struct {
+	xpv1.ResourceStatus `json:",inline"`
+	AtProvider          EtcdMemberObservation `json:"atProvider,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// An EtcdMember observes etcd cluster membership and removes a member when
+// deleted.
+// +kubebuilder:printcolumn:name="READY",type="string",JSONPath=".status.conditions[?(@.type=='Ready')].status"
+// +kubebuilder:printcolumn:name="SYNCED",type="string",JSONPath=".status.conditions[?(@.type=='Synced')].status"
+// +kubebuilder:printcolumn:name="MEMBER-ID",type="string",JSONPath=".status.atProvider.memberID"
+// +kubebuilder:printcolumn:name="EXTERNAL-NAME",type="string",JSONPath=".metadata.annotations.crossplane\\.io/external-name"
+// +kubebuilder:printcolumn:name="AGE",type="date",JSONPath=".metadata.creationTimestamp"
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:scope=Cluster,categories={crossplane,managed,talos}
+type EtcdMember struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   EtcdMemberSpec   `json:"spec"`
+	Status EtcdMemberStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// EtcdMemberList contains a list of EtcdMember
+type EtcdMemberList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []EtcdMember `json:"items"`
+}
+
+// EtcdMember type metadata.
+var (
+	EtcdMemberKind             = reflect.TypeOf(EtcdMember{}).Name()
+	EtcdMemberGroupKind        = schema.GroupKind{Group: Group, Kind: EtcdMemberKind}.String()
+	EtcdMemberKindAPIVersion   = EtcdMemberKind + "." + SchemeGroupVersion.String()
+	EtcdMemberGroupVersionKind = SchemeGroupVersion.WithKind(EtcdMemberKind)
+)
+
+func init() {
+	SchemeBuilder.Register(&EtcdMember{}, &EtcdMemberList{})
+}
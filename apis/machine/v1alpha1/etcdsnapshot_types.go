@@ -0,0 +1,200 @@
+/*
+Copyright 2025 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"reflect"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+)
+
+// EtcdSnapshotParameters are the configurable fields of an EtcdSnapshot.
+// +kubebuilder:validation:XValidation:rule="(has(self.clientConfiguration) ? 1 : 0) + (has(self.clientConfigurationSecretRef) ? 1 : 0) + ((has(self.secretsRef) || has(self.secretsSelector)) ? 1 : 0) == 1",message="exactly one of clientConfiguration, clientConfigurationSecretRef or secretsRef/secretsSelector must be set"
+type EtcdSnapshotParameters struct {
+	// Node is the control plane node to take the snapshot from (required)
+	Node string `json:"node"`
+	// Endpoint is the machine endpoint (optional)
+	// +optional
+	Endpoint *string `json:"endpoint,omitempty"`
+	// Schedule is a cron expression, e.g. "0 */6 * * *" or "@daily", for
+	// taking recurring snapshots. A single snapshot is taken when unset.
+	// +optional
+	Schedule *string `json:"schedule,omitempty"`
+	// Retention is the number of snapshots to keep in the store. Older
+	// snapshots taken by this resource are deleted. Defaults to 7.
+	// +kubebuilder:default=7
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=500
+	// +optional
+	Retention *int `json:"retention,omitempty"`
+	// Storage is where snapshots are written.
+	Storage EtcdSnapshotStorage `json:"storage"`
+	// ClientConfiguration holds the Talos API client credentials inline.
+	// Prefer clientConfigurationSecretRef or secretsRef to keep the client
+	// key out of the resource.
+	// +optional
+	ClientConfiguration *ClientConfiguration `json:"clientConfiguration,omitempty"`
+	// ClientConfigurationSecretRef references a Secret holding the Talos API
+	// client credentials under the client_configuration key, or the
+	// ca_certificate, client_certificate and client_key keys, such as the
+	// connection secret of a Secrets or ClientCertificate.
+	// +optional
+	ClientConfigurationSecretRef *xpv1.SecretReference `json:"clientConfigurationSecretRef,omitempty"`
+	// SecretsRef references the Secrets whose admin client credentials are
+	// used.
+	// +optional
+	SecretsRef *xpv1.Reference `json:"secretsRef,omitempty"`
+	// SecretsSelector selects the Secrets whose admin client credentials are
+	// used.
+	// +optional
+	SecretsSelector *xpv1.Selector `json:"secretsSelector,omitempty"`
+}
+
+// EtcdSnapshotStorage selects the store snapshots are written to. Exactly one
+// store must be set.
+// +kubebuilder:validation:XValidation:rule="(has(self.secret) ? 1 : 0) + (has(self.volume) ? 1 : 0) + (has(self.s3) ? 1 : 0) == 1",message="exactly one of secret, volume or s3 must be set"
+type EtcdSnapshotStorage struct {
+	// Secret writes each snapshot to its own Kubernetes Secret. Secrets are
+	// limited to 1MiB, so this is only suitable for small clusters.
+	// +optional
+	Secret *EtcdSnapshotSecretStorage `json:"secret,omitempty"`
+	// Volume writes snapshots to a directory on a volume mounted into the
+	// provider pod, e.g. a PersistentVolumeClaim mounted through a
+	// DeploymentRuntimeConfig.
+	// +optional
+	Volume *EtcdSnapshotVolumeStorage `json:"volume,omitempty"`
+	// S3 uploads snapshots to an S3-compatible object store.
+	// +optional
+	S3 *EtcdSnapshotS3Storage `json:"s3,omitempty"`
+}
+
+// EtcdSnapshotSecretStorage stores snapshots in Kubernetes Secrets.
+type EtcdSnapshotSecretStorage struct {
+	// Namespace is the namespace the snapshot Secrets are created in.
+	Namespace string `json:"namespace"`
+	// Key is the data key holding the snapshot. Defaults to snapshot.db.
+	// +optional
+	Key *string `json:"key,omitempty"`
+}
+
+// EtcdSnapshotVolumeStorage stores snapshots as files in a directory.
+type EtcdSnapshotVolumeStorage struct {
+	// Path is the directory snapshots are written to.
+	Path string `json:"path"`
+}
+
+// EtcdSnapshotS3Storage stores snapshots in an S3-compatible bucket.
+type EtcdSnapshotS3Storage struct {
+	// Endpoint is the S3 endpoint URL, e.g. https://s3.us-east-1.amazonaws.com
+	// or http://minio.backup.svc:9000. Requests use path-style addressing.
+	Endpoint string `json:"endpoint"`
+	// Bucket is the bucket snapshots are uploaded to.
+	Bucket string `json:"bucket"`
+	// Prefix is prepended to snapshot object keys, e.g. talos/prod/.
+	// +optional
+	Prefix *string `json:"prefix,omitempty"`
+	// Region is the signing region. Defaults to us-east-1.
+	// +optional
+	Region *string `json:"region,omitempty"`
+	// CredentialsSecretRef references a Secret with accessKeyID and
+	// secretAccessKey keys, and an optional sessionToken key.
+	CredentialsSecretRef xpv1.SecretReference `json:"credentialsSecretRef"`
+}
+
+// EtcdSnapshotRecord describes a stored etcd snapshot.
+type EtcdSnapshotRecord struct {
+	// Location is where the snapshot is stored: a namespace/name Secret
+	// reference, a file path, or an s3://bucket/key URL.
+	Location string `json:"location"`
+	// Size is the snapshot size in bytes.
+	Size int64 `json:"size"`
+	// Revision is the etcd revision the snapshot was taken at.
+	// +optional
+	Revision int64 `json:"revision,omitempty"`
+	// Hash is the SHA-256 of the snapshot, hex encoded.
+	Hash string `json:"hash"`
+	// Time is when the snapshot was taken.
+	Time metav1.Time `json:"time"`
+}
+
+// EtcdSnapshotObservation are the observable fields of an EtcdSnapshot.
+type EtcdSnapshotObservation struct {
+	// LastSnapshot is the most recent snapshot.
+	// +optional
+	LastSnapshot *EtcdSnapshotRecord `json:"lastSnapshot,omitempty"`
+	// Snapshots are the retained snapshots taken by this resource, oldest first.
+	// +optional
+	Snapshots []EtcdSnapshotRecord `json:"snapshots,omitempty"`
+	// NextScheduleTime is when the next scheduled snapshot is due.
+	// +optional
+	NextScheduleTime *metav1.Time `json:"nextScheduleTime,omitempty"`
+}
+
+// An EtcdSnapshotSpec defines the desired state of an EtcdSnapshot.
+type EtcdSnapshotSpec struct {
+	xpv1.ResourceSpec `json:",inline"`
+	ForProvider       EtcdSnapshotParameters `json:"forProvider"`
+}
+
+// An EtcdSnapshotStatus represents the observed state of an EtcdSnapshot.
+type EtcdSnapshotStatus struct {
+	xpv1.ResourceStatus `json:",inline"`
+	AtProvider          EtcdSnapshotObservation `json:"atProvider,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// An EtcdSnapshot backs up etcd from a control plane node, once or on a schedule.
+// +kubebuilder:printcolumn:name="READY",type="string",JSONPath=".status.conditions[?(@.type=='Ready')].status"
+// +kubebuilder:printcolumn:name="SYNCED",type="string",JSONPath=".status.conditions[?(@.type=='Synced')].status"
+// +kubebuilder:printcolumn:name="LAST-SNAPSHOT",type="date",JSONPath=".status.atProvider.lastSnapshot.time"
+// +kubebuilder:printcolumn:name="SIZE",type="integer",JSONPath=".status.atProvider.lastSnapshot.size"
+// +kubebuilder:printcolumn:name="EXTERNAL-NAME",type="string",JSONPath=".metadata.annotations.crossplane\\.io/external-name"
+// +kubebuilder:printcolumn:name="AGE",type="date",JSONPath=".metadata.creationTimestamp"
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:scope=Cluster,categories={crossplane,managed,talos}
+type EtcdSnapshot struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   EtcdSnapshotSpec   `json:"spec"`
+	Status EtcdSnapshotStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// EtcdSnapshotList contains a list of EtcdSnapshot
+type EtcdSnapshotList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []EtcdSnapshot `json:"items"`
+}
+
+// EtcdSnapshot type metadata.
+var (
+	EtcdSnapshotKind             = reflect.TypeOf(EtcdSnapshot{}).Name()
+	EtcdSnapshotGroupKind        = schema.GroupKind{Group: Group, Kind: EtcdSnapshotKind}.String()
+	EtcdSnapshotKindAPIVersion   = EtcdSnapshotKind + "." + SchemeGroupVersion.String()
+	EtcdSnapshotGroupVersionKind = SchemeGroupVersion.WithKind(EtcdSnapshotKind)
+)
+
+func init() {
+	SchemeBuilder.Register(&EtcdSnapshot{}, &EtcdSnapshotList{})
+}
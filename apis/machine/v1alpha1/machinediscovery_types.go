@@ -0,0 +1,223 @@
+/*
+Copyright 2025 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"reflect"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+)
+
+// MachineDiscoveryParameters are the configurable fields of a
+// MachineDiscovery.
+// +kubebuilder:validation:XValidation:rule="has(self.cidrs) || has(self.addresses)",message="cidrs or addresses must be set"
+type MachineDiscoveryParameters struct {
+	// CIDRs are the address ranges to scan, e.g. 192.168.1.0/24. The network
+	// and broadcast addresses of IPv4 ranges are skipped.
+	// +optional
+	CIDRs []string `json:"cidrs,omitempty"`
+	// Addresses are individual addresses to probe.
+	// +optional
+	Addresses []string `json:"addresses,omitempty"`
+	// Port is the Talos API port probed on each address.
+	// +kubebuilder:default=50000
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=65535
+	// +optional
+	Port *int `json:"port,omitempty"`
+	// Concurrency is how many addresses are probed at the same time.
+	// +kubebuilder:default=32
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=256
+	// +optional
+	Concurrency *int `json:"concurrency,omitempty"`
+}
+
+// MachineDiscoveryObservation are the observable fields of a
+// MachineDiscovery.
+type MachineDiscoveryObservation struct {
+	// Machines are the machines in maintenance mode found by the latest probe
+	// of each address.
+	// +optional
+	Machines []DiscoveredMachine `json:"machines,omitempty"`
+	// ScannedAddresses is the number of addresses probed by the last complete
+	// scan.
+	// +optional
+	ScannedAddresses int `json:"scannedAddresses,omitempty"`
+	// ScanOffset is the index of the next address to probe while a scan that
+	// does not fit in a single poll is in progress.
+	// +optional
+	ScanOffset int `json:"scanOffset,omitempty"`
+	// LastScanTime is when the last complete scan finished.
+	// +optional
+	LastScanTime *metav1.Time `json:"lastScanTime,omitempty"`
+}
+
+// A DiscoveredMachine is a machine in maintenance mode, as reported by its
+// read-only maintenance API.
+type DiscoveredMachine struct {
+	// Address is the address the machine answered on.
+	Address string `json:"address"`
+	// Hardware describes the machine's system, processors and memory.
+	// +optional
+	Hardware *MachineHardware `json:"hardware,omitempty"`
+	// Disks are the machine's disks.
+	// +optional
+	Disks []MachineDisk `json:"disks,omitempty"`
+	// Links are the machine's physical network links.
+	// +optional
+	Links []MachineLink `json:"links,omitempty"`
+	// MACAddresses are the hardware addresses of the machine's physical
+	// network links.
+	// +optional
+	MACAddresses []string `json:"macAddresses,omitempty"`
+}
+
+// MachineHardware describes the hardware of a machine.
+type MachineHardware struct {
+	// Manufacturer is the system manufacturer.
+	// +optional
+	Manufacturer string `json:"manufacturer,omitempty"`
+	// ProductName is the system product name.
+	// +optional
+	ProductName string `json:"productName,omitempty"`
+	// SerialNumber is the system serial number.
+	// +optional
+	SerialNumber string `json:"serialNumber,omitempty"`
+	// UUID is the system UUID.
+	// +optional
+	UUID string `json:"uuid,omitempty"`
+	// SKUNumber is the system SKU number.
+	// +optional
+	SKUNumber string `json:"skuNumber,omitempty"`
+	// Processors is the number of processor sockets.
+	// +optional
+	Processors int `json:"processors,omitempty"`
+	// CPUCores is the total number of processor cores.
+	// +optional
+	CPUCores int `json:"cpuCores,omitempty"`
+	// MemoryMiB is the total size of the memory modules in MiB.
+	// +optional
+	MemoryMiB int64 `json:"memoryMiB,omitempty"`
+}
+
+// A MachineDisk is a disk of a machine.
+type MachineDisk struct {
+	// Name is the disk name, e.g. sda or nvme0n1.
+	Name string `json:"name"`
+	// DevPath is the device path, e.g. /dev/sda.
+	// +optional
+	DevPath string `json:"devPath,omitempty"`
+	// Size is the disk size in bytes.
+	// +optional
+	Size int64 `json:"size,omitempty"`
+	// Model is the disk model.
+	// +optional
+	Model string `json:"model,omitempty"`
+	// Serial is the disk serial number.
+	// +optional
+	Serial string `json:"serial,omitempty"`
+	// WWID is the disk's world wide identifier.
+	// +optional
+	WWID string `json:"wwid,omitempty"`
+	// Transport is the disk transport, e.g. sata, nvme or virtio.
+	// +optional
+	Transport string `json:"transport,omitempty"`
+	// Rotational indicates a spinning disk.
+	// +optional
+	Rotational bool `json:"rotational,omitempty"`
+	// Readonly indicates a read-only disk.
+	// +optional
+	Readonly bool `json:"readonly,omitempty"`
+	// CDROM indicates a CD-ROM drive.
+	// +optional
+	CDROM bool `json:"cdrom,omitempty"`
+}
+
+// A MachineLink is a physical network link of a machine.
+type MachineLink struct {
+	// Name is the link name, e.g. eth0 or enp1s0.
+	Name string `json:"name"`
+	// HardwareAddr is the link's MAC address.
+	// +optional
+	HardwareAddr string `json:"hardwareAddr,omitempty"`
+	// OperationalState is the link's operational state, e.g. up or down.
+	// +optional
+	OperationalState string `json:"operationalState,omitempty"`
+	// SpeedMegabits is the link speed in Mbit/s.
+	// +optional
+	SpeedMegabits int64 `json:"speedMegabits,omitempty"`
+	// Driver is the kernel driver of the link.
+	// +optional
+	Driver string `json:"driver,omitempty"`
+}
+
+// A MachineDiscoverySpec defines the desired state of a MachineDiscovery.
+type MachineDiscoverySpec struct {
+	xpv1.ResourceSpec `json:",inline"`
+	ForProvider       MachineDiscoveryParameters `json:"forProvider"`
+}
+
+// A MachineDiscoveryStatus represents the observed state of a
+// MachineDiscovery.
+type MachineDiscoveryStatus struct {
+	xpv1.ResourceStatus `json:",inline"`
+	AtProvider          MachineDiscoveryObservation `json:"atProvider,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// A MachineDiscovery scans address ranges for machines in maintenance mode
+// and reports their hardware, disks and network links.
+// +kubebuilder:printcolumn:name="READY",type="string",JSONPath=".status.conditions[?(@.type=='Ready')].status"
+// +kubebuilder:printcolumn:name="SYNCED",type="string",JSONPath=".status.conditions[?(@.type=='Synced')].status"
+// +kubebuilder:printcolumn:name="SCANNED",type="integer",JSONPath=".status.atProvider.scannedAddresses"
+// +kubebuilder:printcolumn:name="LAST-SCAN",type="date",JSONPath=".status.atProvider.lastScanTime"
+// +kubebuilder:printcolumn:name="AGE",type="date",JSONPath=".metadata.creationTimestamp"
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:scope=Cluster,categories={crossplane,managed,talos}
+type MachineDiscovery struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   MachineDiscoverySpec   `json:"spec"`
+	Status MachineDiscoveryStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// MachineDiscoveryList contains a list of MachineDiscovery
+type MachineDiscoveryList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []MachineDiscovery `json:"items"`
+}
+
+// MachineDiscovery type metadata.
+var (
+	MachineDiscoveryKind             = reflect.TypeOf(MachineDiscovery{}).Name()
+	MachineDiscoveryGroupKind        = schema.GroupKind{Group: Group, Kind: MachineDiscoveryKind}.String()
+	MachineDiscoveryKindAPIVersion   = MachineDiscoveryKind + "." + SchemeGroupVersion.String()
+	MachineDiscoveryGroupVersionKind = SchemeGroupVersion.WithKind(MachineDiscoveryKind)
+)
+
+func init() {
+	SchemeBuilder.Register(&MachineDiscovery{}, &MachineDiscoveryList{})
+}
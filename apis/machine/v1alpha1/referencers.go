@@ -0,0 +1,105 @@
+/*
+Copyright 2025 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/reference"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+)
+
+// ResolveSecretsReference resolves a reference or selector to a Secrets and
+// returns the resolved reference.
+func ResolveSecretsReference(ctx context.Context, c client.Reader, mg resource.Managed, ref *xpv1.Reference, selector *xpv1.Selector) (*xpv1.Reference, error) {
+	current := ""
+	if ref != nil {
+		current = ref.Name
+	}
+
+	rsp, err := reference.NewAPIResolver(c, mg).Resolve(ctx, reference.ResolutionRequest{
+		CurrentValue: current,
+		Reference:    ref,
+		Selector:     selector,
+		To:           reference.To{Managed: &Secrets{}, List: &SecretsList{}},
+		Extract:      func(mg resource.Managed) string { return mg.GetName() },
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "spec.forProvider.secretsRef")
+	}
+
+	return rsp.ResolvedReference, nil
+}
+
+// ResolveReferences of this Bootstrap.
+func (mg *Bootstrap) ResolveReferences(ctx context.Context, c client.Reader) error {
+	ref, err := ResolveSecretsReference(ctx, c, mg, mg.Spec.ForProvider.SecretsRef, mg.Spec.ForProvider.SecretsSelector)
+	if err != nil {
+		return err
+	}
+	mg.Spec.ForProvider.SecretsRef = ref
+
+	return nil
+}
+
+// ResolveReferences of this ConfigurationApply.
+func (mg *ConfigurationApply) ResolveReferences(ctx context.Context, c client.Reader) error {
+	ref, err := ResolveSecretsReference(ctx, c, mg, mg.Spec.ForProvider.SecretsRef, mg.Spec.ForProvider.SecretsSelector)
+	if err != nil {
+		return err
+	}
+	mg.Spec.ForProvider.SecretsRef = ref
+
+	return nil
+}
+
+// ResolveReferences of this EtcdMember.
+func (mg *EtcdMember) ResolveReferences(ctx context.Context, c client.Reader) error {
+	ref, err := ResolveSecretsReference(ctx, c, mg, mg.Spec.ForProvider.SecretsRef, mg.Spec.ForProvider.SecretsSelector)
+	if err != nil {
+		return err
+	}
+	mg.Spec.ForProvider.SecretsRef = ref
+
+	return nil
+}
+
+// ResolveReferences of this EtcdSnapshot.
+func (mg *EtcdSnapshot) ResolveReferences(ctx context.Context, c client.Reader) error {
+	ref, err := ResolveSecretsReference(ctx, c, mg, mg.Spec.ForProvider.SecretsRef, mg.Spec.ForProvider.SecretsSelector)
+	if err != nil {
+		return err
+	}
+	mg.Spec.ForProvider.SecretsRef = ref
+
+	return nil
+}
+
+// ResolveReferences of this Upgrade.
+func (mg *Upgrade) ResolveReferences(ctx context.Context, c client.Reader) error {
+	ref, err := ResolveSecretsReference(ctx, c, mg, mg.Spec.ForProvider.SecretsRef, mg.Spec.ForProvider.SecretsSelector)
+	if err != nil {
+		return err
+	}
+	mg.Spec.ForProvider.SecretsRef = ref
+
+	return nil
+}
@@ -25,7 +25,22 @@ import (
 	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
 )
 
+// CA rotation phases.
+const (
+	// CARotationPhaseAcceptingNewCA publishes newly generated CAs as accepted
+	// CAs while the current CAs still issue certificates.
+	CARotationPhaseAcceptingNewCA = "AcceptingNewCA"
+	// CARotationPhaseSwitchingCA makes the new CAs the issuing CAs, keeps the
+	// old CAs accepted and reissues client certificates.
+	CARotationPhaseSwitchingCA = "SwitchingCA"
+	// CARotationPhaseDroppingOldCA stops accepting the old CAs.
+	CARotationPhaseDroppingOldCA = "DroppingOldCA"
+	// CARotationPhaseCompleted means the last requested rotation has finished.
+	CARotationPhaseCompleted = "Completed"
+)
+
 // SecretsParameters are the configurable fields of a Secrets.
+// +kubebuilder:validation:XValidation:rule="!has(self.talosconfigRef) || has(self.secretsBundleRef)",message="talosconfigRef requires secretsBundleRef"
 type SecretsParameters struct {
 	// Node is the Talos node endpoint for secrets validation (optional)
 	// +optional
@@ -33,6 +48,59 @@ type SecretsParameters struct {
 	// TalosVersion is the Talos version for feature compatibility
 	// +optional
 	TalosVersion *string `json:"talosVersion,omitempty"`
+	// ClientCertificateTTL is the validity of issued Talos API admin client
+	// certificates. Defaults to one year.
+	// +optional
+	ClientCertificateTTL *metav1.Duration `json:"clientCertificateTTL,omitempty"`
+	// ClientCertificateRenewBefore is how long before expiry the client
+	// certificate is reissued from the OS CA and republished. Defaults to 30
+	// days, or a third of clientCertificateTTL if that is shorter.
+	// +optional
+	ClientCertificateRenewBefore *metav1.Duration `json:"clientCertificateRenewBefore,omitempty"`
+	// SecretsBundleRef references a Secret key holding an existing secrets
+	// bundle to import instead of generating a new one, such as the
+	// secrets.yaml written by talosctl gen secrets or the machine_secrets
+	// connection detail of another Secrets.
+	// +optional
+	SecretsBundleRef *xpv1.SecretKeySelector `json:"secretsBundleRef,omitempty"`
+	// TalosconfigRef references a Secret key holding an existing talosconfig
+	// whose client credentials are published with the imported bundle. A new
+	// client certificate is issued from the bundle's OS CA when unset.
+	// +optional
+	TalosconfigRef *xpv1.SecretKeySelector `json:"talosconfigRef,omitempty"`
+	// CARotation opts in to rotating the Talos API (OS) and Kubernetes CAs.
+	// +optional
+	CARotation *SecretsCARotation `json:"caRotation,omitempty"`
+	// TalosconfigContext is the name of the context of the published
+	// talosconfig. Defaults to "default".
+	// +optional
+	TalosconfigContext *string `json:"talosconfigContext,omitempty"`
+	// Endpoints are the Talos API endpoints of the published talosconfig.
+	// +optional
+	Endpoints []string `json:"endpoints,omitempty"`
+	// Nodes are the default target nodes of the published talosconfig.
+	// +optional
+	Nodes []string `json:"nodes,omitempty"`
+}
+
+// SecretsCARotation requests rotation of the OS and Kubernetes CAs. Each
+// phase of a rotation waits until the Configurations referencing the Secrets
+// and the ConfigurationApplies applying them have rolled out.
+// +kubebuilder:validation:XValidation:rule="!has(self.os) || !has(self.kubernetes) || self.os || self.kubernetes",message="at least one of os or kubernetes must be rotated"
+type SecretsCARotation struct {
+	// Generation identifies the requested rotation. A rotation starts when it
+	// differs from the generation of the last rotation; secrets generated or
+	// imported with a generation set count as rotated.
+	// +kubebuilder:validation:Minimum=1
+	Generation int64 `json:"generation"`
+	// OS rotates the Talos API CA.
+	// +kubebuilder:default=true
+	// +optional
+	OS *bool `json:"os,omitempty"`
+	// Kubernetes rotates the Kubernetes API CA.
+	// +kubebuilder:default=true
+	// +optional
+	Kubernetes *bool `json:"kubernetes,omitempty"`
 }
 
 // ClientConfiguration contains client configuration for Talos API
@@ -109,10 +177,30 @@ type MachineSecretsKey struct {
 	Key string `json:"key"`
 }
 
+// SecretsCARotationStatus reports the progress of a CA rotation.
+type SecretsCARotationStatus struct {
+	// Generation is the generation of the rotation in progress or last
+	// completed.
+	Generation int64 `json:"generation,omitempty"`
+	// Phase is the rotation phase: AcceptingNewCA, SwitchingCA, DroppingOldCA
+	// or Completed.
+	Phase string `json:"phase,omitempty"`
+	// PhaseTime is when the current phase started.
+	// +optional
+	PhaseTime *metav1.Time `json:"phaseTime,omitempty"`
+	// Pending lists the dependent resources the current phase is waiting on.
+	// +optional
+	Pending []string `json:"pending,omitempty"`
+}
+
 // SecretsObservation are the observable fields of a Secrets.
 type SecretsObservation struct {
 	// Generated indicates machine secrets have been generated and published to connection details.
 	Generated bool `json:"generated,omitempty"`
+	// Imported indicates machine secrets were imported from secretsBundleRef
+	// rather than generated.
+	// +optional
+	Imported bool `json:"imported,omitempty"`
 	// GeneratedTime is when machine secrets were first generated.
 	// +optional
 	GeneratedTime *metav1.Time `json:"generatedTime,omitempty"`
@@ -125,6 +213,20 @@ type SecretsObservation struct {
 	// TalosConfigHash is a SHA-256 hash of the talos config connection detail.
 	// +optional
 	TalosConfigHash string `json:"talosConfigHash,omitempty"`
+	// ClientCertificateNotAfter is when the published client certificate expires.
+	// +optional
+	ClientCertificateNotAfter *metav1.Time `json:"clientCertificateNotAfter,omitempty"`
+	// ClientCertificateRenewalTime is when the client certificate is due to be
+	// reissued.
+	// +optional
+	ClientCertificateRenewalTime *metav1.Time `json:"clientCertificateRenewalTime,omitempty"`
+	// ClientCertificateRenewedTime is when the client certificate was last
+	// reissued.
+	// +optional
+	ClientCertificateRenewedTime *metav1.Time `json:"clientCertificateRenewedTime,omitempty"`
+	// CARotation is the progress of the current or last CA rotation.
+	// +optional
+	CARotation *SecretsCARotationStatus `json:"caRotation,omitempty"`
 }
 
 // A SecretsSpec defines the desired state of a Secrets.
@@ -144,6 +246,8 @@ type SecretsStatus struct {
 // A Secrets generates and manages machine secrets for Talos clusters.
 // +kubebuilder:printcolumn:name="READY",type="string",JSONPath=".status.conditions[?(@.type=='Ready')].status"
 // +kubebuilder:printcolumn:name="SYNCED",type="string",JSONPath=".status.conditions[?(@.type=='Synced')].status"
+// +kubebuilder:printcolumn:name="CLIENT-CERT-EXPIRY",type="string",JSONPath=".status.atProvider.clientCertificateNotAfter"
+// +kubebuilder:printcolumn:name="CA-ROTATION",type="string",JSONPath=".status.atProvider.caRotation.phase"
 // +kubebuilder:printcolumn:name="EXTERNAL-NAME",type="string",JSONPath=".metadata.annotations.crossplane\\.io/external-name"
 // +kubebuilder:printcolumn:name="AGE",type="date",JSONPath=".metadata.creationTimestamp"
 // +kubebuilder:subresource:status
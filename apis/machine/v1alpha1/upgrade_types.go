@@ -0,0 +1,158 @@
+/*
+Copyright 2025 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"reflect"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+)
+
+// UpgradeParameters are the configurable fields of an Upgrade.
+// +kubebuilder:validation:XValidation:rule="has(self.image) || has(self.factorySchematicRef)",message="image or factorySchematicRef must be set"
+// +kubebuilder:validation:XValidation:rule="!has(self.factorySchematicRef) || has(self.talosVersion)",message="talosVersion is required when factorySchematicRef is set"
+// +kubebuilder:validation:XValidation:rule="!has(self.secureBoot) || has(self.factorySchematicRef)",message="secureBoot requires factorySchematicRef"
+// +kubebuilder:validation:XValidation:rule="(has(self.clientConfiguration) ? 1 : 0) + (has(self.clientConfigurationSecretRef) ? 1 : 0) + ((has(self.secretsRef) || has(self.secretsSelector)) ? 1 : 0) == 1",message="exactly one of clientConfiguration, clientConfigurationSecretRef or secretsRef/secretsSelector must be set"
+type UpgradeParameters struct {
+	// Node is the node to upgrade (required)
+	Node string `json:"node"`
+	// Endpoint is the machine endpoint (optional)
+	// +optional
+	Endpoint *string `json:"endpoint,omitempty"`
+	// Image is the target Talos installer image, e.g. ghcr.io/siderolabs/installer:v1.11.0.
+	// +optional
+	Image *string `json:"image,omitempty"`
+	// FactorySchematicRef references a FactorySchematic whose installer image
+	// for its platform is used when image is not set.
+	// +optional
+	FactorySchematicRef *xpv1.Reference `json:"factorySchematicRef,omitempty"`
+	// SecureBoot selects the SecureBoot installer image of the referenced
+	// FactorySchematic, for nodes booted with SecureBoot.
+	// +optional
+	SecureBoot *bool `json:"secureBoot,omitempty"`
+	// TalosVersion is the target Talos version. Defaults to the tag of image.
+	// +optional
+	TalosVersion *string `json:"talosVersion,omitempty"`
+	// Stage stages the upgrade so it is performed on the next reboot.
+	// +optional
+	Stage *bool `json:"stage,omitempty"`
+	// Preserve keeps the ephemeral partition data across the upgrade.
+	// +optional
+	Preserve *bool `json:"preserve,omitempty"`
+	// Force skips the etcd health checks Talos runs before upgrading a control plane node.
+	// +optional
+	Force *bool `json:"force,omitempty"`
+	// ClientConfiguration holds the Talos API client credentials inline.
+	// Prefer clientConfigurationSecretRef or secretsRef to keep the client
+	// key out of the resource.
+	// +optional
+	ClientConfiguration *ClientConfiguration `json:"clientConfiguration,omitempty"`
+	// ClientConfigurationSecretRef references a Secret holding the Talos API
+	// client credentials under the client_configuration key, or the
+	// ca_certificate, client_certificate and client_key keys, such as the
+	// connection secret of a Secrets or ClientCertificate.
+	// +optional
+	ClientConfigurationSecretRef *xpv1.SecretReference `json:"clientConfigurationSecretRef,omitempty"`
+	// SecretsRef references the Secrets whose admin client credentials are
+	// used.
+	// +optional
+	SecretsRef *xpv1.Reference `json:"secretsRef,omitempty"`
+	// SecretsSelector selects the Secrets whose admin client credentials are
+	// used.
+	// +optional
+	SecretsSelector *xpv1.Selector `json:"secretsSelector,omitempty"`
+}
+
+// UpgradeObservation are the observable fields of an Upgrade.
+type UpgradeObservation struct {
+	// Phase is the upgrade progress (Pending, Requested, Rebooting, Completed, Failed)
+	// +optional
+	Phase string `json:"phase,omitempty"`
+	// CurrentVersion is the Talos version last reported by the node
+	// +optional
+	CurrentVersion string `json:"currentVersion,omitempty"`
+	// TargetVersion is the resolved target Talos version
+	// +optional
+	TargetVersion string `json:"targetVersion,omitempty"`
+	// Image is the resolved installer image of the last upgrade request
+	// +optional
+	Image string `json:"image,omitempty"`
+	// ActorID is the Talos actor ID returned for the last upgrade request
+	// +optional
+	ActorID string `json:"actorID,omitempty"`
+	// UpgradeRequestedTime is when the last upgrade was requested
+	// +optional
+	UpgradeRequestedTime *metav1.Time `json:"upgradeRequestedTime,omitempty"`
+	// UpgradeCompletedTime is when the node was first observed running the target version
+	// +optional
+	UpgradeCompletedTime *metav1.Time `json:"upgradeCompletedTime,omitempty"`
+}
+
+// An UpgradeSpec defines the desired state of an Upgrade.
+type UpgradeSpec struct {
+	xpv1.ResourceSpec `json:",inline"`
+	ForProvider       UpgradeParameters `json:"forProvider"`
+}
+
+// An UpgradeStatus represents the observed state of an Upgrade.
+type UpgradeStatus struct {
+	xpv1.ResourceStatus `json:",inline"`
+	AtProvider          UpgradeObservation `json:"atProvider,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// An Upgrade upgrades the Talos OS of a node to a target installer image.
+// +kubebuilder:printcolumn:name="READY",type="string",JSONPath=".status.conditions[?(@.type=='Ready')].status"
+// +kubebuilder:printcolumn:name="SYNCED",type="string",JSONPath=".status.conditions[?(@.type=='Synced')].status"
+// +kubebuilder:printcolumn:name="PHASE",type="string",JSONPath=".status.atProvider.phase"
+// +kubebuilder:printcolumn:name="VERSION",type="string",JSONPath=".status.atProvider.currentVersion"
+// +kubebuilder:printcolumn:name="EXTERNAL-NAME",type="string",JSONPath=".metadata.annotations.crossplane\\.io/external-name"
+// +kubebuilder:printcolumn:name="AGE",type="date",JSONPath=".metadata.creationTimestamp"
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:scope=Cluster,categories={crossplane,managed,talos}
+type Upgrade struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   UpgradeSpec   `json:"spec"`
+	Status UpgradeStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// UpgradeList contains a list of Upgrade
+type UpgradeList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []Upgrade `json:"items"`
+}
+
+// Upgrade type metadata.
+var (
+	UpgradeKind             = reflect.TypeOf(Upgrade{}).Name()
+	UpgradeGroupKind        = schema.GroupKind{Group: Group, Kind: UpgradeKind}.String()
+	UpgradeKindAPIVersion   = UpgradeKind + "." + SchemeGroupVersion.String()
+	UpgradeGroupVersionKind = SchemeGroupVersion.WithKind(UpgradeKind)
+)
+
+func init() {
+	SchemeBuilder.Register(&Upgrade{}, &UpgradeList{})
+}
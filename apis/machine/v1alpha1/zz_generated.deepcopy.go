@@ -10,9 +10,45 @@ package v1alpha1
 
 import (
 	"github.com/crossplane/crossplane-runtime/apis/common/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	runtime "k8s.io/apimachinery/pkg/runtime"
 )
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BondSpec) DeepCopyInto(out *BondSpec) {
+	*out = *in
+	if in.Interfaces != nil {
+		in, out := &in.Interfaces, &out.Interfaces
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.LACPRate != nil {
+		in, out := &in.LACPRate, &out.LACPRate
+		*out = new(string)
+		**out = **in
+	}
+	if in.XmitHashPolicy != nil {
+		in, out := &in.XmitHashPolicy, &out.XmitHashPolicy
+		*out = new(string)
+		**out = **in
+	}
+	if in.MIIMon != nil {
+		in, out := &in.MIIMon, &out.MIIMon
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BondSpec.
+func (in *BondSpec) DeepCopy() *BondSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(BondSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Bootstrap) DeepCopyInto(out *Bootstrap) {
 	*out = *in
@@ -79,6 +115,10 @@ func (in *BootstrapObservation) DeepCopyInto(out *BootstrapObservation) {
 		in, out := &in.BootstrapTime, &out.BootstrapTime
 		*out = (*in).DeepCopy()
 	}
+	if in.RecoveryTime != nil {
+		in, out := &in.RecoveryTime, &out.RecoveryTime
+		*out = (*in).DeepCopy()
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BootstrapObservation.
@@ -99,7 +139,36 @@ func (in *BootstrapParameters) DeepCopyInto(out *BootstrapParameters) {
 		*out = new(string)
 		**out = **in
 	}
-	out.ClientConfiguration = in.ClientConfiguration
+	if in.Endpoints != nil {
+		in, out := &in.Endpoints, &out.Endpoints
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.ClientConfiguration != nil {
+		in, out := &in.ClientConfiguration, &out.ClientConfiguration
+		*out = new(ClientConfiguration)
+		**out = **in
+	}
+	if in.ClientConfigurationSecretRef != nil {
+		in, out := &in.ClientConfigurationSecretRef, &out.ClientConfigurationSecretRef
+		*out = new(v1.SecretReference)
+		**out = **in
+	}
+	if in.SecretsRef != nil {
+		in, out := &in.SecretsRef, &out.SecretsRef
+		*out = new(v1.Reference)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.SecretsSelector != nil {
+		in, out := &in.SecretsSelector, &out.SecretsSelector
+		*out = new(v1.Selector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Recovery != nil {
+		in, out := &in.Recovery, &out.Recovery
+		*out = new(BootstrapRecovery)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BootstrapParameters.
@@ -112,6 +181,61 @@ func (in *BootstrapParameters) DeepCopy() *BootstrapParameters {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BootstrapRecovery) DeepCopyInto(out *BootstrapRecovery) {
+	*out = *in
+	if in.SnapshotSecretRef != nil {
+		in, out := &in.SnapshotSecretRef, &out.SnapshotSecretRef
+		*out = new(v1.SecretKeySelector)
+		**out = **in
+	}
+	if in.EtcdSnapshotRef != nil {
+		in, out := &in.EtcdSnapshotRef, &out.EtcdSnapshotRef
+		*out = new(v1.Reference)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.DataDirectory != nil {
+		in, out := &in.DataDirectory, &out.DataDirectory
+		*out = new(BootstrapRecoveryDataDirectory)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.SkipHashCheck != nil {
+		in, out := &in.SkipHashCheck, &out.SkipHashCheck
+		*out = new(bool)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BootstrapRecovery.
+func (in *BootstrapRecovery) DeepCopy() *BootstrapRecovery {
+	if in == nil {
+		return nil
+	}
+	out := new(BootstrapRecovery)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BootstrapRecoveryDataDirectory) DeepCopyInto(out *BootstrapRecoveryDataDirectory) {
+	*out = *in
+	if in.Node != nil {
+		in, out := &in.Node, &out.Node
+		*out = new(string)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BootstrapRecoveryDataDirectory.
+func (in *BootstrapRecoveryDataDirectory) DeepCopy() *BootstrapRecoveryDataDirectory {
+	if in == nil {
+		return nil
+	}
+	out := new(BootstrapRecoveryDataDirectory)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *BootstrapSpec) DeepCopyInto(out *BootstrapSpec) {
 	*out = *in
@@ -146,6 +270,31 @@ func (in *BootstrapStatus) DeepCopy() *BootstrapStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BridgeSpec) DeepCopyInto(out *BridgeSpec) {
+	*out = *in
+	if in.Interfaces != nil {
+		in, out := &in.Interfaces, &out.Interfaces
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.STP != nil {
+		in, out := &in.STP, &out.STP
+		*out = new(bool)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BridgeSpec.
+func (in *BridgeSpec) DeepCopy() *BridgeSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(BridgeSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *CASpec) DeepCopyInto(out *CASpec) {
 	*out = *in
@@ -161,6 +310,162 @@ func (in *CASpec) DeepCopy() *CASpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClientCertificate) DeepCopyInto(out *ClientCertificate) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClientCertificate.
+func (in *ClientCertificate) DeepCopy() *ClientCertificate {
+	if in == nil {
+		return nil
+	}
+	out := new(ClientCertificate)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ClientCertificate) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClientCertificateList) DeepCopyInto(out *ClientCertificateList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]ClientCertificate, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClientCertificateList.
+func (in *ClientCertificateList) DeepCopy() *ClientCertificateList {
+	if in == nil {
+		return nil
+	}
+	out := new(ClientCertificateList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ClientCertificateList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClientCertificateObservation) DeepCopyInto(out *ClientCertificateObservation) {
+	*out = *in
+	if in.Roles != nil {
+		in, out := &in.Roles, &out.Roles
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.NotAfter != nil {
+		in, out := &in.NotAfter, &out.NotAfter
+		*out = (*in).DeepCopy()
+	}
+	if in.RenewalTime != nil {
+		in, out := &in.RenewalTime, &out.RenewalTime
+		*out = (*in).DeepCopy()
+	}
+	if in.IssuedTime != nil {
+		in, out := &in.IssuedTime, &out.IssuedTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClientCertificateObservation.
+func (in *ClientCertificateObservation) DeepCopy() *ClientCertificateObservation {
+	if in == nil {
+		return nil
+	}
+	out := new(ClientCertificateObservation)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClientCertificateParameters) DeepCopyInto(out *ClientCertificateParameters) {
+	*out = *in
+	in.SecretsRef.DeepCopyInto(&out.SecretsRef)
+	if in.Roles != nil {
+		in, out := &in.Roles, &out.Roles
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.TTL != nil {
+		in, out := &in.TTL, &out.TTL
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.RenewBefore != nil {
+		in, out := &in.RenewBefore, &out.RenewBefore
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClientCertificateParameters.
+func (in *ClientCertificateParameters) DeepCopy() *ClientCertificateParameters {
+	if in == nil {
+		return nil
+	}
+	out := new(ClientCertificateParameters)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClientCertificateSpec) DeepCopyInto(out *ClientCertificateSpec) {
+	*out = *in
+	in.ResourceSpec.DeepCopyInto(&out.ResourceSpec)
+	in.ForProvider.DeepCopyInto(&out.ForProvider)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClientCertificateSpec.
+func (in *ClientCertificateSpec) DeepCopy() *ClientCertificateSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ClientCertificateSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClientCertificateStatus) DeepCopyInto(out *ClientCertificateStatus) {
+	*out = *in
+	in.ResourceStatus.DeepCopyInto(&out.ResourceStatus)
+	in.AtProvider.DeepCopyInto(&out.AtProvider)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClientCertificateStatus.
+func (in *ClientCertificateStatus) DeepCopy() *ClientCertificateStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ClientCertificateStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ClientConfiguration) DeepCopyInto(out *ClientConfiguration) {
 	*out = *in
@@ -216,6 +521,11 @@ func (in *ClusterSpec) DeepCopyInto(out *ClusterSpec) {
 		*out = new(CASpec)
 		**out = **in
 	}
+	if in.SecretboxEncryptionSecret != nil {
+		in, out := &in.SecretboxEncryptionSecret, &out.SecretboxEncryptionSecret
+		*out = new(string)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterSpec.
@@ -325,6 +635,20 @@ func (in *ConfigurationApplyObservation) DeepCopyInto(out *ConfigurationApplyObs
 		in, out := &in.LastStateCheck, &out.LastStateCheck
 		*out = (*in).DeepCopy()
 	}
+	if in.ResetTime != nil {
+		in, out := &in.ResetTime, &out.ResetTime
+		*out = (*in).DeepCopy()
+	}
+	if in.ConfigDrift != nil {
+		in, out := &in.ConfigDrift, &out.ConfigDrift
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Disks != nil {
+		in, out := &in.Disks, &out.Disks
+		*out = make([]MachineDisk, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ConfigurationApplyObservation.
@@ -345,6 +669,11 @@ func (in *ConfigurationApplyParameters) DeepCopyInto(out *ConfigurationApplyPara
 		*out = new(string)
 		**out = **in
 	}
+	if in.Endpoints != nil {
+		in, out := &in.Endpoints, &out.Endpoints
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 	if in.ApplyMode != nil {
 		in, out := &in.ApplyMode, &out.ApplyMode
 		*out = new(string)
@@ -365,12 +694,41 @@ func (in *ConfigurationApplyParameters) DeepCopyInto(out *ConfigurationApplyPara
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
+	if in.KubernetesImages != nil {
+		in, out := &in.KubernetesImages, &out.KubernetesImages
+		*out = new(KubernetesImages)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.OnDestroy != nil {
 		in, out := &in.OnDestroy, &out.OnDestroy
 		*out = new(string)
 		**out = **in
 	}
-	out.ClientConfiguration = in.ClientConfiguration
+	if in.OnDestroyTimeout != nil {
+		in, out := &in.OnDestroyTimeout, &out.OnDestroyTimeout
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.ClientConfiguration != nil {
+		in, out := &in.ClientConfiguration, &out.ClientConfiguration
+		*out = new(ClientConfiguration)
+		**out = **in
+	}
+	if in.ClientConfigurationSecretRef != nil {
+		in, out := &in.ClientConfigurationSecretRef, &out.ClientConfigurationSecretRef
+		*out = new(v1.SecretReference)
+		**out = **in
+	}
+	if in.SecretsRef != nil {
+		in, out := &in.SecretsRef, &out.SecretsRef
+		*out = new(v1.Reference)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.SecretsSelector != nil {
+		in, out := &in.SecretsSelector, &out.SecretsSelector
+		*out = new(v1.Selector)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ConfigurationApplyParameters.
@@ -553,37 +911,629 @@ func (in *ControlPlaneSpec) DeepCopy() *ControlPlaneSpec {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *FeaturesSpec) DeepCopyInto(out *FeaturesSpec) {
+func (in *DiscoveredMachine) DeepCopyInto(out *DiscoveredMachine) {
 	*out = *in
-	if in.RBAC != nil {
-		in, out := &in.RBAC, &out.RBAC
-		*out = new(bool)
+	if in.Hardware != nil {
+		in, out := &in.Hardware, &out.Hardware
+		*out = new(MachineHardware)
 		**out = **in
 	}
+	if in.Disks != nil {
+		in, out := &in.Disks, &out.Disks
+		*out = make([]MachineDisk, len(*in))
+		copy(*out, *in)
+	}
+	if in.Links != nil {
+		in, out := &in.Links, &out.Links
+		*out = make([]MachineLink, len(*in))
+		copy(*out, *in)
+	}
+	if in.MACAddresses != nil {
+		in, out := &in.MACAddresses, &out.MACAddresses
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FeaturesSpec.
-func (in *FeaturesSpec) DeepCopy() *FeaturesSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DiscoveredMachine.
+func (in *DiscoveredMachine) DeepCopy() *DiscoveredMachine {
 	if in == nil {
 		return nil
 	}
-	out := new(FeaturesSpec)
+	out := new(DiscoveredMachine)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *InstallSpec) DeepCopyInto(out *InstallSpec) {
+func (in *EtcdMember) DeepCopyInto(out *EtcdMember) {
 	*out = *in
-	if in.Wipe != nil {
-		in, out := &in.Wipe, &out.Wipe
-		*out = new(bool)
-		**out = **in
-	}
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InstallSpec.
-func (in *InstallSpec) DeepCopy() *InstallSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EtcdMember.
+func (in *EtcdMember) DeepCopy() *EtcdMember {
+	if in == nil {
+		return nil
+	}
+	out := new(EtcdMember)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *EtcdMember) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *EtcdMemberInfo) DeepCopyInto(out *EtcdMemberInfo) {
+	*out = *in
+	if in.PeerURLs != nil {
+		in, out := &in.PeerURLs, &out.PeerURLs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.ClientURLs != nil {
+		in, out := &in.ClientURLs, &out.ClientURLs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EtcdMemberInfo.
+func (in *EtcdMemberInfo) DeepCopy() *EtcdMemberInfo {
+	if in == nil {
+		return nil
+	}
+	out := new(EtcdMemberInfo)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *EtcdMemberList) DeepCopyInto(out *EtcdMemberList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]EtcdMember, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EtcdMemberList.
+func (in *EtcdMemberList) DeepCopy() *EtcdMemberList {
+	if in == nil {
+		return nil
+	}
+	out := new(EtcdMemberList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *EtcdMemberList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *EtcdMemberObservation) DeepCopyInto(out *EtcdMemberObservation) {
+	*out = *in
+	if in.Members != nil {
+		in, out := &in.Members, &out.Members
+		*out = make([]EtcdMemberInfo, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EtcdMemberObservation.
+func (in *EtcdMemberObservation) DeepCopy() *EtcdMemberObservation {
+	if in == nil {
+		return nil
+	}
+	out := new(EtcdMemberObservation)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *EtcdMemberParameters) DeepCopyInto(out *EtcdMemberParameters) {
+	*out = *in
+	if in.Endpoint != nil {
+		in, out := &in.Endpoint, &out.Endpoint
+		*out = new(string)
+		**out = **in
+	}
+	if in.Hostname != nil {
+		in, out := &in.Hostname, &out.Hostname
+		*out = new(string)
+		**out = **in
+	}
+	if in.RemovalAction != nil {
+		in, out := &in.RemovalAction, &out.RemovalAction
+		*out = new(string)
+		**out = **in
+	}
+	if in.ClientConfiguration != nil {
+		in, out := &in.ClientConfiguration, &out.ClientConfiguration
+		*out = new(ClientConfiguration)
+		**out = **in
+	}
+	if in.ClientConfigurationSecretRef != nil {
+		in, out := &in.ClientConfigurationSecretRef, &out.ClientConfigurationSecretRef
+		*out = new(v1.SecretReference)
+		**out = **in
+	}
+	if in.SecretsRef != nil {
+		in, out := &in.SecretsRef, &out.SecretsRef
+		*out = new(v1.Reference)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.SecretsSelector != nil {
+		in, out := &in.SecretsSelector, &out.SecretsSelector
+		*out = new(v1.Selector)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EtcdMemberParameters.
+func (in *EtcdMemberParameters) DeepCopy() *EtcdMemberParameters {
+	if in == nil {
+		return nil
+	}
+	out := new(EtcdMemberParameters)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *EtcdMemberSpec) DeepCopyInto(out *EtcdMemberSpec) {
+	*out = *in
+	in.ResourceSpec.DeepCopyInto(&out.ResourceSpec)
+	in.ForProvider.DeepCopyInto(&out.ForProvider)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EtcdMemberSpec.
+func (in *EtcdMemberSpec) DeepCopy() *EtcdMemberSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(EtcdMemberSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *EtcdMemberStatus) DeepCopyInto(out *EtcdMemberStatus) {
+	*out = *in
+	in.ResourceStatus.DeepCopyInto(&out.ResourceStatus)
+	in.AtProvider.DeepCopyInto(&out.AtProvider)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EtcdMemberStatus.
+func (in *EtcdMemberStatus) DeepCopy() *EtcdMemberStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(EtcdMemberStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *EtcdSnapshot) DeepCopyInto(out *EtcdSnapshot) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EtcdSnapshot.
+func (in *EtcdSnapshot) DeepCopy() *EtcdSnapshot {
+	if in == nil {
+		return nil
+	}
+	out := new(EtcdSnapshot)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *EtcdSnapshot) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *EtcdSnapshotList) DeepCopyInto(out *EtcdSnapshotList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]EtcdSnapshot, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EtcdSnapshotList.
+func (in *EtcdSnapshotList) DeepCopy() *EtcdSnapshotList {
+	if in == nil {
+		return nil
+	}
+	out := new(EtcdSnapshotList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *EtcdSnapshotList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *EtcdSnapshotObservation) DeepCopyInto(out *EtcdSnapshotObservation) {
+	*out = *in
+	if in.LastSnapshot != nil {
+		in, out := &in.LastSnapshot, &out.LastSnapshot
+		*out = new(EtcdSnapshotRecord)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Snapshots != nil {
+		in, out := &in.Snapshots, &out.Snapshots
+		*out = make([]EtcdSnapshotRecord, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.NextScheduleTime != nil {
+		in, out := &in.NextScheduleTime, &out.NextScheduleTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EtcdSnapshotObservation.
+func (in *EtcdSnapshotObservation) DeepCopy() *EtcdSnapshotObservation {
+	if in == nil {
+		return nil
+	}
+	out := new(EtcdSnapshotObservation)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *EtcdSnapshotParameters) DeepCopyInto(out *EtcdSnapshotParameters) {
+	*out = *in
+	if in.Endpoint != nil {
+		in, out := &in.Endpoint, &out.Endpoint
+		*out = new(string)
+		**out = **in
+	}
+	if in.Schedule != nil {
+		in, out := &in.Schedule, &out.Schedule
+		*out = new(string)
+		**out = **in
+	}
+	if in.Retention != nil {
+		in, out := &in.Retention, &out.Retention
+		*out = new(int)
+		**out = **in
+	}
+	in.Storage.DeepCopyInto(&out.Storage)
+	if in.ClientConfiguration != nil {
+		in, out := &in.ClientConfiguration, &out.ClientConfiguration
+		*out = new(ClientConfiguration)
+		**out = **in
+	}
+	if in.ClientConfigurationSecretRef != nil {
+		in, out := &in.ClientConfigurationSecretRef, &out.ClientConfigurationSecretRef
+		*out = new(v1.SecretReference)
+		**out = **in
+	}
+	if in.SecretsRef != nil {
+		in, out := &in.SecretsRef, &out.SecretsRef
+		*out = new(v1.Reference)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.SecretsSelector != nil {
+		in, out := &in.SecretsSelector, &out.SecretsSelector
+		*out = new(v1.Selector)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EtcdSnapshotParameters.
+func (in *EtcdSnapshotParameters) DeepCopy() *EtcdSnapshotParameters {
+	if in == nil {
+		return nil
+	}
+	out := new(EtcdSnapshotParameters)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *EtcdSnapshotRecord) DeepCopyInto(out *EtcdSnapshotRecord) {
+	*out = *in
+	in.Time.DeepCopyInto(&out.Time)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EtcdSnapshotRecord.
+func (in *EtcdSnapshotRecord) DeepCopy() *EtcdSnapshotRecord {
+	if in == nil {
+		return nil
+	}
+	out := new(EtcdSnapshotRecord)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *EtcdSnapshotS3Storage) DeepCopyInto(out *EtcdSnapshotS3Storage) {
+	*out = *in
+	if in.Prefix != nil {
+		in, out := &in.Prefix, &out.Prefix
+		*out = new(string)
+		**out = **in
+	}
+	if in.Region != nil {
+		in, out := &in.Region, &out.Region
+		*out = new(string)
+		**out = **in
+	}
+	out.CredentialsSecretRef = in.CredentialsSecretRef
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EtcdSnapshotS3Storage.
+func (in *EtcdSnapshotS3Storage) DeepCopy() *EtcdSnapshotS3Storage {
+	if in == nil {
+		return nil
+	}
+	out := new(EtcdSnapshotS3Storage)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *EtcdSnapshotSecretStorage) DeepCopyInto(out *EtcdSnapshotSecretStorage) {
+	*out = *in
+	if in.Key != nil {
+		in, out := &in.Key, &out.Key
+		*out = new(string)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EtcdSnapshotSecretStorage.
+func (in *EtcdSnapshotSecretStorage) DeepCopy() *EtcdSnapshotSecretStorage {
+	if in == nil {
+		return nil
+	}
+	out := new(EtcdSnapshotSecretStorage)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *EtcdSnapshotSpec) DeepCopyInto(out *EtcdSnapshotSpec) {
+	*out = *in
+	in.ResourceSpec.DeepCopyInto(&out.ResourceSpec)
+	in.ForProvider.DeepCopyInto(&out.ForProvider)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EtcdSnapshotSpec.
+func (in *EtcdSnapshotSpec) DeepCopy() *EtcdSnapshotSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(EtcdSnapshotSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *EtcdSnapshotStatus) DeepCopyInto(out *EtcdSnapshotStatus) {
+	*out = *in
+	in.ResourceStatus.DeepCopyInto(&out.ResourceStatus)
+	in.AtProvider.DeepCopyInto(&out.AtProvider)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EtcdSnapshotStatus.
+func (in *EtcdSnapshotStatus) DeepCopy() *EtcdSnapshotStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(EtcdSnapshotStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *EtcdSnapshotStorage) DeepCopyInto(out *EtcdSnapshotStorage) {
+	*out = *in
+	if in.Secret != nil {
+		in, out := &in.Secret, &out.Secret
+		*out = new(EtcdSnapshotSecretStorage)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Volume != nil {
+		in, out := &in.Volume, &out.Volume
+		*out = new(EtcdSnapshotVolumeStorage)
+		**out = **in
+	}
+	if in.S3 != nil {
+		in, out := &in.S3, &out.S3
+		*out = new(EtcdSnapshotS3Storage)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EtcdSnapshotStorage.
+func (in *EtcdSnapshotStorage) DeepCopy() *EtcdSnapshotStorage {
+	if in == nil {
+		return nil
+	}
+	out := new(EtcdSnapshotStorage)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *EtcdSnapshotVolumeStorage) DeepCopyInto(out *EtcdSnapshotVolumeStorage) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EtcdSnapshotVolumeStorage.
+func (in *EtcdSnapshotVolumeStorage) DeepCopy() *EtcdSnapshotVolumeStorage {
+	if in == nil {
+		return nil
+	}
+	out := new(EtcdSnapshotVolumeStorage)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ExtraHostEntrySpec) DeepCopyInto(out *ExtraHostEntrySpec) {
+	*out = *in
+	if in.Aliases != nil {
+		in, out := &in.Aliases, &out.Aliases
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ExtraHostEntrySpec.
+func (in *ExtraHostEntrySpec) DeepCopy() *ExtraHostEntrySpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ExtraHostEntrySpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FeaturesSpec) DeepCopyInto(out *FeaturesSpec) {
+	*out = *in
+	if in.RBAC != nil {
+		in, out := &in.RBAC, &out.RBAC
+		*out = new(bool)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FeaturesSpec.
+func (in *FeaturesSpec) DeepCopy() *FeaturesSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(FeaturesSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *InstallDiskSelector) DeepCopyInto(out *InstallDiskSelector) {
+	*out = *in
+	if in.Size != nil {
+		in, out := &in.Size, &out.Size
+		*out = new(string)
+		**out = **in
+	}
+	if in.Name != nil {
+		in, out := &in.Name, &out.Name
+		*out = new(string)
+		**out = **in
+	}
+	if in.Model != nil {
+		in, out := &in.Model, &out.Model
+		*out = new(string)
+		**out = **in
+	}
+	if in.Serial != nil {
+		in, out := &in.Serial, &out.Serial
+		*out = new(string)
+		**out = **in
+	}
+	if in.Modalias != nil {
+		in, out := &in.Modalias, &out.Modalias
+		*out = new(string)
+		**out = **in
+	}
+	if in.UUID != nil {
+		in, out := &in.UUID, &out.UUID
+		*out = new(string)
+		**out = **in
+	}
+	if in.WWID != nil {
+		in, out := &in.WWID, &out.WWID
+		*out = new(string)
+		**out = **in
+	}
+	if in.Type != nil {
+		in, out := &in.Type, &out.Type
+		*out = new(string)
+		**out = **in
+	}
+	if in.BusPath != nil {
+		in, out := &in.BusPath, &out.BusPath
+		*out = new(string)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InstallDiskSelector.
+func (in *InstallDiskSelector) DeepCopy() *InstallDiskSelector {
+	if in == nil {
+		return nil
+	}
+	out := new(InstallDiskSelector)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *InstallSpec) DeepCopyInto(out *InstallSpec) {
+	*out = *in
+	if in.DiskSelector != nil {
+		in, out := &in.DiskSelector, &out.DiskSelector
+		*out = new(InstallDiskSelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Wipe != nil {
+		in, out := &in.Wipe, &out.Wipe
+		*out = new(bool)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InstallSpec.
+func (in *InstallSpec) DeepCopy() *InstallSpec {
 	if in == nil {
 		return nil
 	}
@@ -593,38 +1543,282 @@ func (in *InstallSpec) DeepCopy() *InstallSpec {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *KubeletSpec) DeepCopyInto(out *KubeletSpec) {
+func (in *KubeletSpec) DeepCopyInto(out *KubeletSpec) {
+	*out = *in
+	if in.Image != nil {
+		in, out := &in.Image, &out.Image
+		*out = new(string)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KubeletSpec.
+func (in *KubeletSpec) DeepCopy() *KubeletSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(KubeletSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KubernetesImages) DeepCopyInto(out *KubernetesImages) {
+	*out = *in
+	if in.APIServer != nil {
+		in, out := &in.APIServer, &out.APIServer
+		*out = new(string)
+		**out = **in
+	}
+	if in.ControllerManager != nil {
+		in, out := &in.ControllerManager, &out.ControllerManager
+		*out = new(string)
+		**out = **in
+	}
+	if in.Scheduler != nil {
+		in, out := &in.Scheduler, &out.Scheduler
+		*out = new(string)
+		**out = **in
+	}
+	if in.Proxy != nil {
+		in, out := &in.Proxy, &out.Proxy
+		*out = new(string)
+		**out = **in
+	}
+	if in.Kubelet != nil {
+		in, out := &in.Kubelet, &out.Kubelet
+		*out = new(string)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KubernetesImages.
+func (in *KubernetesImages) DeepCopy() *KubernetesImages {
+	if in == nil {
+		return nil
+	}
+	out := new(KubernetesImages)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MachineConfigurationSpec) DeepCopyInto(out *MachineConfigurationSpec) {
+	*out = *in
+	in.Machine.DeepCopyInto(&out.Machine)
+	in.Cluster.DeepCopyInto(&out.Cluster)
+	if in.ValidationMode != nil {
+		in, out := &in.ValidationMode, &out.ValidationMode
+		*out = new(string)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MachineConfigurationSpec.
+func (in *MachineConfigurationSpec) DeepCopy() *MachineConfigurationSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(MachineConfigurationSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MachineDiscovery) DeepCopyInto(out *MachineDiscovery) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MachineDiscovery.
+func (in *MachineDiscovery) DeepCopy() *MachineDiscovery {
+	if in == nil {
+		return nil
+	}
+	out := new(MachineDiscovery)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *MachineDiscovery) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MachineDiscoveryList) DeepCopyInto(out *MachineDiscoveryList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]MachineDiscovery, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MachineDiscoveryList.
+func (in *MachineDiscoveryList) DeepCopy() *MachineDiscoveryList {
+	if in == nil {
+		return nil
+	}
+	out := new(MachineDiscoveryList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *MachineDiscoveryList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MachineDiscoveryObservation) DeepCopyInto(out *MachineDiscoveryObservation) {
+	*out = *in
+	if in.Machines != nil {
+		in, out := &in.Machines, &out.Machines
+		*out = make([]DiscoveredMachine, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.LastScanTime != nil {
+		in, out := &in.LastScanTime, &out.LastScanTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MachineDiscoveryObservation.
+func (in *MachineDiscoveryObservation) DeepCopy() *MachineDiscoveryObservation {
+	if in == nil {
+		return nil
+	}
+	out := new(MachineDiscoveryObservation)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MachineDiscoveryParameters) DeepCopyInto(out *MachineDiscoveryParameters) {
+	*out = *in
+	if in.CIDRs != nil {
+		in, out := &in.CIDRs, &out.CIDRs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Addresses != nil {
+		in, out := &in.Addresses, &out.Addresses
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Port != nil {
+		in, out := &in.Port, &out.Port
+		*out = new(int)
+		**out = **in
+	}
+	if in.Concurrency != nil {
+		in, out := &in.Concurrency, &out.Concurrency
+		*out = new(int)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MachineDiscoveryParameters.
+func (in *MachineDiscoveryParameters) DeepCopy() *MachineDiscoveryParameters {
+	if in == nil {
+		return nil
+	}
+	out := new(MachineDiscoveryParameters)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MachineDiscoverySpec) DeepCopyInto(out *MachineDiscoverySpec) {
+	*out = *in
+	in.ResourceSpec.DeepCopyInto(&out.ResourceSpec)
+	in.ForProvider.DeepCopyInto(&out.ForProvider)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MachineDiscoverySpec.
+func (in *MachineDiscoverySpec) DeepCopy() *MachineDiscoverySpec {
+	if in == nil {
+		return nil
+	}
+	out := new(MachineDiscoverySpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MachineDiscoveryStatus) DeepCopyInto(out *MachineDiscoveryStatus) {
 	*out = *in
-	if in.Image != nil {
-		in, out := &in.Image, &out.Image
-		*out = new(string)
-		**out = **in
+	in.ResourceStatus.DeepCopyInto(&out.ResourceStatus)
+	in.AtProvider.DeepCopyInto(&out.AtProvider)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MachineDiscoveryStatus.
+func (in *MachineDiscoveryStatus) DeepCopy() *MachineDiscoveryStatus {
+	if in == nil {
+		return nil
 	}
+	out := new(MachineDiscoveryStatus)
+	in.DeepCopyInto(out)
+	return out
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KubeletSpec.
-func (in *KubeletSpec) DeepCopy() *KubeletSpec {
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MachineDisk) DeepCopyInto(out *MachineDisk) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MachineDisk.
+func (in *MachineDisk) DeepCopy() *MachineDisk {
 	if in == nil {
 		return nil
 	}
-	out := new(KubeletSpec)
+	out := new(MachineDisk)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *MachineConfigurationSpec) DeepCopyInto(out *MachineConfigurationSpec) {
+func (in *MachineHardware) DeepCopyInto(out *MachineHardware) {
 	*out = *in
-	in.Machine.DeepCopyInto(&out.Machine)
-	in.Cluster.DeepCopyInto(&out.Cluster)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MachineConfigurationSpec.
-func (in *MachineConfigurationSpec) DeepCopy() *MachineConfigurationSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MachineHardware.
+func (in *MachineHardware) DeepCopy() *MachineHardware {
 	if in == nil {
 		return nil
 	}
-	out := new(MachineConfigurationSpec)
+	out := new(MachineHardware)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MachineLink) DeepCopyInto(out *MachineLink) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MachineLink.
+func (in *MachineLink) DeepCopy() *MachineLink {
+	if in == nil {
+		return nil
+	}
+	out := new(MachineLink)
 	in.DeepCopyInto(out)
 	return out
 }
@@ -658,159 +1852,510 @@ func (in *MachineSecretsCertificateAndKey) DeepCopy() *MachineSecretsCertificate
 	if in == nil {
 		return nil
 	}
-	out := new(MachineSecretsCertificateAndKey)
+	out := new(MachineSecretsCertificateAndKey)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MachineSecretsCerts) DeepCopyInto(out *MachineSecretsCerts) {
+	*out = *in
+	out.Etcd = in.Etcd
+	out.K8s = in.K8s
+	out.K8sAggregator = in.K8sAggregator
+	out.K8sServiceAccount = in.K8sServiceAccount
+	out.OS = in.OS
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MachineSecretsCerts.
+func (in *MachineSecretsCerts) DeepCopy() *MachineSecretsCerts {
+	if in == nil {
+		return nil
+	}
+	out := new(MachineSecretsCerts)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MachineSecretsCluster) DeepCopyInto(out *MachineSecretsCluster) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MachineSecretsCluster.
+func (in *MachineSecretsCluster) DeepCopy() *MachineSecretsCluster {
+	if in == nil {
+		return nil
+	}
+	out := new(MachineSecretsCluster)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MachineSecretsKey) DeepCopyInto(out *MachineSecretsKey) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MachineSecretsKey.
+func (in *MachineSecretsKey) DeepCopy() *MachineSecretsKey {
+	if in == nil {
+		return nil
+	}
+	out := new(MachineSecretsKey)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MachineSecretsSecrets) DeepCopyInto(out *MachineSecretsSecrets) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MachineSecretsSecrets.
+func (in *MachineSecretsSecrets) DeepCopy() *MachineSecretsSecrets {
+	if in == nil {
+		return nil
+	}
+	out := new(MachineSecretsSecrets)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MachineSecretsTrustdInfo) DeepCopyInto(out *MachineSecretsTrustdInfo) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MachineSecretsTrustdInfo.
+func (in *MachineSecretsTrustdInfo) DeepCopy() *MachineSecretsTrustdInfo {
+	if in == nil {
+		return nil
+	}
+	out := new(MachineSecretsTrustdInfo)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MachineSpec) DeepCopyInto(out *MachineSpec) {
+	*out = *in
+	in.Install.DeepCopyInto(&out.Install)
+	if in.Network != nil {
+		in, out := &in.Network, &out.Network
+		*out = new(NetworkSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Kubelet != nil {
+		in, out := &in.Kubelet, &out.Kubelet
+		*out = new(KubeletSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Features != nil {
+		in, out := &in.Features, &out.Features
+		*out = new(FeaturesSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.CA != nil {
+		in, out := &in.CA, &out.CA
+		*out = new(CASpec)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MachineSpec.
+func (in *MachineSpec) DeepCopy() *MachineSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(MachineSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NetworkInterfaceSpec) DeepCopyInto(out *NetworkInterfaceSpec) {
+	*out = *in
+	if in.Addresses != nil {
+		in, out := &in.Addresses, &out.Addresses
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.DHCP != nil {
+		in, out := &in.DHCP, &out.DHCP
+		*out = new(bool)
+		**out = **in
+	}
+	if in.Routes != nil {
+		in, out := &in.Routes, &out.Routes
+		*out = make([]RouteSpec, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.MTU != nil {
+		in, out := &in.MTU, &out.MTU
+		*out = new(int32)
+		**out = **in
+	}
+	if in.VLANs != nil {
+		in, out := &in.VLANs, &out.VLANs
+		*out = make([]VLANSpec, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Bond != nil {
+		in, out := &in.Bond, &out.Bond
+		*out = new(BondSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Bridge != nil {
+		in, out := &in.Bridge, &out.Bridge
+		*out = new(BridgeSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.VIP != nil {
+		in, out := &in.VIP, &out.VIP
+		*out = new(VIPSpec)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NetworkInterfaceSpec.
+func (in *NetworkInterfaceSpec) DeepCopy() *NetworkInterfaceSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(NetworkInterfaceSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NetworkSpec) DeepCopyInto(out *NetworkSpec) {
+	*out = *in
+	if in.Hostname != nil {
+		in, out := &in.Hostname, &out.Hostname
+		*out = new(string)
+		**out = **in
+	}
+	if in.Nameservers != nil {
+		in, out := &in.Nameservers, &out.Nameservers
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Interfaces != nil {
+		in, out := &in.Interfaces, &out.Interfaces
+		*out = make([]NetworkInterfaceSpec, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.ExtraHostEntries != nil {
+		in, out := &in.ExtraHostEntries, &out.ExtraHostEntries
+		*out = make([]ExtraHostEntrySpec, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NetworkSpec.
+func (in *NetworkSpec) DeepCopy() *NetworkSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(NetworkSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RouteSpec) DeepCopyInto(out *RouteSpec) {
+	*out = *in
+	if in.Source != nil {
+		in, out := &in.Source, &out.Source
+		*out = new(string)
+		**out = **in
+	}
+	if in.Metric != nil {
+		in, out := &in.Metric, &out.Metric
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RouteSpec.
+func (in *RouteSpec) DeepCopy() *RouteSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(RouteSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SecretKeyReference) DeepCopyInto(out *SecretKeyReference) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SecretKeyReference.
+func (in *SecretKeyReference) DeepCopy() *SecretKeyReference {
+	if in == nil {
+		return nil
+	}
+	out := new(SecretKeyReference)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *MachineSecretsCerts) DeepCopyInto(out *MachineSecretsCerts) {
+func (in *Secrets) DeepCopyInto(out *Secrets) {
 	*out = *in
-	out.Etcd = in.Etcd
-	out.K8s = in.K8s
-	out.K8sAggregator = in.K8sAggregator
-	out.K8sServiceAccount = in.K8sServiceAccount
-	out.OS = in.OS
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MachineSecretsCerts.
-func (in *MachineSecretsCerts) DeepCopy() *MachineSecretsCerts {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Secrets.
+func (in *Secrets) DeepCopy() *Secrets {
 	if in == nil {
 		return nil
 	}
-	out := new(MachineSecretsCerts)
+	out := new(Secrets)
 	in.DeepCopyInto(out)
 	return out
 }
 
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *Secrets) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *MachineSecretsCluster) DeepCopyInto(out *MachineSecretsCluster) {
+func (in *SecretsCARotation) DeepCopyInto(out *SecretsCARotation) {
 	*out = *in
+	if in.OS != nil {
+		in, out := &in.OS, &out.OS
+		*out = new(bool)
+		**out = **in
+	}
+	if in.Kubernetes != nil {
+		in, out := &in.Kubernetes, &out.Kubernetes
+		*out = new(bool)
+		**out = **in
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MachineSecretsCluster.
-func (in *MachineSecretsCluster) DeepCopy() *MachineSecretsCluster {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SecretsCARotation.
+func (in *SecretsCARotation) DeepCopy() *SecretsCARotation {
 	if in == nil {
 		return nil
 	}
-	out := new(MachineSecretsCluster)
+	out := new(SecretsCARotation)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *MachineSecretsKey) DeepCopyInto(out *MachineSecretsKey) {
+func (in *SecretsCARotationStatus) DeepCopyInto(out *SecretsCARotationStatus) {
 	*out = *in
+	if in.PhaseTime != nil {
+		in, out := &in.PhaseTime, &out.PhaseTime
+		*out = (*in).DeepCopy()
+	}
+	if in.Pending != nil {
+		in, out := &in.Pending, &out.Pending
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MachineSecretsKey.
-func (in *MachineSecretsKey) DeepCopy() *MachineSecretsKey {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SecretsCARotationStatus.
+func (in *SecretsCARotationStatus) DeepCopy() *SecretsCARotationStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(MachineSecretsKey)
+	out := new(SecretsCARotationStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *MachineSecretsSecrets) DeepCopyInto(out *MachineSecretsSecrets) {
+func (in *SecretsList) DeepCopyInto(out *SecretsList) {
 	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]Secrets, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MachineSecretsSecrets.
-func (in *MachineSecretsSecrets) DeepCopy() *MachineSecretsSecrets {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SecretsList.
+func (in *SecretsList) DeepCopy() *SecretsList {
 	if in == nil {
 		return nil
 	}
-	out := new(MachineSecretsSecrets)
+	out := new(SecretsList)
 	in.DeepCopyInto(out)
 	return out
 }
 
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *SecretsList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *MachineSecretsTrustdInfo) DeepCopyInto(out *MachineSecretsTrustdInfo) {
+func (in *SecretsObservation) DeepCopyInto(out *SecretsObservation) {
 	*out = *in
+	if in.GeneratedTime != nil {
+		in, out := &in.GeneratedTime, &out.GeneratedTime
+		*out = (*in).DeepCopy()
+	}
+	if in.ClientCertificateNotAfter != nil {
+		in, out := &in.ClientCertificateNotAfter, &out.ClientCertificateNotAfter
+		*out = (*in).DeepCopy()
+	}
+	if in.ClientCertificateRenewalTime != nil {
+		in, out := &in.ClientCertificateRenewalTime, &out.ClientCertificateRenewalTime
+		*out = (*in).DeepCopy()
+	}
+	if in.ClientCertificateRenewedTime != nil {
+		in, out := &in.ClientCertificateRenewedTime, &out.ClientCertificateRenewedTime
+		*out = (*in).DeepCopy()
+	}
+	if in.CARotation != nil {
+		in, out := &in.CARotation, &out.CARotation
+		*out = new(SecretsCARotationStatus)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MachineSecretsTrustdInfo.
-func (in *MachineSecretsTrustdInfo) DeepCopy() *MachineSecretsTrustdInfo {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SecretsObservation.
+func (in *SecretsObservation) DeepCopy() *SecretsObservation {
 	if in == nil {
 		return nil
 	}
-	out := new(MachineSecretsTrustdInfo)
+	out := new(SecretsObservation)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *MachineSpec) DeepCopyInto(out *MachineSpec) {
+func (in *SecretsParameters) DeepCopyInto(out *SecretsParameters) {
 	*out = *in
-	in.Install.DeepCopyInto(&out.Install)
-	if in.Network != nil {
-		in, out := &in.Network, &out.Network
-		*out = new(NetworkSpec)
+	if in.Node != nil {
+		in, out := &in.Node, &out.Node
+		*out = new(string)
 		**out = **in
 	}
-	if in.Kubelet != nil {
-		in, out := &in.Kubelet, &out.Kubelet
-		*out = new(KubeletSpec)
-		(*in).DeepCopyInto(*out)
+	if in.TalosVersion != nil {
+		in, out := &in.TalosVersion, &out.TalosVersion
+		*out = new(string)
+		**out = **in
 	}
-	if in.Features != nil {
-		in, out := &in.Features, &out.Features
-		*out = new(FeaturesSpec)
+	if in.ClientCertificateTTL != nil {
+		in, out := &in.ClientCertificateTTL, &out.ClientCertificateTTL
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.ClientCertificateRenewBefore != nil {
+		in, out := &in.ClientCertificateRenewBefore, &out.ClientCertificateRenewBefore
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.SecretsBundleRef != nil {
+		in, out := &in.SecretsBundleRef, &out.SecretsBundleRef
+		*out = new(v1.SecretKeySelector)
+		**out = **in
+	}
+	if in.TalosconfigRef != nil {
+		in, out := &in.TalosconfigRef, &out.TalosconfigRef
+		*out = new(v1.SecretKeySelector)
+		**out = **in
+	}
+	if in.CARotation != nil {
+		in, out := &in.CARotation, &out.CARotation
+		*out = new(SecretsCARotation)
 		(*in).DeepCopyInto(*out)
 	}
-	if in.CA != nil {
-		in, out := &in.CA, &out.CA
-		*out = new(CASpec)
+	if in.TalosconfigContext != nil {
+		in, out := &in.TalosconfigContext, &out.TalosconfigContext
+		*out = new(string)
 		**out = **in
 	}
+	if in.Endpoints != nil {
+		in, out := &in.Endpoints, &out.Endpoints
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Nodes != nil {
+		in, out := &in.Nodes, &out.Nodes
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MachineSpec.
-func (in *MachineSpec) DeepCopy() *MachineSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SecretsParameters.
+func (in *SecretsParameters) DeepCopy() *SecretsParameters {
 	if in == nil {
 		return nil
 	}
-	out := new(MachineSpec)
+	out := new(SecretsParameters)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *NetworkSpec) DeepCopyInto(out *NetworkSpec) {
+func (in *SecretsSpec) DeepCopyInto(out *SecretsSpec) {
 	*out = *in
+	in.ResourceSpec.DeepCopyInto(&out.ResourceSpec)
+	in.ForProvider.DeepCopyInto(&out.ForProvider)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NetworkSpec.
-func (in *NetworkSpec) DeepCopy() *NetworkSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SecretsSpec.
+func (in *SecretsSpec) DeepCopy() *SecretsSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(NetworkSpec)
+	out := new(SecretsSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *SecretKeyReference) DeepCopyInto(out *SecretKeyReference) {
+func (in *SecretsStatus) DeepCopyInto(out *SecretsStatus) {
 	*out = *in
+	in.ResourceStatus.DeepCopyInto(&out.ResourceStatus)
+	in.AtProvider.DeepCopyInto(&out.AtProvider)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SecretKeyReference.
-func (in *SecretKeyReference) DeepCopy() *SecretKeyReference {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SecretsStatus.
+func (in *SecretsStatus) DeepCopy() *SecretsStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(SecretKeyReference)
+	out := new(SecretsStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *Secrets) DeepCopyInto(out *Secrets) {
+func (in *Upgrade) DeepCopyInto(out *Upgrade) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
@@ -818,18 +2363,18 @@ func (in *Secrets) DeepCopyInto(out *Secrets) {
 	in.Status.DeepCopyInto(&out.Status)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Secrets.
-func (in *Secrets) DeepCopy() *Secrets {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Upgrade.
+func (in *Upgrade) DeepCopy() *Upgrade {
 	if in == nil {
 		return nil
 	}
-	out := new(Secrets)
+	out := new(Upgrade)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *Secrets) DeepCopyObject() runtime.Object {
+func (in *Upgrade) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -837,31 +2382,31 @@ func (in *Secrets) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *SecretsList) DeepCopyInto(out *SecretsList) {
+func (in *UpgradeList) DeepCopyInto(out *UpgradeList) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ListMeta.DeepCopyInto(&out.ListMeta)
 	if in.Items != nil {
 		in, out := &in.Items, &out.Items
-		*out = make([]Secrets, len(*in))
+		*out = make([]Upgrade, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SecretsList.
-func (in *SecretsList) DeepCopy() *SecretsList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new UpgradeList.
+func (in *UpgradeList) DeepCopy() *UpgradeList {
 	if in == nil {
 		return nil
 	}
-	out := new(SecretsList)
+	out := new(UpgradeList)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *SecretsList) DeepCopyObject() runtime.Object {
+func (in *UpgradeList) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -869,79 +2414,190 @@ func (in *SecretsList) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *SecretsObservation) DeepCopyInto(out *SecretsObservation) {
+func (in *UpgradeObservation) DeepCopyInto(out *UpgradeObservation) {
 	*out = *in
-	if in.GeneratedTime != nil {
-		in, out := &in.GeneratedTime, &out.GeneratedTime
+	if in.UpgradeRequestedTime != nil {
+		in, out := &in.UpgradeRequestedTime, &out.UpgradeRequestedTime
+		*out = (*in).DeepCopy()
+	}
+	if in.UpgradeCompletedTime != nil {
+		in, out := &in.UpgradeCompletedTime, &out.UpgradeCompletedTime
 		*out = (*in).DeepCopy()
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SecretsObservation.
-func (in *SecretsObservation) DeepCopy() *SecretsObservation {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new UpgradeObservation.
+func (in *UpgradeObservation) DeepCopy() *UpgradeObservation {
 	if in == nil {
 		return nil
 	}
-	out := new(SecretsObservation)
+	out := new(UpgradeObservation)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *SecretsParameters) DeepCopyInto(out *SecretsParameters) {
+func (in *UpgradeParameters) DeepCopyInto(out *UpgradeParameters) {
 	*out = *in
-	if in.Node != nil {
-		in, out := &in.Node, &out.Node
+	if in.Endpoint != nil {
+		in, out := &in.Endpoint, &out.Endpoint
+		*out = new(string)
+		**out = **in
+	}
+	if in.Image != nil {
+		in, out := &in.Image, &out.Image
 		*out = new(string)
 		**out = **in
 	}
+	if in.FactorySchematicRef != nil {
+		in, out := &in.FactorySchematicRef, &out.FactorySchematicRef
+		*out = new(v1.Reference)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.SecureBoot != nil {
+		in, out := &in.SecureBoot, &out.SecureBoot
+		*out = new(bool)
+		**out = **in
+	}
 	if in.TalosVersion != nil {
 		in, out := &in.TalosVersion, &out.TalosVersion
 		*out = new(string)
 		**out = **in
 	}
+	if in.Stage != nil {
+		in, out := &in.Stage, &out.Stage
+		*out = new(bool)
+		**out = **in
+	}
+	if in.Preserve != nil {
+		in, out := &in.Preserve, &out.Preserve
+		*out = new(bool)
+		**out = **in
+	}
+	if in.Force != nil {
+		in, out := &in.Force, &out.Force
+		*out = new(bool)
+		**out = **in
+	}
+	if in.ClientConfiguration != nil {
+		in, out := &in.ClientConfiguration, &out.ClientConfiguration
+		*out = new(ClientConfiguration)
+		**out = **in
+	}
+	if in.ClientConfigurationSecretRef != nil {
+		in, out := &in.ClientConfigurationSecretRef, &out.ClientConfigurationSecretRef
+		*out = new(v1.SecretReference)
+		**out = **in
+	}
+	if in.SecretsRef != nil {
+		in, out := &in.SecretsRef, &out.SecretsRef
+		*out = new(v1.Reference)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.SecretsSelector != nil {
+		in, out := &in.SecretsSelector, &out.SecretsSelector
+		*out = new(v1.Selector)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SecretsParameters.
-func (in *SecretsParameters) DeepCopy() *SecretsParameters {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new UpgradeParameters.
+func (in *UpgradeParameters) DeepCopy() *UpgradeParameters {
 	if in == nil {
 		return nil
 	}
-	out := new(SecretsParameters)
+	out := new(UpgradeParameters)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *SecretsSpec) DeepCopyInto(out *SecretsSpec) {
+func (in *UpgradeSpec) DeepCopyInto(out *UpgradeSpec) {
 	*out = *in
 	in.ResourceSpec.DeepCopyInto(&out.ResourceSpec)
 	in.ForProvider.DeepCopyInto(&out.ForProvider)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SecretsSpec.
-func (in *SecretsSpec) DeepCopy() *SecretsSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new UpgradeSpec.
+func (in *UpgradeSpec) DeepCopy() *UpgradeSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(SecretsSpec)
+	out := new(UpgradeSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *SecretsStatus) DeepCopyInto(out *SecretsStatus) {
+func (in *UpgradeStatus) DeepCopyInto(out *UpgradeStatus) {
 	*out = *in
 	in.ResourceStatus.DeepCopyInto(&out.ResourceStatus)
 	in.AtProvider.DeepCopyInto(&out.AtProvider)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SecretsStatus.
-func (in *SecretsStatus) DeepCopy() *SecretsStatus {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new UpgradeStatus.
+func (in *UpgradeStatus) DeepCopy() *UpgradeStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(SecretsStatus)
+	out := new(UpgradeStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VIPSpec) DeepCopyInto(out *VIPSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VIPSpec.
+func (in *VIPSpec) DeepCopy() *VIPSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(VIPSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VLANSpec) DeepCopyInto(out *VLANSpec) {
+	*out = *in
+	if in.Addresses != nil {
+		in, out := &in.Addresses, &out.Addresses
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.DHCP != nil {
+		in, out := &in.DHCP, &out.DHCP
+		*out = new(bool)
+		**out = **in
+	}
+	if in.Routes != nil {
+		in, out := &in.Routes, &out.Routes
+		*out = make([]RouteSpec, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.MTU != nil {
+		in, out := &in.MTU, &out.MTU
+		*out = new(int32)
+		**out = **in
+	}
+	if in.VIP != nil {
+		in, out := &in.VIP, &out.VIP
+		*out = new(VIPSpec)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VLANSpec.
+func (in *VLANSpec) DeepCopy() *VLANSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(VLANSpec)
 	in.DeepCopyInto(out)
 	return out
 }
@@ -17,6 +17,15 @@ func (l *BootstrapList) GetItems() []resource.Managed {
 	return items
 }
 
+// GetItems of this ClientCertificateList.
+func (l *ClientCertificateList) GetItems() []resource.Managed {
+	items := make([]resource.Managed, len(l.Items))
+	for i := range l.Items {
+		items[i] = &l.Items[i]
+	}
+	return items
+}
+
 // GetItems of this ConfigurationApplyList.
 func (l *ConfigurationApplyList) GetItems() []resource.Managed {
 	items := make([]resource.Managed, len(l.Items))
@@ -35,6 +44,33 @@ func (l *ConfigurationList) GetItems() []resource.Managed {
 	return items
 }
 
+// GetItems of this EtcdMemberList.
+func (l *EtcdMemberList) GetItems() []resource.Managed {
+	items := make([]resource.Managed, len(l.Items))
+	for i := range l.Items {
+		items[i] = &l.Items[i]
+	}
+	return items
+}
+
+// GetItems of this EtcdSnapshotList.
+func (l *EtcdSnapshotList) GetItems() []resource.Managed {
+	items := make([]resource.Managed, len(l.Items))
+	for i := range l.Items {
+		items[i] = &l.Items[i]
+	}
+	return items
+}
+
+// GetItems of this MachineDiscoveryList.
+func (l *MachineDiscoveryList) GetItems() []resource.Managed {
+	items := make([]resource.Managed, len(l.Items))
+	for i := range l.Items {
+		items[i] = &l.Items[i]
+	}
+	return items
+}
+
 // GetItems of this SecretsList.
 func (l *SecretsList) GetItems() []resource.Managed {
 	items := make([]resource.Managed, len(l.Items))
@@ -43,3 +79,12 @@ func (l *SecretsList) GetItems() []resource.Managed {
 	}
 	return items
 }
+
+// GetItems of this UpgradeList.
+func (l *UpgradeList) GetItems() []resource.Managed {
+	items := make([]resource.Managed, len(l.Items))
+	for i := range l.Items {
+		items[i] = &l.Items[i]
+	}
+	return items
+}
@@ -29,6 +29,19 @@ import (
 type ProviderConfigSpec struct {
 	// Credentials required to authenticate to this provider.
 	Credentials ProviderCredentials `json:"credentials"`
+
+	// ImageFactory configures the Talos Image Factory used by
+	// FactorySchematics.
+	// +optional
+	ImageFactory *ImageFactory `json:"imageFactory,omitempty"`
+}
+
+// ImageFactory configures a Talos Image Factory.
+type ImageFactory struct {
+	// URL is the base URL of the Image Factory, e.g. a self-hosted factory.
+	// +kubebuilder:default="https://factory.talos.dev"
+	// +optional
+	URL string `json:"url,omitempty"`
 }
 
 // ProviderCredentials required to authenticate.
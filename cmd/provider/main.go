@@ -47,6 +47,7 @@ import (
 
 	"github.com/crossplane-contrib/provider-talos/apis"
 	"github.com/crossplane-contrib/provider-talos/apis/v1alpha1"
+	"github.com/crossplane-contrib/provider-talos/internal/clients"
 	talos "github.com/crossplane-contrib/provider-talos/internal/controller"
 	"github.com/crossplane-contrib/provider-talos/internal/features"
 	"github.com/crossplane-contrib/provider-talos/internal/version"
@@ -63,6 +64,7 @@ func main() {
 		pollStateMetricInterval = app.Flag("poll-state-metric", "State metric recording interval").Default("5s").Duration()
 
 		maxReconcileRate = app.Flag("max-reconcile-rate", "The global maximum rate per second at which resources may checked for drift from the desired state.").Default("10").Int()
+		talosClientTTL   = app.Flag("talos-client-ttl", "How long a Talos API client is reused across reconciles before it is reconnected.").Default(clients.DefaultClientTTL.String()).Duration()
 
 		namespace                  = app.Flag("namespace", "Namespace used to set as default scope in default secret store config.").Default("crossplane-system").Envar("POD_NAMESPACE").String()
 		enableExternalSecretStores = app.Flag("enable-external-secret-stores", "Enable support for ExternalSecretStores.").Default("false").Envar("ENABLE_EXTERNAL_SECRET_STORES").Bool()
@@ -169,6 +171,6 @@ func main() {
 		o.ChangeLogOptions = &clo
 	}
 
-	kingpin.FatalIfError(talos.Setup(mgr, o), "Cannot setup Talos controllers")
+	kingpin.FatalIfError(talos.Setup(mgr, o, clients.NewCache(*talosClientTTL)), "Cannot setup Talos controllers")
 	kingpin.FatalIfError(mgr.Start(ctrl.SetupSignalHandler()), "Cannot start controller manager")
 }
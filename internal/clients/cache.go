@@ -0,0 +1,240 @@
+/*
+Copyright 2025 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package clients
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	talosclient "github.com/siderolabs/talos/pkg/machinery/client"
+
+	machinev1alpha1 "github.com/crossplane-contrib/provider-talos/apis/machine/v1alpha1"
+)
+
+// DefaultClientTTL is how long a cached Talos API client is reused before it
+// is reconnected.
+const DefaultClientTTL = 10 * time.Minute
+
+// A Client is a Talos API client borrowed from a Cache. Closing it returns it
+// to the Cache rather than closing the underlying connection.
+type Client struct {
+	*talosclient.Client
+
+	once    sync.Once
+	release func() error
+}
+
+// Close returns the client to the Cache it was borrowed from. Clients that
+// were not cached are closed.
+func (c *Client) Close() error {
+	var err error
+	c.once.Do(func() { err = c.release() })
+
+	return err
+}
+
+// A Cache shares Talos API clients between reconciles and controllers, so
+// that polling a machine does not open a new gRPC connection every time.
+//
+// Clients are keyed by their endpoints and a hash of the credentials they
+// authenticate with, so resources that use different credentials for the
+// same endpoints each keep their own client. A client is reconnected once its
+// TTL has passed, which also closes clients of credentials that are no longer
+// used, e.g. after the client certificate of a Secrets was renewed. Clients
+// that are still borrowed are closed once they are returned.
+//
+// A nil Cache creates a new client for every call.
+type Cache struct {
+	ttl       time.Duration
+	now       func() time.Time
+	newClient func(context.Context, ...talosclient.OptionFunc) (*talosclient.Client, error)
+
+	mu      sync.Mutex
+	entries map[string]*cacheEntry
+}
+
+type cacheEntry struct {
+	client  *talosclient.Client
+	expires time.Time
+	refs    int
+	evicted bool
+}
+
+// NewCache returns a Cache whose clients are reconnected ttl after they were
+// created.
+func NewCache(ttl time.Duration) *Cache {
+	return &Cache{
+		ttl:       ttl,
+		now:       time.Now,
+		newClient: talosclient.New,
+		entries:   map[string]*cacheEntry{},
+	}
+}
+
+// Client returns a Talos API client for the endpoints that authenticates with
+// the client credentials, or connects insecurely if they select maintenance
+// mode. Callers must Close the client once they are done with it.
+func (c *Cache) Client(ctx context.Context, endpoints []string, clientConfiguration *machinev1alpha1.ClientConfiguration) (*Client, error) {
+	opts, err := ClientOptions(clientConfiguration)
+	if err != nil {
+		return nil, err
+	}
+	opts = append(opts, talosclient.WithEndpoints(endpoints...))
+
+	if c == nil {
+		tc, err := talosclient.New(ctx, opts...)
+		if err != nil {
+			return nil, err
+		}
+		return &Client{Client: tc, release: tc.Close}, nil
+	}
+
+	key := endpointsKey(endpoints) + "/" + credentialsHash(clientConfiguration)
+	if client, ok := c.borrow(key); ok {
+		return client, nil
+	}
+
+	// Connecting may be slow, so it must not block other reconciles.
+	tc, err := c.newClient(ctx, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	e, ok := c.entries[key]
+	if ok {
+		// Another reconcile connected to the same endpoints in the meantime.
+		tc.Close() //nolint:errcheck
+	} else {
+		e = &cacheEntry{client: tc, expires: c.now().Add(c.ttl)}
+		c.entries[key] = e
+	}
+
+	return c.lend(e), nil
+}
+
+// borrow returns the cached client for the key, after closing expired
+// clients.
+func (c *Cache) borrow(key string) (*Client, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := c.now()
+	for k, e := range c.entries {
+		if !now.Before(e.expires) {
+			c.evict(k, e)
+		}
+	}
+
+	e, ok := c.entries[key]
+	if !ok {
+		return nil, false
+	}
+
+	return c.lend(e), true
+}
+
+// lend hands out the client of an entry. The caller must hold c.mu.
+func (c *Cache) lend(e *cacheEntry) *Client {
+	e.refs++
+
+	return &Client{Client: e.client, release: func() error { return c.release(e) }}
+}
+
+// Start closes expired clients until the context is done, then closes all
+// clients. It lets a controller manager run the Cache.
+func (c *Cache) Start(ctx context.Context) error {
+	interval := c.ttl
+	if interval <= 0 || interval > time.Minute {
+		interval = time.Minute
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			c.mu.Lock()
+			for k, e := range c.entries {
+				c.evict(k, e)
+			}
+			c.mu.Unlock()
+			return nil
+		case <-ticker.C:
+			c.mu.Lock()
+			now := c.now()
+			for k, e := range c.entries {
+				if !now.Before(e.expires) {
+					c.evict(k, e)
+				}
+			}
+			c.mu.Unlock()
+		}
+	}
+}
+
+// evict removes an entry from the cache and closes its client unless it is
+// still borrowed. The caller must hold c.mu.
+func (c *Cache) evict(key string, e *cacheEntry) {
+	delete(c.entries, key)
+	e.evicted = true
+	if e.refs == 0 {
+		e.client.Close() //nolint:errcheck
+	}
+}
+
+func (c *Cache) release(e *cacheEntry) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	e.refs--
+	if e.evicted && e.refs == 0 {
+		return e.client.Close()
+	}
+
+	return nil
+}
+
+func endpointsKey(endpoints []string) string {
+	sorted := append([]string{}, endpoints...)
+	sort.Strings(sorted)
+
+	return strings.Join(sorted, ",")
+}
+
+// credentialsHash identifies client credentials without keeping the private
+// key in the cache key.
+func credentialsHash(clientConfiguration *machinev1alpha1.ClientConfiguration) string {
+	if IsInsecure(clientConfiguration) {
+		return Insecure
+	}
+
+	hash := sha256.New()
+	for _, v := range []string{clientConfiguration.CACertificate, clientConfiguration.ClientCertificate, clientConfiguration.ClientKey} {
+		hash.Write([]byte(v))
+		hash.Write([]byte{0})
+	}
+
+	return hex.EncodeToString(hash.Sum(nil))
+}
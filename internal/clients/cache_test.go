@@ -0,0 +1,250 @@
+/*
+Copyright 2025 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package clients
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	talosclient "github.com/siderolabs/talos/pkg/machinery/client"
+
+	machinev1alpha1 "github.com/crossplane-contrib/provider-talos/apis/machine/v1alpha1"
+)
+
+// testCache returns a Cache with a controllable clock that counts the
+// clients it creates.
+func testCache(ttl time.Duration) (*Cache, *time.Time, *int) {
+	now := time.Unix(0, 0)
+	created := 0
+
+	c := NewCache(ttl)
+	c.now = func() time.Time { return now }
+	c.newClient = func(ctx context.Context, opts ...talosclient.OptionFunc) (*talosclient.Client, error) {
+		created++
+		return talosclient.New(ctx, opts...)
+	}
+
+	return c, &now, &created
+}
+
+func TestCacheClient(t *testing.T) {
+	caCert, clientCert, clientKey := generateTestCertificates(t)
+	credentials := &machinev1alpha1.ClientConfiguration{CACertificate: caCert, ClientCertificate: clientCert, ClientKey: clientKey}
+	_, renewedCert, renewedKey := generateTestCertificates(t)
+	renewed := &machinev1alpha1.ClientConfiguration{CACertificate: caCert, ClientCertificate: renewedCert, ClientKey: renewedKey}
+
+	type step struct {
+		endpoints    []string
+		credentials  *machinev1alpha1.ClientConfiguration
+		advance      time.Duration
+		keep         bool
+		wantSameAs   int
+		wantNew      bool
+		wantEntries  int
+		wantEvicted  []int
+		wantBorrowed bool
+	}
+
+	tests := map[string]struct {
+		steps []step
+	}{
+		"ReusesClientForSameEndpointsAndCredentials": {
+			steps: []step{
+				{endpoints: []string{"10.0.0.1:50000"}, credentials: credentials, wantNew: true, wantEntries: 1},
+				{endpoints: []string{"10.0.0.1:50000"}, credentials: credentials, wantSameAs: 0, wantEntries: 1},
+			},
+		},
+		"EndpointOrderDoesNotMatter": {
+			steps: []step{
+				{endpoints: []string{"10.0.0.1:50000", "10.0.0.2:50000"}, credentials: credentials, wantNew: true, wantEntries: 1},
+				{endpoints: []string{"10.0.0.2:50000", "10.0.0.1:50000"}, credentials: credentials, wantSameAs: 0, wantEntries: 1},
+			},
+		},
+		"SeparateClientsPerEndpoint": {
+			steps: []step{
+				{endpoints: []string{"10.0.0.1:50000"}, credentials: credentials, wantNew: true, wantEntries: 1},
+				{endpoints: []string{"10.0.0.2:50000"}, credentials: credentials, wantNew: true, wantEntries: 2},
+			},
+		},
+		"InsecureAndAuthenticatedClientsCoexist": {
+			steps: []step{
+				{endpoints: []string{"10.0.0.1:50000"}, credentials: InsecureClientConfiguration(), wantNew: true, wantEntries: 1},
+				{endpoints: []string{"10.0.0.1:50000"}, credentials: credentials, wantNew: true, wantEntries: 2},
+				{endpoints: []string{"10.0.0.1:50000"}, credentials: InsecureClientConfiguration(), wantSameAs: 0, wantEntries: 2},
+			},
+		},
+		"ClientsPerCredentialsCoexist": {
+			steps: []step{
+				{endpoints: []string{"10.0.0.1:50000"}, credentials: credentials, wantNew: true, wantEntries: 1},
+				{endpoints: []string{"10.0.0.1:50000"}, credentials: renewed, wantNew: true, wantEntries: 2},
+				{endpoints: []string{"10.0.0.1:50000"}, credentials: credentials, wantSameAs: 0, wantEntries: 2},
+				{endpoints: []string{"10.0.0.1:50000"}, credentials: renewed, wantSameAs: 1, wantEntries: 2},
+			},
+		},
+		"UnusedCredentialsExpire": {
+			steps: []step{
+				{endpoints: []string{"10.0.0.1:50000"}, credentials: credentials, wantNew: true, wantEntries: 1},
+				{endpoints: []string{"10.0.0.1:50000"}, credentials: renewed, advance: time.Minute, wantNew: true, wantEntries: 1, wantEvicted: []int{0}},
+			},
+		},
+		"ExpiredClientIsReconnected": {
+			steps: []step{
+				{endpoints: []string{"10.0.0.1:50000"}, credentials: credentials, wantNew: true, wantEntries: 1},
+				{endpoints: []string{"10.0.0.1:50000"}, credentials: credentials, advance: time.Minute, wantNew: true, wantEntries: 1, wantEvicted: []int{0}},
+			},
+		},
+		"BorrowedClientIsNotClosedOnEviction": {
+			steps: []step{
+				{endpoints: []string{"10.0.0.1:50000"}, credentials: credentials, keep: true, wantNew: true, wantEntries: 1},
+				{endpoints: []string{"10.0.0.1:50000"}, credentials: credentials, advance: time.Minute, wantNew: true, wantEntries: 1, wantEvicted: []int{0}, wantBorrowed: true},
+			},
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			c, now, created := testCache(time.Minute)
+
+			var got []*Client
+			var entries []*cacheEntry
+			for i, s := range tc.steps {
+				*now = now.Add(s.advance)
+				before := *created
+
+				client, err := c.Client(context.Background(), s.endpoints, s.credentials)
+				if err != nil {
+					t.Fatalf("step %d: c.Client(...): unexpected error: %v", i, err)
+				}
+				got = append(got, client)
+				entries = append(entries, c.entries[endpointsKey(s.endpoints)+"/"+credentialsHash(s.credentials)])
+
+				if s.wantNew && *created != before+1 {
+					t.Errorf("step %d: c.Client(...): created %d clients, want 1", i, *created-before)
+				}
+				if !s.wantNew && client.Client != got[s.wantSameAs].Client {
+					t.Errorf("step %d: c.Client(...): got a new client, want the client of step %d", i, s.wantSameAs)
+				}
+				if len(c.entries) != s.wantEntries {
+					t.Errorf("step %d: c.Client(...): got %d cached clients, want %d", i, len(c.entries), s.wantEntries)
+				}
+				for _, evicted := range s.wantEvicted {
+					if !entries[evicted].evicted {
+						t.Errorf("step %d: c.Client(...): client of step %d was not evicted", i, evicted)
+					}
+					if borrowed := entries[evicted].refs > 0; borrowed != s.wantBorrowed {
+						t.Errorf("step %d: c.Client(...): client of step %d borrowed = %v, want %v", i, evicted, borrowed, s.wantBorrowed)
+					}
+				}
+
+				if !s.keep {
+					if err := client.Close(); err != nil {
+						t.Fatalf("step %d: client.Close(): unexpected error: %v", i, err)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestCacheClientConnectsWithoutLock(t *testing.T) {
+	c, _, created := testCache(time.Minute)
+
+	// A reconcile that connects to the same endpoints while the first one is
+	// still connecting must neither block nor leave a second cached client.
+	var concurrent *Client
+	connecting := false
+	newClient := c.newClient
+	c.newClient = func(ctx context.Context, opts ...talosclient.OptionFunc) (*talosclient.Client, error) {
+		if !connecting {
+			connecting = true
+			var err error
+			if concurrent, err = c.Client(ctx, []string{"10.0.0.1:50000"}, InsecureClientConfiguration()); err != nil {
+				return nil, err
+			}
+		}
+		return newClient(ctx, opts...)
+	}
+
+	client, err := c.Client(context.Background(), []string{"10.0.0.1:50000"}, InsecureClientConfiguration())
+	if err != nil {
+		t.Fatalf("c.Client(...): unexpected error: %v", err)
+	}
+	if client.Client != concurrent.Client {
+		t.Error("c.Client(...): got a new client, want the client of the concurrent reconcile")
+	}
+	if *created != 2 {
+		t.Errorf("c.Client(...): created %d clients, want 2", *created)
+	}
+	if got := c.entries["10.0.0.1:50000/"+Insecure].refs; got != 2 {
+		t.Errorf("c.Client(...): got %d borrowers, want 2", got)
+	}
+}
+
+func TestCacheClientCloseIsIdempotent(t *testing.T) {
+	c, _, _ := testCache(time.Minute)
+
+	client, err := c.Client(context.Background(), []string{"10.0.0.1:50000"}, InsecureClientConfiguration())
+	if err != nil {
+		t.Fatalf("c.Client(...): unexpected error: %v", err)
+	}
+	if _, err := c.Client(context.Background(), []string{"10.0.0.1:50000"}, InsecureClientConfiguration()); err != nil {
+		t.Fatalf("c.Client(...): unexpected error: %v", err)
+	}
+
+	client.Close() //nolint:errcheck
+	client.Close() //nolint:errcheck
+
+	if got := c.entries["10.0.0.1:50000/"+Insecure].refs; got != 1 {
+		t.Errorf("client.Close(): got %d borrowers, want 1", got)
+	}
+}
+
+func TestCacheStart(t *testing.T) {
+	c, _, _ := testCache(time.Minute)
+
+	client, err := c.Client(context.Background(), []string{"10.0.0.1:50000"}, InsecureClientConfiguration())
+	if err != nil {
+		t.Fatalf("c.Client(...): unexpected error: %v", err)
+	}
+	client.Close() //nolint:errcheck
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := c.Start(ctx); err != nil {
+		t.Fatalf("c.Start(...): unexpected error: %v", err)
+	}
+	if len(c.entries) != 0 {
+		t.Errorf("c.Start(...): got %d cached clients after shutdown, want 0", len(c.entries))
+	}
+}
+
+func TestNilCacheClient(t *testing.T) {
+	var c *Cache
+
+	client, err := c.Client(context.Background(), []string{"10.0.0.1:50000"}, InsecureClientConfiguration())
+	if err != nil {
+		t.Fatalf("c.Client(...): unexpected error: %v", err)
+	}
+	if err := client.Close(); err != nil {
+		t.Fatalf("client.Close(): unexpected error: %v", err)
+	}
+
+	if _, err := c.Client(context.Background(), []string{"10.0.0.1:50000"}, nil); err == nil {
+		t.Fatal("c.Client(nil credentials): expected error")
+	}
+}
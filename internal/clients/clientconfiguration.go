@@ -0,0 +1,211 @@
+/*
+Copyright 2025 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package clients resolves Talos API client credentials and builds Talos API
+// clients for the provider's controllers.
+package clients
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+
+	"github.com/pkg/errors"
+	siderox509 "github.com/siderolabs/crypto/x509"
+	talosclient "github.com/siderolabs/talos/pkg/machinery/client"
+	clientconfig "github.com/siderolabs/talos/pkg/machinery/client/config"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrlclient "sigs.k8s.io/controller-runtime/pkg/client"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+
+	machinev1alpha1 "github.com/crossplane-contrib/provider-talos/apis/machine/v1alpha1"
+)
+
+// Insecure is the certificate value that selects an unauthenticated
+// connection to a machine in maintenance mode.
+const Insecure = "insecure"
+
+// Connection detail keys published by Secrets and ClientCertificates.
+const (
+	connectionKeyClientConfiguration = "client_configuration"
+	connectionKeyCACertificate       = "ca_certificate"
+	connectionKeyClientCertificate   = "client_certificate"
+	connectionKeyClientKey           = "client_key"
+)
+
+const errNoClientConfiguration = "one of clientConfiguration, clientConfigurationSecretRef or secretsRef is required"
+
+// A ClientConfigurationSource holds the ways a managed resource can supply
+// its Talos API client credentials. At most one of them is set.
+type ClientConfigurationSource struct {
+	// ClientConfiguration holds the credentials inline.
+	ClientConfiguration *machinev1alpha1.ClientConfiguration
+	// SecretRef references a Secret holding the credentials under the
+	// connection detail keys of a Secrets.
+	SecretRef *xpv1.SecretReference
+	// SecretsRef references a Secrets whose connection secret holds the
+	// credentials.
+	SecretsRef *xpv1.Reference
+}
+
+// ResolveClientConfiguration returns the client credentials of a source, or
+// nil if the source is empty.
+func ResolveClientConfiguration(ctx context.Context, kube ctrlclient.Reader, source ClientConfigurationSource) (*machinev1alpha1.ClientConfiguration, error) {
+	switch {
+	case source.ClientConfiguration != nil:
+		return source.ClientConfiguration, nil
+	case source.SecretRef != nil:
+		return clientConfigurationFromSecret(ctx, kube, source.SecretRef)
+	case source.SecretsRef != nil:
+		if kube == nil {
+			return nil, errors.New("cannot resolve secretsRef without Kubernetes client")
+		}
+		secrets := &machinev1alpha1.Secrets{}
+		if err := kube.Get(ctx, types.NamespacedName{Name: source.SecretsRef.Name}, secrets); err != nil {
+			return nil, errors.Wrapf(err, "cannot get referenced Secrets %s", source.SecretsRef.Name)
+		}
+		if secrets.Spec.WriteConnectionSecretToReference == nil {
+			return nil, errors.Errorf("referenced Secrets %s must define writeConnectionSecretToRef", source.SecretsRef.Name)
+		}
+		return clientConfigurationFromSecret(ctx, kube, secrets.Spec.WriteConnectionSecretToReference)
+	}
+
+	return nil, nil
+}
+
+// clientConfigurationFromSecret reads the client_configuration connection
+// detail, or the raw PEM ca_certificate, client_certificate and client_key
+// details, of a Secret.
+func clientConfigurationFromSecret(ctx context.Context, kube ctrlclient.Reader, ref *xpv1.SecretReference) (*machinev1alpha1.ClientConfiguration, error) {
+	if kube == nil {
+		return nil, errors.New("cannot resolve clientConfigurationSecretRef without Kubernetes client")
+	}
+
+	secret := &corev1.Secret{}
+	if err := kube.Get(ctx, types.NamespacedName{Namespace: ref.Namespace, Name: ref.Name}, secret); err != nil {
+		return nil, errors.Wrapf(err, "cannot get client configuration Secret %s/%s", ref.Namespace, ref.Name)
+	}
+
+	if data := secret.Data[connectionKeyClientConfiguration]; len(data) > 0 {
+		encoded := &machinev1alpha1.ClientConfiguration{}
+		if err := json.Unmarshal(data, encoded); err != nil {
+			return nil, errors.Wrapf(err, "cannot decode %s of Secret %s/%s", connectionKeyClientConfiguration, ref.Namespace, ref.Name)
+		}
+		clientConfiguration := &machinev1alpha1.ClientConfiguration{}
+		for _, field := range []struct {
+			encoded string
+			decoded *string
+		}{
+			{encoded.CACertificate, &clientConfiguration.CACertificate},
+			{encoded.ClientCertificate, &clientConfiguration.ClientCertificate},
+			{encoded.ClientKey, &clientConfiguration.ClientKey},
+		} {
+			decoded, err := base64.StdEncoding.DecodeString(field.encoded)
+			if err != nil {
+				return nil, errors.Wrapf(err, "cannot decode %s of Secret %s/%s", connectionKeyClientConfiguration, ref.Namespace, ref.Name)
+			}
+			*field.decoded = string(decoded)
+		}
+		return clientConfiguration, nil
+	}
+
+	for _, key := range []string{connectionKeyCACertificate, connectionKeyClientCertificate, connectionKeyClientKey} {
+		if len(secret.Data[key]) == 0 {
+			return nil, errors.Errorf("Secret %s/%s has no %s or %s key", ref.Namespace, ref.Name, connectionKeyClientConfiguration, key)
+		}
+	}
+
+	return &machinev1alpha1.ClientConfiguration{
+		CACertificate:     string(secret.Data[connectionKeyCACertificate]),
+		ClientCertificate: string(secret.Data[connectionKeyClientCertificate]),
+		ClientKey:         string(secret.Data[connectionKeyClientKey]),
+	}, nil
+}
+
+// IsInsecure reports whether client credentials select an unauthenticated
+// maintenance-mode connection.
+func IsInsecure(clientConfiguration *machinev1alpha1.ClientConfiguration) bool {
+	return clientConfiguration.ClientCertificate == Insecure || clientConfiguration.CACertificate == Insecure
+}
+
+// InsecureClientConfiguration returns client credentials that select an
+// unauthenticated maintenance-mode connection.
+func InsecureClientConfiguration() *machinev1alpha1.ClientConfiguration {
+	return &machinev1alpha1.ClientConfiguration{
+		CACertificate:     Insecure,
+		ClientCertificate: Insecure,
+		ClientKey:         Insecure,
+	}
+}
+
+// TalosConfig returns a Talos client config authenticating with the client
+// credentials.
+func TalosConfig(clientConfiguration *machinev1alpha1.ClientConfiguration) (*clientconfig.Config, error) {
+	if clientConfiguration == nil {
+		return nil, errors.New(errNoClientConfiguration)
+	}
+	if clientConfiguration.ClientCertificate == "" {
+		return nil, errors.New("clientConfiguration.clientCertificate is required")
+	}
+	if clientConfiguration.ClientKey == "" {
+		return nil, errors.New("clientConfiguration.clientKey is required")
+	}
+	if clientConfiguration.CACertificate == "" {
+		return nil, errors.New("clientConfiguration.caCertificate is required")
+	}
+
+	cert, err := tls.X509KeyPair([]byte(clientConfiguration.ClientCertificate), []byte(clientConfiguration.ClientKey))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create client certificate")
+	}
+	if len(cert.Certificate) == 0 {
+		return nil, errors.New("failed to create client certificate")
+	}
+
+	roots := x509.NewCertPool()
+	if ok := roots.AppendCertsFromPEM([]byte(clientConfiguration.CACertificate)); !ok {
+		return nil, errors.New("failed to parse CA certificate")
+	}
+
+	return clientconfig.NewConfig("dynamic", nil, []byte(clientConfiguration.CACertificate), &siderox509.PEMEncodedCertificateAndKey{
+		Crt: []byte(clientConfiguration.ClientCertificate),
+		Key: []byte(clientConfiguration.ClientKey),
+	}), nil
+}
+
+// ClientOptions returns the Talos client options connecting with the client
+// credentials, or insecurely if they select maintenance mode.
+func ClientOptions(clientConfiguration *machinev1alpha1.ClientConfiguration) ([]talosclient.OptionFunc, error) {
+	if clientConfiguration == nil {
+		return nil, errors.New(errNoClientConfiguration)
+	}
+	if IsInsecure(clientConfiguration) {
+		return []talosclient.OptionFunc{talosclient.WithTLSConfig(&tls.Config{
+			InsecureSkipVerify: true, //nolint:gosec // Insecure mode needed for maintenance-mode machines.
+		})}, nil
+	}
+
+	cfg, err := TalosConfig(clientConfiguration)
+	if err != nil {
+		return nil, err
+	}
+
+	return []talosclient.OptionFunc{talosclient.WithConfig(cfg)}, nil
+}
@@ -0,0 +1,313 @@
+/*
+Copyright 2025 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package clients
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"math/big"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrlclient "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+
+	machinev1alpha1 "github.com/crossplane-contrib/provider-talos/apis/machine/v1alpha1"
+)
+
+func TestResolveClientConfiguration(t *testing.T) {
+	caCert, clientCert, clientKey := generateTestCertificates(t)
+	want := &machinev1alpha1.ClientConfiguration{
+		CACertificate:     caCert,
+		ClientCertificate: clientCert,
+		ClientKey:         clientKey,
+	}
+
+	encoded, err := json.Marshal(machinev1alpha1.ClientConfiguration{
+		CACertificate:     base64.StdEncoding.EncodeToString([]byte(caCert)),
+		ClientCertificate: base64.StdEncoding.EncodeToString([]byte(clientCert)),
+		ClientKey:         base64.StdEncoding.EncodeToString([]byte(clientKey)),
+	})
+	if err != nil {
+		t.Fatalf("json.Marshal(...): %v", err)
+	}
+
+	objects := []ctrlclient.Object{
+		&corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "crossplane-system", Name: "talos-secrets"},
+			Data:       map[string][]byte{connectionKeyClientConfiguration: encoded},
+		},
+		&corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "crossplane-system", Name: "talos-client"},
+			Data: map[string][]byte{
+				connectionKeyCACertificate:     []byte(caCert),
+				connectionKeyClientCertificate: []byte(clientCert),
+				connectionKeyClientKey:         []byte(clientKey),
+			},
+		},
+		&corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "crossplane-system", Name: "incomplete"},
+			Data:       map[string][]byte{connectionKeyCACertificate: []byte(caCert)},
+		},
+		&machinev1alpha1.Secrets{
+			ObjectMeta: metav1.ObjectMeta{Name: "cluster"},
+			Spec: machinev1alpha1.SecretsSpec{ResourceSpec: xpv1.ResourceSpec{
+				WriteConnectionSecretToReference: &xpv1.SecretReference{Namespace: "crossplane-system", Name: "talos-secrets"},
+			}},
+		},
+		&machinev1alpha1.Secrets{ObjectMeta: metav1.ObjectMeta{Name: "unpublished"}},
+	}
+
+	scheme := runtime.NewScheme()
+	if err := corev1.AddToScheme(scheme); err != nil {
+		t.Fatalf("corev1.AddToScheme(...): %v", err)
+	}
+	if err := machinev1alpha1.SchemeBuilder.AddToScheme(scheme); err != nil {
+		t.Fatalf("machinev1alpha1.AddToScheme(...): %v", err)
+	}
+	kube := fake.NewClientBuilder().WithScheme(scheme).WithObjects(objects...).Build()
+
+	tests := map[string]struct {
+		source  ClientConfigurationSource
+		want    *machinev1alpha1.ClientConfiguration
+		wantErr string
+	}{
+		"Empty": {},
+		"Inline": {
+			source: ClientConfigurationSource{ClientConfiguration: want},
+			want:   want,
+		},
+		"SecretWithClientConfiguration": {
+			source: ClientConfigurationSource{SecretRef: &xpv1.SecretReference{Namespace: "crossplane-system", Name: "talos-secrets"}},
+			want:   want,
+		},
+		"SecretWithPEMKeys": {
+			source: ClientConfigurationSource{SecretRef: &xpv1.SecretReference{Namespace: "crossplane-system", Name: "talos-client"}},
+			want:   want,
+		},
+		"SecretMissingKeys": {
+			source:  ClientConfigurationSource{SecretRef: &xpv1.SecretReference{Namespace: "crossplane-system", Name: "incomplete"}},
+			wantErr: "has no client_configuration or client_certificate key",
+		},
+		"MissingSecret": {
+			source:  ClientConfigurationSource{SecretRef: &xpv1.SecretReference{Namespace: "crossplane-system", Name: "missing"}},
+			wantErr: "cannot get client configuration Secret crossplane-system/missing",
+		},
+		"SecretsReference": {
+			source: ClientConfigurationSource{SecretsRef: &xpv1.Reference{Name: "cluster"}},
+			want:   want,
+		},
+		"SecretsWithoutConnectionSecret": {
+			source:  ClientConfigurationSource{SecretsRef: &xpv1.Reference{Name: "unpublished"}},
+			wantErr: "referenced Secrets unpublished must define writeConnectionSecretToRef",
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, err := ResolveClientConfiguration(context.Background(), kube, tc.source)
+			if tc.wantErr != "" {
+				if err == nil {
+					t.Fatal("ResolveClientConfiguration(...): expected error")
+				}
+				if !strings.Contains(err.Error(), tc.wantErr) {
+					t.Fatalf("ResolveClientConfiguration(...): got error %q, want to contain %q", err.Error(), tc.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ResolveClientConfiguration(...): unexpected error: %v", err)
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("ResolveClientConfiguration(...): -want, +got:\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestClientOptions(t *testing.T) {
+	if _, err := ClientOptions(nil); err == nil || err.Error() != errNoClientConfiguration {
+		t.Fatalf("ClientOptions(nil): got error %v, want %q", err, errNoClientConfiguration)
+	}
+
+	got, err := ClientOptions(&machinev1alpha1.ClientConfiguration{ClientCertificate: Insecure})
+	if err != nil {
+		t.Fatalf("ClientOptions(insecure): unexpected error: %v", err)
+	}
+	if len(got) != 1 {
+		t.Fatalf("ClientOptions(insecure): got %d options, want 1", len(got))
+	}
+}
+
+func TestTalosConfig(t *testing.T) {
+	caCert, clientCert, clientKey := generateTestCertificates(t)
+
+	tests := map[string]struct {
+		clientConfig machinev1alpha1.ClientConfiguration
+		wantErr      string
+	}{
+		"SecureWithPEMValues": {
+			clientConfig: machinev1alpha1.ClientConfiguration{
+				CACertificate:     caCert,
+				ClientCertificate: clientCert,
+				ClientKey:         clientKey,
+			},
+		},
+		"InvalidCAErrors": {
+			clientConfig: machinev1alpha1.ClientConfiguration{
+				CACertificate:     "invalid",
+				ClientCertificate: clientCert,
+				ClientKey:         clientKey,
+			},
+			wantErr: "failed to parse CA certificate",
+		},
+		"InvalidClientCertificateErrors": {
+			clientConfig: machinev1alpha1.ClientConfiguration{
+				CACertificate:     caCert,
+				ClientCertificate: "invalid",
+				ClientKey:         clientKey,
+			},
+			wantErr: "failed to create client certificate",
+		},
+		"MissingCACertificateErrors": {
+			clientConfig: machinev1alpha1.ClientConfiguration{
+				ClientCertificate: clientCert,
+				ClientKey:         clientKey,
+			},
+			wantErr: "clientConfiguration.caCertificate is required",
+		},
+		"MissingClientCertificateErrors": {
+			clientConfig: machinev1alpha1.ClientConfiguration{
+				CACertificate: caCert,
+				ClientKey:     clientKey,
+			},
+			wantErr: "clientConfiguration.clientCertificate is required",
+		},
+		"MissingClientKeyErrors": {
+			clientConfig: machinev1alpha1.ClientConfiguration{
+				CACertificate:     caCert,
+				ClientCertificate: clientCert,
+			},
+			wantErr: "clientConfiguration.clientKey is required",
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, err := TalosConfig(&tc.clientConfig)
+			if tc.wantErr != "" {
+				if err == nil {
+					t.Fatal("TalosConfig(...): expected error")
+				}
+				if !strings.Contains(err.Error(), tc.wantErr) {
+					t.Fatalf("TalosConfig(...): got error %q, want to contain %q", err.Error(), tc.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("TalosConfig(...): unexpected error: %v", err)
+			}
+			if diff := cmp.Diff("dynamic", got.Context); diff != "" {
+				t.Errorf("TalosConfig(...).Context: -want, +got:\n%s", diff)
+			}
+			if len(got.Contexts) != 1 {
+				t.Fatalf("TalosConfig(...): got %d contexts, want 1", len(got.Contexts))
+			}
+			ctx := got.Contexts["dynamic"]
+			if ctx == nil {
+				t.Fatal("TalosConfig(...).Contexts[dynamic] = nil")
+			}
+			assertBase64Value(t, "CA", ctx.CA, tc.clientConfig.CACertificate)
+			assertBase64Value(t, "Crt", ctx.Crt, tc.clientConfig.ClientCertificate)
+			assertBase64Value(t, "Key", ctx.Key, tc.clientConfig.ClientKey)
+			if len(ctx.Endpoints) != 0 {
+				t.Fatalf("TalosConfig(...).Contexts[dynamic].Endpoints = %v, want empty", ctx.Endpoints)
+			}
+		})
+	}
+}
+
+func assertBase64Value(t *testing.T, field, got, wantDecoded string) {
+	t.Helper()
+
+	decoded, err := base64.StdEncoding.DecodeString(got)
+	if err != nil {
+		t.Fatalf("%s is not base64 encoded: %v", field, err)
+	}
+	if diff := cmp.Diff(wantDecoded, string(decoded)); diff != "" {
+		t.Fatalf("%s decoded value: -want, +got:\n%s", field, diff)
+	}
+}
+
+func generateTestCertificates(t *testing.T) (string, string, string) {
+	t.Helper()
+
+	caKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("rsa.GenerateKey(...): %v", err)
+	}
+
+	caTemplate := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "test-ca"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageDigitalSignature,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	caDER, err := x509.CreateCertificate(rand.Reader, caTemplate, caTemplate, &caKey.PublicKey, caKey)
+	if err != nil {
+		t.Fatalf("x509.CreateCertificate(...): %v", err)
+	}
+
+	clientKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("rsa.GenerateKey(...): %v", err)
+	}
+	clientTemplate := &x509.Certificate{
+		SerialNumber: big.NewInt(2),
+		Subject:      pkix.Name{CommonName: "test-client"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}
+	clientDER, err := x509.CreateCertificate(rand.Reader, clientTemplate, caTemplate, &clientKey.PublicKey, caKey)
+	if err != nil {
+		t.Fatalf("x509.CreateCertificate(...): %v", err)
+	}
+
+	caPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: caDER})
+	clientCertPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: clientDER})
+	clientKeyPEM := pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(clientKey)})
+
+	return string(caPEM), string(clientCertPEM), string(clientKeyPEM)
+}
@@ -0,0 +1,210 @@
+/*
+Copyright 2025 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package clients
+
+import (
+	"context"
+	"strings"
+
+	"github.com/cosi-project/runtime/pkg/safe"
+	"github.com/cosi-project/runtime/pkg/state"
+	"github.com/pkg/errors"
+	talosclient "github.com/siderolabs/talos/pkg/machinery/client"
+	"github.com/siderolabs/talos/pkg/machinery/resources/block"
+	"github.com/siderolabs/talos/pkg/machinery/resources/hardware"
+	"github.com/siderolabs/talos/pkg/machinery/resources/network"
+	"github.com/siderolabs/talos/pkg/machinery/resources/runtime"
+
+	machinev1alpha1 "github.com/crossplane-contrib/provider-talos/apis/machine/v1alpha1"
+)
+
+// IsMaintenanceMode reports whether a machine answers an insecure client the
+// way the maintenance API does. Callers should bound ctx with a short timeout.
+func IsMaintenanceMode(ctx context.Context, c *talosclient.Client) bool {
+	// Try to call an API - even if it fails, the connection pattern tells us the mode
+	_, err := c.Version(ctx)
+	if err == nil {
+		// Version succeeded with insecure connection - definitely maintenance mode
+		return true
+	}
+
+	// Check error type to determine machine state
+	errStr := err.Error()
+	// "Unimplemented" means maintenance mode API (connection succeeded but API not available)
+	if strings.Contains(errStr, "Unimplemented") || strings.Contains(errStr, "not implemented in maintenance") {
+		return true
+	}
+	// Auth errors mean configured mode (machine requires credentials), and
+	// any other error (connection refused, timeout, etc.) means unreachable
+	return false
+}
+
+// versionID is the ID of the singleton Talos version resource.
+const versionID = "version"
+
+// NodeStatus is the version, lifecycle stage and identity a machine reports.
+type NodeStatus struct {
+	TalosVersion string
+	Stage        string
+	Hostname     string
+	UUID         string
+}
+
+// ReadNodeStatus returns the Talos version, machine stage, hostname and
+// system UUID a machine reports. Fields a machine has not published yet,
+// such as the hostname early in boot, are left empty.
+func ReadNodeStatus(ctx context.Context, st state.State) (NodeStatus, error) {
+	var status NodeStatus
+
+	version, err := safe.StateGetByID[*runtime.Version](ctx, st, versionID)
+	if err != nil && !state.IsNotFoundError(err) {
+		return NodeStatus{}, errors.Wrap(err, "cannot read Talos version")
+	}
+	if err == nil {
+		status.TalosVersion = version.TypedSpec().Version
+	}
+
+	machineStatus, err := safe.StateGetByID[*runtime.MachineStatus](ctx, st, runtime.MachineStatusID)
+	if err != nil && !state.IsNotFoundError(err) {
+		return NodeStatus{}, errors.Wrap(err, "cannot read machine status")
+	}
+	if err == nil {
+		status.Stage = machineStatus.TypedSpec().Stage.String()
+	}
+
+	hostname, err := safe.StateGetByID[*network.HostnameStatus](ctx, st, network.HostnameID)
+	if err != nil && !state.IsNotFoundError(err) {
+		return NodeStatus{}, errors.Wrap(err, "cannot read hostname")
+	}
+	if err == nil {
+		status.Hostname = hostname.TypedSpec().Hostname
+	}
+
+	system, err := safe.StateGetByID[*hardware.SystemInformation](ctx, st, hardware.SystemInformationID)
+	if err != nil && !state.IsNotFoundError(err) {
+		return NodeStatus{}, errors.Wrap(err, "cannot read system information")
+	}
+	if err == nil {
+		status.UUID = system.TypedSpec().UUID
+	}
+
+	return status, nil
+}
+
+// ReadHardware returns the system, processor and memory information a
+// machine reports.
+func ReadHardware(ctx context.Context, st state.State) (*machinev1alpha1.MachineHardware, error) {
+	system, err := safe.StateGetByID[*hardware.SystemInformation](ctx, st, hardware.SystemInformationID)
+	if err != nil {
+		return nil, errors.Wrap(err, "cannot read system information")
+	}
+	processors, err := safe.StateListAll[*hardware.Processor](ctx, st)
+	if err != nil {
+		return nil, errors.Wrap(err, "cannot read processors")
+	}
+	memory, err := safe.StateListAll[*hardware.MemoryModule](ctx, st)
+	if err != nil {
+		return nil, errors.Wrap(err, "cannot read memory modules")
+	}
+
+	hw := &machinev1alpha1.MachineHardware{
+		Manufacturer: system.TypedSpec().Manufacturer,
+		ProductName:  system.TypedSpec().ProductName,
+		SerialNumber: system.TypedSpec().SerialNumber,
+		UUID:         system.TypedSpec().UUID,
+		SKUNumber:    system.TypedSpec().SKUNumber,
+		Processors:   processors.Len(),
+	}
+	for p := range processors.All() {
+		hw.CPUCores += int(p.TypedSpec().CoreCount)
+	}
+	for m := range memory.All() {
+		hw.MemoryMiB += int64(m.TypedSpec().Size)
+	}
+
+	return hw, nil
+}
+
+// ReadDisks returns the disks a machine reports.
+func ReadDisks(ctx context.Context, st state.State) ([]machinev1alpha1.MachineDisk, error) {
+	disks, err := safe.StateListAll[*block.Disk](ctx, st)
+	if err != nil {
+		return nil, errors.Wrap(err, "cannot read disks")
+	}
+
+	out := make([]machinev1alpha1.MachineDisk, 0, disks.Len())
+	for d := range disks.All() {
+		out = append(out, MachineDisk(d))
+	}
+
+	return out, nil
+}
+
+// MachineDisk converts a Talos disk resource.
+func MachineDisk(d *block.Disk) machinev1alpha1.MachineDisk {
+	spec := d.TypedSpec()
+
+	return machinev1alpha1.MachineDisk{
+		Name:       d.Metadata().ID(),
+		DevPath:    spec.DevPath,
+		Size:       int64(spec.Size), //nolint:gosec // Disk sizes fit into an int64.
+		Model:      spec.Model,
+		Serial:     spec.Serial,
+		WWID:       spec.WWID,
+		Transport:  spec.Transport,
+		Rotational: spec.Rotational,
+		Readonly:   spec.Readonly,
+		CDROM:      spec.CDROM,
+	}
+}
+
+// ReadLinks returns the physical network links a machine reports.
+func ReadLinks(ctx context.Context, st state.State) ([]machinev1alpha1.MachineLink, error) {
+	links, err := safe.StateListAll[*network.LinkStatus](ctx, st)
+	if err != nil {
+		return nil, errors.Wrap(err, "cannot read network links")
+	}
+
+	var out []machinev1alpha1.MachineLink
+	for l := range links.All() {
+		if !l.TypedSpec().Physical() {
+			continue
+		}
+		out = append(out, MachineLink(l))
+	}
+
+	return out, nil
+}
+
+// MachineLink converts a Talos link status resource.
+func MachineLink(l *network.LinkStatus) machinev1alpha1.MachineLink {
+	spec := l.TypedSpec()
+
+	link := machinev1alpha1.MachineLink{
+		Name:             l.Metadata().ID(),
+		OperationalState: spec.OperationalState.String(),
+		Driver:           spec.Driver,
+	}
+	if len(spec.HardwareAddr) > 0 {
+		link.HardwareAddr = spec.HardwareAddr.String()
+	}
+	if spec.SpeedMegabits > 0 {
+		link.SpeedMegabits = int64(spec.SpeedMegabits)
+	}
+
+	return link
+}
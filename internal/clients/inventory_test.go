@@ -0,0 +1,164 @@
+/*
+Copyright 2025 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package clients
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	"github.com/cosi-project/runtime/pkg/resource"
+	"github.com/cosi-project/runtime/pkg/state"
+	"github.com/cosi-project/runtime/pkg/state/impl/inmem"
+	"github.com/cosi-project/runtime/pkg/state/impl/namespaced"
+	"github.com/google/go-cmp/cmp"
+	"github.com/siderolabs/talos/pkg/machinery/nethelpers"
+	"github.com/siderolabs/talos/pkg/machinery/resources/block"
+	"github.com/siderolabs/talos/pkg/machinery/resources/hardware"
+	"github.com/siderolabs/talos/pkg/machinery/resources/network"
+	"github.com/siderolabs/talos/pkg/machinery/resources/runtime"
+
+	machinev1alpha1 "github.com/crossplane-contrib/provider-talos/apis/machine/v1alpha1"
+)
+
+func TestReadInventory(t *testing.T) {
+	ctx := context.Background()
+	st := state.WrapCore(namespaced.NewState(inmem.Build))
+
+	system := hardware.NewSystemInformation(hardware.SystemInformationID)
+	system.TypedSpec().Manufacturer = "Acme"
+	system.TypedSpec().ProductName = "Server 1"
+	system.TypedSpec().SerialNumber = "SN1"
+	system.TypedSpec().UUID = "4c4c4544-0000-1000-8000-000000000001"
+
+	cpu0 := hardware.NewProcessorInfo("cpu0")
+	cpu0.TypedSpec().CoreCount = 8
+	cpu1 := hardware.NewProcessorInfo("cpu1")
+	cpu1.TypedSpec().CoreCount = 8
+	memory := hardware.NewMemoryModuleInfo("dimm0")
+	memory.TypedSpec().Size = 16384
+
+	disk := block.NewDisk(block.NamespaceName, "nvme0n1")
+	disk.TypedSpec().DevPath = "/dev/nvme0n1"
+	disk.TypedSpec().Size = 512110190592
+	disk.TypedSpec().Model = "Samsung SSD 980"
+	disk.TypedSpec().Transport = "nvme"
+
+	mac, _ := net.ParseMAC("52:54:00:12:34:56")
+	eth0 := network.NewLinkStatus(network.NamespaceName, "eth0")
+	eth0.TypedSpec().Type = nethelpers.LinkEther
+	eth0.TypedSpec().HardwareAddr = nethelpers.HardwareAddr(mac)
+	eth0.TypedSpec().OperationalState = nethelpers.OperStateUp
+	eth0.TypedSpec().SpeedMegabits = 1000
+	bond := network.NewLinkStatus(network.NamespaceName, "bond0")
+	bond.TypedSpec().Type = nethelpers.LinkEther
+	bond.TypedSpec().Kind = "bond"
+
+	for _, r := range []resource.Resource{system, cpu0, cpu1, memory, disk, eth0, bond} {
+		if err := st.Create(ctx, r); err != nil {
+			t.Fatalf("st.Create(...): unexpected error: %v", err)
+		}
+	}
+
+	hw, err := ReadHardware(ctx, st)
+	if err != nil {
+		t.Fatalf("ReadHardware(...): unexpected error: %v", err)
+	}
+	wantHardware := &machinev1alpha1.MachineHardware{
+		Manufacturer: "Acme",
+		ProductName:  "Server 1",
+		SerialNumber: "SN1",
+		UUID:         "4c4c4544-0000-1000-8000-000000000001",
+		Processors:   2,
+		CPUCores:     16,
+		MemoryMiB:    16384,
+	}
+	if diff := cmp.Diff(wantHardware, hw); diff != "" {
+		t.Errorf("ReadHardware(...): -want, +got:\n%s", diff)
+	}
+
+	disks, err := ReadDisks(ctx, st)
+	if err != nil {
+		t.Fatalf("ReadDisks(...): unexpected error: %v", err)
+	}
+	wantDisks := []machinev1alpha1.MachineDisk{{
+		Name:      "nvme0n1",
+		DevPath:   "/dev/nvme0n1",
+		Size:      512110190592,
+		Model:     "Samsung SSD 980",
+		Transport: "nvme",
+	}}
+	if diff := cmp.Diff(wantDisks, disks); diff != "" {
+		t.Errorf("ReadDisks(...): -want, +got:\n%s", diff)
+	}
+
+	links, err := ReadLinks(ctx, st)
+	if err != nil {
+		t.Fatalf("ReadLinks(...): unexpected error: %v", err)
+	}
+	wantLinks := []machinev1alpha1.MachineLink{{
+		Name:             "eth0",
+		HardwareAddr:     "52:54:00:12:34:56",
+		OperationalState: "up",
+		SpeedMegabits:    1000,
+	}}
+	if diff := cmp.Diff(wantLinks, links); diff != "" {
+		t.Errorf("ReadLinks(...): -want, +got:\n%s", diff)
+	}
+}
+
+func TestReadNodeStatus(t *testing.T) {
+	ctx := context.Background()
+	st := state.WrapCore(namespaced.NewState(inmem.Build))
+
+	status, err := ReadNodeStatus(ctx, st)
+	if err != nil {
+		t.Fatalf("ReadNodeStatus(...): unexpected error: %v", err)
+	}
+	if diff := cmp.Diff(NodeStatus{}, status); diff != "" {
+		t.Errorf("ReadNodeStatus(...) on an empty state: -want, +got:\n%s", diff)
+	}
+
+	version := runtime.NewVersion()
+	version.TypedSpec().Version = "v1.11.0"
+	machineStatus := runtime.NewMachineStatus()
+	machineStatus.TypedSpec().Stage = runtime.MachineStageInstalling
+	hostname := network.NewHostnameStatus(network.NamespaceName, network.HostnameID)
+	hostname.TypedSpec().Hostname = "worker-1"
+	system := hardware.NewSystemInformation(hardware.SystemInformationID)
+	system.TypedSpec().UUID = "4c4c4544-0000-1000-8000-000000000001"
+
+	for _, r := range []resource.Resource{version, machineStatus, hostname, system} {
+		if err := st.Create(ctx, r); err != nil {
+			t.Fatalf("st.Create(...): unexpected error: %v", err)
+		}
+	}
+
+	status, err = ReadNodeStatus(ctx, st)
+	if err != nil {
+		t.Fatalf("ReadNodeStatus(...): unexpected error: %v", err)
+	}
+	want := NodeStatus{
+		TalosVersion: "v1.11.0",
+		Stage:        "installing",
+		Hostname:     "worker-1",
+		UUID:         "4c4c4544-0000-1000-8000-000000000001",
+	}
+	if diff := cmp.Diff(want, status); diff != "" {
+		t.Errorf("ReadNodeStatus(...): -want, +got:\n%s", diff)
+	}
+}
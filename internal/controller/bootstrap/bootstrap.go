@@ -18,17 +18,14 @@ package bootstrap
 
 import (
 	"context"
-	"crypto/tls"
-	"crypto/x509"
 	"fmt"
+	"io"
 	"strings"
 	"time"
 
 	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
-	siderox509 "github.com/siderolabs/crypto/x509"
 	"github.com/siderolabs/talos/pkg/machinery/api/machine"
 	talosclient "github.com/siderolabs/talos/pkg/machinery/client"
-	clientconfig "github.com/siderolabs/talos/pkg/machinery/client/config"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
@@ -52,6 +49,7 @@ import (
 
 	"github.com/crossplane-contrib/provider-talos/apis/machine/v1alpha1"
 	apisv1alpha1 "github.com/crossplane-contrib/provider-talos/apis/v1alpha1"
+	"github.com/crossplane-contrib/provider-talos/internal/clients"
 	"github.com/crossplane-contrib/provider-talos/internal/features"
 )
 
@@ -62,8 +60,6 @@ const (
 	errGetCreds     = "cannot get credentials"
 
 	errNewClient = "cannot create new Service"
-
-	certInsecure = "insecure"
 )
 
 // A NoOpService does nothing.
@@ -74,7 +70,8 @@ var (
 )
 
 // Setup adds a controller that reconciles Bootstrap managed resources.
-func Setup(mgr ctrl.Manager, o controller.Options) error {
+// Talos API clients are borrowed from the supplied cache.
+func Setup(mgr ctrl.Manager, o controller.Options, talosClients *clients.Cache) error {
 	name := managed.ControllerName(v1alpha1.BootstrapGroupKind)
 
 	cps := []managed.ConnectionPublisher{managed.NewAPISecretPublisher(mgr.GetClient(), mgr.GetScheme())}
@@ -86,7 +83,8 @@ func Setup(mgr ctrl.Manager, o controller.Options) error {
 		managed.WithExternalConnecter(&connector{
 			kube:         mgr.GetClient(),
 			usage:        resource.NewProviderConfigUsageTracker(mgr.GetClient(), &apisv1alpha1.ProviderConfigUsage{}),
-			newServiceFn: newNoOpService}),
+			newServiceFn: newNoOpService,
+			talosClients: talosClients}),
 		managed.WithLogger(o.Logger.WithValues("controller", name)),
 		managed.WithPollInterval(o.PollInterval),
 		managed.WithRecorder(event.NewAPIRecorder(mgr.GetEventRecorderFor(name))),
@@ -127,6 +125,7 @@ type connector struct {
 	kube         ctrlclient.Client
 	usage        resource.Tracker
 	newServiceFn func(creds []byte) (interface{}, error)
+	talosClients *clients.Cache
 }
 
 // Connect typically produces an ExternalClient by:
@@ -160,21 +159,27 @@ func (c *connector) Connect(ctx context.Context, mg resource.Managed) (managed.E
 		return nil, errors.Wrap(err, errNewClient)
 	}
 
-	return &external{service: svc}, nil
+	return &external{kube: c.kube, service: svc, talosClients: c.talosClients}, nil
 }
 
 // An ExternalClient observes, then either creates, updates, or deletes an
 // external resource to ensure it reflects the managed resource's desired state.
 type external struct {
+	kube ctrlclient.Client
 	// A 'client' used to connect to the external resource API. In practice this
 	// would be something like an AWS SDK client.
 	service interface{}
+	// talosClients shares Talos API clients between reconciles.
+	talosClients *clients.Cache
 	// bootstrapFn allows tests to stub the non-idempotent Talos bootstrap call.
 	bootstrapFn func(context.Context, *v1alpha1.Bootstrap) error
 	// isBootstrappedHealthyFn allows tests to stub already-bootstrapped detection.
 	isBootstrappedHealthyFn func(context.Context, *v1alpha1.Bootstrap) bool
 	// newBootstrapHealthClientFn allows tests to stub Talos service state checks.
 	newBootstrapHealthClientFn func(context.Context, *v1alpha1.Bootstrap) (bootstrapHealthClient, error)
+	// newBootstrapClientFn allows tests to stub the Talos bootstrap and etcd
+	// recovery APIs.
+	newBootstrapClientFn func(context.Context, *v1alpha1.Bootstrap) (bootstrapClient, error)
 }
 
 type bootstrapHealthClient interface {
@@ -182,6 +187,13 @@ type bootstrapHealthClient interface {
 	Close() error
 }
 
+type bootstrapClient interface {
+	Bootstrap(context.Context, *machine.BootstrapRequest) error
+	EtcdRecover(context.Context, io.Reader, ...grpc.CallOption) (*machine.EtcdRecoverResponse, error)
+	Copy(context.Context, string) (io.ReadCloser, error)
+	Close() error
+}
+
 func (c *external) Observe(ctx context.Context, mg resource.Managed) (managed.ExternalObservation, error) {
 	cr, ok := mg.(*v1alpha1.Bootstrap)
 	if !ok {
@@ -334,37 +346,28 @@ func (c *external) newBootstrapHealthClient(ctx context.Context, cr *v1alpha1.Bo
 		return c.newBootstrapHealthClientFn(ctx, cr)
 	}
 
-	clientConfig := cr.Spec.ForProvider.ClientConfiguration
-	if clientConfig.ClientCertificate == "" {
-		return nil, errors.New("clientConfiguration is required")
+	return c.newTalosClient(ctx, cr)
+}
+
+func (c *external) newBootstrapClient(ctx context.Context, cr *v1alpha1.Bootstrap) (bootstrapClient, error) {
+	if c.newBootstrapClientFn != nil {
+		return c.newBootstrapClientFn(ctx, cr)
 	}
 
-	endpoint := getBootstrapEndpoint(cr)
-	var talosClient *talosclient.Client
-	var err error
-	if clientConfig.ClientCertificate == certInsecure || clientConfig.CACertificate == certInsecure {
-		talosClient, err = talosclient.New(ctx,
-			talosclient.WithTLSConfig(&tls.Config{
-				InsecureSkipVerify: true, //nolint:gosec // Insecure mode needed for maintenance-mode machines.
-			}),
-			talosclient.WithEndpoints(endpoint),
-		)
-	} else {
-		talosConfig, configErr := buildBootstrapClientConfig(clientConfig)
-		if configErr != nil {
-			return nil, configErr
-		}
+	return c.newTalosClient(ctx, cr)
+}
 
-		talosClient, err = talosclient.New(ctx,
-			talosclient.WithConfig(talosConfig),
-			talosclient.WithEndpoints(endpoint),
-		)
-	}
+func (c *external) newTalosClient(ctx context.Context, cr *v1alpha1.Bootstrap) (*clients.Client, error) {
+	clientConfig, err := clients.ResolveClientConfiguration(ctx, c.kube, clients.ClientConfigurationSource{
+		ClientConfiguration: cr.Spec.ForProvider.ClientConfiguration,
+		SecretRef:           cr.Spec.ForProvider.ClientConfigurationSecretRef,
+		SecretsRef:          cr.Spec.ForProvider.SecretsRef,
+	})
 	if err != nil {
 		return nil, err
 	}
 
-	return talosClient, nil
+	return c.talosClients.Client(ctx, getBootstrapEndpoints(cr), clientConfig)
 }
 
 func isHealthyEtcdService(service *machine.ServiceInfo) bool {
@@ -389,95 +392,57 @@ func hasBootstrapRequiredMessage(message string) bool {
 	return strings.Contains(strings.ToLower(message), "please run talosctl bootstrap")
 }
 
-// bootstrapTalosCluster bootstraps the Talos cluster on the specified control plane node
+// bootstrapTalosCluster bootstraps the Talos cluster on the specified control
+// plane node, recovering etcd from a snapshot first if requested.
 func (c *external) bootstrapTalosCluster(ctx context.Context, cr *v1alpha1.Bootstrap) error {
-	// Get client configuration
-	clientConfig := cr.Spec.ForProvider.ClientConfiguration
-	if clientConfig.ClientCertificate == "" {
-		return errors.New("clientConfiguration is required")
-	}
-
-	endpoint := getBootstrapEndpoint(cr)
-
-	// Handle insecure mode (when certificates are "insecure")
-	var talosClient *talosclient.Client
-	var err error
-
-	if clientConfig.ClientCertificate == certInsecure || clientConfig.CACertificate == certInsecure {
-		fmt.Printf("Using insecure connection to %s\n", endpoint)
-		// Create insecure client
-		talosClient, err = talosclient.New(ctx,
-			talosclient.WithEndpoints(endpoint),
-			talosclient.WithTLSConfig(&tls.Config{
-				InsecureSkipVerify: true, //nolint:gosec // Insecure mode needed for maintenance mode machines
-			}),
-		)
-	} else {
-		fmt.Printf("Using secure connection to %s\n", endpoint)
-		talosConfig, configErr := buildBootstrapClientConfig(clientConfig)
-		if configErr != nil {
-			return configErr
-		}
-
-		// Create Talos client
-		talosClient, err = talosclient.New(ctx,
-			talosclient.WithConfig(talosConfig),
-			talosclient.WithEndpoints(endpoint),
-		)
-	}
+	endpoints := strings.Join(getBootstrapEndpoints(cr), ", ")
 
+	talosClient, err := c.newBootstrapClient(ctx, cr)
 	if err != nil {
 		return errors.Wrap(err, "failed to create Talos client")
 	}
 	defer talosClient.Close() // nolint:errcheck
 
-	fmt.Printf("Attempting to bootstrap Talos cluster on endpoint %s\n", endpoint)
+	nodeCtx := talosclient.WithNode(ctx, cr.Spec.ForProvider.Node)
+	req := &machine.BootstrapRequest{}
+
+	var recovered *recoveredSnapshot
+	if cr.Spec.ForProvider.Recovery != nil {
+		recovered, err = c.recoverEtcd(ctx, cr, talosClient)
+		if err != nil {
+			return err
+		}
+		req.RecoverEtcd = true
+		req.RecoverSkipHashCheck = recovered.skipHashCheck
+	}
+
+	fmt.Printf("Attempting to bootstrap Talos cluster on node %s through %s\n", cr.Spec.ForProvider.Node, endpoints)
 
 	// Bootstrap the cluster
-	err = talosClient.Bootstrap(talosclient.WithNode(ctx, cr.Spec.ForProvider.Node), &machine.BootstrapRequest{})
+	err = talosClient.Bootstrap(nodeCtx, req)
 	if err != nil {
 		return errors.Wrap(err, "failed to bootstrap Talos cluster")
 	}
 
-	fmt.Printf("Successfully bootstrapped Talos cluster on endpoint %s\n", endpoint)
-	return nil
-}
-
-func getBootstrapEndpoint(cr *v1alpha1.Bootstrap) string {
-	endpoint := cr.Spec.ForProvider.Node + ":50000"
-	if cr.Spec.ForProvider.Endpoint != nil && *cr.Spec.ForProvider.Endpoint != "" {
-		endpoint = *cr.Spec.ForProvider.Endpoint
+	if recovered != nil {
+		markRecovered(cr, recovered, metav1.Now())
 	}
 
-	return endpoint
+	fmt.Printf("Successfully bootstrapped Talos cluster on node %s\n", cr.Spec.ForProvider.Node)
+	return nil
 }
 
-func buildBootstrapClientConfig(clientConfig v1alpha1.ClientConfiguration) (*clientconfig.Config, error) {
-	if clientConfig.ClientCertificate == "" {
-		return nil, errors.New("clientConfiguration.clientCertificate is required")
-	}
-	if clientConfig.ClientKey == "" {
-		return nil, errors.New("clientConfiguration.clientKey is required")
-	}
-	if clientConfig.CACertificate == "" {
-		return nil, errors.New("clientConfiguration.caCertificate is required")
+// getBootstrapEndpoints returns the Talos API endpoints calls for the node are
+// routed through.
+func getBootstrapEndpoints(cr *v1alpha1.Bootstrap) []string {
+	if len(cr.Spec.ForProvider.Endpoints) > 0 {
+		return cr.Spec.ForProvider.Endpoints
 	}
 
-	cert, err := tls.X509KeyPair([]byte(clientConfig.ClientCertificate), []byte(clientConfig.ClientKey))
-	if err != nil {
-		return nil, errors.Wrap(err, "failed to create client certificate")
-	}
-	if len(cert.Certificate) == 0 {
-		return nil, errors.New("failed to create client certificate")
-	}
-
-	roots := x509.NewCertPool()
-	if ok := roots.AppendCertsFromPEM([]byte(clientConfig.CACertificate)); !ok {
-		return nil, errors.New("failed to parse CA certificate")
+	endpoint := cr.Spec.ForProvider.Node + ":50000"
+	if cr.Spec.ForProvider.Endpoint != nil && *cr.Spec.ForProvider.Endpoint != "" {
+		endpoint = *cr.Spec.ForProvider.Endpoint
 	}
 
-	return clientconfig.NewConfig("dynamic", nil, []byte(clientConfig.CACertificate), &siderox509.PEMEncodedCertificateAndKey{
-		Crt: []byte(clientConfig.ClientCertificate),
-		Key: []byte(clientConfig.ClientKey),
-	}), nil
+	return []string{endpoint}
 }
@@ -17,14 +17,16 @@ limitations under the License.
 package bootstrap
 
 import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
 	"context"
-	"crypto/rand"
-	"crypto/rsa"
-	"crypto/x509"
-	"crypto/x509/pkix"
-	"encoding/base64"
-	"encoding/pem"
-	"math/big"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
 	"strings"
 	"testing"
 	"time"
@@ -37,9 +39,12 @@ import (
 	talosclient "github.com/siderolabs/talos/pkg/machinery/client"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
 	"google.golang.org/grpc/status"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 
 	"github.com/crossplane-contrib/provider-talos/apis/machine/v1alpha1"
 	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
@@ -346,112 +351,12 @@ func TestIsBootstrapAlreadyExists(t *testing.T) {
 	}
 }
 
-func TestBuildBootstrapClientConfig(t *testing.T) {
-	caCert, clientCert, clientKey := generateTestCertificates(t)
-
-	tests := map[string]struct {
-		clientConfig v1alpha1.ClientConfiguration
-		wantErr      string
-	}{
-		"SecureWithPEMValues": {
-			clientConfig: v1alpha1.ClientConfiguration{
-				CACertificate:     caCert,
-				ClientCertificate: clientCert,
-				ClientKey:         clientKey,
-			},
-		},
-		"InvalidCAErrors": {
-			clientConfig: v1alpha1.ClientConfiguration{
-				CACertificate:     "invalid",
-				ClientCertificate: clientCert,
-				ClientKey:         clientKey,
-			},
-			wantErr: "failed to parse CA certificate",
-		},
-		"InvalidClientCertificateErrors": {
-			clientConfig: v1alpha1.ClientConfiguration{
-				CACertificate:     caCert,
-				ClientCertificate: "invalid",
-				ClientKey:         clientKey,
-			},
-			wantErr: "failed to create client certificate",
-		},
-		"MissingCACertificateErrors": {
-			clientConfig: v1alpha1.ClientConfiguration{
-				ClientCertificate: clientCert,
-				ClientKey:         clientKey,
-			},
-			wantErr: "clientConfiguration.caCertificate is required",
-		},
-		"MissingClientCertificateErrors": {
-			clientConfig: v1alpha1.ClientConfiguration{
-				CACertificate: caCert,
-				ClientKey:     clientKey,
-			},
-			wantErr: "clientConfiguration.clientCertificate is required",
-		},
-		"MissingClientKeyErrors": {
-			clientConfig: v1alpha1.ClientConfiguration{
-				CACertificate:     caCert,
-				ClientCertificate: clientCert,
-			},
-			wantErr: "clientConfiguration.clientKey is required",
-		},
-	}
-
-	for name, tc := range tests {
-		t.Run(name, func(t *testing.T) {
-			got, err := buildBootstrapClientConfig(tc.clientConfig)
-			if tc.wantErr != "" {
-				if err == nil {
-					t.Fatal("buildBootstrapClientConfig(...): expected error")
-				}
-				if !strings.Contains(err.Error(), tc.wantErr) {
-					t.Fatalf("buildBootstrapClientConfig(...): got error %q, want to contain %q", err.Error(), tc.wantErr)
-				}
-				return
-			}
-			if err != nil {
-				t.Fatalf("buildBootstrapClientConfig(...): unexpected error: %v", err)
-			}
-			if diff := cmp.Diff("dynamic", got.Context); diff != "" {
-				t.Errorf("buildBootstrapClientConfig(...).Context: -want, +got:\n%s", diff)
-			}
-			if len(got.Contexts) != 1 {
-				t.Fatalf("buildBootstrapClientConfig(...): got %d contexts, want 1", len(got.Contexts))
-			}
-			ctx := got.Contexts["dynamic"]
-			if ctx == nil {
-				t.Fatal("buildBootstrapClientConfig(...).Contexts[dynamic] = nil")
-			}
-			assertBase64Value(t, "CA", ctx.CA, tc.clientConfig.CACertificate)
-			assertBase64Value(t, "Crt", ctx.Crt, tc.clientConfig.ClientCertificate)
-			assertBase64Value(t, "Key", ctx.Key, tc.clientConfig.ClientKey)
-			if len(ctx.Endpoints) != 0 {
-				t.Fatalf("buildBootstrapClientConfig(...).Contexts[dynamic].Endpoints = %v, want empty", ctx.Endpoints)
-			}
-		})
-	}
-}
-
-func assertBase64Value(t *testing.T, field, got, wantDecoded string) {
-	t.Helper()
-
-	decoded, err := base64.StdEncoding.DecodeString(got)
-	if err != nil {
-		t.Fatalf("%s is not base64 encoded: %v", field, err)
-	}
-	if diff := cmp.Diff(wantDecoded, string(decoded)); diff != "" {
-		t.Fatalf("%s decoded value: -want, +got:\n%s", field, diff)
-	}
-}
-
 func testBootstrap() *v1alpha1.Bootstrap {
 	return &v1alpha1.Bootstrap{
 		ObjectMeta: metav1.ObjectMeta{Name: "test-bootstrap"},
 		Spec: v1alpha1.BootstrapSpec{ForProvider: v1alpha1.BootstrapParameters{
 			Node: "127.0.0.1",
-			ClientConfiguration: v1alpha1.ClientConfiguration{
+			ClientConfiguration: &v1alpha1.ClientConfiguration{
 				ClientCertificate: "insecure",
 			},
 		}},
@@ -485,48 +390,221 @@ func serviceInfo(id, state string, health *machineapi.ServiceHealth, eventMessag
 	return service
 }
 
-func generateTestCertificates(t *testing.T) (string, string, string) {
-	t.Helper()
+type fakeBootstrapClient struct {
+	copied    map[string][]byte
+	copyNode  string
+	uploaded  []byte
+	request   *machineapi.BootstrapRequest
+	uploadErr error
+}
+
+func (f *fakeBootstrapClient) Bootstrap(_ context.Context, req *machineapi.BootstrapRequest) error {
+	f.request = req
+	return nil
+}
 
-	caKey, err := rsa.GenerateKey(rand.Reader, 2048)
+func (f *fakeBootstrapClient) EtcdRecover(_ context.Context, snapshot io.Reader, _ ...grpc.CallOption) (*machineapi.EtcdRecoverResponse, error) {
+	if f.uploadErr != nil {
+		return nil, f.uploadErr
+	}
+	data, err := io.ReadAll(snapshot)
 	if err != nil {
-		t.Fatalf("rsa.GenerateKey(...): %v", err)
+		return nil, err
 	}
+	f.uploaded = data
+
+	return &machineapi.EtcdRecoverResponse{}, nil
+}
 
-	caTemplate := &x509.Certificate{
-		SerialNumber:          big.NewInt(1),
-		Subject:               pkix.Name{CommonName: "test-ca"},
-		NotBefore:             time.Now().Add(-time.Hour),
-		NotAfter:              time.Now().Add(time.Hour),
-		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageDigitalSignature,
-		BasicConstraintsValid: true,
-		IsCA:                  true,
+func (f *fakeBootstrapClient) Copy(ctx context.Context, rootPath string) (io.ReadCloser, error) {
+	if md, ok := metadata.FromOutgoingContext(ctx); ok {
+		if nodes := md.Get("node"); len(nodes) > 0 {
+			f.copyNode = nodes[0]
+		}
 	}
-	caDER, err := x509.CreateCertificate(rand.Reader, caTemplate, caTemplate, &caKey.PublicKey, caKey)
-	if err != nil {
-		t.Fatalf("x509.CreateCertificate(...): %v", err)
+	data, ok := f.copied[rootPath]
+	if !ok {
+		return nil, status.Error(codes.NotFound, "file not found")
 	}
 
-	clientKey, err := rsa.GenerateKey(rand.Reader, 2048)
-	if err != nil {
-		t.Fatalf("rsa.GenerateKey(...): %v", err)
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gz)
+	if err := tw.WriteHeader(&tar.Header{Name: path.Base(rootPath), Typeflag: tar.TypeReg, Mode: 0o600, Size: int64(len(data))}); err != nil {
+		return nil, err
 	}
-	clientTemplate := &x509.Certificate{
-		SerialNumber: big.NewInt(2),
-		Subject:      pkix.Name{CommonName: "test-client"},
-		NotBefore:    time.Now().Add(-time.Hour),
-		NotAfter:     time.Now().Add(time.Hour),
-		KeyUsage:     x509.KeyUsageDigitalSignature,
-		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	if _, err := tw.Write(data); err != nil {
+		return nil, err
 	}
-	clientDER, err := x509.CreateCertificate(rand.Reader, clientTemplate, caTemplate, &clientKey.PublicKey, caKey)
-	if err != nil {
-		t.Fatalf("x509.CreateCertificate(...): %v", err)
+	if err := tw.Close(); err != nil {
+		return nil, err
+	}
+	if err := gz.Close(); err != nil {
+		return nil, err
 	}
 
-	caPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: caDER})
-	clientCertPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: clientDER})
-	clientKeyPEM := pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(clientKey)})
+	return io.NopCloser(&buf), nil
+}
+
+func (f *fakeBootstrapClient) Close() error { return nil }
+
+func TestCreateWithRecovery(t *testing.T) {
+	snapshot := []byte("etcd snapshot")
+	sum := sha256.Sum256(snapshot)
+
+	dir := t.TempDir()
+	stored := filepath.Join(dir, "backup.db")
+	if err := os.WriteFile(stored, snapshot, 0o600); err != nil {
+		t.Fatalf("write snapshot: %v", err)
+	}
+
+	etcdSnapshot := func(name, hash string) *v1alpha1.EtcdSnapshot {
+		return &v1alpha1.EtcdSnapshot{
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			Spec: v1alpha1.EtcdSnapshotSpec{ForProvider: v1alpha1.EtcdSnapshotParameters{
+				Storage: v1alpha1.EtcdSnapshotStorage{Volume: &v1alpha1.EtcdSnapshotVolumeStorage{Path: dir}},
+			}},
+			Status: v1alpha1.EtcdSnapshotStatus{AtProvider: v1alpha1.EtcdSnapshotObservation{
+				LastSnapshot: &v1alpha1.EtcdSnapshotRecord{Location: stored, Hash: hash},
+			}},
+		}
+	}
+
+	type want struct {
+		err           bool
+		uploaded      []byte
+		skipHashCheck bool
+		source        string
+		snapshot      string
+		copyNode      string
+	}
+
+	cases := map[string]struct {
+		reason   string
+		recovery *v1alpha1.BootstrapRecovery
+		want     want
+	}{
+		"Secret": {
+			reason: "A snapshot in a Secret should be uploaded before bootstrapping with recovery.",
+			recovery: &v1alpha1.BootstrapRecovery{
+				SnapshotSecretRef: &xpv1.SecretKeySelector{
+					SecretReference: xpv1.SecretReference{Name: "etcd-backup", Namespace: "crossplane-system"},
+					Key:             "snapshot.db",
+				},
+			},
+			want: want{
+				uploaded: snapshot,
+				source:   "secret:crossplane-system/etcd-backup/snapshot.db",
+				snapshot: "crossplane-system/etcd-backup/snapshot.db",
+			},
+		},
+		"EtcdSnapshot": {
+			reason:   "The last snapshot of an EtcdSnapshot should be uploaded once its hash is verified.",
+			recovery: &v1alpha1.BootstrapRecovery{EtcdSnapshotRef: &xpv1.Reference{Name: "backups"}},
+			want: want{
+				uploaded: snapshot,
+				source:   "etcdSnapshot:backups",
+				snapshot: stored,
+			},
+		},
+		"EtcdSnapshotHashMismatch": {
+			reason:   "A stored snapshot that does not match its recorded hash should not be uploaded.",
+			recovery: &v1alpha1.BootstrapRecovery{EtcdSnapshotRef: &xpv1.Reference{Name: "corrupt"}},
+			want:     want{err: true},
+		},
+		"EtcdSnapshotWithoutSnapshot": {
+			reason:   "An EtcdSnapshot that has not taken a snapshot yet should return an error.",
+			recovery: &v1alpha1.BootstrapRecovery{EtcdSnapshotRef: &xpv1.Reference{Name: "empty"}},
+			want:     want{err: true},
+		},
+		"DataDirectory": {
+			reason: "The etcd database should be copied from the data directory and restored without a hash check.",
+			recovery: &v1alpha1.BootstrapRecovery{
+				DataDirectory: &v1alpha1.BootstrapRecoveryDataDirectory{Node: ptrString("10.0.0.2")},
+			},
+			want: want{
+				uploaded:      snapshot,
+				skipHashCheck: true,
+				source:        "dataDirectory:10.0.0.2",
+				snapshot:      "10.0.0.2:/var/lib/etcd/member/snap/db",
+				copyNode:      "10.0.0.2",
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			scheme := runtime.NewScheme()
+			if err := corev1.AddToScheme(scheme); err != nil {
+				t.Fatalf("add core scheme: %v", err)
+			}
+			if err := v1alpha1.SchemeBuilder.AddToScheme(scheme); err != nil {
+				t.Fatalf("add machine scheme: %v", err)
+			}
+			empty := etcdSnapshot("empty", "")
+			empty.Status.AtProvider.LastSnapshot = nil
+			kube := fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+				&corev1.Secret{
+					ObjectMeta: metav1.ObjectMeta{Name: "etcd-backup", Namespace: "crossplane-system"},
+					Data:       map[string][]byte{"snapshot.db": snapshot},
+				},
+				etcdSnapshot("backups", hex.EncodeToString(sum[:])),
+				etcdSnapshot("corrupt", strings.Repeat("0", 64)),
+				empty,
+			).Build()
+
+			client := &fakeBootstrapClient{copied: map[string][]byte{etcdDatabasePath: snapshot}}
+			cr := testBootstrap()
+			cr.Spec.ForProvider.Recovery = tc.recovery
+			e := external{
+				kube: kube,
+				newBootstrapClientFn: func(context.Context, *v1alpha1.Bootstrap) (bootstrapClient, error) {
+					return client, nil
+				},
+				isBootstrappedHealthyFn: func(context.Context, *v1alpha1.Bootstrap) bool { return true },
+			}
+
+			_, err := e.Create(context.Background(), cr)
+			if tc.want.err {
+				if err == nil {
+					t.Fatalf("\n%s\ne.Create(...): expected error", tc.reason)
+				}
+				if client.request != nil {
+					t.Errorf("\n%s\ne.Create(...): bootstrap should not be called after a failed recovery", tc.reason)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("\n%s\ne.Create(...): unexpected error: %v", tc.reason, err)
+			}
+
+			if diff := cmp.Diff(tc.want.uploaded, client.uploaded); diff != "" {
+				t.Errorf("\n%s\nuploaded snapshot: -want, +got:\n%s", tc.reason, diff)
+			}
+			if client.request == nil || !client.request.GetRecoverEtcd() {
+				t.Fatalf("\n%s\nBootstrapRequest.RecoverEtcd should be set", tc.reason)
+			}
+			if got := client.request.GetRecoverSkipHashCheck(); got != tc.want.skipHashCheck {
+				t.Errorf("\n%s\nBootstrapRequest.RecoverSkipHashCheck: want %t, got %t", tc.reason, tc.want.skipHashCheck, got)
+			}
+			if client.copyNode != tc.want.copyNode {
+				t.Errorf("\n%s\ncopied from node: want %q, got %q", tc.reason, tc.want.copyNode, client.copyNode)
+			}
+
+			got := cr.Status.AtProvider
+			if got.RecoverySource != tc.want.source {
+				t.Errorf("\n%s\nRecoverySource: want %q, got %q", tc.reason, tc.want.source, got.RecoverySource)
+			}
+			if got.RecoverySnapshot != tc.want.snapshot {
+				t.Errorf("\n%s\nRecoverySnapshot: want %q, got %q", tc.reason, tc.want.snapshot, got.RecoverySnapshot)
+			}
+			if got.RecoveryTime == nil {
+				t.Errorf("\n%s\nRecoveryTime should be set", tc.reason)
+			}
+		})
+	}
+}
 
-	return string(caPEM), string(clientCertPEM), string(clientKeyPEM)
+func ptrString(s string) *string {
+	return &s
 }
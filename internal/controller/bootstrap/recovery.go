@@ -0,0 +1,232 @@
+/*
+Copyright 2025 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bootstrap
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"path"
+
+	"github.com/pkg/errors"
+	talosclient "github.com/siderolabs/talos/pkg/machinery/client"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/crossplane-contrib/provider-talos/apis/machine/v1alpha1"
+	"github.com/crossplane-contrib/provider-talos/internal/snapshotstore"
+)
+
+const (
+	// etcdDatabasePath is the etcd bbolt database in a Talos node's data
+	// directory.
+	etcdDatabasePath = "/var/lib/etcd/member/snap/db"
+
+	recoverySourceSecret        = "secret"
+	recoverySourceEtcdSnapshot  = "etcdSnapshot"
+	recoverySourceDataDirectory = "dataDirectory"
+)
+
+// recoveredSnapshot describes a snapshot uploaded to the node for recovery.
+type recoveredSnapshot struct {
+	source        string
+	location      string
+	skipHashCheck bool
+}
+
+func markRecovered(cr *v1alpha1.Bootstrap, recovered *recoveredSnapshot, when metav1.Time) {
+	cr.Status.AtProvider.RecoverySource = recovered.source
+	cr.Status.AtProvider.RecoverySnapshot = recovered.location
+	cr.Status.AtProvider.RecoveryTime = &when
+}
+
+// recoverEtcd uploads the configured recovery snapshot to the node. The node
+// restores it when it is subsequently bootstrapped with RecoverEtcd set.
+func (c *external) recoverEtcd(ctx context.Context, cr *v1alpha1.Bootstrap, talosClient bootstrapClient) (*recoveredSnapshot, error) {
+	recovery := cr.Spec.ForProvider.Recovery
+
+	snapshot, recovered, err := c.openRecoverySnapshot(ctx, cr, talosClient)
+	if err != nil {
+		return nil, err
+	}
+	defer snapshot.Close() //nolint:errcheck
+
+	if recovery.SkipHashCheck != nil && *recovery.SkipHashCheck {
+		recovered.skipHashCheck = true
+	}
+
+	fmt.Printf("Uploading etcd snapshot from %s to node %s\n", recovered.source, cr.Spec.ForProvider.Node)
+
+	if _, err := talosClient.EtcdRecover(talosclient.WithNode(ctx, cr.Spec.ForProvider.Node), snapshot); err != nil {
+		return nil, errors.Wrap(err, "failed to upload etcd snapshot")
+	}
+
+	return recovered, nil
+}
+
+func (c *external) openRecoverySnapshot(ctx context.Context, cr *v1alpha1.Bootstrap, talosClient bootstrapClient) (io.ReadCloser, *recoveredSnapshot, error) {
+	recovery := cr.Spec.ForProvider.Recovery
+
+	switch {
+	case recovery.SnapshotSecretRef != nil:
+		ref := recovery.SnapshotSecretRef
+		secret := &corev1.Secret{}
+		if err := c.kube.Get(ctx, types.NamespacedName{Namespace: ref.Namespace, Name: ref.Name}, secret); err != nil {
+			return nil, nil, errors.Wrapf(err, "cannot get etcd snapshot Secret %s/%s", ref.Namespace, ref.Name)
+		}
+		data, ok := secret.Data[ref.Key]
+		if !ok || len(data) == 0 {
+			return nil, nil, errors.Errorf("etcd snapshot Secret %s/%s has no %s key", ref.Namespace, ref.Name, ref.Key)
+		}
+
+		location := fmt.Sprintf("%s/%s/%s", ref.Namespace, ref.Name, ref.Key)
+		return io.NopCloser(bytes.NewReader(data)), &recoveredSnapshot{
+			source:   recoverySourceSecret + ":" + location,
+			location: location,
+		}, nil
+
+	case recovery.EtcdSnapshotRef != nil:
+		return c.openEtcdSnapshot(ctx, recovery.EtcdSnapshotRef.Name)
+
+	case recovery.DataDirectory != nil:
+		node := cr.Spec.ForProvider.Node
+		if recovery.DataDirectory.Node != nil && *recovery.DataDirectory.Node != "" {
+			node = *recovery.DataDirectory.Node
+		}
+
+		snapshot, err := copyEtcdDatabase(talosclient.WithNode(ctx, node), talosClient)
+		if err != nil {
+			return nil, nil, errors.Wrapf(err, "cannot copy etcd database from node %s", node)
+		}
+
+		return snapshot, &recoveredSnapshot{
+			source:        recoverySourceDataDirectory + ":" + node,
+			location:      node + ":" + etcdDatabasePath,
+			skipHashCheck: true,
+		}, nil
+
+	default:
+		return nil, nil, errors.New("recovery must set one of snapshotSecretRef, etcdSnapshotRef or dataDirectory")
+	}
+}
+
+// openEtcdSnapshot downloads the last snapshot of an EtcdSnapshot and verifies
+// its hash before it is uploaded to the node.
+func (c *external) openEtcdSnapshot(ctx context.Context, name string) (io.ReadCloser, *recoveredSnapshot, error) {
+	es := &v1alpha1.EtcdSnapshot{}
+	if err := c.kube.Get(ctx, types.NamespacedName{Name: name}, es); err != nil {
+		return nil, nil, errors.Wrapf(err, "cannot get EtcdSnapshot %s", name)
+	}
+
+	last := es.Status.AtProvider.LastSnapshot
+	if last == nil {
+		return nil, nil, errors.Errorf("EtcdSnapshot %s has not taken a snapshot yet", name)
+	}
+
+	stored, err := snapshotstore.Open(ctx, c.kube, es, last.Location)
+	if err != nil {
+		return nil, nil, errors.Wrapf(err, "cannot open snapshot %s", last.Location)
+	}
+	defer stored.Close() //nolint:errcheck
+
+	file, err := spool(stored, last.Hash)
+	if err != nil {
+		return nil, nil, errors.Wrapf(err, "cannot download snapshot %s", last.Location)
+	}
+
+	return file, &recoveredSnapshot{
+		source:   recoverySourceEtcdSnapshot + ":" + name,
+		location: last.Location,
+	}, nil
+}
+
+// copyEtcdDatabase copies the etcd database out of a node's data directory.
+// The Talos copy API streams a gzipped tarball.
+func copyEtcdDatabase(ctx context.Context, talosClient bootstrapClient) (io.ReadCloser, error) {
+	stream, err := talosClient.Copy(ctx, etcdDatabasePath)
+	if err != nil {
+		return nil, err
+	}
+	defer stream.Close() //nolint:errcheck
+
+	gz, err := gzip.NewReader(stream)
+	if err != nil {
+		return nil, errors.Wrap(err, "cannot read copy stream")
+	}
+	defer gz.Close() //nolint:errcheck
+
+	tr := tar.NewReader(gz)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			return nil, errors.Errorf("%s not found on node", etcdDatabasePath)
+		}
+		if err != nil {
+			return nil, errors.Wrap(err, "cannot read copy stream")
+		}
+		if hdr.Typeflag == tar.TypeReg && path.Base(hdr.Name) == path.Base(etcdDatabasePath) {
+			return spool(tr, "")
+		}
+	}
+}
+
+// spool writes r to a temporary file that is removed when closed, verifying
+// its SHA-256 against hash unless hash is empty.
+func spool(r io.Reader, hash string) (io.ReadCloser, error) {
+	file, err := os.CreateTemp("", "etcd-recovery-*.db")
+	if err != nil {
+		return nil, errors.Wrap(err, "cannot create temporary snapshot file")
+	}
+	tmp := &tempFile{File: file}
+
+	h := sha256.New()
+	if _, err := io.Copy(io.MultiWriter(file, h), r); err != nil {
+		tmp.Close() //nolint:errcheck
+		return nil, errors.Wrap(err, "cannot write temporary snapshot file")
+	}
+	if sum := hex.EncodeToString(h.Sum(nil)); hash != "" && sum != hash {
+		tmp.Close() //nolint:errcheck
+		return nil, errors.Errorf("snapshot hash %s does not match recorded hash %s", sum, hash)
+	}
+	if _, err := file.Seek(0, io.SeekStart); err != nil {
+		tmp.Close() //nolint:errcheck
+		return nil, errors.Wrap(err, "cannot rewind temporary snapshot file")
+	}
+
+	return tmp, nil
+}
+
+// A tempFile is removed when it is closed.
+type tempFile struct {
+	*os.File
+}
+
+func (f *tempFile) Close() error {
+	err := f.File.Close()
+	if rmErr := os.Remove(f.Name()); rmErr != nil && err == nil {
+		err = rmErr
+	}
+
+	return err
+}
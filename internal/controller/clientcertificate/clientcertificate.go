@@ -0,0 +1,424 @@
+/*
+Copyright 2025 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package clientcertificate
+
+import (
+	"bytes"
+	"context"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"slices"
+	"time"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/siderolabs/talos/pkg/machinery/constants"
+	"github.com/siderolabs/talos/pkg/machinery/role"
+
+	"github.com/crossplane/crossplane-runtime/pkg/feature"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	ctrlclient "sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane/crossplane-runtime/pkg/connection"
+	"github.com/crossplane/crossplane-runtime/pkg/controller"
+	"github.com/crossplane/crossplane-runtime/pkg/event"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/pkg/ratelimiter"
+	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/crossplane/crossplane-runtime/pkg/statemetrics"
+
+	"github.com/crossplane-contrib/provider-talos/apis/machine/v1alpha1"
+	apisv1alpha1 "github.com/crossplane-contrib/provider-talos/apis/v1alpha1"
+	"github.com/crossplane-contrib/provider-talos/internal/features"
+	"github.com/crossplane-contrib/provider-talos/internal/secrets"
+
+	talossecrets "github.com/siderolabs/talos/pkg/machinery/config/generate/secrets"
+)
+
+const (
+	errNotClientCertificate = "managed resource is not a ClientCertificate custom resource"
+	errTrackPCUsage         = "cannot track ProviderConfig usage"
+	errGetPC                = "cannot get ProviderConfig"
+	errGetCreds             = "cannot get credentials"
+
+	errNewClient = "cannot create new Service"
+
+	connectionKeyMachineSecrets       = "machine_secrets"
+	connectionKeyMachineSecretsBundle = "machine_secrets_bundle"
+	connectionKeyCACertificate        = "ca_certificate"
+	connectionKeyClientCertificate    = "client_certificate"
+	connectionKeyClientKey            = "client_key"
+	connectionKeyTalosConfig          = "talos_config"
+
+	// defaultRenewBefore is how long before expiry the certificate is
+	// reissued when no renewal window is configured. Shorter certificate
+	// lifetimes are renewed after two thirds of their validity.
+	defaultRenewBefore = 30 * 24 * time.Hour
+)
+
+// A NoOpService does nothing.
+type NoOpService struct{}
+
+var (
+	newNoOpService = func(_ []byte) (interface{}, error) { return &NoOpService{}, nil }
+)
+
+// Setup adds a controller that reconciles ClientCertificate managed resources.
+func Setup(mgr ctrl.Manager, o controller.Options) error {
+	name := managed.ControllerName(v1alpha1.ClientCertificateGroupKind)
+
+	cps := []managed.ConnectionPublisher{managed.NewAPISecretPublisher(mgr.GetClient(), mgr.GetScheme())}
+	if o.Features.Enabled(features.EnableAlphaExternalSecretStores) {
+		cps = append(cps, connection.NewDetailsManager(mgr.GetClient(), apisv1alpha1.StoreConfigGroupVersionKind))
+	}
+
+	opts := []managed.ReconcilerOption{
+		managed.WithExternalConnecter(&connector{
+			kube:         mgr.GetClient(),
+			usage:        resource.NewProviderConfigUsageTracker(mgr.GetClient(), &apisv1alpha1.ProviderConfigUsage{}),
+			newServiceFn: newNoOpService}),
+		managed.WithLogger(o.Logger.WithValues("controller", name)),
+		managed.WithPollInterval(o.PollInterval),
+		managed.WithRecorder(event.NewAPIRecorder(mgr.GetEventRecorderFor(name))),
+		managed.WithConnectionPublishers(cps...),
+		managed.WithManagementPolicies(),
+	}
+
+	if o.Features.Enabled(feature.EnableAlphaChangeLogs) {
+		opts = append(opts, managed.WithChangeLogger(o.ChangeLogOptions.ChangeLogger))
+	}
+
+	if o.MetricOptions != nil {
+		opts = append(opts, managed.WithMetricRecorder(o.MetricOptions.MRMetrics))
+	}
+
+	if o.MetricOptions != nil && o.MetricOptions.MRStateMetrics != nil {
+		stateMetricsRecorder := statemetrics.NewMRStateRecorder(
+			mgr.GetClient(), o.Logger, o.MetricOptions.MRStateMetrics, &v1alpha1.ClientCertificateList{}, o.MetricOptions.PollStateMetricInterval,
+		)
+		if err := mgr.Add(stateMetricsRecorder); err != nil {
+			return errors.Wrap(err, "cannot register MR state metrics recorder for kind v1alpha1.ClientCertificateList")
+		}
+	}
+
+	r := managed.NewReconciler(mgr, resource.ManagedKind(v1alpha1.ClientCertificateGroupVersionKind), opts...)
+
+	return ctrl.NewControllerManagedBy(mgr).
+		Named(name).
+		WithOptions(o.ForControllerRuntime()).
+		WithEventFilter(resource.DesiredStateChanged()).
+		For(&v1alpha1.ClientCertificate{}).
+		Complete(ratelimiter.NewReconciler(name, r, o.GlobalRateLimiter))
+}
+
+// A connector is expected to produce an ExternalClient when its Connect method
+// is called.
+type connector struct {
+	kube         ctrlclient.Client
+	usage        resource.Tracker
+	newServiceFn func(creds []byte) (interface{}, error)
+}
+
+// Connect typically produces an ExternalClient by:
+// 1. Tracking that the managed resource is using a ProviderConfig.
+// 2. Getting the managed resource's ProviderConfig.
+// 3. Getting the credentials specified by the ProviderConfig.
+// 4. Using the credentials to form a client.
+func (c *connector) Connect(ctx context.Context, mg resource.Managed) (managed.ExternalClient, error) {
+	cr, ok := mg.(*v1alpha1.ClientCertificate)
+	if !ok {
+		return nil, errors.New(errNotClientCertificate)
+	}
+
+	if err := c.usage.Track(ctx, mg); err != nil {
+		return nil, errors.Wrap(err, errTrackPCUsage)
+	}
+
+	pc := &apisv1alpha1.ProviderConfig{}
+	if err := c.kube.Get(ctx, types.NamespacedName{Name: cr.GetProviderConfigReference().Name}, pc); err != nil {
+		return nil, errors.Wrap(err, errGetPC)
+	}
+
+	cd := pc.Spec.Credentials
+	data, err := resource.CommonCredentialExtractor(ctx, cd.Source, c.kube, cd.CommonCredentialSelectors)
+	if err != nil {
+		return nil, errors.Wrap(err, errGetCreds)
+	}
+
+	svc, err := c.newServiceFn(data)
+	if err != nil {
+		return nil, errors.Wrap(err, errNewClient)
+	}
+
+	return &external{kube: c.kube, service: svc}, nil
+}
+
+// An ExternalClient observes, then either creates, updates, or deletes an
+// external resource to ensure it reflects the managed resource's desired state.
+type external struct {
+	kube ctrlclient.Client
+	// A 'client' used to connect to the external resource API. In practice this
+	// would be something like an AWS SDK client.
+	service interface{}
+}
+
+func (c *external) Observe(ctx context.Context, mg resource.Managed) (managed.ExternalObservation, error) {
+	cr, ok := mg.(*v1alpha1.ClientCertificate)
+	if !ok {
+		return managed.ExternalObservation{}, errors.New(errNotClientCertificate)
+	}
+
+	fmt.Printf("Observing ClientCertificate: %s\n", cr.Name)
+
+	// Issued certificates cannot be revoked; they are left to expire.
+	if meta.WasDeleted(cr) {
+		return managed.ExternalObservation{ResourceExists: false}, nil
+	}
+
+	connectionDetails, err := c.publishedConnectionDetails(ctx, cr)
+	if err != nil {
+		return managed.ExternalObservation{}, err
+	}
+	if connectionDetails == nil {
+		return managed.ExternalObservation{ResourceExists: false}, nil
+	}
+
+	_, trustedCAs, talosconfigOptions, err := c.secretsBundle(ctx, cr)
+	if err != nil {
+		return managed.ExternalObservation{}, err
+	}
+
+	renewalTime, err := observeCertificate(cr, connectionDetails[connectionKeyClientCertificate])
+	if err != nil {
+		return managed.ExternalObservation{}, err
+	}
+
+	talosConfig, err := secrets.MarshalTalosconfig(&v1alpha1.ClientConfiguration{
+		CACertificate:     string(connectionDetails[connectionKeyCACertificate]),
+		ClientCertificate: string(connectionDetails[connectionKeyClientCertificate]),
+		ClientKey:         string(connectionDetails[connectionKeyClientKey]),
+	}, talosconfigOptions)
+	if err != nil {
+		return managed.ExternalObservation{}, err
+	}
+
+	// The certificate is reissued when it is due for renewal, when the roles
+	// change, when the Secrets' trusted OS CAs change, e.g. during a CA
+	// rotation, or when the Secrets' talosconfig options change.
+	upToDate := time.Now().Before(renewalTime) &&
+		slices.Equal(cr.Status.AtProvider.Roles, desiredRoles(cr).Strings()) &&
+		bytes.Equal(connectionDetails[connectionKeyCACertificate], trustedCAs) &&
+		bytes.Equal(connectionDetails[connectionKeyTalosConfig], talosConfig)
+
+	cr.SetConditions(xpv1.Available())
+
+	return managed.ExternalObservation{
+		ResourceExists:    true,
+		ResourceUpToDate:  upToDate,
+		ConnectionDetails: connectionDetails,
+	}, nil
+}
+
+func (c *external) Create(ctx context.Context, mg resource.Managed) (managed.ExternalCreation, error) {
+	cr, ok := mg.(*v1alpha1.ClientCertificate)
+	if !ok {
+		return managed.ExternalCreation{}, errors.New(errNotClientCertificate)
+	}
+
+	fmt.Printf("Issuing ClientCertificate: %s\n", cr.Name)
+
+	connectionDetails, err := c.issue(ctx, cr)
+	if err != nil {
+		return managed.ExternalCreation{}, err
+	}
+
+	return managed.ExternalCreation{ConnectionDetails: connectionDetails}, nil
+}
+
+func (c *external) Update(ctx context.Context, mg resource.Managed) (managed.ExternalUpdate, error) {
+	cr, ok := mg.(*v1alpha1.ClientCertificate)
+	if !ok {
+		return managed.ExternalUpdate{}, errors.New(errNotClientCertificate)
+	}
+
+	fmt.Printf("Reissuing ClientCertificate: %s\n", cr.Name)
+
+	connectionDetails, err := c.issue(ctx, cr)
+	if err != nil {
+		return managed.ExternalUpdate{}, err
+	}
+
+	return managed.ExternalUpdate{ConnectionDetails: connectionDetails}, nil
+}
+
+func (c *external) Delete(ctx context.Context, mg resource.Managed) (managed.ExternalDelete, error) {
+	_, ok := mg.(*v1alpha1.ClientCertificate)
+	if !ok {
+		return managed.ExternalDelete{}, errors.New(errNotClientCertificate)
+	}
+
+	// Talos has no certificate revocation; the published connection secret is
+	// removed by the managed reconciler.
+	return managed.ExternalDelete{}, nil
+}
+
+func (c *external) Disconnect(ctx context.Context) error {
+	return nil
+}
+
+// issue issues a certificate with the desired roles and TTL from the OS CA of
+// the referenced Secrets.
+func (c *external) issue(ctx context.Context, cr *v1alpha1.ClientCertificate) (managed.ConnectionDetails, error) {
+	bundle, trustedCAs, talosconfigOptions, err := c.secretsBundle(ctx, cr)
+	if err != nil {
+		return nil, err
+	}
+
+	clientConfiguration, err := secrets.GenerateRoleClientConfiguration(bundle, desiredRoles(cr), ttl(cr))
+	if err != nil {
+		return nil, err
+	}
+	clientConfiguration.CACertificate = string(trustedCAs)
+	connectionDetails, err := secrets.ClientConnectionDetails(clientConfiguration, talosconfigOptions)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := observeCertificate(cr, connectionDetails[connectionKeyClientCertificate]); err != nil {
+		return nil, err
+	}
+	now := metav1.Now()
+	cr.Status.AtProvider.IssuedTime = &now
+
+	return connectionDetails, nil
+}
+
+// publishedConnectionDetails returns the connection details published by a
+// previous reconcile, or nil if no certificate has been published yet.
+func (c *external) publishedConnectionDetails(ctx context.Context, cr *v1alpha1.ClientCertificate) (managed.ConnectionDetails, error) {
+	ref := cr.Spec.WriteConnectionSecretToReference
+	if ref == nil {
+		return nil, errors.New("writeConnectionSecretToRef is required to publish the talosconfig")
+	}
+
+	secret := &corev1.Secret{}
+	if err := c.kube.Get(ctx, types.NamespacedName{Name: ref.Name, Namespace: ref.Namespace}, secret); err != nil {
+		if kerrors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, errors.Wrapf(err, "cannot get connection secret %s/%s", ref.Namespace, ref.Name)
+	}
+	if len(secret.Data[connectionKeyClientCertificate]) == 0 || len(secret.Data[connectionKeyTalosConfig]) == 0 {
+		return nil, nil
+	}
+
+	return managed.ConnectionDetails(secret.Data), nil
+}
+
+// secretsBundle loads the machine secrets published by the referenced
+// Secrets, the OS CAs its clients trust, and the options of its talosconfig
+// that issued talosconfigs share.
+func (c *external) secretsBundle(ctx context.Context, cr *v1alpha1.ClientCertificate) (*talossecrets.Bundle, []byte, secrets.TalosconfigOptions, error) {
+	name := cr.Spec.ForProvider.SecretsRef.Name
+	secretsResource := &v1alpha1.Secrets{}
+	if err := c.kube.Get(ctx, types.NamespacedName{Name: name}, secretsResource); err != nil {
+		return nil, nil, secrets.TalosconfigOptions{}, errors.Wrapf(err, "cannot get referenced Secrets %s", name)
+	}
+	talosconfigOptions := secrets.SecretsTalosconfigOptions(secretsResource)
+	ref := secretsResource.Spec.WriteConnectionSecretToReference
+	if ref == nil {
+		return nil, nil, talosconfigOptions, errors.Errorf("referenced Secrets %s must define writeConnectionSecretToRef", name)
+	}
+
+	connectionSecret := &corev1.Secret{}
+	if err := c.kube.Get(ctx, types.NamespacedName{Name: ref.Name, Namespace: ref.Namespace}, connectionSecret); err != nil {
+		return nil, nil, talosconfigOptions, errors.Wrapf(err, "cannot get referenced Secrets connection secret %s/%s", ref.Namespace, ref.Name)
+	}
+
+	for _, key := range []string{connectionKeyMachineSecrets, connectionKeyMachineSecretsBundle} {
+		if data := connectionSecret.Data[key]; len(data) > 0 {
+			bundle, err := secrets.ParseSecretsBundle(data)
+			if err != nil {
+				return nil, nil, talosconfigOptions, errors.Wrapf(err, "cannot decode referenced Secrets %s", name)
+			}
+			trustedCAs, err := secrets.TrustedOSCAs(bundle.Certs.OS.Crt, connectionSecret.Data)
+			if err != nil {
+				return nil, nil, talosconfigOptions, errors.Wrapf(err, "cannot decode CA rotation of referenced Secrets %s", name)
+			}
+			return bundle, trustedCAs, talosconfigOptions, nil
+		}
+	}
+
+	return nil, nil, talosconfigOptions, errors.Errorf("referenced Secrets connection secret %s/%s has no machine secrets", ref.Namespace, ref.Name)
+}
+
+// observeCertificate records the roles and expiry of a certificate in status
+// and returns when it is due for renewal.
+func observeCertificate(cr *v1alpha1.ClientCertificate, clientCertificate []byte) (time.Time, error) {
+	certTTL := ttl(cr)
+	before := renewBefore(cr)
+	if before >= certTTL {
+		return time.Time{}, errors.Errorf("renewBefore (%s) must be shorter than ttl (%s)", before, certTTL)
+	}
+
+	block, _ := pem.Decode(clientCertificate)
+	if block == nil {
+		return time.Time{}, errors.New("client certificate is not PEM encoded")
+	}
+	cert, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		return time.Time{}, errors.Wrap(err, "failed to parse client certificate")
+	}
+
+	roles, _ := role.Parse(cert.Subject.Organization)
+	renewalTime := cert.NotAfter.Add(-before)
+	notAfter := metav1.NewTime(cert.NotAfter)
+	renewal := metav1.NewTime(renewalTime)
+	cr.Status.AtProvider.Roles = roles.Strings()
+	cr.Status.AtProvider.NotAfter = &notAfter
+	cr.Status.AtProvider.RenewalTime = &renewal
+
+	return renewalTime, nil
+}
+
+func desiredRoles(cr *v1alpha1.ClientCertificate) role.Set {
+	roles, _ := role.Parse(cr.Spec.ForProvider.Roles)
+	return roles
+}
+
+func ttl(cr *v1alpha1.ClientCertificate) time.Duration {
+	if t := cr.Spec.ForProvider.TTL; t != nil && t.Duration > 0 {
+		return t.Duration
+	}
+
+	return constants.TalosAPIDefaultCertificateValidityDuration
+}
+
+func renewBefore(cr *v1alpha1.ClientCertificate) time.Duration {
+	if before := cr.Spec.ForProvider.RenewBefore; before != nil && before.Duration > 0 {
+		return before.Duration
+	}
+
+	return min(defaultRenewBefore, ttl(cr)/3)
+}
@@ -0,0 +1,250 @@
+/*
+Copyright 2025 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package clientcertificate
+
+import (
+	"context"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"testing"
+	"time"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
+	"github.com/google/go-cmp/cmp"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrlclient "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/crossplane-contrib/provider-talos/apis/machine/v1alpha1"
+	"github.com/crossplane-contrib/provider-talos/internal/secrets"
+
+	talossecrets "github.com/siderolabs/talos/pkg/machinery/config/generate/secrets"
+)
+
+// Unlike many Kubernetes projects Crossplane does not use third party testing
+// libraries, per the common Go test review comments. Crossplane encourages the
+// use of table driven unit tests. The tests of the crossplane-runtime project
+// are representative of the testing style Crossplane encourages.
+//
+// https://github.com/golang/go/wiki/TestComments
+// https://github.com/crossplane/crossplane/blob/master/CONTRIBUTING.md#contributing-code
+
+// testSecrets returns a Secrets resource and its connection secret holding a
+// newly generated bundle.
+func testSecrets(t *testing.T) (*talossecrets.Bundle, []ctrlclient.Object) {
+	t.Helper()
+
+	bundle, err := talossecrets.NewBundle(talossecrets.NewClock(), nil)
+	if err != nil {
+		t.Fatalf("talossecrets.NewBundle(...): %v", err)
+	}
+	machineSecrets, err := secrets.SecretsBundleToMachineSecrets(bundle)
+	if err != nil {
+		t.Fatalf("SecretsBundleToMachineSecrets(...): %v", err)
+	}
+	machineSecretsJSON, err := json.Marshal(machineSecrets)
+	if err != nil {
+		t.Fatalf("json.Marshal(...): %v", err)
+	}
+
+	return bundle, []ctrlclient.Object{
+		&v1alpha1.Secrets{
+			ObjectMeta: metav1.ObjectMeta{Name: "cluster-secrets"},
+			Spec: v1alpha1.SecretsSpec{
+				ResourceSpec: xpv1.ResourceSpec{WriteConnectionSecretToReference: &xpv1.SecretReference{Name: "cluster-secrets", Namespace: "default"}},
+			},
+		},
+		&corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "cluster-secrets", Namespace: "default"},
+			Data:       map[string][]byte{connectionKeyMachineSecrets: machineSecretsJSON},
+		},
+	}
+}
+
+func testClientCertificate(roles ...string) *v1alpha1.ClientCertificate {
+	return &v1alpha1.ClientCertificate{
+		ObjectMeta: metav1.ObjectMeta{Name: "backup"},
+		Spec: v1alpha1.ClientCertificateSpec{
+			ResourceSpec: xpv1.ResourceSpec{WriteConnectionSecretToReference: &xpv1.SecretReference{Name: "backup-talosconfig", Namespace: "default"}},
+			ForProvider: v1alpha1.ClientCertificateParameters{
+				SecretsRef: xpv1.Reference{Name: "cluster-secrets"},
+				Roles:      roles,
+				TTL:        &metav1.Duration{Duration: 24 * time.Hour},
+			},
+		},
+	}
+}
+
+func testClient(t *testing.T, objs ...ctrlclient.Object) ctrlclient.Client {
+	t.Helper()
+
+	scheme := runtime.NewScheme()
+	if err := corev1.AddToScheme(scheme); err != nil {
+		t.Fatalf("corev1.AddToScheme(...): %v", err)
+	}
+	if err := v1alpha1.SchemeBuilder.AddToScheme(scheme); err != nil {
+		t.Fatalf("v1alpha1.AddToScheme(...): %v", err)
+	}
+
+	return fake.NewClientBuilder().WithScheme(scheme).WithObjects(objs...).Build()
+}
+
+func connectionSecret(cr *v1alpha1.ClientCertificate, details managed.ConnectionDetails) *corev1.Secret {
+	ref := cr.Spec.WriteConnectionSecretToReference
+	return &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: ref.Name, Namespace: ref.Namespace},
+		Data:       map[string][]byte(details),
+	}
+}
+
+func TestCreate(t *testing.T) {
+	bundle, objs := testSecrets(t)
+	cr := testClientCertificate("os:reader", "os:etcd:backup")
+
+	got, err := (&external{kube: testClient(t, objs...)}).Create(context.Background(), cr)
+	if err != nil {
+		t.Fatalf("Create(...): %v", err)
+	}
+
+	block, _ := pem.Decode(got.ConnectionDetails[connectionKeyClientCertificate])
+	if block == nil {
+		t.Fatal("Create(...): client_certificate is not PEM encoded")
+	}
+	cert, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		t.Fatalf("x509.ParseCertificate(...): %v", err)
+	}
+	if diff := cmp.Diff([]string{"os:etcd:backup", "os:reader"}, cr.Status.AtProvider.Roles); diff != "" {
+		t.Errorf("Create(...): status roles -want, +got:\n%s", diff)
+	}
+	if d := time.Until(cert.NotAfter); d <= 23*time.Hour || d > 24*time.Hour {
+		t.Errorf("Create(...): certificate expires in %s, want about 24h", d)
+	}
+
+	roots := x509.NewCertPool()
+	roots.AppendCertsFromPEM(bundle.Certs.OS.Crt)
+	if _, err := cert.Verify(x509.VerifyOptions{Roots: roots, KeyUsages: []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth}}); err != nil {
+		t.Errorf("Create(...): certificate is not issued by the OS CA: %v", err)
+	}
+	if len(got.ConnectionDetails[connectionKeyTalosConfig]) == 0 {
+		t.Error("Create(...): expected talos_config connection detail")
+	}
+	if cr.Status.AtProvider.IssuedTime == nil {
+		t.Error("Create(...): expected IssuedTime")
+	}
+}
+
+func TestObserve(t *testing.T) {
+	_, objs := testSecrets(t)
+	issued := testClientCertificate("os:reader")
+	details, err := (&external{kube: testClient(t, objs...)}).Create(context.Background(), issued)
+	if err != nil {
+		t.Fatalf("Create(...): %v", err)
+	}
+	_, otherObjs := testSecrets(t)
+
+	type want struct {
+		o   managed.ExternalObservation
+		err bool
+	}
+
+	cases := map[string]struct {
+		reason  string
+		cr      *v1alpha1.ClientCertificate
+		objs    []ctrlclient.Object
+		publish bool
+		want    want
+	}{
+		"NotIssued": {
+			reason: "A certificate that has not been published yet should not exist.",
+			cr:     testClientCertificate("os:reader"),
+			objs:   objs,
+			want:   want{o: managed.ExternalObservation{ResourceExists: false}},
+		},
+		"UpToDate": {
+			reason:  "A published certificate with the desired roles outside its renewal window should be up to date.",
+			cr:      testClientCertificate("os:reader"),
+			objs:    objs,
+			publish: true,
+			want:    want{o: managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: true}},
+		},
+		"RolesChanged": {
+			reason:  "A certificate whose roles differ from the desired roles should be reissued.",
+			cr:      testClientCertificate("os:operator"),
+			objs:    objs,
+			publish: true,
+			want:    want{o: managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: false}},
+		},
+		"RenewalDue": {
+			reason: "A certificate inside its renewal window should be reissued.",
+			cr: func() *v1alpha1.ClientCertificate {
+				cr := testClientCertificate("os:reader")
+				cr.Spec.ForProvider.TTL = &metav1.Duration{Duration: 48 * time.Hour}
+				cr.Spec.ForProvider.RenewBefore = &metav1.Duration{Duration: 36 * time.Hour}
+				return cr
+			}(),
+			objs:    objs,
+			publish: true,
+			want:    want{o: managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: false}},
+		},
+		"CAChanged": {
+			reason:  "A certificate not issued by the referenced Secrets' OS CA should be reissued.",
+			cr:      testClientCertificate("os:reader"),
+			objs:    otherObjs,
+			publish: true,
+			want:    want{o: managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: false}},
+		},
+		"RenewBeforeTooLong": {
+			reason: "A renewal window longer than the TTL should be rejected.",
+			cr: func() *v1alpha1.ClientCertificate {
+				cr := testClientCertificate("os:reader")
+				cr.Spec.ForProvider.RenewBefore = &metav1.Duration{Duration: 48 * time.Hour}
+				return cr
+			}(),
+			objs:    objs,
+			publish: true,
+			want:    want{err: true},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			kubeObjs := append([]ctrlclient.Object{}, tc.objs...)
+			if tc.publish {
+				kubeObjs = append(kubeObjs, connectionSecret(tc.cr, details.ConnectionDetails))
+			}
+
+			got, err := (&external{kube: testClient(t, kubeObjs...)}).Observe(context.Background(), tc.cr)
+			if tc.want.err {
+				if err == nil {
+					t.Fatalf("\n%s\nObserve(...): expected error", tc.reason)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("\n%s\nObserve(...): %v", tc.reason, err)
+			}
+			if got.ResourceExists != tc.want.o.ResourceExists || got.ResourceUpToDate != tc.want.o.ResourceUpToDate {
+				t.Errorf("\n%s\nObserve(...): exists %t, up to date %t, want %t, %t", tc.reason, got.ResourceExists, got.ResourceUpToDate, tc.want.o.ResourceExists, tc.want.o.ResourceUpToDate)
+			}
+		})
+	}
+}
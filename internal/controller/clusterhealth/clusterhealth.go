@@ -12,18 +12,14 @@ package clusterhealth
 
 import (
 	"context"
-	"crypto/tls"
-	"crypto/x509"
 	stderrors "errors"
 	"fmt"
 	"io"
 	"strings"
 	"time"
 
-	siderox509 "github.com/siderolabs/crypto/x509"
 	clusterapi "github.com/siderolabs/talos/pkg/machinery/api/cluster"
 	talosclient "github.com/siderolabs/talos/pkg/machinery/client"
-	clientconfig "github.com/siderolabs/talos/pkg/machinery/client/config"
 	"google.golang.org/grpc/status"
 
 	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
@@ -42,7 +38,9 @@ import (
 	ctrlclient "sigs.k8s.io/controller-runtime/pkg/client"
 
 	"github.com/crossplane-contrib/provider-talos/apis/cluster/v1alpha1"
+	machinev1alpha1 "github.com/crossplane-contrib/provider-talos/apis/machine/v1alpha1"
 	apisv1alpha1 "github.com/crossplane-contrib/provider-talos/apis/v1alpha1"
+	"github.com/crossplane-contrib/provider-talos/internal/clients"
 	"github.com/crossplane-contrib/provider-talos/internal/features"
 )
 
@@ -60,7 +58,8 @@ type NoOpService struct{}
 var newNoOpService = func(_ []byte) (interface{}, error) { return &NoOpService{}, nil }
 
 // Setup adds a controller that reconciles ClusterHealth managed resources.
-func Setup(mgr ctrl.Manager, o controller.Options) error {
+// Talos API clients are borrowed from the supplied cache.
+func Setup(mgr ctrl.Manager, o controller.Options, talosClients *clients.Cache) error {
 	name := managed.ControllerName(v1alpha1.ClusterHealthGroupKind)
 
 	cps := []managed.ConnectionPublisher{managed.NewAPISecretPublisher(mgr.GetClient(), mgr.GetScheme())}
@@ -69,7 +68,7 @@ func Setup(mgr ctrl.Manager, o controller.Options) error {
 	}
 
 	opts := []managed.ReconcilerOption{
-		managed.WithExternalConnecter(&connector{kube: mgr.GetClient(), usage: resource.NewProviderConfigUsageTracker(mgr.GetClient(), &apisv1alpha1.ProviderConfigUsage{}), newServiceFn: newNoOpService}),
+		managed.WithExternalConnecter(&connector{kube: mgr.GetClient(), usage: resource.NewProviderConfigUsageTracker(mgr.GetClient(), &apisv1alpha1.ProviderConfigUsage{}), newServiceFn: newNoOpService, talosClients: talosClients}),
 		managed.WithLogger(o.Logger.WithValues("controller", name)),
 		managed.WithPollInterval(o.PollInterval),
 		managed.WithRecorder(event.NewAPIRecorder(mgr.GetEventRecorderFor(name))),
@@ -104,6 +103,7 @@ type connector struct {
 	kube         ctrlclient.Client
 	usage        resource.Tracker
 	newServiceFn func(creds []byte) (interface{}, error)
+	talosClients *clients.Cache
 }
 
 func (c *connector) Connect(ctx context.Context, mg resource.Managed) (managed.ExternalClient, error) {
@@ -127,12 +127,13 @@ func (c *connector) Connect(ctx context.Context, mg resource.Managed) (managed.E
 	if err != nil {
 		return nil, errors.Wrap(err, errNewClient)
 	}
-	return &external{kube: c.kube, service: svc}, nil
+	return &external{kube: c.kube, service: svc, talosClients: c.talosClients}, nil
 }
 
 type external struct {
 	kube                 ctrlclient.Client
 	service              interface{}
+	talosClients         *clients.Cache
 	checkClusterHealthFn func(context.Context, *v1alpha1.ClusterHealth) (bool, string, error)
 }
 
@@ -194,45 +195,43 @@ func (c *external) checkClusterHealth(ctx context.Context, cr *v1alpha1.ClusterH
 	if c.checkClusterHealthFn != nil {
 		return c.checkClusterHealthFn(ctx, cr)
 	}
-	return checkClusterHealth(ctx, cr)
+	return c.checkTalosClusterHealth(ctx, cr)
 }
 
-func checkClusterHealth(ctx context.Context, cr *v1alpha1.ClusterHealth) (bool, string, error) {
+func (c *external) checkTalosClusterHealth(ctx context.Context, cr *v1alpha1.ClusterHealth) (bool, string, error) {
 	if err := validateClusterHealthSpec(cr); err != nil {
 		return false, "", err
 	}
-	cfg, err := buildClusterHealthClientConfig(cr.Spec.ForProvider.ClientConfiguration)
+	clientConfig, err := clients.ResolveClientConfiguration(ctx, c.kube, clients.ClientConfigurationSource{
+		ClientConfiguration: (*machinev1alpha1.ClientConfiguration)(cr.Spec.ForProvider.ClientConfiguration),
+		SecretRef:           cr.Spec.ForProvider.ClientConfigurationSecretRef,
+		SecretsRef:          cr.Spec.ForProvider.SecretsRef,
+	})
 	if err != nil {
 		return false, "", err
 	}
+	if _, err := clients.TalosConfig(clientConfig); err != nil {
+		return false, "", err
+	}
 
 	checkCtx, cancel := context.WithTimeout(ctx, 15*time.Second)
 	defer cancel()
 
-	client, message := newClusterHealthClient(checkCtx, cfg, cr.Spec.ForProvider.Endpoints)
-	if client == nil {
-		return false, message, nil
+	client, err := c.talosClients.Client(checkCtx, cr.Spec.ForProvider.Endpoints, clientConfig)
+	if err != nil {
+		return false, fmt.Sprintf("waiting for Talos API: %v", err), nil
 	}
 	defer client.Close() //nolint:errcheck
 
 	if cr.Spec.ForProvider.SkipKubernetesChecks != nil && *cr.Spec.ForProvider.SkipKubernetesChecks {
-		healthy, message := checkNodeServices(checkCtx, client, allNodes(cr))
+		healthy, message := checkNodeServices(checkCtx, client.Client, allNodes(cr))
 		return healthy, message, nil
 	}
 
-	healthy, message := checkFullClusterHealth(checkCtx, client, cr)
+	healthy, message := checkFullClusterHealth(checkCtx, client.Client, cr)
 	return healthy, message, nil
 }
 
-func newClusterHealthClient(ctx context.Context, cfg *clientconfig.Config, endpoints []string) (*talosclient.Client, string) {
-	client, err := talosclient.New(ctx, talosclient.WithConfig(cfg), talosclient.WithEndpoints(endpoints...))
-	if err != nil {
-		return nil, fmt.Sprintf("waiting for Talos API: %v", err)
-	}
-
-	return client, ""
-}
-
 func allNodes(cr *v1alpha1.ClusterHealth) []string {
 	nodes := append([]string{}, cr.Spec.ForProvider.ControlPlaneNodes...)
 
@@ -240,7 +239,14 @@ func allNodes(cr *v1alpha1.ClusterHealth) []string {
 }
 
 func checkFullClusterHealth(ctx context.Context, client *talosclient.Client, cr *v1alpha1.ClusterHealth) (bool, string) {
-	stream, err := client.ClusterHealthCheck(ctx, 10*time.Second, &clusterapi.ClusterInfo{ControlPlaneNodes: cr.Spec.ForProvider.ControlPlaneNodes, WorkerNodes: cr.Spec.ForProvider.WorkerNodes})
+	return CheckFullClusterHealth(ctx, client, cr.Spec.ForProvider.ControlPlaneNodes, cr.Spec.ForProvider.WorkerNodes)
+}
+
+// CheckFullClusterHealth runs the Talos cluster health checks, including
+// Kubernetes component checks, against the supplied nodes. It returns whether
+// the cluster is healthy and the last reported progress message.
+func CheckFullClusterHealth(ctx context.Context, client *talosclient.Client, controlPlaneNodes, workerNodes []string) (bool, string) {
+	stream, err := client.ClusterHealthCheck(ctx, 10*time.Second, &clusterapi.ClusterInfo{ControlPlaneNodes: controlPlaneNodes, WorkerNodes: workerNodes})
 	if err != nil {
 		return false, fmt.Sprintf("waiting for cluster health: %v", err)
 	}
@@ -298,27 +304,3 @@ func validateClusterHealthSpec(cr *v1alpha1.ClusterHealth) error {
 	}
 	return nil
 }
-
-func buildClusterHealthClientConfig(clientConfig v1alpha1.ClientConfiguration) (*clientconfig.Config, error) {
-	if clientConfig.ClientCertificate == "" {
-		return nil, errors.New("clientConfiguration.clientCertificate is required")
-	}
-	if clientConfig.ClientKey == "" {
-		return nil, errors.New("clientConfiguration.clientKey is required")
-	}
-	if clientConfig.CACertificate == "" {
-		return nil, errors.New("clientConfiguration.caCertificate is required")
-	}
-	cert, err := tls.X509KeyPair([]byte(clientConfig.ClientCertificate), []byte(clientConfig.ClientKey))
-	if err != nil {
-		return nil, errors.Wrap(err, "failed to create client certificate")
-	}
-	if len(cert.Certificate) == 0 {
-		return nil, errors.New("failed to create client certificate")
-	}
-	roots := x509.NewCertPool()
-	if ok := roots.AppendCertsFromPEM([]byte(clientConfig.CACertificate)); !ok {
-		return nil, errors.New("failed to parse CA certificate")
-	}
-	return clientconfig.NewConfig("dynamic", nil, []byte(clientConfig.CACertificate), &siderox509.PEMEncodedCertificateAndKey{Crt: []byte(clientConfig.ClientCertificate), Key: []byte(clientConfig.ClientKey)}), nil
-}
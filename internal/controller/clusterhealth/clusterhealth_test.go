@@ -134,22 +134,6 @@ func TestValidateClusterHealthSpec(t *testing.T) {
 	}
 }
 
-func TestBuildClusterHealthClientConfig(t *testing.T) {
-	cfg := validClientConfiguration(t)
-	if _, err := buildClusterHealthClientConfig(cfg); err != nil {
-		t.Fatalf("buildClusterHealthClientConfig() unexpected error: %v", err)
-	}
-	cfg.ClientCertificate = ""
-	if _, err := buildClusterHealthClientConfig(cfg); err == nil {
-		t.Fatal("missing client cert error = nil, want error")
-	}
-	cfg = validClientConfiguration(t)
-	cfg.ClientKey = "not pem"
-	if _, err := buildClusterHealthClientConfig(cfg); err == nil {
-		t.Fatal("invalid PEM error = nil, want error")
-	}
-}
-
 func TestSkipKubernetesChecksPassedToChecker(t *testing.T) {
 	cr := testClusterHealth(1, 0)
 	skip := true
@@ -180,21 +164,12 @@ func testClusterHealth(cp, workers int) *v1alpha1.ClusterHealth {
 	return &v1alpha1.ClusterHealth{Spec: v1alpha1.ClusterHealthSpec{ForProvider: v1alpha1.ClusterHealthParameters{Endpoints: []string{"10.0.0.1:50000"}, ControlPlaneNodes: cps, WorkerNodes: ws, ClientConfiguration: validClientConfigurationMust()}}}
 }
 
-func validClientConfiguration(t *testing.T) v1alpha1.ClientConfiguration {
-	t.Helper()
-	cert, key, err := testCertAndKey()
-	if err != nil {
-		t.Fatal(err)
-	}
-	return v1alpha1.ClientConfiguration{CACertificate: cert, ClientCertificate: cert, ClientKey: key}
-}
-
-func validClientConfigurationMust() v1alpha1.ClientConfiguration {
+func validClientConfigurationMust() *v1alpha1.ClientConfiguration {
 	cert, key, err := testCertAndKey()
 	if err != nil {
 		panic(err)
 	}
-	return v1alpha1.ClientConfiguration{CACertificate: cert, ClientCertificate: cert, ClientKey: key}
+	return &v1alpha1.ClientConfiguration{CACertificate: cert, ClientCertificate: cert, ClientKey: key}
 }
 
 func testCertAndKey() (string, string, error) {
@@ -19,6 +19,7 @@ package configuration
 import (
 	"context"
 	"crypto/sha256"
+	"encoding/base64"
 	"encoding/hex"
 	"encoding/json"
 
@@ -50,8 +51,8 @@ import (
 
 	machinev1alpha1 "github.com/crossplane-contrib/provider-talos/apis/machine/v1alpha1"
 	apisv1alpha1 "github.com/crossplane-contrib/provider-talos/apis/v1alpha1"
-	secretscontroller "github.com/crossplane-contrib/provider-talos/internal/controller/secrets"
 	"github.com/crossplane-contrib/provider-talos/internal/features"
+	"github.com/crossplane-contrib/provider-talos/internal/secrets"
 )
 
 const (
@@ -256,7 +257,15 @@ func (c *external) Disconnect(ctx context.Context) error {
 // generateMachineConfiguration renders Talos machine configuration with the Talos SDK.
 func (c *external) generateMachineConfiguration(ctx context.Context, cr *machinev1alpha1.Configuration) (string, error) {
 	clusterName, clusterEndpoint, kubernetesVersion := configurationInput(cr)
-	options, err := c.generationOptions(ctx, cr)
+	secretsData, err := c.getMachineSecretsData(ctx, cr)
+	if err != nil {
+		return "", err
+	}
+	options, err := generationOptions(cr, secretsData)
+	if err != nil {
+		return "", err
+	}
+	acceptedCAsPatch, err := acceptedCAsConfigPatch(secretsData)
 	if err != nil {
 		return "", err
 	}
@@ -276,7 +285,18 @@ func (c *external) generateMachineConfiguration(ctx context.Context, cr *machine
 		return "", err
 	}
 
-	return renderMachineConfig(config, cr.Spec.ForProvider.ConfigPatches)
+	configPatches := cr.Spec.ForProvider.ConfigPatches
+	if acceptedCAsPatch != "" {
+		configPatches = append([]string{acceptedCAsPatch}, configPatches...)
+	}
+
+	machineConfig, err := renderMachineConfig(config, configPatches)
+	if err != nil {
+		return "", err
+	}
+	cr.Status.AtProvider.MachineSecretsHash = secrets.MachineSecretsHash(secretsData)
+
+	return machineConfig, nil
 }
 
 func configurationInput(cr *machinev1alpha1.Configuration) (string, string, string) {
@@ -298,7 +318,7 @@ func configurationInput(cr *machinev1alpha1.Configuration) (string, string, stri
 	return clusterName, clusterEndpoint, kubernetesVersion
 }
 
-func (c *external) generationOptions(ctx context.Context, cr *machinev1alpha1.Configuration) ([]generate.Option, error) {
+func generationOptions(cr *machinev1alpha1.Configuration, secretsData map[string][]byte) ([]generate.Option, error) {
 	options := []generate.Option{}
 	if cr.Spec.ForProvider.TalosVersion != nil && *cr.Spec.ForProvider.TalosVersion != "" {
 		versionContract, err := talosconfig.ParseContractFromVersion(*cr.Spec.ForProvider.TalosVersion)
@@ -308,7 +328,7 @@ func (c *external) generationOptions(ctx context.Context, cr *machinev1alpha1.Co
 		options = append(options, generate.WithVersionContract(versionContract))
 	}
 
-	secretsBundle, err := c.getMachineSecretsBundle(ctx, cr)
+	secretsBundle, err := decodeMachineSecretsBundle(secretsData)
 	if err != nil {
 		return nil, err
 	}
@@ -343,7 +363,54 @@ func renderMachineConfig(config talosconfig.Provider, configPatches []string) (s
 	return string(configBytes), nil
 }
 
+// acceptedCAsConfigPatch returns a config patch accepting the CAs of a CA
+// rotation in progress on the referenced Secrets, or an empty patch.
+func acceptedCAsConfigPatch(secretsData map[string][]byte) (string, error) {
+	accepted, err := secrets.ParseAcceptedCAs(secretsData)
+	if err != nil {
+		return "", err
+	}
+
+	patch := map[string]interface{}{}
+	if len(accepted.OS) > 0 {
+		patch["machine"] = map[string]interface{}{"acceptedCAs": acceptedCAList(accepted.OS)}
+	}
+	if len(accepted.Kubernetes) > 0 {
+		patch["cluster"] = map[string]interface{}{"acceptedCAs": acceptedCAList(accepted.Kubernetes)}
+	}
+	if len(patch) == 0 {
+		return "", nil
+	}
+
+	data, err := json.Marshal(patch)
+	if err != nil {
+		return "", errors.Wrap(err, "cannot encode accepted CAs config patch")
+	}
+
+	return string(data), nil
+}
+
+func acceptedCAList(certificates [][]byte) []map[string]string {
+	list := make([]map[string]string, 0, len(certificates))
+	for _, crt := range certificates {
+		list = append(list, map[string]string{"crt": base64.StdEncoding.EncodeToString(crt)})
+	}
+
+	return list
+}
+
 func (c *external) getMachineSecretsBundle(ctx context.Context, cr *machinev1alpha1.Configuration) (*talossecrets.Bundle, error) {
+	secretsData, err := c.getMachineSecretsData(ctx, cr)
+	if err != nil {
+		return nil, err
+	}
+
+	return decodeMachineSecretsBundle(secretsData)
+}
+
+// getMachineSecretsData returns the connection details published by the
+// referenced Secrets.
+func (c *external) getMachineSecretsData(ctx context.Context, cr *machinev1alpha1.Configuration) (map[string][]byte, error) {
 	if cr.Spec.ForProvider.MachineSecretsRef == nil {
 		return nil, errors.New("machineSecretsRef is required to generate deterministic machine configuration")
 	}
@@ -365,7 +432,11 @@ func (c *external) getMachineSecretsBundle(ctx context.Context, cr *machinev1alp
 		return nil, errors.Wrapf(err, "cannot get referenced machine secrets connection secret %s/%s", ref.Namespace, ref.Name)
 	}
 
-	if bundleJSON, ok := connectionSecret.Data[connectionKeyMachineSecretsBundle]; ok {
+	return connectionSecret.Data, nil
+}
+
+func decodeMachineSecretsBundle(secretsData map[string][]byte) (*talossecrets.Bundle, error) {
+	if bundleJSON, ok := secretsData[connectionKeyMachineSecretsBundle]; ok {
 		bundle := &talossecrets.Bundle{Clock: talossecrets.NewClock()}
 		if err := json.Unmarshal(bundleJSON, bundle); err != nil {
 			return nil, errors.Wrap(err, "cannot decode referenced machine secrets bundle")
@@ -375,14 +446,14 @@ func (c *external) getMachineSecretsBundle(ctx context.Context, cr *machinev1alp
 		return bundle, nil
 	}
 
-	if structuredJSON, ok := connectionSecret.Data[connectionKeyMachineSecrets]; ok {
+	if structuredJSON, ok := secretsData[connectionKeyMachineSecrets]; ok {
 		machineSecrets := &machinev1alpha1.MachineSecrets{}
 		if err := json.Unmarshal(structuredJSON, machineSecrets); err != nil {
 			return nil, errors.Wrap(err, "cannot decode referenced structured machine secrets")
 		}
 
-		return secretscontroller.MachineSecretsToSecretsBundle(machineSecrets)
+		return secrets.MachineSecretsToSecretsBundle(machineSecrets)
 	}
 
-	return nil, errors.Errorf("referenced machine secrets connection secret %s/%s is missing %q or %q", ref.Namespace, ref.Name, connectionKeyMachineSecretsBundle, connectionKeyMachineSecrets)
+	return nil, errors.Errorf("referenced machine secrets connection secret is missing %q or %q", connectionKeyMachineSecretsBundle, connectionKeyMachineSecrets)
 }
@@ -18,6 +18,7 @@ package configuration
 
 import (
 	"context"
+	"encoding/base64"
 	"encoding/json"
 	"strings"
 	"testing"
@@ -35,7 +36,7 @@ import (
 	"github.com/crossplane/crossplane-runtime/pkg/test"
 
 	machinev1alpha1 "github.com/crossplane-contrib/provider-talos/apis/machine/v1alpha1"
-	secretscontroller "github.com/crossplane-contrib/provider-talos/internal/controller/secrets"
+	"github.com/crossplane-contrib/provider-talos/internal/secrets"
 )
 
 // Unlike many Kubernetes projects Crossplane does not use third party testing
@@ -153,6 +154,68 @@ func TestObservePublishesMachineConfiguration(t *testing.T) {
 	}
 }
 
+func TestObserveAcceptsRotatingCAs(t *testing.T) {
+	t.Parallel()
+
+	bundle, err := talossecrets.NewBundle(talossecrets.NewClock(), nil)
+	if err != nil {
+		t.Fatalf("talossecrets.NewBundle(...): %v", err)
+	}
+	bundleJSON, err := json.Marshal(bundle)
+	if err != nil {
+		t.Fatalf("json.Marshal(...): %v", err)
+	}
+	rotated, err := talossecrets.NewBundle(talossecrets.NewClock(), nil)
+	if err != nil {
+		t.Fatalf("talossecrets.NewBundle(...): %v", err)
+	}
+	rotationJSON, err := json.Marshal(map[string]interface{}{
+		"os": map[string]string{"cert": base64.StdEncoding.EncodeToString(rotated.Certs.OS.Crt)},
+	})
+	if err != nil {
+		t.Fatalf("json.Marshal(...): %v", err)
+	}
+	scheme := runtime.NewScheme()
+	if err := corev1.AddToScheme(scheme); err != nil {
+		t.Fatalf("corev1.AddToScheme(...): %v", err)
+	}
+	if err := machinev1alpha1.SchemeBuilder.AddToScheme(scheme); err != nil {
+		t.Fatalf("machinev1alpha1.SchemeBuilder.AddToScheme(...): %v", err)
+	}
+
+	connectionSecret := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "example-machine-secrets-connection", Namespace: "default"}, Data: map[string][]byte{
+		connectionKeyMachineSecretsBundle: bundleJSON,
+		"ca_rotation":                     rotationJSON,
+	}}
+	machineSecrets := &machinev1alpha1.Secrets{
+		ObjectMeta: metav1.ObjectMeta{Name: "example-machine-secrets"},
+		Spec:       machinev1alpha1.SecretsSpec{ResourceSpec: xpv1.ResourceSpec{WriteConnectionSecretToReference: &xpv1.SecretReference{Name: connectionSecret.Name, Namespace: connectionSecret.Namespace}}},
+	}
+	configuration := &machinev1alpha1.Configuration{
+		ObjectMeta: metav1.ObjectMeta{Name: "example-config"},
+		Spec: machinev1alpha1.ConfigurationSpec{ForProvider: machinev1alpha1.ConfigurationParameters{
+			ClusterName:       "example-cluster",
+			ClusterEndpoint:   "https://10.0.0.1:6443",
+			MachineType:       "controlplane",
+			MachineSecretsRef: &xpv1.Reference{Name: machineSecrets.Name},
+		}},
+	}
+
+	e := external{kube: fake.NewClientBuilder().WithScheme(scheme).WithObjects(machineSecrets, connectionSecret).Build()}
+	got, err := e.Observe(context.Background(), configuration)
+	if err != nil {
+		t.Fatalf("e.Observe(...): %v", err)
+	}
+
+	machineConfig := string(got.ConnectionDetails[connectionKeyMachineConfiguration])
+	if !strings.Contains(machineConfig, "acceptedCAs:") || !strings.Contains(machineConfig, base64.StdEncoding.EncodeToString(rotated.Certs.OS.Crt)) {
+		t.Fatal("expected generated machine configuration to accept the rotating OS CA")
+	}
+	if diff := cmp.Diff(secrets.MachineSecretsHash(connectionSecret.Data), configuration.Status.AtProvider.MachineSecretsHash); diff != "" {
+		t.Errorf("MachineSecretsHash: -want, +got:\n%s", diff)
+	}
+}
+
 func TestGetMachineSecretsBundleFallsBackToRawBundle(t *testing.T) {
 	t.Parallel()
 
@@ -202,9 +265,9 @@ func TestGetMachineSecretsBundleFallsBackToStructuredSecret(t *testing.T) {
 	if err != nil {
 		t.Fatalf("talossecrets.NewBundle(...): %v", err)
 	}
-	machineSecretsData, err := secretscontroller.SecretsBundleToMachineSecrets(bundle)
+	machineSecretsData, err := secrets.SecretsBundleToMachineSecrets(bundle)
 	if err != nil {
-		t.Fatalf("secretscontroller.SecretsBundleToMachineSecrets(...): %v", err)
+		t.Fatalf("secrets.SecretsBundleToMachineSecrets(...): %v", err)
 	}
 	structuredJSON, err := json.Marshal(machineSecretsData)
 	if err != nil {
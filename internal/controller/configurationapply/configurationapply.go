@@ -18,15 +18,31 @@ package configurationapply
 
 import (
 	"context"
-	"crypto/tls"
-	"crypto/x509"
-	"encoding/base64"
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
+	"net/url"
+	"reflect"
+	"sort"
 	"strings"
 	"time"
 
+	"github.com/blang/semver/v4"
+	"github.com/cosi-project/runtime/pkg/safe"
+	siderox509 "github.com/siderolabs/crypto/x509"
 	"github.com/siderolabs/talos/pkg/machinery/api/machine"
 	talosclient "github.com/siderolabs/talos/pkg/machinery/client"
+	"github.com/siderolabs/talos/pkg/machinery/config/configloader"
+	"github.com/siderolabs/talos/pkg/machinery/config/configpatcher"
+	"github.com/siderolabs/talos/pkg/machinery/config/container"
+	"github.com/siderolabs/talos/pkg/machinery/config/encoder"
+	talosv1alpha1 "github.com/siderolabs/talos/pkg/machinery/config/types/v1alpha1"
+	"github.com/siderolabs/talos/pkg/machinery/config/validation"
+	"github.com/siderolabs/talos/pkg/machinery/constants"
+	configresource "github.com/siderolabs/talos/pkg/machinery/resources/config"
+	yamlv3 "gopkg.in/yaml.v3"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/yaml"
 
 	"github.com/crossplane/crossplane-runtime/pkg/feature"
 
@@ -49,6 +65,7 @@ import (
 
 	"github.com/crossplane-contrib/provider-talos/apis/machine/v1alpha1"
 	apisv1alpha1 "github.com/crossplane-contrib/provider-talos/apis/v1alpha1"
+	"github.com/crossplane-contrib/provider-talos/internal/clients"
 	"github.com/crossplane-contrib/provider-talos/internal/features"
 )
 
@@ -59,6 +76,20 @@ const (
 	errGetCreds              = "cannot get credentials"
 
 	errNewClient = "cannot create new Service"
+
+	// defaultOnDestroyTimeout applies when spec.forProvider.onDestroyTimeout
+	// is not set.
+	defaultOnDestroyTimeout = 30 * time.Minute
+)
+
+// TypeReset reports the progress of the onDestroy reset of a ConfigurationApply
+// being deleted.
+const TypeReset xpv1.ConditionType = "Reset"
+
+// Reasons a reset is waited for or given up on.
+const (
+	ReasonResetPending  xpv1.ConditionReason = "WaitingForMaintenanceMode"
+	ReasonResetTimedOut xpv1.ConditionReason = "TimedOut"
 )
 
 // A NoOpService does nothing.
@@ -69,7 +100,8 @@ var (
 )
 
 // Setup adds a controller that reconciles ConfigurationApply managed resources.
-func Setup(mgr ctrl.Manager, o controller.Options) error {
+// Talos API clients are borrowed from the supplied cache.
+func Setup(mgr ctrl.Manager, o controller.Options, talosClients *clients.Cache) error {
 	name := managed.ControllerName(v1alpha1.ConfigurationApplyGroupKind)
 
 	cps := []managed.ConnectionPublisher{managed.NewAPISecretPublisher(mgr.GetClient(), mgr.GetScheme())}
@@ -81,7 +113,8 @@ func Setup(mgr ctrl.Manager, o controller.Options) error {
 		managed.WithExternalConnecter(&connector{
 			kube:         mgr.GetClient(),
 			usage:        resource.NewProviderConfigUsageTracker(mgr.GetClient(), &apisv1alpha1.ProviderConfigUsage{}),
-			newServiceFn: newNoOpService}),
+			newServiceFn: newNoOpService,
+			talosClients: talosClients}),
 		managed.WithLogger(o.Logger.WithValues("controller", name)),
 		managed.WithPollInterval(o.PollInterval),
 		managed.WithRecorder(event.NewAPIRecorder(mgr.GetEventRecorderFor(name))),
@@ -122,6 +155,7 @@ type connector struct {
 	kube         ctrlclient.Client
 	usage        resource.Tracker
 	newServiceFn func(creds []byte) (interface{}, error)
+	talosClients *clients.Cache
 }
 
 // Connect typically produces an ExternalClient by:
@@ -159,6 +193,7 @@ func (c *connector) Connect(ctx context.Context, mg resource.Managed) (managed.E
 		kube:               c.kube,
 		service:            svc,
 		providerConfigData: data,
+		talosClients:       c.talosClients,
 	}, nil
 }
 
@@ -170,10 +205,18 @@ type external struct {
 	// A 'client' used to connect to the external resource API. In practice this
 	// would be something like an AWS SDK client.
 	service interface{}
+	// talosClients shares Talos API clients between reconciles.
+	talosClients *clients.Cache
 	// ProviderConfig credentials for verifying machine state
 	providerConfigData []byte
 	// canConnectInsecureFn allows tests to stub maintenance-mode detection.
 	canConnectInsecureFn func(context.Context, *v1alpha1.ConfigurationApply) bool
+	// readMachineConfigFn allows tests to stub reading the running machine configuration.
+	readMachineConfigFn func(context.Context, *v1alpha1.ConfigurationApply) ([]byte, error)
+	// readDisksFn allows tests to stub reading the disk inventory of the node.
+	readDisksFn func(context.Context, *v1alpha1.ConfigurationApply, MachineState) ([]v1alpha1.MachineDisk, error)
+	// readNodeStatusFn allows tests to stub reading the version, stage and identity of the node.
+	readNodeStatusFn func(context.Context, *v1alpha1.ConfigurationApply, MachineState) (clients.NodeStatus, error)
 }
 
 func (c *external) Observe(ctx context.Context, mg resource.Managed) (managed.ExternalObservation, error) {
@@ -193,8 +236,29 @@ func (c *external) Observe(ctx context.Context, mg resource.Managed) (managed.Ex
 	cr.Status.AtProvider.MachineState = string(machineState)
 	cr.Status.AtProvider.LastStateCheck = &now
 
+	if meta.WasDeleted(cr) {
+		return observeDeletion(cr, machineState, applied, now.Time), nil
+	}
+
+	if machineState == MachineStateMaintenanceMode || machineState == MachineStateConfigured {
+		c.observeNode(ctx, cr, machineState)
+	}
+
 	resourceExists, resourceUpToDate := observationState(machineState, applied, hasValidMachineConfig(cr))
 
+	diff := ""
+	if machineState == MachineStateConfigured && resourceUpToDate {
+		drift, err := c.observeConfigDrift(ctx, cr)
+		if err != nil {
+			return managed.ExternalObservation{}, errors.Wrap(err, "cannot observe machine configuration drift")
+		}
+		if len(drift) > 0 {
+			resourceUpToDate = false
+			diff = "machine configuration drifted: " + strings.Join(drift, ", ")
+			fmt.Printf("Machine %s %s\n", cr.Spec.ForProvider.Node, diff)
+		}
+	}
+
 	switch machineState {
 	case MachineStateMaintenanceMode:
 		if applied {
@@ -222,6 +286,7 @@ func (c *external) Observe(ctx context.Context, mg resource.Managed) (managed.Ex
 	return managed.ExternalObservation{
 		ResourceExists:    resourceExists,
 		ResourceUpToDate:  resourceUpToDate,
+		Diff:              diff,
 		ConnectionDetails: managed.ConnectionDetails{},
 	}, nil
 }
@@ -267,6 +332,61 @@ func observationState(machineState MachineState, applied, hasValidConfig bool) (
 	return false, false
 }
 
+// observeDeletion reports the node as existing until the onDestroy reset has
+// returned it to maintenance mode, or until the onDestroy timeout has passed
+// without the node coming back, e.g. because it cannot boot without its
+// system disk or was powered off.
+func observeDeletion(cr *v1alpha1.ConfigurationApply, machineState MachineState, applied bool, now time.Time) managed.ExternalObservation {
+	req, err := getResetRequest(cr.Spec.ForProvider.OnDestroy)
+	if err != nil || req == nil {
+		// Unknown modes surface from Delete; nothing to do for none.
+		return managed.ExternalObservation{ResourceExists: err != nil, ResourceUpToDate: true}
+	}
+
+	resetRequested := cr.Status.AtProvider.ResetTime != nil
+	if machineState == MachineStateMaintenanceMode || (!applied && !resetRequested) {
+		fmt.Printf("Machine %s is in maintenance mode - deletion complete\n", cr.Spec.ForProvider.Node)
+		return managed.ExternalObservation{ResourceExists: false}
+	}
+
+	since, timeout := onDestroyDeadline(cr)
+	if !since.IsZero() && now.Sub(since) >= timeout {
+		msg := fmt.Sprintf("node %s did not return to maintenance mode within %s; giving up on it", cr.Spec.ForProvider.Node, timeout)
+		fmt.Printf("Machine %s\n", msg)
+		cr.SetConditions(xpv1.Condition{Type: TypeReset, Status: corev1.ConditionFalse, Reason: ReasonResetTimedOut, Message: msg, LastTransitionTime: metav1.NewTime(now)})
+		return managed.ExternalObservation{ResourceExists: false}
+	}
+
+	msg := fmt.Sprintf("waiting up to %s for node %s to be reset", timeout, cr.Spec.ForProvider.Node)
+	if resetRequested {
+		cr.Status.AtProvider.MachineState = string(MachineStateResetting)
+		msg = fmt.Sprintf("waiting up to %s for node %s to return to maintenance mode", timeout, cr.Spec.ForProvider.Node)
+		fmt.Printf("Waiting for machine %s to return to maintenance mode\n", cr.Spec.ForProvider.Node)
+	}
+	cr.SetConditions(xpv1.Condition{Type: TypeReset, Status: corev1.ConditionFalse, Reason: ReasonResetPending, Message: msg, LastTransitionTime: metav1.NewTime(now)})
+
+	return managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: true}
+}
+
+// onDestroyDeadline returns when the wait for the onDestroy reset started,
+// which is when the reset was requested or, until it was, when the
+// ConfigurationApply was deleted, and how long it may last.
+func onDestroyDeadline(cr *v1alpha1.ConfigurationApply) (time.Time, time.Duration) {
+	timeout := defaultOnDestroyTimeout
+	if t := cr.Spec.ForProvider.OnDestroyTimeout; t != nil {
+		timeout = t.Duration
+	}
+
+	switch {
+	case cr.Status.AtProvider.ResetTime != nil:
+		return cr.Status.AtProvider.ResetTime.Time, timeout
+	case cr.GetDeletionTimestamp() != nil:
+		return cr.GetDeletionTimestamp().Time, timeout
+	default:
+		return time.Time{}, timeout
+	}
+}
+
 func hasSuccessfulExternalCreate(cr *v1alpha1.ConfigurationApply) bool {
 	return cr.GetAnnotations()[meta.AnnotationKeyExternalCreateSucceeded] != ""
 }
@@ -330,7 +450,31 @@ func (c *external) Delete(ctx context.Context, mg resource.Managed) (managed.Ext
 		return managed.ExternalDelete{}, errors.New(errNotConfigurationApply)
 	}
 
-	fmt.Printf("Deleting: %+v", cr)
+	req, err := getResetRequest(cr.Spec.ForProvider.OnDestroy)
+	if err != nil {
+		return managed.ExternalDelete{}, err
+	}
+	if req == nil {
+		fmt.Printf("Leaving node %s untouched on delete\n", cr.Spec.ForProvider.Node)
+		return managed.ExternalDelete{}, nil
+	}
+
+	// The reset is only requested once; later reconciles wait for Observe to
+	// see the node back in maintenance mode.
+	if cr.Status.AtProvider.ResetTime != nil {
+		fmt.Printf("Reset of node %s already requested at %s\n", cr.Spec.ForProvider.Node, cr.Status.AtProvider.ResetTime)
+		return managed.ExternalDelete{}, nil
+	}
+
+	fmt.Printf("Resetting node %s (onDestroy: %s)\n", cr.Spec.ForProvider.Node, *cr.Spec.ForProvider.OnDestroy)
+
+	if err := c.resetNode(ctx, cr, req); err != nil {
+		return managed.ExternalDelete{}, errors.Wrap(err, "failed to reset node")
+	}
+
+	now := metav1.Now()
+	cr.Status.AtProvider.ResetTime = &now
+	cr.Status.AtProvider.MachineState = string(MachineStateResetting)
 
 	return managed.ExternalDelete{}, nil
 }
@@ -346,6 +490,7 @@ const (
 	MachineStateMaintenanceMode MachineState = "MaintenanceMode"
 	MachineStateConfigured      MachineState = "Configured"
 	MachineStateUnreachable     MachineState = "Unreachable"
+	MachineStateResetting       MachineState = "Resetting"
 )
 
 // checkMachineState checks the current state of the Talos machine
@@ -364,8 +509,11 @@ func (c *external) checkMachineState(ctx context.Context, cr *v1alpha1.Configura
 	}
 
 	// Try to connect with configured credentials (if not insecure)
-	clientConfig := cr.Spec.ForProvider.ClientConfiguration
-	if clientConfig.ClientCertificate != "" && clientConfig.ClientCertificate != "insecure" {
+	clientConfig, err := c.clientConfiguration(checkCtx, cr)
+	if err != nil {
+		fmt.Printf("Cannot resolve client configuration for machine %s: %v\n", node, err)
+	}
+	if clientConfig != nil && !clients.IsInsecure(clientConfig) {
 		if c.canConnectWithCreds(checkCtx, cr) {
 			fmt.Printf("Machine %s is configured and running (authenticated connection succeeded)\n", node)
 			return MachineStateConfigured
@@ -383,14 +531,7 @@ func (c *external) canConnectInsecure(ctx context.Context, cr *v1alpha1.Configur
 		return c.canConnectInsecureFn(ctx, cr)
 	}
 
-	endpoint := getConfigurationApplyEndpoint(cr)
-
-	talosClient, err := talosclient.New(ctx,
-		talosclient.WithEndpoints(endpoint),
-		talosclient.WithTLSConfig(&tls.Config{
-			InsecureSkipVerify: true, //nolint:gosec
-		}),
-	)
+	talosClient, ctx, err := c.nodeClient(ctx, cr, clients.InsecureClientConfiguration())
 	if err != nil {
 		return false
 	}
@@ -401,51 +542,280 @@ func (c *external) canConnectInsecure(ctx context.Context, cr *v1alpha1.Configur
 	checkCtx, cancel := context.WithTimeout(ctx, 1*time.Second)
 	defer cancel()
 
-	// Try to call an API - even if it fails, the connection pattern tells us the mode
+	return clients.IsMaintenanceMode(checkCtx, talosClient.Client)
+}
+
+// canConnectWithCreds checks if the machine accepts authenticated connections (configured mode)
+func (c *external) canConnectWithCreds(ctx context.Context, cr *v1alpha1.ConfigurationApply) bool {
+	talosClient, ctx, err := c.talosClient(ctx, cr)
+	if err != nil {
+		return false
+	}
+	defer talosClient.Close() //nolint:errcheck
+
+	// Try to get version with authenticated connection
+	checkCtx, cancel := context.WithTimeout(ctx, 1*time.Second)
+	defer cancel()
+
 	_, err = talosClient.Version(checkCtx)
-	if err == nil {
-		// Version succeeded with insecure connection - definitely maintenance mode
-		return true
+	return err == nil
+}
+
+// observeConfigDrift compares the desired machine configuration with the
+// configuration running on the node and returns the drifted paths.
+func (c *external) observeConfigDrift(ctx context.Context, cr *v1alpha1.ConfigurationApply) ([]string, error) {
+	base, err := c.resolveBaseMachineConfiguration(ctx, cr)
+	if err != nil {
+		return nil, err
+	}
+	desired, err := desiredMachineConfiguration(cr, base)
+	if err != nil {
+		return nil, err
+	}
+	desired, err = normalizeMachineConfig(desired)
+	if err != nil {
+		return nil, errors.Wrap(err, "cannot normalize desired machine configuration")
 	}
 
-	// Check error type to determine machine state
-	errStr := err.Error()
-	// "Unimplemented" means maintenance mode API (connection succeeded but API not available)
-	if strings.Contains(errStr, "Unimplemented") || strings.Contains(errStr, "not implemented in maintenance") {
-		return true
+	running, err := c.readMachineConfig(ctx, cr)
+	if err != nil {
+		return nil, err
 	}
-	// Auth errors mean configured mode (machine requires credentials)
-	if strings.Contains(errStr, "authentication") || strings.Contains(errStr, "credentials") {
-		return false
+	running, err = normalizeMachineConfig(running)
+	if err != nil {
+		return nil, errors.Wrap(err, "cannot normalize running machine configuration")
 	}
-	// Any other error (connection refused, timeout, etc.) means unreachable
-	return false
+
+	cr.Status.AtProvider.DesiredConfigHash = machineConfigHash(desired)
+	cr.Status.AtProvider.AppliedConfigHash = machineConfigHash(running)
+	cr.Status.AtProvider.SourceConfigHash = ""
+	if cr.Spec.ForProvider.MachineConfigurationRef != nil {
+		cr.Status.AtProvider.SourceConfigHash = machineConfigHash(base)
+	}
+	cr.Status.AtProvider.ConfigDrift = nil
+	if cr.Status.AtProvider.DesiredConfigHash == cr.Status.AtProvider.AppliedConfigHash {
+		return nil, nil
+	}
+
+	drift, err := machineConfigDiff(desired, running)
+	if err != nil {
+		return nil, err
+	}
+	if len(drift) == 0 {
+		// Hashes differ only in document ordering; report the config as a whole.
+		drift = []string{"."}
+	}
+	cr.Status.AtProvider.ConfigDrift = drift
+
+	return drift, nil
 }
 
-// canConnectWithCreds checks if the machine accepts authenticated connections (configured mode)
-func (c *external) canConnectWithCreds(ctx context.Context, cr *v1alpha1.ConfigurationApply) bool {
-	endpoint := getConfigurationApplyEndpoint(cr)
+// readMachineConfig reads the machine configuration from the node over the
+// authenticated Talos API. Staged applies are compared against the persisted
+// configuration, since the active one only changes after a reboot.
+func (c *external) readMachineConfig(ctx context.Context, cr *v1alpha1.ConfigurationApply) ([]byte, error) {
+	if c.readMachineConfigFn != nil {
+		return c.readMachineConfigFn(ctx, cr)
+	}
 
-	tlsConfig, err := buildConfigurationApplyTLSConfig(cr.Spec.ForProvider.ClientConfiguration, cr.Spec.ForProvider.Node)
+	talosClient, ctx, err := c.talosClient(ctx, cr)
 	if err != nil {
-		return false
+		return nil, errors.Wrap(err, "failed to create Talos client")
+	}
+	defer talosClient.Close() // nolint:errcheck
+
+	id := configresource.ActiveID
+	if cr.Spec.ForProvider.ApplyMode != nil && *cr.Spec.ForProvider.ApplyMode == "staged" {
+		id = configresource.PersistentID
 	}
 
-	talosClient, err := talosclient.New(ctx,
-		talosclient.WithTLSConfig(tlsConfig),
-		talosclient.WithEndpoints(endpoint),
-	)
+	mc, err := safe.StateGetByID[*configresource.MachineConfig](ctx, talosClient.COSI, id)
 	if err != nil {
-		return false
+		return nil, errors.Wrap(err, "failed to read machine configuration from Talos node")
 	}
-	defer talosClient.Close() //nolint:errcheck
 
-	// Try to get version with authenticated connection
-	checkCtx, cancel := context.WithTimeout(ctx, 1*time.Second)
+	return mc.Provider().EncodeBytes(encoder.WithComments(encoder.CommentsDisabled))
+}
+
+// observeNode records the Talos version, stage, identity and disk inventory
+// of a reachable node. The last known values are kept if the node cannot
+// report them right now.
+func (c *external) observeNode(ctx context.Context, cr *v1alpha1.ConfigurationApply, machineState MachineState) {
+	node := cr.Spec.ForProvider.Node
+
+	if status, err := c.readNodeStatus(ctx, cr, machineState); err != nil {
+		fmt.Printf("Cannot read status of machine %s: %v\n", node, err)
+	} else {
+		cr.Status.AtProvider.TalosVersion = status.TalosVersion
+		cr.Status.AtProvider.MachineStage = status.Stage
+		cr.Status.AtProvider.Hostname = status.Hostname
+		cr.Status.AtProvider.NodeUUID = status.UUID
+	}
+
+	if disks, err := c.readDisks(ctx, cr, machineState); err != nil {
+		fmt.Printf("Cannot read disk inventory of machine %s: %v\n", node, err)
+	} else {
+		cr.Status.AtProvider.Disks = disks
+	}
+}
+
+// readNodeStatus reads the Talos version, stage and identity of the node.
+func (c *external) readNodeStatus(ctx context.Context, cr *v1alpha1.ConfigurationApply, machineState MachineState) (clients.NodeStatus, error) {
+	if c.readNodeStatusFn != nil {
+		return c.readNodeStatusFn(ctx, cr, machineState)
+	}
+
+	talosClient, ctx, err := c.stateClient(ctx, cr, machineState)
+	if err != nil {
+		return clients.NodeStatus{}, err
+	}
+	defer talosClient.Close() // nolint:errcheck
+
+	readCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
 	defer cancel()
 
-	_, err = talosClient.Version(checkCtx)
-	return err == nil
+	return clients.ReadNodeStatus(readCtx, talosClient.COSI)
+}
+
+// readDisks reads the disk inventory of the node.
+func (c *external) readDisks(ctx context.Context, cr *v1alpha1.ConfigurationApply, machineState MachineState) ([]v1alpha1.MachineDisk, error) {
+	if c.readDisksFn != nil {
+		return c.readDisksFn(ctx, cr, machineState)
+	}
+
+	talosClient, ctx, err := c.stateClient(ctx, cr, machineState)
+	if err != nil {
+		return nil, err
+	}
+	defer talosClient.Close() // nolint:errcheck
+
+	readCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	return clients.ReadDisks(readCtx, talosClient.COSI)
+}
+
+// stateClient borrows a Talos client for reading resources of the node in
+// the given state. Machines in maintenance mode serve them over the insecure
+// maintenance API.
+func (c *external) stateClient(ctx context.Context, cr *v1alpha1.ConfigurationApply, machineState MachineState) (*clients.Client, context.Context, error) {
+	clientConfig := clients.InsecureClientConfiguration()
+	if machineState != MachineStateMaintenanceMode {
+		var err error
+		if clientConfig, err = c.clientConfiguration(ctx, cr); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	talosClient, ctx, err := c.nodeClient(ctx, cr, clientConfig)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "failed to create Talos client")
+	}
+
+	return talosClient, ctx, nil
+}
+
+// normalizeMachineConfig re-encodes machine configuration without comments so
+// that formatting and key ordering do not affect comparison.
+func normalizeMachineConfig(data []byte) ([]byte, error) {
+	cfg, err := configloader.NewFromBytes(data)
+	if err != nil {
+		return nil, err
+	}
+
+	return cfg.EncodeBytes(encoder.WithComments(encoder.CommentsDisabled))
+}
+
+func machineConfigHash(normalized []byte) string {
+	hash := sha256.Sum256(normalized)
+	return hex.EncodeToString(hash[:])
+}
+
+// machineConfigDiff returns the sorted paths whose values differ between two
+// normalized machine configurations. Values are never included so secrets do
+// not leak into status.
+func machineConfigDiff(desired, running []byte) ([]string, error) {
+	desiredDocs, err := decodeConfigDocuments(desired)
+	if err != nil {
+		return nil, errors.Wrap(err, "cannot decode desired machine configuration")
+	}
+	runningDocs, err := decodeConfigDocuments(running)
+	if err != nil {
+		return nil, errors.Wrap(err, "cannot decode running machine configuration")
+	}
+
+	paths := map[string]struct{}{}
+	for key, doc := range desiredDocs {
+		diffConfigValues(key, doc, runningDocs[key], paths)
+	}
+	for key, doc := range runningDocs {
+		if _, ok := desiredDocs[key]; !ok {
+			diffConfigValues(key, nil, doc, paths)
+		}
+	}
+
+	drift := make([]string, 0, len(paths))
+	for path := range paths {
+		drift = append(drift, path)
+	}
+	sort.Strings(drift)
+
+	return drift, nil
+}
+
+// decodeConfigDocuments splits a multi-document configuration and keys each
+// document by its kind and name. The v1alpha1 document has an empty key.
+func decodeConfigDocuments(data []byte) (map[string]interface{}, error) {
+	docs := map[string]interface{}{}
+	for _, raw := range strings.Split(string(data), "\n---\n") {
+		if strings.TrimSpace(raw) == "" {
+			continue
+		}
+
+		doc := map[string]interface{}{}
+		if err := yaml.Unmarshal([]byte(raw), &doc); err != nil {
+			return nil, err
+		}
+
+		key := ""
+		if kind, ok := doc["kind"].(string); ok {
+			key = kind
+			if name, ok := doc["name"].(string); ok {
+				key += "/" + name
+			}
+		}
+		docs[key] = doc
+	}
+
+	return docs, nil
+}
+
+func diffConfigValues(path string, desired, running interface{}, paths map[string]struct{}) {
+	desiredMap, desiredOK := desired.(map[string]interface{})
+	runningMap, runningOK := running.(map[string]interface{})
+	if !desiredOK || !runningOK {
+		if !reflect.DeepEqual(desired, running) {
+			paths[path] = struct{}{}
+		}
+		return
+	}
+
+	for key, value := range desiredMap {
+		diffConfigValues(joinConfigPath(path, key), value, runningMap[key], paths)
+	}
+	for key, value := range runningMap {
+		if _, ok := desiredMap[key]; !ok {
+			diffConfigValues(joinConfigPath(path, key), nil, value, paths)
+		}
+	}
+}
+
+func joinConfigPath(path, key string) string {
+	if path == "" {
+		return key
+	}
+
+	return path + "." + key
 }
 
 // applyConfigurationToNode applies a Talos configuration to the specified node
@@ -460,24 +830,19 @@ func (c *external) applyConfigurationToNode(ctx context.Context, cr *v1alpha1.Co
 	// For now, skip config parsing validation
 	// In a complete implementation, this would validate the configuration
 
-	endpoint := getConfigurationApplyEndpoint(cr)
-
-	tlsConfig, maintenanceMode, err := c.buildApplyTLSConfig(ctx, cr)
+	clientConfig, maintenanceMode, err := c.applyClientConfiguration(ctx, cr)
 	if err != nil {
 		return err
 	}
 	switch {
 	case maintenanceMode:
 		fmt.Printf("Machine %s is in maintenance mode; using insecure first apply\n", cr.Spec.ForProvider.Node)
-	case tlsConfig.InsecureSkipVerify:
+	case clients.IsInsecure(clientConfig):
 		fmt.Printf("Using insecure gRPC connection for maintenance mode machine\n")
 	default:
 		fmt.Printf("Using secure TLS connection with client certificates\n")
 	}
-	talosClient, err := talosclient.New(ctx,
-		talosclient.WithTLSConfig(tlsConfig),
-		talosclient.WithEndpoints(endpoint),
-	)
+	talosClient, ctx, err := c.nodeClient(ctx, cr, clientConfig)
 	if err != nil {
 		return errors.Wrap(err, "failed to create Talos client")
 	}
@@ -501,7 +866,151 @@ func (c *external) applyConfigurationToNode(ctx context.Context, cr *v1alpha1.Co
 	return nil
 }
 
+// resolveMachineConfiguration resolves the base machine configuration and
+// applies spec.forProvider.configPatches on top of it.
 func (c *external) resolveMachineConfiguration(ctx context.Context, cr *v1alpha1.ConfigurationApply) ([]byte, error) {
+	configInput, err := c.resolveBaseMachineConfiguration(ctx, cr)
+	if err != nil {
+		return nil, err
+	}
+
+	return desiredMachineConfiguration(cr, configInput)
+}
+
+// desiredMachineConfiguration patches the base machine configuration and keeps
+// the Kubernetes component images rolled out to the node, so that applying it
+// does not revert a KubernetesUpgrade.
+func desiredMachineConfiguration(cr *v1alpha1.ConfigurationApply, base []byte) ([]byte, error) {
+	desired, err := patchMachineConfiguration(cr, base)
+	if err != nil {
+		return nil, err
+	}
+
+	return patchKubernetesImages(desired, cr.Spec.ForProvider.KubernetesImages)
+}
+
+// patchKubernetesImages replaces the Kubernetes component images of a machine
+// configuration with the given ones, unless the configured image is already
+// on the same or a newer version.
+func patchKubernetesImages(configInput []byte, images *v1alpha1.KubernetesImages) ([]byte, error) {
+	if images == nil {
+		return configInput, nil
+	}
+
+	cfg, err := configloader.NewFromBytes(configInput)
+	if err != nil {
+		return nil, errors.Wrap(err, "cannot parse machine configuration")
+	}
+
+	clusterPatch := map[string]interface{}{}
+	machinePatch := map[string]interface{}{}
+	for _, component := range []struct {
+		image      *string
+		configured string
+		section    map[string]interface{}
+		key        string
+	}{
+		{images.APIServer, cfg.Cluster().APIServer().Image(), clusterPatch, "apiServer"},
+		{images.ControllerManager, cfg.Cluster().ControllerManager().Image(), clusterPatch, "controllerManager"},
+		{images.Scheduler, cfg.Cluster().Scheduler().Image(), clusterPatch, "scheduler"},
+		{images.Proxy, cfg.Cluster().Proxy().Image(), clusterPatch, "proxy"},
+		{images.Kubelet, cfg.Machine().Kubelet().Image(), machinePatch, "kubelet"},
+	} {
+		if component.image == nil || !imageVersionLess(component.configured, *component.image) {
+			continue
+		}
+		component.section[component.key] = map[string]interface{}{"image": *component.image}
+	}
+
+	patch := map[string]interface{}{}
+	if len(clusterPatch) > 0 {
+		patch["cluster"] = clusterPatch
+	}
+	if len(machinePatch) > 0 {
+		patch["machine"] = machinePatch
+	}
+	if len(patch) == 0 {
+		return configInput, nil
+	}
+
+	data, err := yaml.Marshal(patch)
+	if err != nil {
+		return nil, errors.Wrap(err, "cannot encode spec.forProvider.kubernetesImages patch")
+	}
+	p, err := configpatcher.LoadPatch(data)
+	if err != nil {
+		return nil, errors.Wrap(err, "cannot load spec.forProvider.kubernetesImages patch")
+	}
+	patched, err := configpatcher.Apply(configpatcher.WithBytes(configInput), []configpatcher.Patch{p})
+	if err != nil {
+		return nil, errors.Wrap(err, "cannot apply spec.forProvider.kubernetesImages")
+	}
+
+	configBytes, err := patched.Bytes()
+	if err != nil {
+		return nil, errors.Wrap(err, "cannot encode patched machine configuration")
+	}
+
+	return configBytes, nil
+}
+
+// imageVersionLess reports whether the tag of image a is an older version
+// than the tag of image b. Tags that are not versions sort first.
+func imageVersionLess(a, b string) bool {
+	av, aErr := semver.ParseTolerant(imageTag(a))
+	bv, bErr := semver.ParseTolerant(imageTag(b))
+	switch {
+	case aErr != nil:
+		return bErr == nil
+	case bErr != nil:
+		return false
+	default:
+		return av.LT(bv)
+	}
+}
+
+// imageTag returns the tag of an image reference, ignoring registry ports and digests.
+func imageTag(image string) string {
+	image = strings.SplitN(image, "@", 2)[0]
+	lastColon := strings.LastIndex(image, ":")
+	if lastColon < 0 || lastColon < strings.LastIndex(image, "/") {
+		return ""
+	}
+
+	return image[lastColon+1:]
+}
+
+// patchMachineConfiguration applies spec.forProvider.configPatches on top of
+// the base machine configuration.
+func patchMachineConfiguration(cr *v1alpha1.ConfigurationApply, configInput []byte) ([]byte, error) {
+	configInput, err := applyConfigPatches(configInput, cr.Spec.ForProvider.ConfigPatches)
+	if err != nil {
+		return nil, err
+	}
+
+	// Raw configuration from a Secret is sent as-is; structured configuration
+	// is validated once patches have been applied.
+	if cr.Spec.ForProvider.MachineConfigurationRef == nil {
+		if err := validateMachineConfiguration(configInput, cr.Spec.ForProvider.MachineConfiguration.ValidationMode); err != nil {
+			return nil, err
+		}
+	}
+
+	return configInput, nil
+}
+
+// resetNode resets the node over the authenticated Talos API.
+func (c *external) resetNode(ctx context.Context, cr *v1alpha1.ConfigurationApply, req *machine.ResetRequest) error {
+	talosClient, ctx, err := c.talosClient(ctx, cr)
+	if err != nil {
+		return errors.Wrap(err, "failed to create Talos client")
+	}
+	defer talosClient.Close() // nolint:errcheck
+
+	return errors.Wrap(talosClient.ResetGeneric(ctx, req), "failed to reset Talos node")
+}
+
+func (c *external) resolveBaseMachineConfiguration(ctx context.Context, cr *v1alpha1.ConfigurationApply) ([]byte, error) {
 	if ref := cr.Spec.ForProvider.MachineConfigurationRef; ref != nil {
 		return c.resolveMachineConfigurationRef(ctx, ref)
 	}
@@ -521,6 +1030,31 @@ func (c *external) resolveMachineConfiguration(ctx context.Context, cr *v1alpha1
 	return []byte(configInput), nil
 }
 
+// applyConfigPatches applies strategic merge and JSON6902 patches to raw
+// machine configuration bytes using the Talos configpatcher pipeline.
+func applyConfigPatches(configInput []byte, configPatches []string) ([]byte, error) {
+	if len(configPatches) == 0 {
+		return configInput, nil
+	}
+
+	patches, err := configpatcher.LoadPatches(configPatches)
+	if err != nil {
+		return nil, errors.Wrap(err, "cannot load spec.forProvider.configPatches")
+	}
+
+	patched, err := configpatcher.Apply(configpatcher.WithBytes(configInput), patches)
+	if err != nil {
+		return nil, errors.Wrap(err, "cannot apply spec.forProvider.configPatches")
+	}
+
+	configBytes, err := patched.Bytes()
+	if err != nil {
+		return nil, errors.Wrap(err, "cannot encode patched machine configuration")
+	}
+
+	return configBytes, nil
+}
+
 func (c *external) resolveMachineConfigurationRef(ctx context.Context, ref *v1alpha1.SecretKeyReference) ([]byte, error) {
 	if c.kube == nil {
 		return nil, errors.New("cannot resolve machineConfigurationRef without Kubernetes client")
@@ -551,47 +1085,71 @@ func (c *external) resolveMachineConfigurationRef(ctx context.Context, ref *v1al
 	return data, nil
 }
 
-func (c *external) buildApplyTLSConfig(ctx context.Context, cr *v1alpha1.ConfigurationApply) (*tls.Config, bool, error) {
+// applyClientConfiguration returns the client credentials to apply the
+// configuration with, and whether the machine is in maintenance mode. Machines
+// in maintenance mode only accept insecure connections for their first apply.
+func (c *external) applyClientConfiguration(ctx context.Context, cr *v1alpha1.ConfigurationApply) (*v1alpha1.ClientConfiguration, bool, error) {
 	checkCtx, cancel := context.WithTimeout(ctx, 2*time.Second)
 	defer cancel()
 
 	if c.canConnectInsecure(checkCtx, cr) {
-		return &tls.Config{
-			InsecureSkipVerify: true, //nolint:gosec // Insecure mode needed for Talos maintenance-mode first apply.
-		}, true, nil
+		return clients.InsecureClientConfiguration(), true, nil
+	}
+
+	clientConfig, err := c.clientConfiguration(ctx, cr)
+	if err != nil {
+		return nil, false, err
+	}
+	if _, err := clients.ClientOptions(clientConfig); err != nil {
+		return nil, false, err
 	}
 
-	tlsConfig, err := buildConfigurationApplyTLSConfig(cr.Spec.ForProvider.ClientConfiguration, cr.Spec.ForProvider.Node)
-	return tlsConfig, false, err
+	return clientConfig, false, nil
 }
 
-func buildConfigurationApplyTLSConfig(clientConfig v1alpha1.ClientConfiguration, node string) (*tls.Config, error) {
-	if clientConfig.ClientCertificate == "" || clientConfig.ClientCertificate == "insecure" {
-		return &tls.Config{
-			InsecureSkipVerify: true, //nolint:gosec // Insecure mode needed for maintenance mode machines.
-		}, nil
+// clientConfiguration resolves the client credentials of a ConfigurationApply.
+// Machines without client credentials are reached in maintenance mode.
+func (c *external) clientConfiguration(ctx context.Context, cr *v1alpha1.ConfigurationApply) (*v1alpha1.ClientConfiguration, error) {
+	clientConfig, err := clients.ResolveClientConfiguration(ctx, c.kube, clients.ClientConfigurationSource{
+		ClientConfiguration: cr.Spec.ForProvider.ClientConfiguration,
+		SecretRef:           cr.Spec.ForProvider.ClientConfigurationSecretRef,
+		SecretsRef:          cr.Spec.ForProvider.SecretsRef,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if clientConfig == nil || clientConfig.ClientCertificate == "" {
+		return clients.InsecureClientConfiguration(), nil
 	}
 
-	cert, err := tls.X509KeyPair([]byte(clientConfig.ClientCertificate), []byte(clientConfig.ClientKey))
+	return clientConfig, nil
+}
+
+// talosClient borrows a Talos client authenticating with the client
+// credentials of a ConfigurationApply, and returns the context its calls must
+// use.
+func (c *external) talosClient(ctx context.Context, cr *v1alpha1.ConfigurationApply) (*clients.Client, context.Context, error) {
+	clientConfig, err := c.clientConfiguration(ctx, cr)
 	if err != nil {
-		return nil, errors.Wrap(err, "failed to create client certificate")
+		return nil, nil, err
 	}
 
-	tlsConfig := &tls.Config{
-		Certificates: []tls.Certificate{cert},
-		ServerName:   node,
-		MinVersion:   tls.VersionTLS12,
-	}
+	return c.nodeClient(ctx, cr, clientConfig)
+}
 
-	if clientConfig.CACertificate != "" && clientConfig.CACertificate != "insecure" {
-		roots := x509.NewCertPool()
-		if ok := roots.AppendCertsFromPEM([]byte(clientConfig.CACertificate)); !ok {
-			return nil, errors.New("failed to parse CA certificate")
-		}
-		tlsConfig.RootCAs = roots
+// nodeClient borrows a Talos client for the node of a ConfigurationApply, and
+// returns the context its calls must use. Authenticated calls are routed to
+// the node through spec.forProvider.endpoints. Machines in maintenance mode
+// only accept direct insecure connections.
+func (c *external) nodeClient(ctx context.Context, cr *v1alpha1.ConfigurationApply, clientConfig *v1alpha1.ClientConfiguration) (*clients.Client, context.Context, error) {
+	if clients.IsInsecure(clientConfig) {
+		talosClient, err := c.talosClients.Client(ctx, []string{getConfigurationApplyEndpoint(cr)}, clientConfig)
+		return talosClient, ctx, err
 	}
 
-	return tlsConfig, nil
+	talosClient, err := c.talosClients.Client(ctx, getConfigurationApplyEndpoints(cr), clientConfig)
+
+	return talosClient, talosclient.WithNode(ctx, cr.Spec.ForProvider.Node), err
 }
 
 func getConfigurationApplyEndpoint(cr *v1alpha1.ConfigurationApply) string {
@@ -603,6 +1161,16 @@ func getConfigurationApplyEndpoint(cr *v1alpha1.ConfigurationApply) string {
 	return endpoint
 }
 
+// getConfigurationApplyEndpoints returns the Talos API endpoints authenticated
+// calls for the node are routed through.
+func getConfigurationApplyEndpoints(cr *v1alpha1.ConfigurationApply) []string {
+	if len(cr.Spec.ForProvider.Endpoints) > 0 {
+		return cr.Spec.ForProvider.Endpoints
+	}
+
+	return []string{getConfigurationApplyEndpoint(cr)}
+}
+
 func getConfigurationApplyMode(applyMode *string) (machine.ApplyConfigurationRequest_Mode, error) {
 	if applyMode == nil || *applyMode == "" || *applyMode == "reboot" {
 		return machine.ApplyConfigurationRequest_REBOOT, nil
@@ -620,152 +1188,358 @@ func getConfigurationApplyMode(applyMode *string) (machine.ApplyConfigurationReq
 	}
 }
 
-// generateMachineConfigurationYAML converts structured configuration to Talos machine configuration YAML
-//
-//nolint:gocyclo // Function complexity is acceptable for config generation
+// getResetRequest maps spec.forProvider.onDestroy to a Talos reset request. A
+// nil request means the node is left untouched.
+func getResetRequest(onDestroy *string) (*machine.ResetRequest, error) {
+	if onDestroy == nil || *onDestroy == "" || *onDestroy == "none" {
+		return nil, nil
+	}
+
+	switch *onDestroy {
+	case "reset":
+		return &machine.ResetRequest{Graceful: true, Reboot: true}, nil
+	case "wipe":
+		return &machine.ResetRequest{
+			Reboot: true,
+			Mode:   machine.ResetRequest_SYSTEM_DISK,
+			SystemPartitionsToWipe: []*machine.ResetPartitionSpec{
+				{Label: constants.StatePartitionLabel, Wipe: true},
+				{Label: constants.EphemeralPartitionLabel, Wipe: true},
+			},
+		}, nil
+	case "maintenance":
+		return &machine.ResetRequest{
+			Graceful: true,
+			Reboot:   true,
+			Mode:     machine.ResetRequest_SYSTEM_DISK,
+			SystemPartitionsToWipe: []*machine.ResetPartitionSpec{
+				{Label: constants.StatePartitionLabel, Wipe: true},
+			},
+		}, nil
+	default:
+		return nil, errors.Errorf("unknown onDestroy mode %q", *onDestroy)
+	}
+}
+
+// validationMode is the Talos runtime mode a generated configuration is
+// validated against.
+type validationMode struct {
+	name            string
+	requiresInstall bool
+	inContainer     bool
+}
+
+func (m validationMode) String() string        { return m.name }
+func (m validationMode) RequiresInstall() bool { return m.requiresInstall }
+func (m validationMode) InContainer() bool     { return m.inContainer }
+
+func getValidationMode(mode *string) (validation.RuntimeMode, error) {
+	if mode == nil || *mode == "" || *mode == "metal" {
+		return validationMode{name: "metal", requiresInstall: true}, nil
+	}
+
+	switch *mode {
+	case "cloud":
+		return validationMode{name: "cloud"}, nil
+	case "container":
+		return validationMode{name: "container", inContainer: true}, nil
+	default:
+		return nil, errors.Errorf("unknown validation mode %q", *mode)
+	}
+}
+
+// validateMachineConfiguration validates rendered machine configuration with
+// the Talos config validator for the given runtime mode.
+func validateMachineConfiguration(configInput []byte, mode *string) error {
+	runtimeMode, err := getValidationMode(mode)
+	if err != nil {
+		return err
+	}
+
+	cfg, err := configloader.NewFromBytes(configInput)
+	if err != nil {
+		return errors.Wrap(err, "cannot load machine configuration")
+	}
+
+	if _, err := cfg.Validate(runtimeMode); err != nil {
+		return errors.Wrapf(err, "machine configuration is invalid for %s mode", runtimeMode)
+	}
+
+	return nil
+}
+
+// generateMachineConfigurationYAML converts structured configuration to Talos
+// machine configuration YAML using the Talos machinery config types.
 func (c *external) generateMachineConfigurationYAML(config v1alpha1.MachineConfigurationSpec) (string, error) {
-	// Build the YAML configuration from structured fields
+	cfg, err := buildMachineConfig(config)
+	if err != nil {
+		return "", err
+	}
 
-	// Set defaults
+	provider, err := container.New(cfg)
+	if err != nil {
+		return "", err
+	}
+
+	return provider.EncodeString(encoder.WithComments(encoder.CommentsDisabled))
+}
+
+func buildMachineConfig(config v1alpha1.MachineConfigurationSpec) (*talosv1alpha1.Config, error) {
 	version := config.Version
 	if version == "" {
 		version = "v1alpha1"
 	}
+	if version != "v1alpha1" {
+		return nil, errors.Errorf("unsupported machine configuration version %q", version)
+	}
 
-	// Build machine section
-	machineType := config.Machine.Type
-	if machineType != "controlplane" && machineType != "worker" {
-		return "", errors.New("machine.type must be 'controlplane' or 'worker'")
+	machineConfig, err := buildMachineSection(config.Machine)
+	if err != nil {
+		return nil, err
+	}
+
+	clusterConfig, err := buildClusterSection(config.Cluster)
+	if err != nil {
+		return nil, err
+	}
+
+	return &talosv1alpha1.Config{
+		ConfigVersion: version,
+		ConfigDebug:   ptr.To(false),
+		ConfigPersist: ptr.To(true),
+		MachineConfig: machineConfig,
+		ClusterConfig: clusterConfig,
+	}, nil
+}
+
+func buildMachineSection(spec v1alpha1.MachineSpec) (*talosv1alpha1.MachineConfig, error) {
+	if spec.Type != "controlplane" && spec.Type != "worker" {
+		return nil, errors.New("machine.type must be 'controlplane' or 'worker'")
+	}
+
+	machineConfig := &talosv1alpha1.MachineConfig{
+		MachineType:  spec.Type,
+		MachineToken: spec.Token,
+		MachineInstall: &talosv1alpha1.InstallConfig{
+			InstallDisk:  spec.Install.Disk,
+			InstallImage: spec.Install.Image,
+			InstallWipe:  ptr.To(spec.Install.Wipe != nil && *spec.Install.Wipe),
+		},
+	}
+
+	if spec.Install.DiskSelector != nil {
+		selector, err := buildInstallDiskSelector(*spec.Install.DiskSelector)
+		if err != nil {
+			return nil, err
+		}
+		machineConfig.MachineInstall.InstallDiskSelector = selector
+	}
+
+	if spec.Network != nil {
+		machineConfig.MachineNetwork = buildNetworkSection(*spec.Network)
+	}
+
+	if spec.Kubelet != nil && spec.Kubelet.Image != nil {
+		machineConfig.MachineKubelet = &talosv1alpha1.KubeletConfig{
+			KubeletImage: *spec.Kubelet.Image,
+			KubeletDefaultRuntimeSeccompProfileEnabled: ptr.To(true),
+			KubeletDisableManifestsDirectory:           ptr.To(true),
+		}
+	}
+
+	if spec.Features != nil && spec.Features.RBAC != nil && *spec.Features.RBAC {
+		machineConfig.MachineFeatures = &talosv1alpha1.FeaturesConfig{
+			RBAC:                 ptr.To(true),
+			StableHostname:       ptr.To(true),
+			ApidCheckExtKeyUsage: ptr.To(true),
+			DiskQuotaSupport:     ptr.To(true),
+		}
+	}
+
+	if spec.CA != nil && spec.CA.Crt != "" {
+		crt := []byte(strings.TrimSpace(spec.CA.Crt))
+		if spec.Type == "controlplane" && spec.CA.Key != "" {
+			// Controlplane nodes get the full CA with private key.
+			machineConfig.MachineCA = &siderox509.PEMEncodedCertificateAndKey{Crt: crt, Key: []byte(strings.TrimSpace(spec.CA.Key))}
+		} else {
+			// Worker nodes only get the certificate in acceptedCAs (no private key).
+			machineConfig.MachineAcceptedCAs = []*siderox509.PEMEncodedCertificate{{Crt: crt}}
+		}
+	}
+
+	return machineConfig, nil
+}
+
+func buildInstallDiskSelector(spec v1alpha1.InstallDiskSelector) (*talosv1alpha1.InstallDiskSelector, error) {
+	selector := &talosv1alpha1.InstallDiskSelector{
+		Name:     ptr.Deref(spec.Name, ""),
+		Model:    ptr.Deref(spec.Model, ""),
+		Serial:   ptr.Deref(spec.Serial, ""),
+		Modalias: ptr.Deref(spec.Modalias, ""),
+		UUID:     ptr.Deref(spec.UUID, ""),
+		WWID:     ptr.Deref(spec.WWID, ""),
+		Type:     talosv1alpha1.InstallDiskType(ptr.Deref(spec.Type, "")),
+		BusPath:  ptr.Deref(spec.BusPath, ""),
+	}
+
+	if spec.Size != nil {
+		// The size matcher only parses its condition when unmarshaled.
+		node := &yamlv3.Node{Kind: yamlv3.ScalarNode, Tag: "!!str", Value: *spec.Size}
+		selector.Size = &talosv1alpha1.InstallDiskSizeMatcher{}
+		if err := node.Decode(selector.Size); err != nil {
+			return nil, errors.Wrapf(err, "invalid install disk selector size %q", *spec.Size)
+		}
+	}
+
+	return selector, nil
+}
+
+func buildNetworkSection(spec v1alpha1.NetworkSpec) *talosv1alpha1.NetworkConfig {
+	network := &talosv1alpha1.NetworkConfig{
+		NameServers: spec.Nameservers,
+	}
+	if spec.Hostname != nil {
+		network.NetworkHostname = *spec.Hostname
+	}
+
+	for _, iface := range spec.Interfaces {
+		device := &talosv1alpha1.Device{
+			DeviceInterface: iface.Interface,
+			DeviceAddresses: iface.Addresses,
+			DeviceDHCP:      iface.DHCP,
+			DeviceRoutes:    buildRoutes(iface.Routes),
+			DeviceVIPConfig: buildVIP(iface.VIP),
+		}
+		if iface.MTU != nil {
+			device.DeviceMTU = int(*iface.MTU)
+		}
+
+		for _, vlan := range iface.VLANs {
+			v := &talosv1alpha1.Vlan{
+				VlanID:        uint16(vlan.VLANID), //nolint:gosec // Bounded to 1-4094 by CRD validation.
+				VlanAddresses: vlan.Addresses,
+				VlanDHCP:      vlan.DHCP,
+				VlanRoutes:    buildRoutes(vlan.Routes),
+				VlanVIP:       buildVIP(vlan.VIP),
+			}
+			if vlan.MTU != nil {
+				v.VlanMTU = uint32(*vlan.MTU) //nolint:gosec // Non-negative by CRD validation.
+			}
+			device.DeviceVlans = append(device.DeviceVlans, v)
+		}
+
+		if bond := iface.Bond; bond != nil {
+			device.DeviceBond = &talosv1alpha1.Bond{
+				BondInterfaces: bond.Interfaces,
+				BondMode:       bond.Mode,
+			}
+			if bond.LACPRate != nil {
+				device.DeviceBond.BondLACPRate = *bond.LACPRate
+			}
+			if bond.XmitHashPolicy != nil {
+				device.DeviceBond.BondHashPolicy = *bond.XmitHashPolicy
+			}
+			if bond.MIIMon != nil {
+				device.DeviceBond.BondMIIMon = uint32(*bond.MIIMon) //nolint:gosec // Non-negative by CRD validation.
+			}
+		}
+
+		if bridge := iface.Bridge; bridge != nil {
+			device.DeviceBridge = &talosv1alpha1.Bridge{BridgedInterfaces: bridge.Interfaces}
+			if bridge.STP != nil {
+				device.DeviceBridge.BridgeSTP = &talosv1alpha1.STP{STPEnabled: bridge.STP}
+			}
+		}
+
+		network.NetworkInterfaces = append(network.NetworkInterfaces, device)
+	}
+
+	for _, host := range spec.ExtraHostEntries {
+		network.ExtraHostEntries = append(network.ExtraHostEntries, &talosv1alpha1.ExtraHost{
+			HostIP:      host.IP,
+			HostAliases: host.Aliases,
+		})
+	}
+
+	return network
+}
+
+func buildRoutes(specs []v1alpha1.RouteSpec) []*talosv1alpha1.Route {
+	routes := make([]*talosv1alpha1.Route, 0, len(specs))
+	for _, spec := range specs {
+		route := &talosv1alpha1.Route{
+			RouteNetwork: spec.Network,
+			RouteGateway: spec.Gateway,
+		}
+		if spec.Source != nil {
+			route.RouteSource = *spec.Source
+		}
+		if spec.Metric != nil {
+			route.RouteMetric = uint32(*spec.Metric) //nolint:gosec // Non-negative by CRD validation.
+		}
+		routes = append(routes, route)
+	}
+
+	return routes
+}
+
+func buildVIP(spec *v1alpha1.VIPSpec) *talosv1alpha1.DeviceVIPConfig {
+	if spec == nil {
+		return nil
+	}
+
+	return &talosv1alpha1.DeviceVIPConfig{SharedIP: spec.IP}
+}
+
+func buildClusterSection(spec v1alpha1.ClusterSpec) (*talosv1alpha1.ClusterConfig, error) {
+	if spec.ControlPlane.Endpoint == "" {
+		return nil, errors.New("cluster.controlPlane.endpoint must be set")
+	}
+	endpoint, err := url.Parse(spec.ControlPlane.Endpoint)
+	if err != nil {
+		return nil, errors.Wrap(err, "cannot parse cluster.controlPlane.endpoint")
 	}
 
-	// Build cluster networking defaults
 	dnsDomain := "cluster.local"
-	if config.Cluster.Network.DNSDomain != nil {
-		dnsDomain = *config.Cluster.Network.DNSDomain
+	if spec.Network.DNSDomain != nil {
+		dnsDomain = *spec.Network.DNSDomain
 	}
 
 	podSubnets := []string{"10.244.0.0/16"}
-	if len(config.Cluster.Network.PodSubnets) > 0 {
-		podSubnets = config.Cluster.Network.PodSubnets
+	if len(spec.Network.PodSubnets) > 0 {
+		podSubnets = spec.Network.PodSubnets
 	}
 
 	serviceSubnets := []string{"10.96.0.0/12"}
-	if len(config.Cluster.Network.ServiceSubnets) > 0 {
-		serviceSubnets = config.Cluster.Network.ServiceSubnets
-	}
-
-	// Build kubelet section
-	kubeletSection := ""
-	if config.Machine.Kubelet != nil && config.Machine.Kubelet.Image != nil {
-		kubeletSection = fmt.Sprintf(`  kubelet:
-    image: %s
-    defaultRuntimeSeccompProfileEnabled: true
-    disableManifestsDirectory: true`, *config.Machine.Kubelet.Image)
-	}
-
-	// Build features section
-	featuresSection := ""
-	if config.Machine.Features != nil && config.Machine.Features.RBAC != nil && *config.Machine.Features.RBAC {
-		featuresSection = `  features:
-    rbac: true
-    stableHostname: true
-    apidCheckExtKeyUsage: true
-    diskQuotaSupport: true`
-	}
-
-	// Build machine CA section
-	caSection := ""
-	if config.Machine.CA != nil && config.Machine.CA.Crt != "" {
-		if machineType == "controlplane" && config.Machine.CA.Key != "" {
-			// Controlplane nodes get the full CA with private key
-			crtBase64 := base64.StdEncoding.EncodeToString([]byte(strings.TrimSpace(config.Machine.CA.Crt)))
-			keyBase64 := base64.StdEncoding.EncodeToString([]byte(strings.TrimSpace(config.Machine.CA.Key)))
-
-			caSection = fmt.Sprintf(`  ca:
-    crt: %s
-    key: %s`, crtBase64, keyBase64)
-		} else {
-			// Worker nodes only get the certificate in acceptedCAs (no private key)
-			crtBase64 := base64.StdEncoding.EncodeToString([]byte(strings.TrimSpace(config.Machine.CA.Crt)))
+	if len(spec.Network.ServiceSubnets) > 0 {
+		serviceSubnets = spec.Network.ServiceSubnets
+	}
 
-			caSection = fmt.Sprintf(`  acceptedCAs:
-    - crt: %s`, crtBase64)
-		}
+	clusterConfig := &talosv1alpha1.ClusterConfig{
+		ClusterID:     spec.ID,
+		ClusterSecret: spec.Secret,
+		ClusterName:   spec.ClusterName,
+		ControlPlane: &talosv1alpha1.ControlPlaneConfig{
+			Endpoint: &talosv1alpha1.Endpoint{URL: endpoint},
+		},
+		ClusterNetwork: &talosv1alpha1.ClusterNetworkConfig{
+			DNSDomain:     dnsDomain,
+			PodSubnet:     podSubnets,
+			ServiceSubnet: serviceSubnets,
+		},
+		BootstrapToken: spec.Token,
 	}
 
-	// Build cluster CA section (Kubernetes CA)
-	clusterCASection := ""
-	if config.Cluster.CA != nil && config.Cluster.CA.Crt != "" {
-		// Base64 encode the Kubernetes CA certificate
-		crtBase64 := base64.StdEncoding.EncodeToString([]byte(strings.TrimSpace(config.Cluster.CA.Crt)))
+	if spec.SecretboxEncryptionSecret != nil {
+		clusterConfig.ClusterSecretboxEncryptionSecret = *spec.SecretboxEncryptionSecret
+	}
 
-		if config.Cluster.CA.Key != "" {
-			// Include both certificate and key if key is provided
-			keyBase64 := base64.StdEncoding.EncodeToString([]byte(strings.TrimSpace(config.Cluster.CA.Key)))
-			clusterCASection = fmt.Sprintf(`  ca:
-    crt: %s
-    key: %s`, crtBase64, keyBase64)
-		} else {
-			// Include only certificate if no key is provided (typical for worker nodes)
-			clusterCASection = fmt.Sprintf(`  ca:
-    crt: %s`, crtBase64)
+	if spec.CA != nil && spec.CA.Crt != "" {
+		clusterConfig.ClusterCA = &siderox509.PEMEncodedCertificateAndKey{Crt: []byte(strings.TrimSpace(spec.CA.Crt))}
+		if spec.CA.Key != "" {
+			clusterConfig.ClusterCA.Key = []byte(strings.TrimSpace(spec.CA.Key))
 		}
 	}
 
-	// Generate YAML configuration
-	yamlConfig := fmt.Sprintf(`# Talos machine configuration generated from structured fields
-version: %s
-debug: false
-persist: true
-machine:
-  type: %s
-  token: %s
-  install:
-    disk: %s
-    image: %s
-    wipe: %t
-%s
-%s
-%s
-  network: {}
-  sysctls: {}
-  sysfs: {}
-  registries: {}
-cluster:
-  id: %s
-  secret: %s
-  controlPlane:
-    endpoint: %s
-  clusterName: %s
-  network:
-    dnsDomain: %s
-    podSubnets:
-      - %s
-    serviceSubnets:
-      - %s
-  token: %s
-%s
-  secretboxEncryptionSecret: ""
-`,
-		version,
-		machineType,
-		config.Machine.Token,
-		config.Machine.Install.Disk,
-		config.Machine.Install.Image,
-		config.Machine.Install.Wipe != nil && *config.Machine.Install.Wipe,
-		kubeletSection,
-		featuresSection,
-		caSection,
-		config.Cluster.ID,
-		config.Cluster.Secret,
-		config.Cluster.ControlPlane.Endpoint,
-		config.Cluster.ClusterName,
-		dnsDomain,
-		podSubnets[0],     // First pod subnet
-		serviceSubnets[0], // First service subnet
-		config.Cluster.Token,
-		clusterCASection,
-	)
-
-	return yamlConfig, nil
+	return clusterConfig, nil
 }
@@ -20,7 +20,6 @@ import (
 	"context"
 	"crypto/rand"
 	"crypto/rsa"
-	"crypto/tls"
 	"crypto/x509"
 	"crypto/x509/pkix"
 	"encoding/pem"
@@ -32,13 +31,18 @@ import (
 	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
 	"github.com/crossplane/crossplane-runtime/pkg/meta"
 	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
 	"github.com/siderolabs/talos/pkg/machinery/api/machine"
+	talosconfig "github.com/siderolabs/talos/pkg/machinery/config"
+	"github.com/siderolabs/talos/pkg/machinery/config/configloader"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/utils/ptr"
 	ctrlfake "sigs.k8s.io/controller-runtime/pkg/client/fake"
 
 	v1alpha1 "github.com/crossplane-contrib/provider-talos/apis/machine/v1alpha1"
+	"github.com/crossplane-contrib/provider-talos/internal/clients"
 	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
 	"github.com/crossplane/crossplane-runtime/pkg/resource"
 	"github.com/crossplane/crossplane-runtime/pkg/test"
@@ -159,6 +163,328 @@ func TestObserveMaintenanceModeKeepsSuccessfulExternalCreate(t *testing.T) {
 	}
 }
 
+func TestObserveDiskInventory(t *testing.T) {
+	disks := []v1alpha1.MachineDisk{{Name: "nvme0n1", DevPath: "/dev/nvme0n1", Size: 1 << 40, Transport: "nvme"}}
+	previous := []v1alpha1.MachineDisk{{Name: "sda", DevPath: "/dev/sda"}}
+
+	tests := map[string]struct {
+		maintenanceMode bool
+		readErr         error
+		want            []v1alpha1.MachineDisk
+		wantState       MachineState
+	}{
+		"MaintenanceModePublishesDisks": {
+			maintenanceMode: true,
+			want:            disks,
+			wantState:       MachineStateMaintenanceMode,
+		},
+		"ReadErrorKeepsPreviousDisks": {
+			maintenanceMode: true,
+			readErr:         errors.New("boom"),
+			want:            previous,
+			wantState:       MachineStateMaintenanceMode,
+		},
+		"UnreachableKeepsPreviousDisks": {
+			want: previous,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			cr := testConfigurationApply()
+			cr.Status.AtProvider.Disks = previous
+
+			var gotState MachineState
+			e := external{
+				canConnectInsecureFn: func(context.Context, *v1alpha1.ConfigurationApply) bool { return tc.maintenanceMode },
+				readDisksFn: func(_ context.Context, _ *v1alpha1.ConfigurationApply, state MachineState) ([]v1alpha1.MachineDisk, error) {
+					gotState = state
+					return disks, tc.readErr
+				},
+				readNodeStatusFn: func(context.Context, *v1alpha1.ConfigurationApply, MachineState) (clients.NodeStatus, error) {
+					return clients.NodeStatus{}, nil
+				},
+			}
+			if _, err := e.Observe(context.Background(), cr); err != nil {
+				t.Fatalf("e.Observe(...): unexpected error: %v", err)
+			}
+
+			if diff := cmp.Diff(tc.want, cr.Status.AtProvider.Disks); diff != "" {
+				t.Errorf("cr.Status.AtProvider.Disks: -want, +got:\n%s", diff)
+			}
+			if gotState != tc.wantState {
+				t.Errorf("readDisks(...) called with state %q, want %q", gotState, tc.wantState)
+			}
+		})
+	}
+}
+
+func TestObserveNodeStatus(t *testing.T) {
+	status := clients.NodeStatus{
+		TalosVersion: "v1.11.0",
+		Stage:        "maintenance",
+		Hostname:     "talos-abc-def",
+		UUID:         "4c4c4544-0000-1000-8000-000000000001",
+	}
+
+	tests := map[string]struct {
+		maintenanceMode bool
+		readErr         error
+		want            v1alpha1.ConfigurationApplyObservation
+	}{
+		"MaintenanceModePublishesStatus": {
+			maintenanceMode: true,
+			want: v1alpha1.ConfigurationApplyObservation{
+				TalosVersion: "v1.11.0",
+				MachineStage: "maintenance",
+				Hostname:     "talos-abc-def",
+				NodeUUID:     "4c4c4544-0000-1000-8000-000000000001",
+			},
+		},
+		"ReadErrorKeepsPreviousStatus": {
+			maintenanceMode: true,
+			readErr:         errors.New("boom"),
+			want:            v1alpha1.ConfigurationApplyObservation{TalosVersion: "v1.10.0", MachineStage: "installing"},
+		},
+		"UnreachableKeepsPreviousStatus": {
+			want: v1alpha1.ConfigurationApplyObservation{TalosVersion: "v1.10.0", MachineStage: "installing"},
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			cr := testConfigurationApply()
+			cr.Status.AtProvider.TalosVersion = "v1.10.0"
+			cr.Status.AtProvider.MachineStage = "installing"
+
+			e := external{
+				canConnectInsecureFn: func(context.Context, *v1alpha1.ConfigurationApply) bool { return tc.maintenanceMode },
+				readNodeStatusFn: func(context.Context, *v1alpha1.ConfigurationApply, MachineState) (clients.NodeStatus, error) {
+					return status, tc.readErr
+				},
+				readDisksFn: func(context.Context, *v1alpha1.ConfigurationApply, MachineState) ([]v1alpha1.MachineDisk, error) {
+					return nil, nil
+				},
+			}
+			if _, err := e.Observe(context.Background(), cr); err != nil {
+				t.Fatalf("e.Observe(...): unexpected error: %v", err)
+			}
+
+			got := v1alpha1.ConfigurationApplyObservation{
+				TalosVersion: cr.Status.AtProvider.TalosVersion,
+				MachineStage: cr.Status.AtProvider.MachineStage,
+				Hostname:     cr.Status.AtProvider.Hostname,
+				NodeUUID:     cr.Status.AtProvider.NodeUUID,
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("cr.Status.AtProvider: -want, +got:\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestObserveDeletion(t *testing.T) {
+	reset := "reset"
+	none := "none"
+
+	tests := map[string]struct {
+		onDestroy       *string
+		maintenanceMode bool
+		applied         bool
+		resetRequested  bool
+		age             time.Duration
+		timeout         *metav1.Duration
+		wantExists      bool
+		wantState       string
+		wantReason      xpv1.ConditionReason
+	}{
+		"NilOnDestroyCompletesImmediately": {
+			applied: true,
+		},
+		"NoneCompletesImmediately": {
+			onDestroy: &none,
+			applied:   true,
+		},
+		"ResetPendingForAppliedNode": {
+			onDestroy:  &reset,
+			applied:    true,
+			wantExists: true,
+			wantState:  string(MachineStateUnreachable),
+			wantReason: ReasonResetPending,
+		},
+		"ResetInProgressUntilMaintenanceMode": {
+			onDestroy:      &reset,
+			applied:        true,
+			resetRequested: true,
+			age:            29 * time.Minute,
+			wantExists:     true,
+			wantState:      string(MachineStateResetting),
+			wantReason:     ReasonResetPending,
+		},
+		"ResetTimesOut": {
+			onDestroy:      &reset,
+			applied:        true,
+			resetRequested: true,
+			age:            30 * time.Minute,
+			wantReason:     ReasonResetTimedOut,
+		},
+		"ResetHonorsOnDestroyTimeout": {
+			onDestroy:      &reset,
+			applied:        true,
+			resetRequested: true,
+			age:            30 * time.Minute,
+			timeout:        &metav1.Duration{Duration: time.Hour},
+			wantExists:     true,
+			wantReason:     ReasonResetPending,
+		},
+		"UnreachableNodeTimesOutBeforeReset": {
+			onDestroy:  &reset,
+			applied:    true,
+			age:        time.Hour,
+			wantReason: ReasonResetTimedOut,
+		},
+		"ResetCompleteInMaintenanceMode": {
+			onDestroy:       &reset,
+			applied:         true,
+			resetRequested:  true,
+			maintenanceMode: true,
+			wantState:       string(MachineStateMaintenanceMode),
+		},
+		"NeverAppliedNodeCompletesImmediately": {
+			onDestroy: &reset,
+			wantState: string(MachineStateUnreachable),
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			cr := testConfigurationApply()
+			cr.Spec.ForProvider.OnDestroy = tc.onDestroy
+			cr.Spec.ForProvider.OnDestroyTimeout = tc.timeout
+			cr.Status.AtProvider.Applied = tc.applied
+			deleted := metav1.NewTime(time.Now().Add(-tc.age))
+			cr.SetDeletionTimestamp(&deleted)
+			if tc.resetRequested {
+				cr.Status.AtProvider.ResetTime = &deleted
+			}
+
+			e := external{canConnectInsecureFn: func(context.Context, *v1alpha1.ConfigurationApply) bool { return tc.maintenanceMode }}
+			got, err := e.Observe(context.Background(), cr)
+			if err != nil {
+				t.Fatalf("e.Observe(...): unexpected error: %v", err)
+			}
+			if diff := cmp.Diff(tc.wantExists, got.ResourceExists); diff != "" {
+				t.Errorf("e.Observe(...).ResourceExists: -want, +got:\n%s", diff)
+			}
+			if tc.wantState != "" {
+				if diff := cmp.Diff(tc.wantState, cr.Status.AtProvider.MachineState); diff != "" {
+					t.Errorf("cr.Status.AtProvider.MachineState: -want, +got:\n%s", diff)
+				}
+			}
+			if diff := cmp.Diff(tc.wantReason, cr.GetCondition(TypeReset).Reason); diff != "" {
+				t.Errorf("cr.GetCondition(TypeReset).Reason: -want, +got:\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestDeleteSkipsRequestedReset(t *testing.T) {
+	reset := "reset"
+	cr := testConfigurationApply()
+	cr.Spec.ForProvider.OnDestroy = &reset
+	resetTime := metav1.NewTime(time.Date(2026, 5, 8, 12, 0, 0, 0, time.UTC))
+	cr.Status.AtProvider.ResetTime = &resetTime
+
+	e := external{}
+	if _, err := e.Delete(context.Background(), cr); err != nil {
+		t.Fatalf("e.Delete(...): unexpected error: %v", err)
+	}
+	if !cr.Status.AtProvider.ResetTime.Equal(&resetTime) {
+		t.Fatalf("cr.Status.AtProvider.ResetTime = %v, want %v", cr.Status.AtProvider.ResetTime, resetTime)
+	}
+}
+
+func TestGetResetRequest(t *testing.T) {
+	empty := ""
+	none := "none"
+	reset := "reset"
+	wipe := "wipe"
+	maintenance := "maintenance"
+	unknown := "unknown"
+
+	type want struct {
+		noop       bool
+		graceful   bool
+		partitions []string
+	}
+
+	tests := map[string]struct {
+		onDestroy *string
+		want      want
+		wantErr   bool
+	}{
+		"NilIsNoop": {
+			want: want{noop: true},
+		},
+		"EmptyIsNoop": {
+			onDestroy: &empty,
+			want:      want{noop: true},
+		},
+		"None": {
+			onDestroy: &none,
+			want:      want{noop: true},
+		},
+		"ResetIsGracefulFullWipe": {
+			onDestroy: &reset,
+			want:      want{graceful: true},
+		},
+		"WipeClearsStateAndEphemeral": {
+			onDestroy: &wipe,
+			want:      want{partitions: []string{"STATE", "EPHEMERAL"}},
+		},
+		"MaintenanceClearsState": {
+			onDestroy: &maintenance,
+			want:      want{graceful: true, partitions: []string{"STATE"}},
+		},
+		"UnknownErrors": {
+			onDestroy: &unknown,
+			want:      want{noop: true},
+			wantErr:   true,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, err := getResetRequest(tc.onDestroy)
+			if tc.wantErr && err == nil {
+				t.Fatal("getResetRequest(...): expected error")
+			}
+			if !tc.wantErr && err != nil {
+				t.Fatalf("getResetRequest(...): unexpected error: %v", err)
+			}
+			if tc.want.noop {
+				if got != nil {
+					t.Fatalf("getResetRequest(...) = %v, want nil", got)
+				}
+				return
+			}
+			if !got.GetReboot() {
+				t.Error("getResetRequest(...).Reboot = false, want true")
+			}
+			if diff := cmp.Diff(tc.want.graceful, got.GetGraceful()); diff != "" {
+				t.Errorf("getResetRequest(...).Graceful: -want, +got:\n%s", diff)
+			}
+			var partitions []string
+			for _, p := range got.GetSystemPartitionsToWipe() {
+				partitions = append(partitions, p.GetLabel())
+			}
+			if diff := cmp.Diff(tc.want.partitions, partitions); diff != "" {
+				t.Errorf("getResetRequest(...).SystemPartitionsToWipe: -want, +got:\n%s", diff)
+			}
+		})
+	}
+}
+
 func testConfigurationApply() *v1alpha1.ConfigurationApply {
 	return &v1alpha1.ConfigurationApply{
 		ObjectMeta: metav1.ObjectMeta{Name: "test-apply"},
@@ -173,6 +499,7 @@ func testConfigurationApply() *v1alpha1.ConfigurationApply {
 						Disk:  "/dev/sda",
 						Image: "ghcr.io/siderolabs/installer:v1.11.0",
 					},
+					CA: &v1alpha1.CASpec{Crt: "test-os-ca-crt", Key: "test-os-ca-key"},
 				},
 				Cluster: v1alpha1.ClusterSpec{
 					ID:          "cluster-id",
@@ -229,6 +556,25 @@ func TestResolveMachineConfiguration(t *testing.T) {
 			cr:   testConfigurationApply(),
 			want: []byte("version: v1alpha1"),
 		},
+		"SecretRefAppliesConfigPatches": {
+			cr: testConfigurationApplyWithPatches(
+				testConfigurationApplyWithSecretRef("config", "default", "machine_configuration"),
+				`[{"op": "replace", "path": "/debug", "value": false}]`,
+			),
+			objects: []runtime.Object{&corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Name: "config", Namespace: "default"},
+				Data:       map[string][]byte{"machine_configuration": rawConfig},
+			}},
+			want: []byte("debug: false"),
+		},
+		"StructuredAppliesConfigPatches": {
+			cr:   testConfigurationApplyWithPatches(testConfigurationApply(), "machine:\n  network:\n    hostname: node-a\n"),
+			want: []byte("hostname: node-a"),
+		},
+		"InvalidConfigPatchErrors": {
+			cr:      testConfigurationApplyWithPatches(testConfigurationApply(), "[{\"op\": \"replace\", \"path\": \"/missing/field\", \"value\": 1}]"),
+			wantErr: true,
+		},
 	}
 
 	for name, tc := range tests {
@@ -249,7 +595,7 @@ func TestResolveMachineConfiguration(t *testing.T) {
 			if tc.wantErr {
 				return
 			}
-			if name == "StructuredFallbackRendersYAML" {
+			if name == "StructuredFallbackRendersYAML" || strings.HasSuffix(name, "AppliesConfigPatches") {
 				if !strings.Contains(string(got), string(tc.want)) {
 					t.Fatalf("resolveMachineConfiguration(...) = %q, want to contain %q", got, tc.want)
 				}
@@ -269,6 +615,559 @@ func testConfigurationApplyWithSecretRef(name, namespace, key string) *v1alpha1.
 	return cr
 }
 
+func TestObserveConfigDrift(t *testing.T) {
+	desired := []byte("version: v1alpha1\n# node name\nmachine:\n  type: worker\n  token: machine-token\n  network:\n    hostname: worker-1\ncluster:\n  clusterName: test-cluster\n")
+
+	upgraded := []byte("version: v1alpha1\nmachine:\n  type: worker\n  token: machine-token\n  network:\n    hostname: worker-1\n  kubelet:\n    image: ghcr.io/siderolabs/kubelet:v1.35.0\ncluster:\n  clusterName: test-cluster\n")
+	kubeletImage := &v1alpha1.KubernetesImages{Kubelet: ptr.To("ghcr.io/siderolabs/kubelet:v1.35.0")}
+
+	tests := map[string]struct {
+		running   []byte
+		images    *v1alpha1.KubernetesImages
+		readErr   error
+		wantDrift []string
+		wantErr   bool
+	}{
+		"IdenticalConfigHasNoDrift": {
+			running: desired,
+		},
+		"RecordedKubernetesImagesHaveNoDrift": {
+			running: upgraded,
+			images:  kubeletImage,
+		},
+		"UnrecordedKubernetesUpgradeReportsPath": {
+			running:   upgraded,
+			wantDrift: []string{"machine.kubelet"},
+		},
+		"CommentsAndKeyOrderAreIgnored": {
+			running: []byte("cluster:\n  clusterName: test-cluster\nmachine:\n  network:\n    hostname: worker-1\n  token: machine-token\n  type: worker\nversion: v1alpha1\n"),
+		},
+		"ChangedValueReportsPath": {
+			running:   []byte("version: v1alpha1\nmachine:\n  type: worker\n  token: machine-token\n  network:\n    hostname: edited\ncluster:\n  clusterName: test-cluster\n"),
+			wantDrift: []string{"machine.network.hostname"},
+		},
+		"AddedAndRemovedFieldsReportPaths": {
+			running:   []byte("version: v1alpha1\nmachine:\n  type: worker\n  token: machine-token\n  nodeLabels:\n    rack: r1\ncluster:\n  clusterName: test-cluster\n"),
+			wantDrift: []string{"machine.network", "machine.nodeLabels"},
+		},
+		"ReadErrorIsReturned": {
+			readErr: errors.New("boom"),
+			wantErr: true,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			scheme := runtime.NewScheme()
+			if err := corev1.AddToScheme(scheme); err != nil {
+				t.Fatalf("corev1.AddToScheme(...): %v", err)
+			}
+
+			e := external{
+				kube: ctrlfake.NewClientBuilder().WithScheme(scheme).WithRuntimeObjects(&corev1.Secret{
+					ObjectMeta: metav1.ObjectMeta{Name: "config", Namespace: "default"},
+					Data:       map[string][]byte{"machine_configuration": desired},
+				}).Build(),
+				readMachineConfigFn: func(context.Context, *v1alpha1.ConfigurationApply) ([]byte, error) {
+					return tc.running, tc.readErr
+				},
+			}
+			cr := testConfigurationApplyWithSecretRef("config", "default", "machine_configuration")
+			cr.Spec.ForProvider.KubernetesImages = tc.images
+
+			got, err := e.observeConfigDrift(context.Background(), cr)
+			if tc.wantErr && err == nil {
+				t.Fatal("observeConfigDrift(...): expected error")
+			}
+			if !tc.wantErr && err != nil {
+				t.Fatalf("observeConfigDrift(...): unexpected error: %v", err)
+			}
+			if tc.wantErr {
+				return
+			}
+			if diff := cmp.Diff(tc.wantDrift, got); diff != "" {
+				t.Errorf("observeConfigDrift(...): -want, +got:\n%s", diff)
+			}
+			if diff := cmp.Diff(tc.wantDrift, cr.Status.AtProvider.ConfigDrift); diff != "" {
+				t.Errorf("cr.Status.AtProvider.ConfigDrift: -want, +got:\n%s", diff)
+			}
+			if cr.Status.AtProvider.DesiredConfigHash == "" || cr.Status.AtProvider.AppliedConfigHash == "" {
+				t.Fatal("observeConfigDrift(...): config hashes not recorded in status")
+			}
+			if gotEqual := cr.Status.AtProvider.DesiredConfigHash == cr.Status.AtProvider.AppliedConfigHash; gotEqual != (len(tc.wantDrift) == 0) {
+				t.Errorf("observeConfigDrift(...): hashes equal = %t, want %t", gotEqual, len(tc.wantDrift) == 0)
+			}
+			if cr.Status.AtProvider.SourceConfigHash != machineConfigHash(desired) {
+				t.Errorf("observeConfigDrift(...): SourceConfigHash = %q, want hash of machineConfigurationRef", cr.Status.AtProvider.SourceConfigHash)
+			}
+		})
+	}
+}
+
+func TestPatchKubernetesImages(t *testing.T) {
+	configInput := func(version string) []byte {
+		return []byte("version: v1alpha1\nmachine:\n  type: controlplane\n  kubelet:\n    image: ghcr.io/siderolabs/kubelet:" + version +
+			"\ncluster:\n  apiServer:\n    image: registry.k8s.io/kube-apiserver:" + version +
+			"\n  scheduler:\n    image: registry.k8s.io/kube-scheduler:" + version + "\n")
+	}
+
+	type want struct {
+		apiServer string
+		scheduler string
+		kubelet   string
+	}
+
+	cases := map[string]struct {
+		reason string
+		config []byte
+		images *v1alpha1.KubernetesImages
+		want   want
+	}{
+		"NoImages": {
+			reason: "Without kubernetesImages the configuration should be kept.",
+			config: configInput("v1.33.4"),
+			want: want{
+				apiServer: "registry.k8s.io/kube-apiserver:v1.33.4",
+				scheduler: "registry.k8s.io/kube-scheduler:v1.33.4",
+				kubelet:   "ghcr.io/siderolabs/kubelet:v1.33.4",
+			},
+		},
+		"NewerImagesReplaceConfigured": {
+			reason: "Recorded images newer than the configured ones should replace them, keeping their repository.",
+			config: configInput("v1.33.4"),
+			images: &v1alpha1.KubernetesImages{
+				APIServer: ptr.To("mirror.local:5000/kube-apiserver:v1.34.0"),
+				Kubelet:   ptr.To("ghcr.io/siderolabs/kubelet:v1.34.0"),
+			},
+			want: want{
+				apiServer: "mirror.local:5000/kube-apiserver:v1.34.0",
+				scheduler: "registry.k8s.io/kube-scheduler:v1.33.4",
+				kubelet:   "ghcr.io/siderolabs/kubelet:v1.34.0",
+			},
+		},
+		"ConfiguredIsNewer": {
+			reason: "A configuration already past the recorded version should be kept.",
+			config: configInput("v1.35.0"),
+			images: &v1alpha1.KubernetesImages{
+				APIServer: ptr.To("registry.k8s.io/kube-apiserver:v1.34.0"),
+				Scheduler: ptr.To("registry.k8s.io/kube-scheduler:v1.35.0"),
+			},
+			want: want{
+				apiServer: "registry.k8s.io/kube-apiserver:v1.35.0",
+				scheduler: "registry.k8s.io/kube-scheduler:v1.35.0",
+				kubelet:   "ghcr.io/siderolabs/kubelet:v1.35.0",
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			data, err := patchKubernetesImages(tc.config, tc.images)
+			if err != nil {
+				t.Fatalf("\n%s\npatchKubernetesImages(...): unexpected error: %v", tc.reason, err)
+			}
+			cfg, err := configloader.NewFromBytes(data)
+			if err != nil {
+				t.Fatalf("NewFromBytes(...): %v", err)
+			}
+			got := want{
+				apiServer: cfg.Cluster().APIServer().Image(),
+				scheduler: cfg.Cluster().Scheduler().Image(),
+				kubelet:   cfg.Machine().Kubelet().Image(),
+			}
+			if diff := cmp.Diff(tc.want, got, cmp.AllowUnexported(want{})); diff != "" {
+				t.Errorf("\n%s\npatchKubernetesImages(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func testConfigurationApplyWithPatches(cr *v1alpha1.ConfigurationApply, patches ...string) *v1alpha1.ConfigurationApply {
+	cr.Spec.ForProvider.ConfigPatches = patches
+	return cr
+}
+
+func TestApplyConfigPatches(t *testing.T) {
+	base := []byte("version: v1alpha1\nmachine:\n  type: worker\n  token: machine-token\ncluster:\n  clusterName: test-cluster\n")
+
+	tests := map[string]struct {
+		patches  []string
+		contains []string
+		wantErr  bool
+	}{
+		"NoPatchesReturnsInput": {
+			contains: []string{string(base)},
+		},
+		"StrategicMergePatch": {
+			patches:  []string{"machine:\n  network:\n    hostname: worker-1\n"},
+			contains: []string{"hostname: worker-1", "token: machine-token"},
+		},
+		"JSON6902Patch": {
+			patches:  []string{`[{"op": "add", "path": "/machine/nodeLabels", "value": {"rack": "r1"}}]`},
+			contains: []string{"rack: r1", "clusterName: test-cluster"},
+		},
+		"PatchesApplyInOrder": {
+			patches: []string{
+				"machine:\n  network:\n    hostname: worker-1\n",
+				`[{"op": "replace", "path": "/machine/network/hostname", "value": "worker-2"}]`,
+			},
+			contains: []string{"hostname: worker-2"},
+		},
+		"InvalidPatchErrors": {
+			patches: []string{"not: [valid"},
+			wantErr: true,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, err := applyConfigPatches(base, tc.patches)
+			if tc.wantErr && err == nil {
+				t.Fatal("applyConfigPatches(...): expected error")
+			}
+			if !tc.wantErr && err != nil {
+				t.Fatalf("applyConfigPatches(...): unexpected error: %v", err)
+			}
+			for _, want := range tc.contains {
+				if !strings.Contains(string(got), want) {
+					t.Errorf("applyConfigPatches(...) = %q, want to contain %q", got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestGenerateMachineConfigurationYAML(t *testing.T) {
+	secretbox := "c2VjcmV0Ym94LWtleQ=="
+	diskSize := ">= 1TB"
+	diskModel := "WDC*"
+	nvme := "nvme"
+	invalidDiskSize := ">= lots"
+
+	tests := map[string]struct {
+		config   func(*v1alpha1.MachineConfigurationSpec)
+		check    func(t *testing.T, cfg talosconfig.Provider)
+		contains []string
+		excludes []string
+		wantErr  bool
+	}{
+		"KeepsAllSubnets": {
+			config: func(c *v1alpha1.MachineConfigurationSpec) {
+				c.Cluster.Network.PodSubnets = []string{"10.244.0.0/16", "fd00:10:244::/56"}
+				c.Cluster.Network.ServiceSubnets = []string{"10.96.0.0/12", "fd00:10:96::/112"}
+			},
+			check: func(t *testing.T, cfg talosconfig.Provider) {
+				t.Helper()
+				if diff := cmp.Diff([]string{"10.244.0.0/16", "fd00:10:244::/56"}, cfg.Cluster().Network().PodCIDRs()); diff != "" {
+					t.Errorf("PodCIDRs(): -want, +got:\n%s", diff)
+				}
+				if diff := cmp.Diff([]string{"10.96.0.0/12", "fd00:10:96::/112"}, cfg.Cluster().Network().ServiceCIDRs()); diff != "" {
+					t.Errorf("ServiceCIDRs(): -want, +got:\n%s", diff)
+				}
+			},
+		},
+		"DefaultsSubnetsAndDNSDomain": {
+			check: func(t *testing.T, cfg talosconfig.Provider) {
+				t.Helper()
+				if diff := cmp.Diff("cluster.local", cfg.Cluster().Network().DNSDomain()); diff != "" {
+					t.Errorf("DNSDomain(): -want, +got:\n%s", diff)
+				}
+				if diff := cmp.Diff([]string{"10.244.0.0/16"}, cfg.Cluster().Network().PodCIDRs()); diff != "" {
+					t.Errorf("PodCIDRs(): -want, +got:\n%s", diff)
+				}
+			},
+			excludes: []string{"secretboxEncryptionSecret", "network: {}"},
+		},
+		"SetsSecretboxEncryptionSecret": {
+			config: func(c *v1alpha1.MachineConfigurationSpec) {
+				c.Cluster.SecretboxEncryptionSecret = &secretbox
+			},
+			check: func(t *testing.T, cfg talosconfig.Provider) {
+				t.Helper()
+				if diff := cmp.Diff(secretbox, cfg.Cluster().SecretboxEncryptionSecret()); diff != "" {
+					t.Errorf("SecretboxEncryptionSecret(): -want, +got:\n%s", diff)
+				}
+			},
+		},
+		"EscapesValues": {
+			config: func(c *v1alpha1.MachineConfigurationSpec) {
+				c.Cluster.ClusterName = "team: a # prod"
+				c.Machine.Token = "abc\"def"
+			},
+			check: func(t *testing.T, cfg talosconfig.Provider) {
+				t.Helper()
+				if diff := cmp.Diff("team: a # prod", cfg.Cluster().Name()); diff != "" {
+					t.Errorf("Cluster().Name(): -want, +got:\n%s", diff)
+				}
+				if diff := cmp.Diff("abc\"def", cfg.Machine().Security().Token()); diff != "" {
+					t.Errorf("Machine().Security().Token(): -want, +got:\n%s", diff)
+				}
+			},
+		},
+		"RendersNetwork": {
+			config: func(c *v1alpha1.MachineConfigurationSpec) {
+				c.Machine.Network = testNetworkSpec()
+			},
+			check: func(t *testing.T, cfg talosconfig.Provider) {
+				t.Helper()
+				network := cfg.Machine().Network()
+				if diff := cmp.Diff("cp-1", network.Hostname()); diff != "" {
+					t.Errorf("Hostname(): -want, +got:\n%s", diff)
+				}
+				if diff := cmp.Diff([]string{"192.168.1.1", "1.1.1.1"}, network.Resolvers()); diff != "" {
+					t.Errorf("Resolvers(): -want, +got:\n%s", diff)
+				}
+				devices := network.Devices()
+				if len(devices) != 2 {
+					t.Fatalf("len(Devices()) = %d, want 2", len(devices))
+				}
+				bond := devices[0]
+				if diff := cmp.Diff([]string{"192.168.1.10/24"}, bond.Addresses()); diff != "" {
+					t.Errorf("bond0 Addresses(): -want, +got:\n%s", diff)
+				}
+				if bond.Bond() == nil || bond.Bond().Mode() != "802.3ad" || bond.Bond().LACPRate() != "fast" {
+					t.Errorf("bond0 Bond() = %v, want 802.3ad with fast LACP", bond.Bond())
+				}
+				if diff := cmp.Diff([]string{"eth0", "eth1"}, bond.Bond().Interfaces()); diff != "" {
+					t.Errorf("bond0 Bond().Interfaces(): -want, +got:\n%s", diff)
+				}
+				if len(bond.Routes()) != 1 || bond.Routes()[0].Gateway() != "192.168.1.1" || bond.Routes()[0].Metric() != 1024 {
+					t.Errorf("bond0 Routes() = %v, want default route via 192.168.1.1 metric 1024", bond.Routes())
+				}
+				if bond.VIPConfig() == nil || bond.VIPConfig().IP() != "192.168.1.5" {
+					t.Errorf("bond0 VIPConfig() = %v, want 192.168.1.5", bond.VIPConfig())
+				}
+				if len(bond.Vlans()) != 1 || bond.Vlans()[0].ID() != 100 || !bond.Vlans()[0].DHCP() {
+					t.Errorf("bond0 Vlans() = %v, want DHCP VLAN 100", bond.Vlans())
+				}
+				bridge := devices[1]
+				if bridge.Bridge() == nil || !bridge.Bridge().STP().Enabled() {
+					t.Errorf("br0 Bridge() = %v, want STP enabled", bridge.Bridge())
+				}
+				if bridge.MTU() != 9000 {
+					t.Errorf("br0 MTU() = %d, want 9000", bridge.MTU())
+				}
+				hosts := network.ExtraHosts()
+				if len(hosts) != 1 || hosts[0].IP() != "192.168.1.5" {
+					t.Fatalf("ExtraHosts() = %v, want one entry for 192.168.1.5", hosts)
+				}
+				if diff := cmp.Diff([]string{"api.cluster.local"}, hosts[0].Aliases()); diff != "" {
+					t.Errorf("ExtraHosts()[0].Aliases(): -want, +got:\n%s", diff)
+				}
+			},
+		},
+		"WorkerGetsAcceptedCAs": {
+			config: func(c *v1alpha1.MachineConfigurationSpec) {
+				c.Machine.Type = "worker"
+			},
+			check: func(t *testing.T, cfg talosconfig.Provider) {
+				t.Helper()
+				if cfg.Machine().Security().IssuingCA() != nil {
+					t.Error("Machine().Security().IssuingCA() != nil, want nil for worker")
+				}
+				if diff := cmp.Diff(1, len(cfg.Machine().Security().AcceptedCAs())); diff != "" {
+					t.Errorf("len(AcceptedCAs()): -want, +got:\n%s", diff)
+				}
+			},
+		},
+		"RendersInstallDiskSelector": {
+			config: func(c *v1alpha1.MachineConfigurationSpec) {
+				c.Machine.Install.Disk = ""
+				c.Machine.Install.DiskSelector = &v1alpha1.InstallDiskSelector{
+					Size:  &diskSize,
+					Model: &diskModel,
+					Type:  &nvme,
+				}
+			},
+			check: func(t *testing.T, cfg talosconfig.Provider) {
+				t.Helper()
+				if diff := cmp.Diff("", cfg.Machine().Install().Disk()); diff != "" {
+					t.Errorf("Install().Disk(): -want, +got:\n%s", diff)
+				}
+				expr, err := cfg.Machine().Install().DiskMatchExpression()
+				if err != nil {
+					t.Fatalf("Install().DiskMatchExpression(): unexpected error: %v", err)
+				}
+				if expr == nil {
+					t.Error("Install().DiskMatchExpression() = nil, want selector expression")
+				}
+			},
+			contains: []string{"diskSelector:", "size: '>= 1TB'", "model: WDC*", "type: nvme"},
+		},
+		"InvalidInstallDiskSizeErrors": {
+			config: func(c *v1alpha1.MachineConfigurationSpec) {
+				c.Machine.Install.DiskSelector = &v1alpha1.InstallDiskSelector{Size: &invalidDiskSize}
+			},
+			wantErr: true,
+		},
+		"UnknownMachineTypeErrors": {
+			config: func(c *v1alpha1.MachineConfigurationSpec) {
+				c.Machine.Type = "init"
+			},
+			wantErr: true,
+		},
+		"UnsupportedVersionErrors": {
+			config: func(c *v1alpha1.MachineConfigurationSpec) {
+				c.Version = "v2"
+			},
+			wantErr: true,
+		},
+		"MissingEndpointErrors": {
+			config: func(c *v1alpha1.MachineConfigurationSpec) {
+				c.Cluster.ControlPlane.Endpoint = ""
+			},
+			wantErr: true,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			config := *testConfigurationApply().Spec.ForProvider.MachineConfiguration
+			if tc.config != nil {
+				tc.config(&config)
+			}
+
+			e := external{}
+			got, err := e.generateMachineConfigurationYAML(config)
+			if tc.wantErr && err == nil {
+				t.Fatal("generateMachineConfigurationYAML(...): expected error")
+			}
+			if !tc.wantErr && err != nil {
+				t.Fatalf("generateMachineConfigurationYAML(...): unexpected error: %v", err)
+			}
+			if tc.wantErr {
+				return
+			}
+
+			cfg, err := configloader.NewFromBytes([]byte(got))
+			if err != nil {
+				t.Fatalf("configloader.NewFromBytes(...): %v", err)
+			}
+			if tc.check != nil {
+				tc.check(t, cfg)
+			}
+			for _, exclude := range tc.excludes {
+				if strings.Contains(got, exclude) {
+					t.Errorf("generateMachineConfigurationYAML(...) = %q, want not to contain %q", got, exclude)
+				}
+			}
+		})
+	}
+}
+
+func testNetworkSpec() *v1alpha1.NetworkSpec {
+	hostname := "cp-1"
+	lacpRate := "fast"
+	metric := int32(1024)
+	mtu := int32(9000)
+	dhcp := true
+	stp := true
+
+	return &v1alpha1.NetworkSpec{
+		Hostname:    &hostname,
+		Nameservers: []string{"192.168.1.1", "1.1.1.1"},
+		Interfaces: []v1alpha1.NetworkInterfaceSpec{
+			{
+				Interface: "bond0",
+				Addresses: []string{"192.168.1.10/24"},
+				Routes:    []v1alpha1.RouteSpec{{Network: "0.0.0.0/0", Gateway: "192.168.1.1", Metric: &metric}},
+				Bond:      &v1alpha1.BondSpec{Interfaces: []string{"eth0", "eth1"}, Mode: "802.3ad", LACPRate: &lacpRate},
+				VLANs:     []v1alpha1.VLANSpec{{VLANID: 100, DHCP: &dhcp}},
+				VIP:       &v1alpha1.VIPSpec{IP: "192.168.1.5"},
+			},
+			{
+				Interface: "br0",
+				MTU:       &mtu,
+				Bridge:    &v1alpha1.BridgeSpec{Interfaces: []string{"eth2"}, STP: &stp},
+			},
+		},
+		ExtraHostEntries: []v1alpha1.ExtraHostEntrySpec{{IP: "192.168.1.5", Aliases: []string{"api.cluster.local"}}},
+	}
+}
+
+func TestValidateMachineConfiguration(t *testing.T) {
+	cloud := "cloud"
+	containerMode := "container"
+	ssd := "ssd"
+	unknown := "unknown"
+
+	tests := map[string]struct {
+		config  func(*v1alpha1.MachineConfigurationSpec)
+		mode    *string
+		wantErr bool
+	}{
+		"MetalByDefault": {},
+		"MetalRequiresInstallDisk": {
+			config: func(c *v1alpha1.MachineConfigurationSpec) {
+				c.Machine.Install.Disk = ""
+			},
+			wantErr: true,
+		},
+		"MetalAcceptsInstallDiskSelector": {
+			config: func(c *v1alpha1.MachineConfigurationSpec) {
+				c.Machine.Install.Disk = ""
+				c.Machine.Install.DiskSelector = &v1alpha1.InstallDiskSelector{Type: &ssd}
+			},
+		},
+		"CloudSkipsInstallDisk": {
+			config: func(c *v1alpha1.MachineConfigurationSpec) {
+				c.Machine.Install.Disk = ""
+			},
+			mode: &cloud,
+		},
+		"ContainerRequiresHostDNS": {
+			mode:    &containerMode,
+			wantErr: true,
+		},
+		"StaticNetworkIsValid": {
+			config: func(c *v1alpha1.MachineConfigurationSpec) {
+				c.Machine.Network = testNetworkSpec()
+			},
+		},
+		"WorkerVIPErrors": {
+			config: func(c *v1alpha1.MachineConfigurationSpec) {
+				c.Machine.Type = "worker"
+				c.Machine.CA.Key = ""
+				c.Machine.Network = testNetworkSpec()
+			},
+			wantErr: true,
+		},
+		"MissingCAErrors": {
+			config: func(c *v1alpha1.MachineConfigurationSpec) {
+				c.Machine.CA = nil
+			},
+			wantErr: true,
+		},
+		"UnknownModeErrors": {
+			mode:    &unknown,
+			wantErr: true,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			config := *testConfigurationApply().Spec.ForProvider.MachineConfiguration
+			if tc.config != nil {
+				tc.config(&config)
+			}
+
+			e := external{}
+			rendered, err := e.generateMachineConfigurationYAML(config)
+			if err != nil {
+				t.Fatalf("generateMachineConfigurationYAML(...): unexpected error: %v", err)
+			}
+
+			err = validateMachineConfiguration([]byte(rendered), tc.mode)
+			if tc.wantErr && err == nil {
+				t.Fatal("validateMachineConfiguration(...): expected error")
+			}
+			if !tc.wantErr && err != nil {
+				t.Fatalf("validateMachineConfiguration(...): unexpected error: %v", err)
+			}
+		})
+	}
+}
+
 func TestGetConfigurationApplyMode(t *testing.T) {
 	empty := ""
 	reboot := "reboot"
@@ -328,164 +1227,131 @@ func TestGetConfigurationApplyMode(t *testing.T) {
 	}
 }
 
-func TestBuildConfigurationApplyTLSConfig(t *testing.T) {
+func TestClientConfiguration(t *testing.T) {
 	caCert, clientCert, clientKey := generateTestCertificates(t)
+	secure := &v1alpha1.ClientConfiguration{
+		CACertificate:     caCert,
+		ClientCertificate: clientCert,
+		ClientKey:         clientKey,
+	}
+
+	scheme := runtime.NewScheme()
+	if err := corev1.AddToScheme(scheme); err != nil {
+		t.Fatalf("corev1.AddToScheme(...): %v", err)
+	}
+	kube := ctrlfake.NewClientBuilder().WithScheme(scheme).WithRuntimeObjects(&corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "crossplane-system", Name: "talos-client"},
+		Data: map[string][]byte{
+			"ca_certificate":     []byte(caCert),
+			"client_certificate": []byte(clientCert),
+			"client_key":         []byte(clientKey),
+		},
+	}).Build()
 
 	tests := map[string]struct {
-		clientConfig v1alpha1.ClientConfiguration
-		node         string
-		check        func(t *testing.T, cfg *tls.Config)
+		clientConfig *v1alpha1.ClientConfiguration
+		secretRef    *xpv1.SecretReference
+		want         *v1alpha1.ClientConfiguration
 		wantErr      bool
 	}{
+		"InsecureWithoutClientConfiguration": {
+			want: clients.InsecureClientConfiguration(),
+		},
 		"InsecureEmptyClientCertificate": {
-			clientConfig: v1alpha1.ClientConfiguration{},
-			check: func(t *testing.T, cfg *tls.Config) {
-				t.Helper()
-				if !cfg.InsecureSkipVerify {
-					t.Fatal("buildConfigurationApplyTLSConfig(...): InsecureSkipVerify = false")
-				}
-			},
+			clientConfig: &v1alpha1.ClientConfiguration{},
+			want:         clients.InsecureClientConfiguration(),
 		},
 		"InsecureClientCertificateValue": {
-			clientConfig: v1alpha1.ClientConfiguration{ClientCertificate: "insecure"},
-			check: func(t *testing.T, cfg *tls.Config) {
-				t.Helper()
-				if !cfg.InsecureSkipVerify {
-					t.Fatal("buildConfigurationApplyTLSConfig(...): InsecureSkipVerify = false")
-				}
-			},
+			clientConfig: &v1alpha1.ClientConfiguration{ClientCertificate: "insecure"},
+			want:         &v1alpha1.ClientConfiguration{ClientCertificate: "insecure"},
 		},
-		"SecureWithCA": {
-			clientConfig: v1alpha1.ClientConfiguration{
-				CACertificate:     caCert,
-				ClientCertificate: clientCert,
-				ClientKey:         clientKey,
-			},
-			node: "127.0.0.1",
-			check: func(t *testing.T, cfg *tls.Config) {
-				t.Helper()
-				if len(cfg.Certificates) != 1 {
-					t.Fatalf("buildConfigurationApplyTLSConfig(...): got %d certificates, want 1", len(cfg.Certificates))
-				}
-				if cfg.RootCAs == nil {
-					t.Fatal("buildConfigurationApplyTLSConfig(...): RootCAs = nil")
-				}
-				if diff := cmp.Diff("127.0.0.1", cfg.ServerName); diff != "" {
-					t.Errorf("buildConfigurationApplyTLSConfig(...).ServerName: -want, +got:\n%s", diff)
-				}
-				if diff := cmp.Diff(uint16(tls.VersionTLS12), cfg.MinVersion); diff != "" {
-					t.Errorf("buildConfigurationApplyTLSConfig(...).MinVersion: -want, +got:\n%s", diff)
-				}
-			},
+		"InlineClientConfiguration": {
+			clientConfig: secure,
+			want:         secure,
 		},
-		"InvalidCAErrors": {
-			clientConfig: v1alpha1.ClientConfiguration{
-				CACertificate:     "invalid",
-				ClientCertificate: clientCert,
-				ClientKey:         clientKey,
-			},
-			wantErr: true,
+		"ClientConfigurationSecretRef": {
+			secretRef: &xpv1.SecretReference{Namespace: "crossplane-system", Name: "talos-client"},
+			want:      secure,
 		},
-		"InvalidClientCertificateErrors": {
-			clientConfig: v1alpha1.ClientConfiguration{
-				CACertificate:     caCert,
-				ClientCertificate: "invalid",
-				ClientKey:         clientKey,
-			},
-			wantErr: true,
+		"MissingClientConfigurationSecretErrors": {
+			secretRef: &xpv1.SecretReference{Namespace: "crossplane-system", Name: "missing"},
+			wantErr:   true,
 		},
 	}
 
 	for name, tc := range tests {
 		t.Run(name, func(t *testing.T) {
-			got, err := buildConfigurationApplyTLSConfig(tc.clientConfig, tc.node)
-			if tc.wantErr && err == nil {
-				t.Fatal("buildConfigurationApplyTLSConfig(...): expected error")
+			e := external{kube: kube}
+			cr := &v1alpha1.ConfigurationApply{
+				Spec: v1alpha1.ConfigurationApplySpec{
+					ForProvider: v1alpha1.ConfigurationApplyParameters{
+						Node:                         "127.0.0.1",
+						ClientConfiguration:          tc.clientConfig,
+						ClientConfigurationSecretRef: tc.secretRef,
+					},
+				},
 			}
-			if !tc.wantErr && err != nil {
-				t.Fatalf("buildConfigurationApplyTLSConfig(...): unexpected error: %v", err)
+
+			got, err := e.clientConfiguration(context.Background(), cr)
+			if tc.wantErr {
+				if err == nil {
+					t.Fatal("e.clientConfiguration(...): expected error")
+				}
+				return
 			}
-			if tc.check != nil {
-				tc.check(t, got)
+			if err != nil {
+				t.Fatalf("e.clientConfiguration(...): unexpected error: %v", err)
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("e.clientConfiguration(...): -want, +got:\n%s", diff)
 			}
 		})
 	}
 }
 
-func TestBuildApplyTLSConfig(t *testing.T) {
+func TestApplyClientConfiguration(t *testing.T) {
 	caCert, clientCert, clientKey := generateTestCertificates(t)
+	secure := &v1alpha1.ClientConfiguration{
+		CACertificate:     caCert,
+		ClientCertificate: clientCert,
+		ClientKey:         clientKey,
+	}
 
 	tests := map[string]struct {
 		maintenanceMode bool
-		clientConfig    v1alpha1.ClientConfiguration
-		check           func(t *testing.T, cfg *tls.Config, maintenanceMode bool)
+		clientConfig    *v1alpha1.ClientConfiguration
+		want            *v1alpha1.ClientConfiguration
 		wantErr         bool
 	}{
-		"MaintenanceModeUsesInsecureTLSWithInlineClientConfiguration": {
+		"MaintenanceModeUsesInsecureConnectionWithInlineClientConfiguration": {
 			maintenanceMode: true,
-			clientConfig: v1alpha1.ClientConfiguration{
-				CACertificate:     caCert,
-				ClientCertificate: clientCert,
-				ClientKey:         clientKey,
-			},
-			check: func(t *testing.T, cfg *tls.Config, maintenanceMode bool) {
-				t.Helper()
-				if !maintenanceMode {
-					t.Fatal("buildApplyTLSConfig(...): maintenanceMode = false")
-				}
-				if !cfg.InsecureSkipVerify {
-					t.Fatal("buildApplyTLSConfig(...): InsecureSkipVerify = false")
-				}
-				if len(cfg.Certificates) != 0 {
-					t.Fatalf("buildApplyTLSConfig(...): got %d certificates, want 0", len(cfg.Certificates))
-				}
-			},
+			clientConfig:    secure,
+			want:            clients.InsecureClientConfiguration(),
 		},
 		"SecureFallbackUsesInlineClientConfiguration": {
-			clientConfig: v1alpha1.ClientConfiguration{
-				CACertificate:     caCert,
-				ClientCertificate: clientCert,
-				ClientKey:         clientKey,
-			},
-			check: func(t *testing.T, cfg *tls.Config, maintenanceMode bool) {
-				t.Helper()
-				if maintenanceMode {
-					t.Fatal("buildApplyTLSConfig(...): maintenanceMode = true")
-				}
-				if cfg.InsecureSkipVerify {
-					t.Fatal("buildApplyTLSConfig(...): InsecureSkipVerify = true")
-				}
-				if len(cfg.Certificates) != 1 {
-					t.Fatalf("buildApplyTLSConfig(...): got %d certificates, want 1", len(cfg.Certificates))
-				}
-				if cfg.RootCAs == nil {
-					t.Fatal("buildApplyTLSConfig(...): RootCAs = nil")
-				}
-				if diff := cmp.Diff("127.0.0.1", cfg.ServerName); diff != "" {
-					t.Errorf("buildApplyTLSConfig(...).ServerName: -want, +got:\n%s", diff)
-				}
-			},
+			clientConfig: secure,
+			want:         secure,
 		},
 		"ExplicitInsecureFallbackPreservesExistingBehavior": {
-			clientConfig: v1alpha1.ClientConfiguration{ClientCertificate: "insecure"},
-			check: func(t *testing.T, cfg *tls.Config, maintenanceMode bool) {
-				t.Helper()
-				if maintenanceMode {
-					t.Fatal("buildApplyTLSConfig(...): maintenanceMode = true")
-				}
-				if !cfg.InsecureSkipVerify {
-					t.Fatal("buildApplyTLSConfig(...): InsecureSkipVerify = false")
-				}
-			},
+			clientConfig: &v1alpha1.ClientConfiguration{ClientCertificate: "insecure"},
+			want:         &v1alpha1.ClientConfiguration{ClientCertificate: "insecure"},
 		},
-		"InvalidSecureClientConfigurationErrorsAfterMaintenanceProbeFails": {
-			clientConfig: v1alpha1.ClientConfiguration{
+		"InvalidCAErrorsAfterMaintenanceProbeFails": {
+			clientConfig: &v1alpha1.ClientConfiguration{
 				CACertificate:     "invalid",
 				ClientCertificate: clientCert,
 				ClientKey:         clientKey,
 			},
 			wantErr: true,
 		},
+		"InvalidClientCertificateErrorsAfterMaintenanceProbeFails": {
+			clientConfig: &v1alpha1.ClientConfiguration{
+				CACertificate:     caCert,
+				ClientCertificate: "invalid",
+				ClientKey:         clientKey,
+			},
+			wantErr: true,
+		},
 	}
 
 	for name, tc := range tests {
@@ -504,15 +1370,21 @@ func TestBuildApplyTLSConfig(t *testing.T) {
 				},
 			}
 
-			got, maintenanceMode, err := e.buildApplyTLSConfig(context.Background(), cr)
-			if tc.wantErr && err == nil {
-				t.Fatal("buildApplyTLSConfig(...): expected error")
+			got, maintenanceMode, err := e.applyClientConfiguration(context.Background(), cr)
+			if tc.wantErr {
+				if err == nil {
+					t.Fatal("e.applyClientConfiguration(...): expected error")
+				}
+				return
 			}
-			if !tc.wantErr && err != nil {
-				t.Fatalf("buildApplyTLSConfig(...): unexpected error: %v", err)
+			if err != nil {
+				t.Fatalf("e.applyClientConfiguration(...): unexpected error: %v", err)
 			}
-			if tc.check != nil {
-				tc.check(t, got, maintenanceMode)
+			if maintenanceMode != tc.maintenanceMode {
+				t.Errorf("e.applyClientConfiguration(...): maintenanceMode = %v, want %v", maintenanceMode, tc.maintenanceMode)
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("e.applyClientConfiguration(...): -want, +got:\n%s", diff)
 			}
 		})
 	}
@@ -569,6 +1441,49 @@ func TestGetConfigurationApplyEndpoint(t *testing.T) {
 	}
 }
 
+func TestGetConfigurationApplyEndpoints(t *testing.T) {
+	node := "127.0.0.2"
+	customEndpoint := "127.0.0.1:50000"
+
+	tests := map[string]struct {
+		cr   *v1alpha1.ConfigurationApply
+		want []string
+	}{
+		"DefaultsToEndpoint": {
+			cr: &v1alpha1.ConfigurationApply{
+				Spec: v1alpha1.ConfigurationApplySpec{
+					ForProvider: v1alpha1.ConfigurationApplyParameters{
+						Node:     node,
+						Endpoint: &customEndpoint,
+					},
+				},
+			},
+			want: []string{customEndpoint},
+		},
+		"UsesProvidedEndpoints": {
+			cr: &v1alpha1.ConfigurationApply{
+				Spec: v1alpha1.ConfigurationApplySpec{
+					ForProvider: v1alpha1.ConfigurationApplyParameters{
+						Node:      node,
+						Endpoint:  &customEndpoint,
+						Endpoints: []string{"10.0.0.1:50000", "10.0.0.2:50000"},
+					},
+				},
+			},
+			want: []string{"10.0.0.1:50000", "10.0.0.2:50000"},
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			got := getConfigurationApplyEndpoints(tc.cr)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("getConfigurationApplyEndpoints(...): -want, +got:\n%s", diff)
+			}
+		})
+	}
+}
+
 func generateTestCertificates(t *testing.T) (string, string, string) {
 	t.Helper()
 
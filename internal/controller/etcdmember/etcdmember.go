@@ -0,0 +1,411 @@
+/*
+Copyright 2025 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package etcdmember
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strconv"
+	"time"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/siderolabs/talos/pkg/machinery/api/machine"
+	talosclient "github.com/siderolabs/talos/pkg/machinery/client"
+	"google.golang.org/grpc"
+
+	"github.com/crossplane/crossplane-runtime/pkg/feature"
+
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	ctrlclient "sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane/crossplane-runtime/pkg/connection"
+	"github.com/crossplane/crossplane-runtime/pkg/controller"
+	"github.com/crossplane/crossplane-runtime/pkg/event"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/pkg/ratelimiter"
+	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/crossplane/crossplane-runtime/pkg/statemetrics"
+
+	"github.com/crossplane-contrib/provider-talos/apis/machine/v1alpha1"
+	apisv1alpha1 "github.com/crossplane-contrib/provider-talos/apis/v1alpha1"
+	"github.com/crossplane-contrib/provider-talos/internal/clients"
+	"github.com/crossplane-contrib/provider-talos/internal/features"
+)
+
+const (
+	errNotEtcdMember = "managed resource is not an EtcdMember custom resource"
+	errTrackPCUsage  = "cannot track ProviderConfig usage"
+	errGetPC         = "cannot get ProviderConfig"
+	errGetCreds      = "cannot get credentials"
+
+	errNewClient = "cannot create new Service"
+
+	// memberTimeout bounds each etcd membership API call.
+	memberTimeout = 30 * time.Second
+)
+
+// A NoOpService does nothing.
+type NoOpService struct{}
+
+var (
+	newNoOpService = func(_ []byte) (interface{}, error) { return &NoOpService{}, nil }
+)
+
+// Setup adds a controller that reconciles EtcdMember managed resources.
+// Talos API clients are borrowed from the supplied cache.
+func Setup(mgr ctrl.Manager, o controller.Options, talosClients *clients.Cache) error {
+	name := managed.ControllerName(v1alpha1.EtcdMemberGroupKind)
+
+	cps := []managed.ConnectionPublisher{managed.NewAPISecretPublisher(mgr.GetClient(), mgr.GetScheme())}
+	if o.Features.Enabled(features.EnableAlphaExternalSecretStores) {
+		cps = append(cps, connection.NewDetailsManager(mgr.GetClient(), apisv1alpha1.StoreConfigGroupVersionKind))
+	}
+
+	opts := []managed.ReconcilerOption{
+		managed.WithExternalConnecter(&connector{
+			kube:         mgr.GetClient(),
+			usage:        resource.NewProviderConfigUsageTracker(mgr.GetClient(), &apisv1alpha1.ProviderConfigUsage{}),
+			newServiceFn: newNoOpService,
+			talosClients: talosClients}),
+		managed.WithLogger(o.Logger.WithValues("controller", name)),
+		managed.WithPollInterval(o.PollInterval),
+		managed.WithRecorder(event.NewAPIRecorder(mgr.GetEventRecorderFor(name))),
+		managed.WithConnectionPublishers(cps...),
+		managed.WithManagementPolicies(),
+	}
+
+	if o.Features.Enabled(feature.EnableAlphaChangeLogs) {
+		opts = append(opts, managed.WithChangeLogger(o.ChangeLogOptions.ChangeLogger))
+	}
+
+	if o.MetricOptions != nil {
+		opts = append(opts, managed.WithMetricRecorder(o.MetricOptions.MRMetrics))
+	}
+
+	if o.MetricOptions != nil && o.MetricOptions.MRStateMetrics != nil {
+		stateMetricsRecorder := statemetrics.NewMRStateRecorder(
+			mgr.GetClient(), o.Logger, o.MetricOptions.MRStateMetrics, &v1alpha1.EtcdMemberList{}, o.MetricOptions.PollStateMetricInterval,
+		)
+		if err := mgr.Add(stateMetricsRecorder); err != nil {
+			return errors.Wrap(err, "cannot register MR state metrics recorder for kind v1alpha1.EtcdMemberList")
+		}
+	}
+
+	r := managed.NewReconciler(mgr, resource.ManagedKind(v1alpha1.EtcdMemberGroupVersionKind), opts...)
+
+	return ctrl.NewControllerManagedBy(mgr).
+		Named(name).
+		WithOptions(o.ForControllerRuntime()).
+		WithEventFilter(resource.DesiredStateChanged()).
+		For(&v1alpha1.EtcdMember{}).
+		Complete(ratelimiter.NewReconciler(name, r, o.GlobalRateLimiter))
+}
+
+// A connector is expected to produce an ExternalClient when its Connect method
+// is called.
+type connector struct {
+	kube         ctrlclient.Client
+	usage        resource.Tracker
+	newServiceFn func(creds []byte) (interface{}, error)
+	talosClients *clients.Cache
+}
+
+// Connect typically produces an ExternalClient by:
+// 1. Tracking that the managed resource is using a ProviderConfig.
+// 2. Getting the managed resource's ProviderConfig.
+// 3. Getting the credentials specified by the ProviderConfig.
+// 4. Using the credentials to form a client.
+func (c *connector) Connect(ctx context.Context, mg resource.Managed) (managed.ExternalClient, error) {
+	cr, ok := mg.(*v1alpha1.EtcdMember)
+	if !ok {
+		return nil, errors.New(errNotEtcdMember)
+	}
+
+	if err := c.usage.Track(ctx, mg); err != nil {
+		return nil, errors.Wrap(err, errTrackPCUsage)
+	}
+
+	pc := &apisv1alpha1.ProviderConfig{}
+	if err := c.kube.Get(ctx, types.NamespacedName{Name: cr.GetProviderConfigReference().Name}, pc); err != nil {
+		return nil, errors.Wrap(err, errGetPC)
+	}
+
+	cd := pc.Spec.Credentials
+	data, err := resource.CommonCredentialExtractor(ctx, cd.Source, c.kube, cd.CommonCredentialSelectors)
+	if err != nil {
+		return nil, errors.Wrap(err, errGetCreds)
+	}
+
+	svc, err := c.newServiceFn(data)
+	if err != nil {
+		return nil, errors.Wrap(err, errNewClient)
+	}
+
+	return &external{kube: c.kube, service: svc, talosClients: c.talosClients}, nil
+}
+
+// An ExternalClient observes, then either creates, updates, or deletes an
+// external resource to ensure it reflects the managed resource's desired state.
+type external struct {
+	// kube reads referenced client credentials.
+	kube ctrlclient.Client
+	// A 'client' used to connect to the external resource API. In practice this
+	// would be something like an AWS SDK client.
+	service interface{}
+	// talosClients shares Talos API clients between reconciles.
+	talosClients *clients.Cache
+	// newMemberClientFn allows tests to stub the Talos etcd membership API.
+	newMemberClientFn func(context.Context, *v1alpha1.EtcdMember) (memberClient, error)
+}
+
+type memberClient interface {
+	EtcdMemberList(context.Context, *machine.EtcdMemberListRequest, ...grpc.CallOption) (*machine.EtcdMemberListResponse, error)
+	EtcdRemoveMemberByID(context.Context, *machine.EtcdRemoveMemberByIDRequest, ...grpc.CallOption) error
+	EtcdLeaveCluster(context.Context, *machine.EtcdLeaveClusterRequest, ...grpc.CallOption) error
+	Close() error
+}
+
+func (c *external) Observe(ctx context.Context, mg resource.Managed) (managed.ExternalObservation, error) {
+	cr, ok := mg.(*v1alpha1.EtcdMember)
+	if !ok {
+		return managed.ExternalObservation{}, errors.New(errNotEtcdMember)
+	}
+
+	fmt.Printf("Observing EtcdMember: %s\n", cr.Name)
+
+	if meta.WasDeleted(cr) && (removalAction(cr) == v1alpha1.EtcdMemberRemovalNone || cr.Spec.ForProvider.Hostname == nil) {
+		return managed.ExternalObservation{ResourceExists: false}, nil
+	}
+
+	members, err := c.listMembers(ctx, cr)
+	if err != nil {
+		return managed.ExternalObservation{}, err
+	}
+
+	cr.Status.AtProvider.Members = members
+	member := findMember(members, cr.Spec.ForProvider.Hostname)
+	cr.Status.AtProvider.Present = member != nil
+	cr.Status.AtProvider.MemberID = ""
+	if member != nil {
+		cr.Status.AtProvider.MemberID = member.ID
+	}
+
+	// The resource only exists while deleting if there is a member left to
+	// remove.
+	if meta.WasDeleted(cr) {
+		return managed.ExternalObservation{ResourceExists: member != nil}, nil
+	}
+
+	if cr.Spec.ForProvider.Hostname != nil && member == nil {
+		cr.SetConditions(xpv1.Unavailable().WithMessage(fmt.Sprintf("etcd member %s is not in the cluster", *cr.Spec.ForProvider.Hostname)))
+	} else {
+		cr.SetConditions(xpv1.Available())
+	}
+
+	// Members join etcd when Talos starts it on a control plane node; there is
+	// nothing to create or update.
+	return managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: true}, nil
+}
+
+func (c *external) Create(ctx context.Context, mg resource.Managed) (managed.ExternalCreation, error) {
+	_, ok := mg.(*v1alpha1.EtcdMember)
+	if !ok {
+		return managed.ExternalCreation{}, errors.New(errNotEtcdMember)
+	}
+
+	return managed.ExternalCreation{}, nil
+}
+
+func (c *external) Update(ctx context.Context, mg resource.Managed) (managed.ExternalUpdate, error) {
+	_, ok := mg.(*v1alpha1.EtcdMember)
+	if !ok {
+		return managed.ExternalUpdate{}, errors.New(errNotEtcdMember)
+	}
+
+	return managed.ExternalUpdate{}, nil
+}
+
+func (c *external) Delete(ctx context.Context, mg resource.Managed) (managed.ExternalDelete, error) {
+	cr, ok := mg.(*v1alpha1.EtcdMember)
+	if !ok {
+		return managed.ExternalDelete{}, errors.New(errNotEtcdMember)
+	}
+
+	action := removalAction(cr)
+	if action == v1alpha1.EtcdMemberRemovalNone || cr.Spec.ForProvider.Hostname == nil {
+		return managed.ExternalDelete{}, nil
+	}
+
+	members, err := c.listMembers(ctx, cr)
+	if err != nil {
+		return managed.ExternalDelete{}, err
+	}
+	member := findMember(members, cr.Spec.ForProvider.Hostname)
+	if member == nil {
+		return managed.ExternalDelete{}, nil
+	}
+
+	talosClient, err := c.newMemberClient(ctx, cr)
+	if err != nil {
+		return managed.ExternalDelete{}, errors.Wrap(err, "failed to create Talos client")
+	}
+	defer talosClient.Close() //nolint:errcheck
+
+	callCtx, cancel := context.WithTimeout(ctx, memberTimeout)
+	defer cancel()
+
+	switch action {
+	case v1alpha1.EtcdMemberRemovalForget:
+		if memberHost(member) == cr.Spec.ForProvider.Node {
+			return managed.ExternalDelete{}, errors.Errorf("cannot forget etcd member %s through itself; use the Leave removal action or another node", member.Hostname)
+		}
+
+		id, err := strconv.ParseUint(member.ID, 16, 64)
+		if err != nil {
+			return managed.ExternalDelete{}, errors.Wrapf(err, "invalid etcd member ID %s", member.ID)
+		}
+
+		fmt.Printf("Forgetting etcd member %s (%s) through node %s\n", member.Hostname, member.ID, cr.Spec.ForProvider.Node)
+
+		err = talosClient.EtcdRemoveMemberByID(talosclient.WithNode(callCtx, cr.Spec.ForProvider.Node), &machine.EtcdRemoveMemberByIDRequest{MemberId: id})
+		if err != nil {
+			return managed.ExternalDelete{}, errors.Wrapf(err, "failed to remove etcd member %s", member.Hostname)
+		}
+	case v1alpha1.EtcdMemberRemovalLeave:
+		host := memberHost(member)
+		if host == "" {
+			return managed.ExternalDelete{}, errors.Errorf("cannot determine the node address of etcd member %s from its peer URLs", member.Hostname)
+		}
+
+		fmt.Printf("Etcd member %s (%s) leaving the cluster\n", member.Hostname, host)
+
+		err = talosClient.EtcdLeaveCluster(talosclient.WithNode(callCtx, host), &machine.EtcdLeaveClusterRequest{})
+		if err != nil {
+			return managed.ExternalDelete{}, errors.Wrapf(err, "failed to leave etcd cluster on node %s", host)
+		}
+	}
+
+	return managed.ExternalDelete{}, nil
+}
+
+func (c *external) Disconnect(ctx context.Context) error {
+	return nil
+}
+
+// listMembers returns the etcd cluster members as seen by the resource's node.
+func (c *external) listMembers(ctx context.Context, cr *v1alpha1.EtcdMember) ([]v1alpha1.EtcdMemberInfo, error) {
+	talosClient, err := c.newMemberClient(ctx, cr)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create Talos client")
+	}
+	defer talosClient.Close() //nolint:errcheck
+
+	callCtx, cancel := context.WithTimeout(ctx, memberTimeout)
+	defer cancel()
+
+	resp, err := talosClient.EtcdMemberList(talosclient.WithNode(callCtx, cr.Spec.ForProvider.Node), &machine.EtcdMemberListRequest{})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list etcd members")
+	}
+
+	var members []v1alpha1.EtcdMemberInfo
+	for _, msg := range resp.GetMessages() {
+		for _, m := range msg.GetMembers() {
+			members = append(members, v1alpha1.EtcdMemberInfo{
+				ID:         formatMemberID(m.GetId()),
+				Hostname:   m.GetHostname(),
+				PeerURLs:   m.GetPeerUrls(),
+				ClientURLs: m.GetClientUrls(),
+				Learner:    m.GetIsLearner(),
+			})
+		}
+	}
+
+	return members, nil
+}
+
+func findMember(members []v1alpha1.EtcdMemberInfo, hostname *string) *v1alpha1.EtcdMemberInfo {
+	if hostname == nil {
+		return nil
+	}
+	for i := range members {
+		if members[i].Hostname == *hostname {
+			return &members[i]
+		}
+	}
+
+	return nil
+}
+
+// memberHost returns the host of the member's first peer URL, which is the
+// node's address on the etcd peer network.
+func memberHost(member *v1alpha1.EtcdMemberInfo) string {
+	for _, peerURL := range member.PeerURLs {
+		u, err := url.Parse(peerURL)
+		if err == nil && u.Hostname() != "" {
+			return u.Hostname()
+		}
+	}
+
+	return ""
+}
+
+// formatMemberID formats a member ID the way talosctl etcd members does.
+func formatMemberID(id uint64) string {
+	return fmt.Sprintf("%016x", id)
+}
+
+func removalAction(cr *v1alpha1.EtcdMember) string {
+	if cr.Spec.ForProvider.RemovalAction == nil || *cr.Spec.ForProvider.RemovalAction == "" {
+		return v1alpha1.EtcdMemberRemovalNone
+	}
+
+	return *cr.Spec.ForProvider.RemovalAction
+}
+
+func (c *external) newMemberClient(ctx context.Context, cr *v1alpha1.EtcdMember) (memberClient, error) {
+	if c.newMemberClientFn != nil {
+		return c.newMemberClientFn(ctx, cr)
+	}
+
+	clientConfig, err := clients.ResolveClientConfiguration(ctx, c.kube, clients.ClientConfigurationSource{
+		ClientConfiguration: cr.Spec.ForProvider.ClientConfiguration,
+		SecretRef:           cr.Spec.ForProvider.ClientConfigurationSecretRef,
+		SecretsRef:          cr.Spec.ForProvider.SecretsRef,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if _, err := clients.TalosConfig(clientConfig); err != nil {
+		return nil, err
+	}
+
+	return c.talosClients.Client(ctx, []string{getEtcdMemberEndpoint(cr)}, clientConfig)
+}
+
+func getEtcdMemberEndpoint(cr *v1alpha1.EtcdMember) string {
+	endpoint := cr.Spec.ForProvider.Node + ":50000"
+	if cr.Spec.ForProvider.Endpoint != nil && *cr.Spec.ForProvider.Endpoint != "" {
+		endpoint = *cr.Spec.ForProvider.Endpoint
+	}
+
+	return endpoint
+}
@@ -0,0 +1,243 @@
+/*
+Copyright 2025 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package etcdmember
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
+	"github.com/google/go-cmp/cmp"
+	"github.com/siderolabs/talos/pkg/machinery/api/machine"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/crossplane-contrib/provider-talos/apis/machine/v1alpha1"
+)
+
+// Unlike many Kubernetes projects Crossplane does not use third party testing
+// libraries, per the common Go test review comments. Crossplane encourages the
+// use of table driven unit tests. The tests of the crossplane-runtime project
+// are representative of the testing style Crossplane encourages.
+//
+// https://github.com/golang/go/wiki/TestComments
+// https://github.com/crossplane/crossplane/blob/master/CONTRIBUTING.md#contributing-code
+
+type fakeMemberClient struct {
+	members []*machine.EtcdMember
+	removed []uint64
+	left    []string
+}
+
+func (f *fakeMemberClient) EtcdMemberList(context.Context, *machine.EtcdMemberListRequest, ...grpc.CallOption) (*machine.EtcdMemberListResponse, error) {
+	return &machine.EtcdMemberListResponse{Messages: []*machine.EtcdMembers{{Members: f.members}}}, nil
+}
+
+func (f *fakeMemberClient) EtcdRemoveMemberByID(_ context.Context, req *machine.EtcdRemoveMemberByIDRequest, _ ...grpc.CallOption) error {
+	f.removed = append(f.removed, req.GetMemberId())
+	return nil
+}
+
+func (f *fakeMemberClient) EtcdLeaveCluster(ctx context.Context, _ *machine.EtcdLeaveClusterRequest, _ ...grpc.CallOption) error {
+	md, _ := metadata.FromOutgoingContext(ctx)
+	f.left = append(f.left, md.Get("node")...)
+	return nil
+}
+
+func (f *fakeMemberClient) Close() error {
+	return nil
+}
+
+func testMembers() []*machine.EtcdMember {
+	return []*machine.EtcdMember{
+		{Id: 0x1a2b, Hostname: "cp-1", PeerUrls: []string{"https://10.0.0.1:2380"}, ClientUrls: []string{"https://10.0.0.1:2379"}},
+		{Id: 0x3c4d, Hostname: "cp-2", PeerUrls: []string{"https://10.0.0.2:2380"}, ClientUrls: []string{"https://10.0.0.2:2379"}, IsLearner: true},
+	}
+}
+
+func testEtcdMember(hostname, action *string) *v1alpha1.EtcdMember {
+	return &v1alpha1.EtcdMember{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-etcd-member"},
+		Spec: v1alpha1.EtcdMemberSpec{
+			ForProvider: v1alpha1.EtcdMemberParameters{
+				Node:          "10.0.0.1",
+				Hostname:      hostname,
+				RemovalAction: action,
+				ClientConfiguration: &v1alpha1.ClientConfiguration{
+					CACertificate:     "ca",
+					ClientCertificate: "crt",
+					ClientKey:         "key",
+				},
+			},
+		},
+	}
+}
+
+func ptr[T any](v T) *T {
+	return &v
+}
+
+func TestObserve(t *testing.T) {
+	type want struct {
+		o        managed.ExternalObservation
+		memberID string
+		ready    corev1.ConditionStatus
+	}
+
+	cases := map[string]struct {
+		reason  string
+		cr      *v1alpha1.EtcdMember
+		deleted bool
+		want    want
+	}{
+		"AllMembers": {
+			reason: "A resource without a hostname should observe all members.",
+			cr:     testEtcdMember(nil, nil),
+			want:   want{o: managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: true}, ready: corev1.ConditionTrue},
+		},
+		"MemberPresent": {
+			reason: "A member matching the hostname should be reported with its ID.",
+			cr:     testEtcdMember(ptr("cp-2"), nil),
+			want:   want{o: managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: true}, memberID: "0000000000003c4d", ready: corev1.ConditionTrue},
+		},
+		"MemberMissing": {
+			reason: "A missing member should make the resource unavailable.",
+			cr:     testEtcdMember(ptr("cp-3"), nil),
+			want:   want{o: managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: true}, ready: corev1.ConditionFalse},
+		},
+		"DeletingWithForget": {
+			reason:  "A deleted resource should exist while its member is still present.",
+			cr:      testEtcdMember(ptr("cp-2"), ptr(v1alpha1.EtcdMemberRemovalForget)),
+			deleted: true,
+			want:    want{o: managed.ExternalObservation{ResourceExists: true}, memberID: "0000000000003c4d"},
+		},
+		"DeletingWithoutAction": {
+			reason:  "A deleted resource without a removal action should not exist.",
+			cr:      testEtcdMember(ptr("cp-2"), nil),
+			deleted: true,
+			want:    want{o: managed.ExternalObservation{ResourceExists: false}},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			if tc.deleted {
+				now := metav1.Now()
+				tc.cr.SetDeletionTimestamp(&now)
+			}
+
+			e := &external{newMemberClientFn: func(context.Context, *v1alpha1.EtcdMember) (memberClient, error) {
+				return &fakeMemberClient{members: testMembers()}, nil
+			}}
+			got, err := e.Observe(context.Background(), tc.cr)
+			if err != nil {
+				t.Fatalf("\n%s\ne.Observe(...): unexpected error: %v", tc.reason, err)
+			}
+			if diff := cmp.Diff(tc.want.o, got); diff != "" {
+				t.Errorf("\n%s\ne.Observe(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+			if tc.cr.Status.AtProvider.MemberID != tc.want.memberID {
+				t.Errorf("\n%s\nMemberID: want %q, got %q", tc.reason, tc.want.memberID, tc.cr.Status.AtProvider.MemberID)
+			}
+			if tc.want.ready != "" {
+				if got := tc.cr.GetCondition(xpv1.TypeReady).Status; got != tc.want.ready {
+					t.Errorf("\n%s\nReady: want %s, got %s", tc.reason, tc.want.ready, got)
+				}
+			}
+		})
+	}
+
+	cr := testEtcdMember(nil, nil)
+	e := &external{newMemberClientFn: func(context.Context, *v1alpha1.EtcdMember) (memberClient, error) {
+		return &fakeMemberClient{members: testMembers()}, nil
+	}}
+	if _, err := e.Observe(context.Background(), cr); err != nil {
+		t.Fatalf("e.Observe(...): unexpected error: %v", err)
+	}
+	wantMembers := []v1alpha1.EtcdMemberInfo{
+		{ID: "0000000000001a2b", Hostname: "cp-1", PeerURLs: []string{"https://10.0.0.1:2380"}, ClientURLs: []string{"https://10.0.0.1:2379"}},
+		{ID: "0000000000003c4d", Hostname: "cp-2", PeerURLs: []string{"https://10.0.0.2:2380"}, ClientURLs: []string{"https://10.0.0.2:2379"}, Learner: true},
+	}
+	if diff := cmp.Diff(wantMembers, cr.Status.AtProvider.Members); diff != "" {
+		t.Errorf("Members: -want, +got:\n%s\n", diff)
+	}
+}
+
+func TestDelete(t *testing.T) {
+	type want struct {
+		removed []uint64
+		left    []string
+		err     bool
+	}
+
+	cases := map[string]struct {
+		reason string
+		cr     *v1alpha1.EtcdMember
+		want   want
+	}{
+		"Forget": {
+			reason: "Forget should remove the member by ID through the resource's node.",
+			cr:     testEtcdMember(ptr("cp-2"), ptr(v1alpha1.EtcdMemberRemovalForget)),
+			want:   want{removed: []uint64{0x3c4d}},
+		},
+		"ForgetSelf": {
+			reason: "Forget should refuse to remove the member the request is sent through.",
+			cr:     testEtcdMember(ptr("cp-1"), ptr(v1alpha1.EtcdMemberRemovalForget)),
+			want:   want{err: true},
+		},
+		"Leave": {
+			reason: "Leave should ask the member's own node to leave the cluster.",
+			cr:     testEtcdMember(ptr("cp-2"), ptr(v1alpha1.EtcdMemberRemovalLeave)),
+			want:   want{left: []string{"10.0.0.2"}},
+		},
+		"None": {
+			reason: "No removal action should leave the member in place.",
+			cr:     testEtcdMember(ptr("cp-2"), nil),
+		},
+		"AlreadyGone": {
+			reason: "A member that already left should not be removed again.",
+			cr:     testEtcdMember(ptr("cp-3"), ptr(v1alpha1.EtcdMemberRemovalForget)),
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			client := &fakeMemberClient{members: testMembers()}
+			e := &external{newMemberClientFn: func(context.Context, *v1alpha1.EtcdMember) (memberClient, error) {
+				return client, nil
+			}}
+
+			ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+			defer cancel()
+
+			_, err := e.Delete(ctx, tc.cr)
+			if tc.want.err != (err != nil) {
+				t.Fatalf("\n%s\ne.Delete(...): want error %t, got %v", tc.reason, tc.want.err, err)
+			}
+			if diff := cmp.Diff(tc.want.removed, client.removed); diff != "" {
+				t.Errorf("\n%s\nremoved members: -want, +got:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.left, client.left); diff != "" {
+				t.Errorf("\n%s\nleft nodes: -want, +got:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,496 @@
+/*
+Copyright 2025 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package etcdsnapshot
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/robfig/cron/v3"
+	"github.com/siderolabs/talos/pkg/machinery/api/machine"
+	talosclient "github.com/siderolabs/talos/pkg/machinery/client"
+	bolt "go.etcd.io/bbolt"
+	"google.golang.org/grpc"
+
+	"github.com/crossplane/crossplane-runtime/pkg/feature"
+
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	ctrlclient "sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane/crossplane-runtime/pkg/connection"
+	"github.com/crossplane/crossplane-runtime/pkg/controller"
+	"github.com/crossplane/crossplane-runtime/pkg/event"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/pkg/ratelimiter"
+	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/crossplane/crossplane-runtime/pkg/statemetrics"
+
+	"github.com/crossplane-contrib/provider-talos/apis/machine/v1alpha1"
+	apisv1alpha1 "github.com/crossplane-contrib/provider-talos/apis/v1alpha1"
+	"github.com/crossplane-contrib/provider-talos/internal/clients"
+	"github.com/crossplane-contrib/provider-talos/internal/features"
+	"github.com/crossplane-contrib/provider-talos/internal/snapshotstore"
+)
+
+const (
+	errNotEtcdSnapshot = "managed resource is not an EtcdSnapshot custom resource"
+	errTrackPCUsage    = "cannot track ProviderConfig usage"
+	errGetPC           = "cannot get ProviderConfig"
+	errGetCreds        = "cannot get credentials"
+
+	errNewClient = "cannot create new Service"
+
+	// snapshotTimeout bounds how long streaming a snapshot from the node may take.
+	snapshotTimeout = 10 * time.Minute
+
+	// defaultRetention applies when the API server did not default
+	// spec.forProvider.retention.
+	defaultRetention = 7
+
+	// maxExpiredSnapshots bounds how many expired snapshots that failed to
+	// delete stay in status to be retried, so that a store that keeps
+	// rejecting deletes cannot grow the status without bound.
+	maxExpiredSnapshots = 10
+
+	// etcdKeyBucket is the bbolt bucket etcd stores revisions in. Each key is
+	// an 8-byte big-endian main revision, an underscore and an 8-byte sub
+	// revision.
+	etcdKeyBucket = "key"
+)
+
+// A NoOpService does nothing.
+type NoOpService struct{}
+
+var (
+	newNoOpService = func(_ []byte) (interface{}, error) { return &NoOpService{}, nil }
+)
+
+// Setup adds a controller that reconciles EtcdSnapshot managed resources.
+// Talos API clients are borrowed from the supplied cache.
+func Setup(mgr ctrl.Manager, o controller.Options, talosClients *clients.Cache) error {
+	name := managed.ControllerName(v1alpha1.EtcdSnapshotGroupKind)
+
+	cps := []managed.ConnectionPublisher{managed.NewAPISecretPublisher(mgr.GetClient(), mgr.GetScheme())}
+	if o.Features.Enabled(features.EnableAlphaExternalSecretStores) {
+		cps = append(cps, connection.NewDetailsManager(mgr.GetClient(), apisv1alpha1.StoreConfigGroupVersionKind))
+	}
+
+	opts := []managed.ReconcilerOption{
+		managed.WithExternalConnecter(&connector{
+			kube:         mgr.GetClient(),
+			usage:        resource.NewProviderConfigUsageTracker(mgr.GetClient(), &apisv1alpha1.ProviderConfigUsage{}),
+			newServiceFn: newNoOpService,
+			talosClients: talosClients}),
+		managed.WithLogger(o.Logger.WithValues("controller", name)),
+		managed.WithPollInterval(o.PollInterval),
+		managed.WithRecorder(event.NewAPIRecorder(mgr.GetEventRecorderFor(name))),
+		managed.WithConnectionPublishers(cps...),
+		managed.WithManagementPolicies(),
+	}
+
+	if o.Features.Enabled(feature.EnableAlphaChangeLogs) {
+		opts = append(opts, managed.WithChangeLogger(o.ChangeLogOptions.ChangeLogger))
+	}
+
+	if o.MetricOptions != nil {
+		opts = append(opts, managed.WithMetricRecorder(o.MetricOptions.MRMetrics))
+	}
+
+	if o.MetricOptions != nil && o.MetricOptions.MRStateMetrics != nil {
+		stateMetricsRecorder := statemetrics.NewMRStateRecorder(
+			mgr.GetClient(), o.Logger, o.MetricOptions.MRStateMetrics, &v1alpha1.EtcdSnapshotList{}, o.MetricOptions.PollStateMetricInterval,
+		)
+		if err := mgr.Add(stateMetricsRecorder); err != nil {
+			return errors.Wrap(err, "cannot register MR state metrics recorder for kind v1alpha1.EtcdSnapshotList")
+		}
+	}
+
+	r := managed.NewReconciler(mgr, resource.ManagedKind(v1alpha1.EtcdSnapshotGroupVersionKind), opts...)
+
+	return ctrl.NewControllerManagedBy(mgr).
+		Named(name).
+		WithOptions(o.ForControllerRuntime()).
+		WithEventFilter(resource.DesiredStateChanged()).
+		For(&v1alpha1.EtcdSnapshot{}).
+		Complete(ratelimiter.NewReconciler(name, r, o.GlobalRateLimiter))
+}
+
+// A connector is expected to produce an ExternalClient when its Connect method
+// is called.
+type connector struct {
+	kube         ctrlclient.Client
+	usage        resource.Tracker
+	newServiceFn func(creds []byte) (interface{}, error)
+	talosClients *clients.Cache
+}
+
+// Connect typically produces an ExternalClient by:
+// 1. Tracking that the managed resource is using a ProviderConfig.
+// 2. Getting the managed resource's ProviderConfig.
+// 3. Getting the credentials specified by the ProviderConfig.
+// 4. Using the credentials to form a client.
+func (c *connector) Connect(ctx context.Context, mg resource.Managed) (managed.ExternalClient, error) {
+	cr, ok := mg.(*v1alpha1.EtcdSnapshot)
+	if !ok {
+		return nil, errors.New(errNotEtcdSnapshot)
+	}
+
+	if err := c.usage.Track(ctx, mg); err != nil {
+		return nil, errors.Wrap(err, errTrackPCUsage)
+	}
+
+	pc := &apisv1alpha1.ProviderConfig{}
+	if err := c.kube.Get(ctx, types.NamespacedName{Name: cr.GetProviderConfigReference().Name}, pc); err != nil {
+		return nil, errors.Wrap(err, errGetPC)
+	}
+
+	cd := pc.Spec.Credentials
+	data, err := resource.CommonCredentialExtractor(ctx, cd.Source, c.kube, cd.CommonCredentialSelectors)
+	if err != nil {
+		return nil, errors.Wrap(err, errGetCreds)
+	}
+
+	svc, err := c.newServiceFn(data)
+	if err != nil {
+		return nil, errors.Wrap(err, errNewClient)
+	}
+
+	return &external{kube: c.kube, service: svc, talosClients: c.talosClients}, nil
+}
+
+// An ExternalClient observes, then either creates, updates, or deletes an
+// external resource to ensure it reflects the managed resource's desired state.
+type external struct {
+	kube ctrlclient.Client
+	// A 'client' used to connect to the external resource API. In practice this
+	// would be something like an AWS SDK client.
+	service interface{}
+	// talosClients shares Talos API clients between reconciles.
+	talosClients *clients.Cache
+	// newSnapshotClientFn allows tests to stub the Talos etcd snapshot API.
+	newSnapshotClientFn func(context.Context, *v1alpha1.EtcdSnapshot) (snapshotClient, error)
+	// newStoreFn allows tests to stub the snapshot store.
+	newStoreFn func(context.Context, *v1alpha1.EtcdSnapshot) (snapshotstore.Store, error)
+}
+
+type snapshotClient interface {
+	EtcdSnapshot(context.Context, *machine.EtcdSnapshotRequest, ...grpc.CallOption) (io.ReadCloser, error)
+	Close() error
+}
+
+func (c *external) Observe(ctx context.Context, mg resource.Managed) (managed.ExternalObservation, error) {
+	cr, ok := mg.(*v1alpha1.EtcdSnapshot)
+	if !ok {
+		return managed.ExternalObservation{}, errors.New(errNotEtcdSnapshot)
+	}
+
+	fmt.Printf("Observing EtcdSnapshot: %s\n", cr.Name)
+
+	// Stored snapshots outlive the resource; there is nothing to delete.
+	if meta.WasDeleted(cr) {
+		return managed.ExternalObservation{ResourceExists: false}, nil
+	}
+
+	last := cr.Status.AtProvider.LastSnapshot
+	if last == nil {
+		return managed.ExternalObservation{ResourceExists: false}, nil
+	}
+
+	cr.SetConditions(xpv1.Available())
+
+	next, err := nextScheduleTime(cr, last.Time.Time)
+	if err != nil {
+		return managed.ExternalObservation{}, err
+	}
+	if next == nil {
+		cr.Status.AtProvider.NextScheduleTime = nil
+		return managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: true}, nil
+	}
+
+	nextTime := metav1.NewTime(*next)
+	cr.Status.AtProvider.NextScheduleTime = &nextTime
+	if time.Now().Before(*next) {
+		return managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: true}, nil
+	}
+
+	return managed.ExternalObservation{
+		ResourceExists:   true,
+		ResourceUpToDate: false,
+		Diff:             fmt.Sprintf("scheduled snapshot was due at %s", next.Format(time.RFC3339)),
+	}, nil
+}
+
+func (c *external) Create(ctx context.Context, mg resource.Managed) (managed.ExternalCreation, error) {
+	cr, ok := mg.(*v1alpha1.EtcdSnapshot)
+	if !ok {
+		return managed.ExternalCreation{}, errors.New(errNotEtcdSnapshot)
+	}
+
+	if err := c.snapshot(ctx, cr); err != nil {
+		return managed.ExternalCreation{}, err
+	}
+
+	return managed.ExternalCreation{
+		ConnectionDetails: managed.ConnectionDetails{},
+	}, nil
+}
+
+func (c *external) Update(ctx context.Context, mg resource.Managed) (managed.ExternalUpdate, error) {
+	cr, ok := mg.(*v1alpha1.EtcdSnapshot)
+	if !ok {
+		return managed.ExternalUpdate{}, errors.New(errNotEtcdSnapshot)
+	}
+
+	if err := c.snapshot(ctx, cr); err != nil {
+		return managed.ExternalUpdate{}, err
+	}
+
+	return managed.ExternalUpdate{
+		ConnectionDetails: managed.ConnectionDetails{},
+	}, nil
+}
+
+func (c *external) Delete(ctx context.Context, mg resource.Managed) (managed.ExternalDelete, error) {
+	cr, ok := mg.(*v1alpha1.EtcdSnapshot)
+	if !ok {
+		return managed.ExternalDelete{}, errors.New(errNotEtcdSnapshot)
+	}
+
+	fmt.Printf("Deleting EtcdSnapshot %s keeps %d stored snapshots\n", cr.Name, len(cr.Status.AtProvider.Snapshots))
+
+	return managed.ExternalDelete{}, nil
+}
+
+func (c *external) Disconnect(ctx context.Context) error {
+	return nil
+}
+
+// snapshot streams an etcd snapshot from the node to a temporary file, writes
+// it to the configured store and prunes snapshots beyond the retention count.
+func (c *external) snapshot(ctx context.Context, cr *v1alpha1.EtcdSnapshot) error {
+	if _, err := parseSchedule(cr); err != nil {
+		return err
+	}
+
+	store, err := c.newStore(ctx, cr)
+	if err != nil {
+		return err
+	}
+
+	snapshotCtx, cancel := context.WithTimeout(ctx, snapshotTimeout)
+	defer cancel()
+
+	talosClient, err := c.newSnapshotClient(snapshotCtx, cr)
+	if err != nil {
+		return errors.Wrap(err, "failed to create Talos client")
+	}
+	defer talosClient.Close() //nolint:errcheck
+
+	fmt.Printf("Taking etcd snapshot from node %s\n", cr.Spec.ForProvider.Node)
+
+	stream, err := talosClient.EtcdSnapshot(talosclient.WithNode(snapshotCtx, cr.Spec.ForProvider.Node), &machine.EtcdSnapshotRequest{})
+	if err != nil {
+		return errors.Wrap(err, "failed to take etcd snapshot")
+	}
+	defer stream.Close() //nolint:errcheck
+
+	file, err := os.CreateTemp("", "etcd-snapshot-*.db")
+	if err != nil {
+		return errors.Wrap(err, "cannot create temporary snapshot file")
+	}
+	defer os.Remove(file.Name()) //nolint:errcheck
+	defer file.Close()           //nolint:errcheck
+
+	hash := sha256.New()
+	size, err := io.Copy(io.MultiWriter(file, hash), stream)
+	if err != nil {
+		return errors.Wrap(err, "failed to stream etcd snapshot")
+	}
+	if size == 0 {
+		return errors.New("node returned an empty etcd snapshot")
+	}
+
+	revision, err := snapshotRevision(file.Name())
+	if err != nil {
+		return err
+	}
+
+	if _, err := file.Seek(0, io.SeekStart); err != nil {
+		return errors.Wrap(err, "cannot rewind temporary snapshot file")
+	}
+
+	now := metav1.Now()
+	location, err := store.Put(ctx, snapshotName(cr, now.Time), file, size)
+	if err != nil {
+		return errors.Wrap(err, "failed to store etcd snapshot")
+	}
+
+	record := v1alpha1.EtcdSnapshotRecord{
+		Location: location,
+		Size:     size,
+		Revision: revision,
+		Hash:     hex.EncodeToString(hash.Sum(nil)),
+		Time:     now,
+	}
+	cr.Status.AtProvider.LastSnapshot = record.DeepCopy()
+	cr.Status.AtProvider.Snapshots = append(cr.Status.AtProvider.Snapshots, record)
+
+	fmt.Printf("Stored etcd snapshot of node %s at %s (%d bytes, revision %d)\n", cr.Spec.ForProvider.Node, location, size, revision)
+
+	if err := pruneSnapshots(ctx, cr, store); err != nil {
+		return err
+	}
+
+	cr.SetConditions(xpv1.Available())
+
+	return nil
+}
+
+// pruneSnapshots deletes the oldest snapshots beyond the retention count.
+// Snapshots that fail to delete stay in status so that the next snapshot
+// retries them, up to maxExpiredSnapshots; older ones are forgotten and left
+// in the store.
+func pruneSnapshots(ctx context.Context, cr *v1alpha1.EtcdSnapshot, store snapshotstore.Store) error {
+	retention := defaultRetention
+	if r := cr.Spec.ForProvider.Retention; r != nil && *r > 0 {
+		retention = *r
+	}
+
+	snapshots := cr.Status.AtProvider.Snapshots
+	for len(snapshots) > retention {
+		location := snapshots[0].Location
+		if err := store.Delete(ctx, location); err != nil {
+			if expired := len(snapshots) - retention; expired > maxExpiredSnapshots {
+				snapshots = snapshots[expired-maxExpiredSnapshots:]
+			}
+			cr.Status.AtProvider.Snapshots = snapshots
+			return errors.Wrapf(err, "failed to delete expired etcd snapshot %s", location)
+		}
+		snapshots = snapshots[1:]
+	}
+	cr.Status.AtProvider.Snapshots = snapshots
+
+	return nil
+}
+
+func parseSchedule(cr *v1alpha1.EtcdSnapshot) (cron.Schedule, error) {
+	schedule := cr.Spec.ForProvider.Schedule
+	if schedule == nil || strings.TrimSpace(*schedule) == "" {
+		return nil, nil
+	}
+
+	parsed, err := cron.ParseStandard(*schedule)
+	if err != nil {
+		return nil, errors.Wrapf(err, "invalid schedule %q", *schedule)
+	}
+
+	return parsed, nil
+}
+
+// nextScheduleTime returns when the snapshot after the one taken at last is
+// due, or nil when no schedule is set.
+func nextScheduleTime(cr *v1alpha1.EtcdSnapshot, last time.Time) (*time.Time, error) {
+	schedule, err := parseSchedule(cr)
+	if err != nil || schedule == nil {
+		return nil, err
+	}
+
+	next := schedule.Next(last)
+
+	return &next, nil
+}
+
+// snapshotName is the store-independent name of a snapshot taken at the given
+// time. It is a valid Kubernetes object name, file name and object key.
+func snapshotName(cr *v1alpha1.EtcdSnapshot, when time.Time) string {
+	return fmt.Sprintf("%s-%s", cr.Name, when.UTC().Format("20060102-150405"))
+}
+
+// snapshotRevision returns the highest etcd revision stored in a snapshot.
+func snapshotRevision(path string) (int64, error) {
+	db, err := bolt.Open(path, 0o400, &bolt.Options{ReadOnly: true, Timeout: time.Second})
+	if err != nil {
+		return 0, errors.Wrap(err, "cannot open etcd snapshot")
+	}
+	defer db.Close() //nolint:errcheck
+
+	var revision int64
+	err = db.View(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket([]byte(etcdKeyBucket))
+		if bucket == nil {
+			return errors.New("etcd snapshot has no key bucket")
+		}
+		key, _ := bucket.Cursor().Last()
+		if len(key) < 8 {
+			return nil
+		}
+		revision = int64(binary.BigEndian.Uint64(key[:8])) //nolint:gosec // etcd revisions are positive int64 values.
+
+		return nil
+	})
+	if err != nil {
+		return 0, errors.Wrap(err, "cannot read etcd snapshot revision")
+	}
+
+	return revision, nil
+}
+
+func (c *external) newStore(ctx context.Context, cr *v1alpha1.EtcdSnapshot) (snapshotstore.Store, error) {
+	if c.newStoreFn != nil {
+		return c.newStoreFn(ctx, cr)
+	}
+
+	return snapshotstore.New(ctx, c.kube, cr)
+}
+
+func (c *external) newSnapshotClient(ctx context.Context, cr *v1alpha1.EtcdSnapshot) (snapshotClient, error) {
+	if c.newSnapshotClientFn != nil {
+		return c.newSnapshotClientFn(ctx, cr)
+	}
+
+	clientConfig, err := clients.ResolveClientConfiguration(ctx, c.kube, clients.ClientConfigurationSource{
+		ClientConfiguration: cr.Spec.ForProvider.ClientConfiguration,
+		SecretRef:           cr.Spec.ForProvider.ClientConfigurationSecretRef,
+		SecretsRef:          cr.Spec.ForProvider.SecretsRef,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return c.talosClients.Client(ctx, []string{getEtcdSnapshotEndpoint(cr)}, clientConfig)
+}
+
+func getEtcdSnapshotEndpoint(cr *v1alpha1.EtcdSnapshot) string {
+	endpoint := cr.Spec.ForProvider.Node + ":50000"
+	if cr.Spec.ForProvider.Endpoint != nil && *cr.Spec.ForProvider.Endpoint != "" {
+		endpoint = *cr.Spec.ForProvider.Endpoint
+	}
+
+	return endpoint
+}
@@ -0,0 +1,358 @@
+/*
+Copyright 2025 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package etcdsnapshot
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	"github.com/siderolabs/talos/pkg/machinery/api/machine"
+	bolt "go.etcd.io/bbolt"
+	"google.golang.org/grpc"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/crossplane-contrib/provider-talos/apis/machine/v1alpha1"
+	"github.com/crossplane-contrib/provider-talos/internal/snapshotstore"
+)
+
+// Unlike many Kubernetes projects Crossplane does not use third party testing
+// libraries, per the common Go test review comments. Crossplane encourages the
+// use of table driven unit tests. The tests of the crossplane-runtime project
+// are representative of the testing style Crossplane encourages.
+//
+// https://github.com/golang/go/wiki/TestComments
+// https://github.com/crossplane/crossplane/blob/master/CONTRIBUTING.md#contributing-code
+
+type fakeSnapshotClient struct {
+	data []byte
+}
+
+func (f *fakeSnapshotClient) EtcdSnapshot(context.Context, *machine.EtcdSnapshotRequest, ...grpc.CallOption) (io.ReadCloser, error) {
+	return io.NopCloser(bytes.NewReader(f.data)), nil
+}
+
+func (f *fakeSnapshotClient) Close() error {
+	return nil
+}
+
+// testSnapshotData returns a bbolt database laid out like an etcd snapshot
+// with keys up to the given revision.
+func testSnapshotData(t *testing.T, revision uint64) []byte {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "snapshot.db")
+	db, err := bolt.Open(path, 0o600, nil)
+	if err != nil {
+		t.Fatalf("open bbolt: %v", err)
+	}
+	err = db.Update(func(tx *bolt.Tx) error {
+		bucket, err := tx.CreateBucket([]byte(etcdKeyBucket))
+		if err != nil {
+			return err
+		}
+		for rev := uint64(1); rev <= revision; rev++ {
+			key := make([]byte, 17)
+			binary.BigEndian.PutUint64(key[:8], rev)
+			key[8] = '_'
+			if err := bucket.Put(key, []byte("value")); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("write bbolt: %v", err)
+	}
+	if err := db.Close(); err != nil {
+		t.Fatalf("close bbolt: %v", err)
+	}
+
+	data, err := os.ReadFile(path) //nolint:gosec // Test file in a temporary directory.
+	if err != nil {
+		t.Fatalf("read bbolt: %v", err)
+	}
+
+	return data
+}
+
+func testEtcdSnapshot(schedule *string) *v1alpha1.EtcdSnapshot {
+	return &v1alpha1.EtcdSnapshot{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-etcd-snapshot"},
+		Spec: v1alpha1.EtcdSnapshotSpec{
+			ForProvider: v1alpha1.EtcdSnapshotParameters{
+				Node:     "192.168.1.100",
+				Schedule: schedule,
+				ClientConfiguration: &v1alpha1.ClientConfiguration{
+					CACertificate:     "ca",
+					ClientCertificate: "crt",
+					ClientKey:         "key",
+				},
+			},
+		},
+	}
+}
+
+func ptr[T any](v T) *T {
+	return &v
+}
+
+func TestObserve(t *testing.T) {
+	recent := metav1.NewTime(time.Now().Add(-time.Minute))
+	old := metav1.NewTime(time.Now().Add(-2 * time.Hour))
+
+	type want struct {
+		o       managed.ExternalObservation
+		nextSet bool
+		err     bool
+	}
+
+	cases := map[string]struct {
+		reason   string
+		schedule *string
+		last     *metav1.Time
+		want     want
+	}{
+		"NoSnapshot": {
+			reason: "A resource without a snapshot should report that the external resource does not exist.",
+			want:   want{o: managed.ExternalObservation{ResourceExists: false}},
+		},
+		"OneShot": {
+			reason: "A resource without a schedule should be up to date once a snapshot was taken.",
+			last:   &old,
+			want:   want{o: managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: true}},
+		},
+		"ScheduleNotDue": {
+			reason:   "A scheduled snapshot should not be taken before it is due.",
+			schedule: ptr("@hourly"),
+			last:     &recent,
+			want:     want{o: managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: true}, nextSet: true},
+		},
+		"ScheduleDue": {
+			reason:   "A scheduled snapshot should be taken once it is due.",
+			schedule: ptr("@hourly"),
+			last:     &old,
+			want:     want{o: managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: false}, nextSet: true},
+		},
+		"InvalidSchedule": {
+			reason:   "An invalid schedule should return an error.",
+			schedule: ptr("every hour"),
+			last:     &old,
+			want:     want{err: true},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			cr := testEtcdSnapshot(tc.schedule)
+			if tc.last != nil {
+				cr.Status.AtProvider.LastSnapshot = &v1alpha1.EtcdSnapshotRecord{Location: "ns/snap", Time: *tc.last}
+			}
+
+			e := &external{}
+			got, err := e.Observe(context.Background(), cr)
+			if tc.want.err {
+				if err == nil {
+					t.Fatalf("\n%s\ne.Observe(...): expected error", tc.reason)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("\n%s\ne.Observe(...): unexpected error: %v", tc.reason, err)
+			}
+
+			got.Diff = ""
+			if diff := cmp.Diff(tc.want.o, got); diff != "" {
+				t.Errorf("\n%s\ne.Observe(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+			if gotSet := cr.Status.AtProvider.NextScheduleTime != nil; gotSet != tc.want.nextSet {
+				t.Errorf("\n%s\nNextScheduleTime set: want %t, got %t", tc.reason, tc.want.nextSet, gotSet)
+			}
+		})
+	}
+}
+
+func TestCreateVolumeWithRetention(t *testing.T) {
+	dir := t.TempDir()
+	data := testSnapshotData(t, 42)
+	sum := sha256.Sum256(data)
+
+	cr := testEtcdSnapshot(nil)
+	cr.Spec.ForProvider.Retention = ptr(2)
+	cr.Spec.ForProvider.Storage.Volume = &v1alpha1.EtcdSnapshotVolumeStorage{Path: dir}
+
+	var existing []v1alpha1.EtcdSnapshotRecord
+	for _, name := range []string{"old-1.db", "old-2.db"} {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte("old"), 0o600); err != nil {
+			t.Fatalf("write old snapshot: %v", err)
+		}
+		existing = append(existing, v1alpha1.EtcdSnapshotRecord{Location: path})
+	}
+	cr.Status.AtProvider.Snapshots = existing
+
+	e := &external{
+		newSnapshotClientFn: func(context.Context, *v1alpha1.EtcdSnapshot) (snapshotClient, error) {
+			return &fakeSnapshotClient{data: data}, nil
+		},
+	}
+
+	if _, err := e.Create(context.Background(), cr); err != nil {
+		t.Fatalf("e.Create(...): unexpected error: %v", err)
+	}
+
+	last := cr.Status.AtProvider.LastSnapshot
+	if last == nil {
+		t.Fatal("e.Create(...): expected LastSnapshot to be set")
+	}
+	if last.Revision != 42 {
+		t.Errorf("e.Create(...): revision: want 42, got %d", last.Revision)
+	}
+	if last.Size != int64(len(data)) {
+		t.Errorf("e.Create(...): size: want %d, got %d", len(data), last.Size)
+	}
+	if want := hex.EncodeToString(sum[:]); last.Hash != want {
+		t.Errorf("e.Create(...): hash: want %s, got %s", want, last.Hash)
+	}
+
+	stored, err := os.ReadFile(last.Location)
+	if err != nil {
+		t.Fatalf("read stored snapshot: %v", err)
+	}
+	if !bytes.Equal(stored, data) {
+		t.Error("e.Create(...): stored snapshot does not match the streamed snapshot")
+	}
+
+	wantLocations := []string{existing[1].Location, last.Location}
+	var gotLocations []string
+	for _, s := range cr.Status.AtProvider.Snapshots {
+		gotLocations = append(gotLocations, s.Location)
+	}
+	if diff := cmp.Diff(wantLocations, gotLocations); diff != "" {
+		t.Errorf("e.Create(...): retained snapshots: -want, +got:\n%s\n", diff)
+	}
+	if _, err := os.Stat(existing[0].Location); !os.IsNotExist(err) {
+		t.Errorf("e.Create(...): expected %s to be pruned", existing[0].Location)
+	}
+
+	if diff := cmp.Diff(xpv1.Available(), cr.GetCondition(xpv1.TypeReady), cmp.FilterPath(func(p cmp.Path) bool {
+		return p.Last().String() == ".LastTransitionTime"
+	}, cmp.Ignore())); diff != "" {
+		t.Errorf("e.Create(...): Ready condition: -want, +got:\n%s\n", diff)
+	}
+}
+
+// fakeStore records deletes and fails them when err is set.
+type fakeStore struct {
+	snapshotstore.Store
+	deleted []string
+	err     error
+}
+
+func (s *fakeStore) Delete(_ context.Context, location string) error {
+	if s.err != nil {
+		return s.err
+	}
+	s.deleted = append(s.deleted, location)
+	return nil
+}
+
+func TestPruneSnapshots(t *testing.T) {
+	records := func(n int) []v1alpha1.EtcdSnapshotRecord {
+		var r []v1alpha1.EtcdSnapshotRecord
+		for i := range n {
+			r = append(r, v1alpha1.EtcdSnapshotRecord{Location: fmt.Sprintf("snapshot-%02d", i)})
+		}
+		return r
+	}
+
+	type want struct {
+		first   string
+		kept    int
+		deleted int
+		err     bool
+	}
+
+	cases := map[string]struct {
+		reason    string
+		retention *int
+		snapshots int
+		deleteErr error
+		want      want
+	}{
+		"DefaultRetention": {
+			reason:    "Snapshots beyond the default retention should be deleted when retention is unset.",
+			snapshots: defaultRetention + 2,
+			want:      want{first: "snapshot-02", kept: defaultRetention, deleted: 2},
+		},
+		"Retention": {
+			reason:    "Snapshots beyond the retention should be deleted.",
+			retention: ptr(3),
+			snapshots: 5,
+			want:      want{first: "snapshot-02", kept: 3, deleted: 2},
+		},
+		"FailedDeleteIsRetried": {
+			reason:    "Expired snapshots that fail to delete should stay in status to be retried.",
+			retention: ptr(3),
+			snapshots: 5,
+			deleteErr: errors.New("boom"),
+			want:      want{first: "snapshot-00", kept: 5, err: true},
+		},
+		"FailedDeletesAreCapped": {
+			reason:    "Status should not grow beyond the retention and the expired snapshots retried.",
+			retention: ptr(3),
+			snapshots: 3 + maxExpiredSnapshots + 5,
+			deleteErr: errors.New("boom"),
+			want:      want{first: "snapshot-05", kept: 3 + maxExpiredSnapshots, err: true},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			cr := testEtcdSnapshot(nil)
+			cr.Spec.ForProvider.Retention = tc.retention
+			cr.Status.AtProvider.Snapshots = records(tc.snapshots)
+			store := &fakeStore{err: tc.deleteErr}
+
+			err := pruneSnapshots(context.Background(), cr, store)
+			if gotErr := err != nil; gotErr != tc.want.err {
+				t.Fatalf("\n%s\npruneSnapshots(...): want error %t, got %v", tc.reason, tc.want.err, err)
+			}
+			got := want{
+				first:   cr.Status.AtProvider.Snapshots[0].Location,
+				kept:    len(cr.Status.AtProvider.Snapshots),
+				deleted: len(store.deleted),
+				err:     tc.want.err,
+			}
+			if diff := cmp.Diff(tc.want, got, cmp.AllowUnexported(want{})); diff != "" {
+				t.Errorf("\n%s\npruneSnapshots(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -18,18 +18,21 @@ package factoryschematic
 
 import (
 	"context"
-	"fmt"
+	"encoding/json"
 
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
 	"github.com/crossplane/crossplane-runtime/pkg/feature"
 
 	"github.com/pkg/errors"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	"github.com/crossplane/crossplane-runtime/pkg/connection"
 	"github.com/crossplane/crossplane-runtime/pkg/controller"
 	"github.com/crossplane/crossplane-runtime/pkg/event"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
 	"github.com/crossplane/crossplane-runtime/pkg/ratelimiter"
 	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
 	"github.com/crossplane/crossplane-runtime/pkg/resource"
@@ -38,22 +41,28 @@ import (
 	"github.com/crossplane-contrib/provider-talos/apis/image/v1alpha1"
 	apisv1alpha1 "github.com/crossplane-contrib/provider-talos/apis/v1alpha1"
 	"github.com/crossplane-contrib/provider-talos/internal/features"
+	"github.com/crossplane-contrib/provider-talos/internal/imagefactory"
 )
 
 const (
 	errNotFactorySchematic = "managed resource is not a FactorySchematic custom resource"
 	errTrackPCUsage        = "cannot track ProviderConfig usage"
 	errGetPC               = "cannot get ProviderConfig"
-	errGetCreds            = "cannot get credentials"
 
-	errNewClient = "cannot create new Service"
+	errSchematic          = "cannot build schematic"
+	errSchematicExclusive = "schematic cannot be combined with systemExtensions, extraKernelArgs, meta, overlay or secureboot"
+	errCreateSchematic    = "cannot create schematic"
 )
 
-// A NoOpService does nothing.
-type NoOpService struct{}
-
-var (
-	newNoOpService = func(_ []byte) (interface{}, error) { return &NoOpService{}, nil }
+// Connection detail keys published by a FactorySchematic.
+const (
+	connectionKeySchematicID              = "schematic_id"
+	connectionKeyInstallerImage           = "installer_image"
+	connectionKeySecureBootInstallerImage = "secureboot_installer_image"
+	connectionKeyISO                      = "iso_url"
+	connectionKeyDiskImage                = "disk_image_url"
+	connectionKeyKernel                   = "kernel_url"
+	connectionKeyInitramfs                = "initramfs_url"
 )
 
 // Setup adds a controller that reconciles FactorySchematic managed resources.
@@ -69,7 +78,7 @@ func Setup(mgr ctrl.Manager, o controller.Options) error {
 		managed.WithExternalConnecter(&connector{
 			kube:         mgr.GetClient(),
 			usage:        resource.NewProviderConfigUsageTracker(mgr.GetClient(), &apisv1alpha1.ProviderConfigUsage{}),
-			newServiceFn: newNoOpService}),
+			newServiceFn: imagefactory.NewClient}),
 		managed.WithLogger(o.Logger.WithValues("controller", name)),
 		managed.WithPollInterval(o.PollInterval),
 		managed.WithRecorder(event.NewAPIRecorder(mgr.GetEventRecorderFor(name))),
@@ -109,14 +118,11 @@ func Setup(mgr ctrl.Manager, o controller.Options) error {
 type connector struct {
 	kube         client.Client
 	usage        resource.Tracker
-	newServiceFn func(creds []byte) (interface{}, error)
+	newServiceFn func(baseURL string) *imagefactory.Client
 }
 
-// Connect typically produces an ExternalClient by:
-// 1. Tracking that the managed resource is using a ProviderConfig.
-// 2. Getting the managed resource's ProviderConfig.
-// 3. Getting the credentials specified by the ProviderConfig.
-// 4. Using the credentials to form a client.
+// Connect produces an ExternalClient for the Image Factory configured by the
+// managed resource's ProviderConfig.
 func (c *connector) Connect(ctx context.Context, mg resource.Managed) (managed.ExternalClient, error) {
 	cr, ok := mg.(*v1alpha1.FactorySchematic)
 	if !ok {
@@ -132,26 +138,18 @@ func (c *connector) Connect(ctx context.Context, mg resource.Managed) (managed.E
 		return nil, errors.Wrap(err, errGetPC)
 	}
 
-	cd := pc.Spec.Credentials
-	data, err := resource.CommonCredentialExtractor(ctx, cd.Source, c.kube, cd.CommonCredentialSelectors)
-	if err != nil {
-		return nil, errors.Wrap(err, errGetCreds)
+	baseURL := ""
+	if pc.Spec.ImageFactory != nil {
+		baseURL = pc.Spec.ImageFactory.URL
 	}
 
-	svc, err := c.newServiceFn(data)
-	if err != nil {
-		return nil, errors.Wrap(err, errNewClient)
-	}
-
-	return &external{service: svc}, nil
+	return &external{service: c.newServiceFn(baseURL)}, nil
 }
 
 // An ExternalClient observes, then either creates, updates, or deletes an
 // external resource to ensure it reflects the managed resource's desired state.
 type external struct {
-	// A 'client' used to connect to the external resource API. In practice this
-	// would be something like an AWS SDK client.
-	service interface{}
+	service *imagefactory.Client
 }
 
 func (c *external) Observe(ctx context.Context, mg resource.Managed) (managed.ExternalObservation, error) {
@@ -160,23 +158,45 @@ func (c *external) Observe(ctx context.Context, mg resource.Managed) (managed.Ex
 		return managed.ExternalObservation{}, errors.New(errNotFactorySchematic)
 	}
 
-	// These fmt statements should be removed in the real implementation.
-	fmt.Printf("Observing: %+v", cr)
+	// Schematics are immutable and cannot be deleted from the Image Factory.
+	if meta.WasDeleted(cr) {
+		return managed.ExternalObservation{ResourceExists: false}, nil
+	}
+
+	s, err := schematic(cr)
+	if err != nil {
+		return managed.ExternalObservation{}, errors.Wrap(err, errSchematic)
+	}
+	hash, err := s.ID()
+	if err != nil {
+		return managed.ExternalObservation{}, errors.Wrap(err, errSchematic)
+	}
+
+	// Offline schematics are never uploaded; their ID is the one the Image
+	// Factory would assign.
+	if ptr.Deref(cr.Spec.ForProvider.Offline, false) {
+		cr.Status.AtProvider.ID = hash
+		cr.Status.AtProvider.SpecHash = hash
+	}
+	if cr.Status.AtProvider.ID == "" {
+		return managed.ExternalObservation{ResourceExists: false}, nil
+	}
+
+	// Schematics uploaded before the hash was recorded are current if the
+	// factory assigned the ID computed locally.
+	if cr.Status.AtProvider.SpecHash == "" && cr.Status.AtProvider.ID == hash {
+		cr.Status.AtProvider.SpecHash = hash
+	}
+
+	cr.SetConditions(xpv1.Available())
 
+	// A changed schematic must be uploaded again. It is compared with the hash
+	// of the uploaded schematic rather than the assigned ID, which a factory
+	// of another version may compute differently.
 	return managed.ExternalObservation{
-		// Return false when the external resource does not exist. This lets
-		// the managed resource reconciler know that it needs to call Create to
-		// (re)create the resource, or that it has successfully been deleted.
-		ResourceExists: true,
-
-		// Return false when the external resource exists, but it not up to date
-		// with the desired managed resource state. This lets the managed
-		// resource reconciler know that it needs to call Update.
-		ResourceUpToDate: true,
-
-		// Return any details that may be required to connect to the external
-		// resource. These will be stored as the connection secret.
-		ConnectionDetails: managed.ConnectionDetails{},
+		ResourceExists:    true,
+		ResourceUpToDate:  cr.Status.AtProvider.SpecHash == hash,
+		ConnectionDetails: c.publishArtifacts(cr),
 	}, nil
 }
 
@@ -186,13 +206,11 @@ func (c *external) Create(ctx context.Context, mg resource.Managed) (managed.Ext
 		return managed.ExternalCreation{}, errors.New(errNotFactorySchematic)
 	}
 
-	fmt.Printf("Creating: %+v", cr)
+	if err := c.createSchematic(ctx, cr); err != nil {
+		return managed.ExternalCreation{}, err
+	}
 
-	return managed.ExternalCreation{
-		// Optionally return any details that may be required to connect to the
-		// external resource. These will be stored as the connection secret.
-		ConnectionDetails: managed.ConnectionDetails{},
-	}, nil
+	return managed.ExternalCreation{ConnectionDetails: c.publishArtifacts(cr)}, nil
 }
 
 func (c *external) Update(ctx context.Context, mg resource.Managed) (managed.ExternalUpdate, error) {
@@ -201,26 +219,147 @@ func (c *external) Update(ctx context.Context, mg resource.Managed) (managed.Ext
 		return managed.ExternalUpdate{}, errors.New(errNotFactorySchematic)
 	}
 
-	fmt.Printf("Updating: %+v", cr)
+	if err := c.createSchematic(ctx, cr); err != nil {
+		return managed.ExternalUpdate{}, err
+	}
 
-	return managed.ExternalUpdate{
-		// Optionally return any details that may be required to connect to the
-		// external resource. These will be stored as the connection secret.
-		ConnectionDetails: managed.ConnectionDetails{},
-	}, nil
+	return managed.ExternalUpdate{ConnectionDetails: c.publishArtifacts(cr)}, nil
 }
 
 func (c *external) Delete(ctx context.Context, mg resource.Managed) (managed.ExternalDelete, error) {
-	cr, ok := mg.(*v1alpha1.FactorySchematic)
+	_, ok := mg.(*v1alpha1.FactorySchematic)
 	if !ok {
 		return managed.ExternalDelete{}, errors.New(errNotFactorySchematic)
 	}
 
-	fmt.Printf("Deleting: %+v", cr)
-
+	// The Image Factory keeps schematics forever; there is nothing to delete.
 	return managed.ExternalDelete{}, nil
 }
 
 func (c *external) Disconnect(ctx context.Context) error {
 	return nil
 }
+
+// createSchematic uploads the schematic to the Image Factory and records the
+// ID it was assigned and the hash of the uploaded schematic, or records the
+// locally computed ID of an offline schematic.
+func (c *external) createSchematic(ctx context.Context, cr *v1alpha1.FactorySchematic) error {
+	s, err := schematic(cr)
+	if err != nil {
+		return errors.Wrap(err, errSchematic)
+	}
+
+	hash, err := s.ID()
+	if err != nil {
+		return errors.Wrap(err, errSchematic)
+	}
+
+	if ptr.Deref(cr.Spec.ForProvider.Offline, false) {
+		cr.Status.AtProvider.ID = hash
+		cr.Status.AtProvider.SpecHash = hash
+		return nil
+	}
+
+	id, err := c.service.CreateSchematic(ctx, s)
+	if err != nil {
+		return errors.Wrap(err, errCreateSchematic)
+	}
+	cr.Status.AtProvider.ID = id
+	cr.Status.AtProvider.SpecHash = hash
+
+	return nil
+}
+
+// publishArtifacts records the artifact references of the schematic in the
+// status of a FactorySchematic and returns them as connection details. Only
+// the schematic ID is published until a talosVersion is requested.
+func (c *external) publishArtifacts(cr *v1alpha1.FactorySchematic) managed.ConnectionDetails {
+	details := managed.ConnectionDetails{connectionKeySchematicID: []byte(cr.Status.AtProvider.ID)}
+
+	p := cr.Spec.ForProvider
+	if p.TalosVersion == nil || *p.TalosVersion == "" {
+		cr.Status.AtProvider.Artifacts = nil
+		return details
+	}
+
+	a := c.service.Artifacts(cr.Status.AtProvider.ID, *p.TalosVersion, ptr.Deref(p.Platform, ""), ptr.Deref(p.Arch, ""))
+	cr.Status.AtProvider.Artifacts = &v1alpha1.FactorySchematicArtifacts{
+		TalosVersion:             *p.TalosVersion,
+		InstallerImage:           a.InstallerImage,
+		SecureBootInstallerImage: a.SecureBootInstallerImage,
+		ISO:                      a.ISO,
+		DiskImage:                a.DiskImage,
+		Kernel:                   a.Kernel,
+		Initramfs:                a.Initramfs,
+	}
+
+	details[connectionKeyInstallerImage] = []byte(a.InstallerImage)
+	details[connectionKeySecureBootInstallerImage] = []byte(a.SecureBootInstallerImage)
+	details[connectionKeyISO] = []byte(a.ISO)
+	details[connectionKeyDiskImage] = []byte(a.DiskImage)
+	details[connectionKeyKernel] = []byte(a.Kernel)
+	details[connectionKeyInitramfs] = []byte(a.Initramfs)
+
+	return details
+}
+
+// schematic returns the validated schematic described by a FactorySchematic,
+// either parsed from its YAML or built from its typed fields.
+func schematic(cr *v1alpha1.FactorySchematic) (*imagefactory.Schematic, error) {
+	p := cr.Spec.ForProvider
+
+	var s *imagefactory.Schematic
+	if p.Schematic != nil {
+		if hasTypedSchematic(p) {
+			return nil, errors.New(errSchematicExclusive)
+		}
+		parsed, err := imagefactory.ParseSchematic([]byte(*p.Schematic))
+		if err != nil {
+			return nil, err
+		}
+		s = parsed
+	} else {
+		built, err := typedSchematic(p)
+		if err != nil {
+			return nil, err
+		}
+		s = built
+	}
+
+	return s, s.Validate()
+}
+
+func hasTypedSchematic(p v1alpha1.FactorySchematicParameters) bool {
+	return len(p.SystemExtensions) > 0 || len(p.ExtraKernelArgs) > 0 || len(p.Meta) > 0 || p.Overlay != nil || p.SecureBoot != nil
+}
+
+// typedSchematic builds a schematic from the typed fields of a
+// FactorySchematic.
+func typedSchematic(p v1alpha1.FactorySchematicParameters) (*imagefactory.Schematic, error) {
+	s := &imagefactory.Schematic{}
+	s.Customization.SystemExtensions.OfficialExtensions = p.SystemExtensions
+	s.Customization.ExtraKernelArgs = p.ExtraKernelArgs
+
+	for _, m := range p.Meta {
+		if m.Key < 0 || m.Key > 255 {
+			return nil, errors.Errorf("META key %d is out of range", m.Key)
+		}
+		s.Customization.Meta = append(s.Customization.Meta, imagefactory.MetaValue{Key: uint8(m.Key), Value: m.Value})
+	}
+
+	if p.Overlay != nil {
+		s.Overlay.Name = p.Overlay.Name
+		s.Overlay.Image = p.Overlay.Image
+		if p.Overlay.Options != nil && len(p.Overlay.Options.Raw) > 0 {
+			if err := json.Unmarshal(p.Overlay.Options.Raw, &s.Overlay.Options); err != nil {
+				return nil, errors.Wrap(err, "cannot decode overlay options")
+			}
+		}
+	}
+
+	if p.SecureBoot != nil {
+		s.Customization.SecureBoot.IncludeWellKnownCertificates = p.SecureBoot.IncludeWellKnownCertificates
+	}
+
+	return s, nil
+}
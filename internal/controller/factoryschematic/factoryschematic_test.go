@@ -18,13 +18,28 @@ package factoryschematic
 
 import (
 	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
 	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
 	"github.com/crossplane/crossplane-runtime/pkg/resource"
 	"github.com/crossplane/crossplane-runtime/pkg/test"
+
+	"github.com/crossplane-contrib/provider-talos/apis/image/v1alpha1"
+	apisv1alpha1 "github.com/crossplane-contrib/provider-talos/apis/v1alpha1"
+	"github.com/crossplane-contrib/provider-talos/internal/imagefactory"
 )
 
 // Unlike many Kubernetes projects Crossplane does not use third party testing
@@ -35,14 +50,25 @@ import (
 // https://github.com/golang/go/wiki/TestComments
 // https://github.com/crossplane/crossplane/blob/master/CONTRIBUTING.md#contributing-code
 
-func TestObserve(t *testing.T) {
-	type fields struct {
-		service interface{}
+// vanillaID is the ID the Image Factory assigns to the empty schematic.
+const vanillaID = "376567988ad370138ad8b2698212367b8edcb69b5fd68c80be1f2ec7d603b4ba"
+
+func factorySchematic(schematic *string, id string) *v1alpha1.FactorySchematic {
+	return &v1alpha1.FactorySchematic{
+		Spec:   v1alpha1.FactorySchematicSpec{ForProvider: v1alpha1.FactorySchematicParameters{Schematic: schematic}},
+		Status: v1alpha1.FactorySchematicStatus{AtProvider: v1alpha1.FactorySchematicObservation{ID: id}},
 	}
+}
 
-	type args struct {
-		ctx context.Context
-		mg  resource.Managed
+func TestObserve(t *testing.T) {
+	extensions := "customization:\n  systemExtensions:\n    officialExtensions: [siderolabs/gvisor]\n"
+	unknownField := "customization:\n  systemExtension: {}\n"
+	deleted := factorySchematic(nil, vanillaID)
+	deleted.SetDeletionTimestamp(&metav1.Time{Time: time.Now()})
+	uploaded := func(schematic *string) *v1alpha1.FactorySchematic {
+		cr := factorySchematic(schematic, "factory-assigned-id")
+		cr.Status.AtProvider.SpecHash = vanillaID
+		return cr
 	}
 
 	type want struct {
@@ -52,17 +78,72 @@ func TestObserve(t *testing.T) {
 
 	cases := map[string]struct {
 		reason string
-		fields fields
-		args   args
+		mg     resource.Managed
 		want   want
 	}{
-		// TODO: Add test cases.
+		"NotCreated": {
+			reason: "A FactorySchematic without an ID has not been uploaded.",
+			mg:     factorySchematic(nil, ""),
+			want:   want{o: managed.ExternalObservation{ResourceExists: false}},
+		},
+		"UpToDate": {
+			reason: "The recorded ID matches the ID of the desired schematic.",
+			mg:     factorySchematic(nil, vanillaID),
+			want: want{o: managed.ExternalObservation{
+				ResourceExists:    true,
+				ResourceUpToDate:  true,
+				ConnectionDetails: managed.ConnectionDetails{connectionKeySchematicID: []byte(vanillaID)},
+			}},
+		},
+		"SchematicChanged": {
+			reason: "A changed schematic has a different ID and must be uploaded again.",
+			mg:     factorySchematic(&extensions, vanillaID),
+			want: want{o: managed.ExternalObservation{
+				ResourceExists:    true,
+				ResourceUpToDate:  false,
+				ConnectionDetails: managed.ConnectionDetails{connectionKeySchematicID: []byte(vanillaID)},
+			}},
+		},
+		"FactoryAssignedOtherID": {
+			reason: "A schematic is up to date when the uploaded schematic is unchanged, whatever ID the factory assigned.",
+			mg:     uploaded(nil),
+			want: want{o: managed.ExternalObservation{
+				ResourceExists:    true,
+				ResourceUpToDate:  true,
+				ConnectionDetails: managed.ConnectionDetails{connectionKeySchematicID: []byte("factory-assigned-id")},
+			}},
+		},
+		"SchematicChangedSinceUpload": {
+			reason: "A schematic that changed since it was uploaded must be uploaded again.",
+			mg:     uploaded(&extensions),
+			want: want{o: managed.ExternalObservation{
+				ResourceExists:    true,
+				ResourceUpToDate:  false,
+				ConnectionDetails: managed.ConnectionDetails{connectionKeySchematicID: []byte("factory-assigned-id")},
+			}},
+		},
+		"Deleted": {
+			reason: "Schematics cannot be deleted, so a deleted FactorySchematic is gone.",
+			mg:     deleted,
+			want:   want{o: managed.ExternalObservation{ResourceExists: false}},
+		},
+		"InvalidSchematic": {
+			reason: "Unknown schematic fields are rejected.",
+			mg:     factorySchematic(&unknownField, vanillaID),
+			want:   want{err: errors.New(errSchematic)},
+		},
 	}
 
 	for name, tc := range cases {
 		t.Run(name, func(t *testing.T) {
-			e := external{service: tc.fields.service}
-			got, err := e.Observe(tc.args.ctx, tc.args.mg)
+			e := external{service: imagefactory.NewClient("")}
+			got, err := e.Observe(context.Background(), tc.mg)
+			if tc.want.err != nil {
+				if err == nil || !strings.Contains(err.Error(), tc.want.err.Error()) {
+					t.Errorf("\n%s\ne.Observe(...): got error %v, want %v\n", tc.reason, err, tc.want.err)
+				}
+				return
+			}
 			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
 				t.Errorf("\n%s\ne.Observe(...): -want error, +got error:\n%s\n", tc.reason, diff)
 			}
@@ -72,3 +153,266 @@ func TestObserve(t *testing.T) {
 		})
 	}
 }
+
+func TestCreate(t *testing.T) {
+	var uploaded string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		uploaded = string(body)
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(`{"id":"` + vanillaID + `"}`)) //nolint:errcheck
+	}))
+	defer srv.Close()
+
+	cr := factorySchematic(nil, "")
+	e := external{service: imagefactory.NewClient(srv.URL)}
+	if _, err := e.Create(context.Background(), cr); err != nil {
+		t.Fatalf("e.Create(...): unexpected error: %v", err)
+	}
+
+	if uploaded != "customization: {}\n" {
+		t.Errorf("e.Create(...): uploaded %q, want the canonical empty schematic", uploaded)
+	}
+	if cr.Status.AtProvider.ID != vanillaID {
+		t.Errorf("e.Create(...): got ID %q, want %q", cr.Status.AtProvider.ID, vanillaID)
+	}
+
+	o, err := e.Observe(context.Background(), cr)
+	if err != nil {
+		t.Fatalf("e.Observe(...): unexpected error: %v", err)
+	}
+	if !o.ResourceExists || !o.ResourceUpToDate {
+		t.Errorf("e.Observe(...): got %+v, want an existing, up to date schematic", o)
+	}
+}
+
+func TestCreateRecordsSpecHash(t *testing.T) {
+	// A factory on another version may assign IDs that differ from the
+	// locally computed hash.
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(`{"id":"factory-assigned-id"}`)) //nolint:errcheck
+	}))
+	defer srv.Close()
+
+	cr := factorySchematic(nil, "")
+	e := external{service: imagefactory.NewClient(srv.URL)}
+	if _, err := e.Create(context.Background(), cr); err != nil {
+		t.Fatalf("e.Create(...): unexpected error: %v", err)
+	}
+	if cr.Status.AtProvider.ID != "factory-assigned-id" || cr.Status.AtProvider.SpecHash != vanillaID {
+		t.Errorf("e.Create(...): got ID %q and spec hash %q, want the assigned ID and %q", cr.Status.AtProvider.ID, cr.Status.AtProvider.SpecHash, vanillaID)
+	}
+
+	o, err := e.Observe(context.Background(), cr)
+	if err != nil {
+		t.Fatalf("e.Observe(...): unexpected error: %v", err)
+	}
+	if !o.ResourceUpToDate {
+		t.Error("e.Observe(...): want the uploaded schematic to be up to date")
+	}
+}
+
+func TestConnectUsesProviderConfigImageFactory(t *testing.T) {
+	var requested bool
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requested = true
+		w.Write([]byte(`{"id":"` + vanillaID + `"}`)) //nolint:errcheck
+	}))
+	defer srv.Close()
+
+	scheme := runtime.NewScheme()
+	if err := apisv1alpha1.SchemeBuilder.AddToScheme(scheme); err != nil {
+		t.Fatalf("AddToScheme(...): unexpected error: %v", err)
+	}
+	pc := &apisv1alpha1.ProviderConfig{
+		ObjectMeta: metav1.ObjectMeta{Name: "default"},
+		Spec:       apisv1alpha1.ProviderConfigSpec{ImageFactory: &apisv1alpha1.ImageFactory{URL: srv.URL}},
+	}
+
+	cr := factorySchematic(nil, "")
+	cr.SetProviderConfigReference(&xpv1.Reference{Name: "default"})
+
+	c := &connector{
+		kube:         fake.NewClientBuilder().WithScheme(scheme).WithObjects(pc).Build(),
+		usage:        resource.TrackerFn(func(context.Context, resource.Managed) error { return nil }),
+		newServiceFn: imagefactory.NewClient,
+	}
+	e, err := c.Connect(context.Background(), cr)
+	if err != nil {
+		t.Fatalf("c.Connect(...): unexpected error: %v", err)
+	}
+	if _, err := e.Create(context.Background(), cr); err != nil {
+		t.Fatalf("e.Create(...): unexpected error: %v", err)
+	}
+	if !requested {
+		t.Error("e.Create(...): the Image Factory of the ProviderConfig was not called")
+	}
+}
+
+func TestSchematic(t *testing.T) {
+	yamlSchematic := `overlay:
+  name: rpi_generic
+  image: siderolabs/sbc-raspberrypi
+  options:
+    configTxtAppend: dtoverlay=disable-bt
+customization:
+  extraKernelArgs:
+    - net.ifnames=0
+  meta:
+    - key: 0xa
+      value: production
+  systemExtensions:
+    officialExtensions:
+      - siderolabs/gvisor
+      - siderolabs/util-linux-tools
+  secureboot:
+    includeWellKnownCertificates: true
+`
+	typed := v1alpha1.FactorySchematicParameters{
+		SystemExtensions: []string{"siderolabs/gvisor", "siderolabs/util-linux-tools"},
+		ExtraKernelArgs:  []string{"net.ifnames=0"},
+		Meta:             []v1alpha1.SchematicMetaValue{{Key: 10, Value: "production"}},
+		Overlay: &v1alpha1.SchematicOverlay{
+			Name:    "rpi_generic",
+			Image:   "siderolabs/sbc-raspberrypi",
+			Options: &runtime.RawExtension{Raw: []byte(`{"configTxtAppend":"dtoverlay=disable-bt"}`)},
+		},
+		SecureBoot: &v1alpha1.SchematicSecureBoot{IncludeWellKnownCertificates: true},
+	}
+
+	wantID := func(t *testing.T) string {
+		t.Helper()
+		s, err := schematic(factorySchematic(&yamlSchematic, ""))
+		if err != nil {
+			t.Fatalf("schematic(...): unexpected error: %v", err)
+		}
+		id, err := s.ID()
+		if err != nil {
+			t.Fatalf("s.ID(): unexpected error: %v", err)
+		}
+		return id
+	}(t)
+
+	tests := map[string]struct {
+		params  v1alpha1.FactorySchematicParameters
+		wantID  string
+		wantErr string
+	}{
+		"TypedFieldsMatchYAML": {
+			params: typed,
+			wantID: wantID,
+		},
+		"EmptyIsVanilla": {
+			wantID: vanillaID,
+		},
+		"SchematicAndTypedFields": {
+			params:  v1alpha1.FactorySchematicParameters{Schematic: &yamlSchematic, SystemExtensions: []string{"siderolabs/gvisor"}},
+			wantErr: errSchematicExclusive,
+		},
+		"InvalidExtensionName": {
+			params:  v1alpha1.FactorySchematicParameters{SystemExtensions: []string{"siderolab/gvisor"}},
+			wantErr: "invalid official extension name",
+		},
+		"MetaKeyOutOfRange": {
+			params:  v1alpha1.FactorySchematicParameters{Meta: []v1alpha1.SchematicMetaValue{{Key: 256, Value: "x"}}},
+			wantErr: "out of range",
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			cr := &v1alpha1.FactorySchematic{Spec: v1alpha1.FactorySchematicSpec{ForProvider: tc.params}}
+			s, err := schematic(cr)
+			if tc.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+					t.Fatalf("schematic(...): got error %v, want %q", err, tc.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("schematic(...): unexpected error: %v", err)
+			}
+			id, err := s.ID()
+			if err != nil {
+				t.Fatalf("s.ID(): unexpected error: %v", err)
+			}
+			if id != tc.wantID {
+				t.Errorf("schematic(...).ID(): got %q, want %q", id, tc.wantID)
+			}
+		})
+	}
+}
+
+func TestObservePublishesArtifacts(t *testing.T) {
+	cr := factorySchematic(nil, vanillaID)
+	cr.Spec.ForProvider.TalosVersion = ptr.To("1.11.0")
+	cr.Spec.ForProvider.Arch = ptr.To("arm64")
+
+	e := external{service: imagefactory.NewClient("")}
+	got, err := e.Observe(context.Background(), cr)
+	if err != nil {
+		t.Fatalf("e.Observe(...): unexpected error: %v", err)
+	}
+
+	wantArtifacts := &v1alpha1.FactorySchematicArtifacts{
+		TalosVersion:             "1.11.0",
+		InstallerImage:           "factory.talos.dev/installer/" + vanillaID + ":v1.11.0",
+		SecureBootInstallerImage: "factory.talos.dev/installer-secureboot/" + vanillaID + ":v1.11.0",
+		ISO:                      "https://factory.talos.dev/image/" + vanillaID + "/v1.11.0/metal-arm64.iso",
+		DiskImage:                "https://factory.talos.dev/image/" + vanillaID + "/v1.11.0/metal-arm64.raw.xz",
+		Kernel:                   "https://factory.talos.dev/image/" + vanillaID + "/v1.11.0/kernel-arm64",
+		Initramfs:                "https://factory.talos.dev/image/" + vanillaID + "/v1.11.0/initramfs-arm64.xz",
+	}
+	if diff := cmp.Diff(wantArtifacts, cr.Status.AtProvider.Artifacts); diff != "" {
+		t.Errorf("e.Observe(...): -want artifacts, +got artifacts:\n%s", diff)
+	}
+
+	wantDetails := managed.ConnectionDetails{
+		connectionKeySchematicID:              []byte(vanillaID),
+		connectionKeyInstallerImage:           []byte(wantArtifacts.InstallerImage),
+		connectionKeySecureBootInstallerImage: []byte(wantArtifacts.SecureBootInstallerImage),
+		connectionKeyISO:                      []byte(wantArtifacts.ISO),
+		connectionKeyDiskImage:                []byte(wantArtifacts.DiskImage),
+		connectionKeyKernel:                   []byte(wantArtifacts.Kernel),
+		connectionKeyInitramfs:                []byte(wantArtifacts.Initramfs),
+	}
+	if diff := cmp.Diff(wantDetails, got.ConnectionDetails); diff != "" {
+		t.Errorf("e.Observe(...): -want connection details, +got connection details:\n%s", diff)
+	}
+}
+
+func TestOffline(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("offline FactorySchematic called the Image Factory: %s %s", r.Method, r.URL.Path)
+		http.Error(w, "offline", http.StatusServiceUnavailable)
+	}))
+	defer srv.Close()
+
+	e := external{service: imagefactory.NewClient(srv.URL)}
+
+	cr := factorySchematic(nil, "")
+	cr.Spec.ForProvider.Offline = ptr.To(true)
+	o, err := e.Observe(context.Background(), cr)
+	if err != nil {
+		t.Fatalf("e.Observe(...): unexpected error: %v", err)
+	}
+	if !o.ResourceExists || !o.ResourceUpToDate {
+		t.Errorf("e.Observe(...): got %+v, want an existing, up to date schematic", o)
+	}
+	if cr.Status.AtProvider.ID != vanillaID {
+		t.Errorf("e.Observe(...): got ID %q, want %q", cr.Status.AtProvider.ID, vanillaID)
+	}
+	if diff := cmp.Diff(xpv1.Available(), cr.GetCondition(xpv1.TypeReady), test.EquateConditions()); diff != "" {
+		t.Errorf("e.Observe(...): -want Ready condition, +got Ready condition:\n%s", diff)
+	}
+
+	cr = factorySchematic(nil, "")
+	cr.Spec.ForProvider.Offline = ptr.To(true)
+	if _, err := e.Create(context.Background(), cr); err != nil {
+		t.Fatalf("e.Create(...): unexpected error: %v", err)
+	}
+	if cr.Status.AtProvider.ID != vanillaID {
+		t.Errorf("e.Create(...): got ID %q, want %q", cr.Status.AtProvider.ID, vanillaID)
+	}
+}
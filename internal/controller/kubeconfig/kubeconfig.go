@@ -18,13 +18,9 @@ package kubeconfig
 
 import (
 	"context"
-	"crypto/tls"
-	"crypto/x509"
 	"fmt"
 
-	siderox509 "github.com/siderolabs/crypto/x509"
 	talosclient "github.com/siderolabs/talos/pkg/machinery/client"
-	clientconfig "github.com/siderolabs/talos/pkg/machinery/client/config"
 
 	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
 	"github.com/crossplane/crossplane-runtime/pkg/feature"
@@ -47,7 +43,9 @@ import (
 	"github.com/crossplane/crossplane-runtime/pkg/statemetrics"
 
 	"github.com/crossplane-contrib/provider-talos/apis/cluster/v1alpha1"
+	machinev1alpha1 "github.com/crossplane-contrib/provider-talos/apis/machine/v1alpha1"
 	apisv1alpha1 "github.com/crossplane-contrib/provider-talos/apis/v1alpha1"
+	"github.com/crossplane-contrib/provider-talos/internal/clients"
 	"github.com/crossplane-contrib/provider-talos/internal/features"
 )
 
@@ -74,7 +72,8 @@ var (
 )
 
 // Setup adds a controller that reconciles Kubeconfig managed resources.
-func Setup(mgr ctrl.Manager, o controller.Options) error {
+// Talos API clients are borrowed from the supplied cache.
+func Setup(mgr ctrl.Manager, o controller.Options, talosClients *clients.Cache) error {
 	name := managed.ControllerName(v1alpha1.KubeconfigGroupKind)
 
 	cps := []managed.ConnectionPublisher{managed.NewAPISecretPublisher(mgr.GetClient(), mgr.GetScheme())}
@@ -86,7 +85,8 @@ func Setup(mgr ctrl.Manager, o controller.Options) error {
 		managed.WithExternalConnecter(&connector{
 			kube:         mgr.GetClient(),
 			usage:        resource.NewProviderConfigUsageTracker(mgr.GetClient(), &apisv1alpha1.ProviderConfigUsage{}),
-			newServiceFn: newNoOpService}),
+			newServiceFn: newNoOpService,
+			talosClients: talosClients}),
 		managed.WithLogger(o.Logger.WithValues("controller", name)),
 		managed.WithPollInterval(o.PollInterval),
 		managed.WithRecorder(event.NewAPIRecorder(mgr.GetEventRecorderFor(name))),
@@ -127,6 +127,7 @@ type connector struct {
 	kube         ctrlclient.Client
 	usage        resource.Tracker
 	newServiceFn func(creds []byte) (interface{}, error)
+	talosClients *clients.Cache
 }
 
 // Connect typically produces an ExternalClient by:
@@ -160,7 +161,7 @@ func (c *connector) Connect(ctx context.Context, mg resource.Managed) (managed.E
 		return nil, errors.Wrap(err, errNewClient)
 	}
 
-	return &external{kube: c.kube, service: svc}, nil
+	return &external{kube: c.kube, service: svc, talosClients: c.talosClients}, nil
 }
 
 // An ExternalClient observes, then either creates, updates, or deletes an
@@ -170,6 +171,7 @@ type external struct {
 	// would be something like an AWS SDK client.
 	kube                 ctrlclient.Client
 	service              interface{}
+	talosClients         *clients.Cache
 	retrieveKubeconfigFn func(context.Context, *v1alpha1.Kubeconfig) (string, error)
 }
 
@@ -299,24 +301,25 @@ func (c *external) retrieveKubeconfig(ctx context.Context, cr *v1alpha1.Kubeconf
 		return c.retrieveKubeconfigFn(ctx, cr)
 	}
 
-	return retrieveKubeconfigFromTalos(ctx, cr)
+	return c.retrieveKubeconfigFromTalos(ctx, cr)
 }
 
-func retrieveKubeconfigFromTalos(ctx context.Context, cr *v1alpha1.Kubeconfig) (string, error) {
+func (c *external) retrieveKubeconfigFromTalos(ctx context.Context, cr *v1alpha1.Kubeconfig) (string, error) {
 	// Get client configuration
-	clientConfig := cr.Spec.ForProvider.ClientConfiguration
-	talosConfig, err := buildKubeconfigClientConfig(clientConfig)
+	clientConfig, err := clients.ResolveClientConfiguration(ctx, c.kube, clients.ClientConfigurationSource{
+		ClientConfiguration: (*machinev1alpha1.ClientConfiguration)(cr.Spec.ForProvider.ClientConfiguration),
+		SecretRef:           cr.Spec.ForProvider.ClientConfigurationSecretRef,
+		SecretsRef:          cr.Spec.ForProvider.SecretsRef,
+	})
 	if err != nil {
 		return "", err
 	}
+	if _, err := clients.TalosConfig(clientConfig); err != nil {
+		return "", err
+	}
 
-	endpoint := getKubeconfigEndpoint(cr)
-
-	// Create Talos client
-	talosClient, err := talosclient.New(ctx,
-		talosclient.WithConfig(talosConfig),
-		talosclient.WithEndpoints(endpoint),
-	)
+	// Borrow a Talos client
+	talosClient, err := c.talosClients.Client(ctx, getKubeconfigEndpoints(cr), clientConfig)
 	if err != nil {
 		return "", errors.Wrap(err, "failed to create Talos client")
 	}
@@ -337,43 +340,19 @@ func retrieveKubeconfigFromTalos(ctx context.Context, cr *v1alpha1.Kubeconfig) (
 	return string(kubeconfigBytes), nil
 }
 
-func getKubeconfigEndpoint(cr *v1alpha1.Kubeconfig) string {
+// getKubeconfigEndpoints returns the Talos API endpoints calls for the node
+// are routed through.
+func getKubeconfigEndpoints(cr *v1alpha1.Kubeconfig) []string {
+	if len(cr.Spec.ForProvider.Endpoints) > 0 {
+		return cr.Spec.ForProvider.Endpoints
+	}
+
 	endpoint := cr.Spec.ForProvider.Node + ":50000"
 	if cr.Spec.ForProvider.Endpoint != nil && *cr.Spec.ForProvider.Endpoint != "" {
 		endpoint = *cr.Spec.ForProvider.Endpoint
 	}
 
-	return endpoint
-}
-
-func buildKubeconfigClientConfig(clientConfig v1alpha1.ClientConfiguration) (*clientconfig.Config, error) {
-	if clientConfig.ClientCertificate == "" {
-		return nil, errors.New("clientConfiguration.clientCertificate is required")
-	}
-	if clientConfig.ClientKey == "" {
-		return nil, errors.New("clientConfiguration.clientKey is required")
-	}
-	if clientConfig.CACertificate == "" {
-		return nil, errors.New("clientConfiguration.caCertificate is required")
-	}
-
-	cert, err := tls.X509KeyPair([]byte(clientConfig.ClientCertificate), []byte(clientConfig.ClientKey))
-	if err != nil {
-		return nil, errors.Wrap(err, "failed to create client certificate")
-	}
-	if len(cert.Certificate) == 0 {
-		return nil, errors.New("failed to create client certificate")
-	}
-
-	roots := x509.NewCertPool()
-	if ok := roots.AppendCertsFromPEM([]byte(clientConfig.CACertificate)); !ok {
-		return nil, errors.New("failed to parse CA certificate")
-	}
-
-	return clientconfig.NewConfig("dynamic", nil, []byte(clientConfig.CACertificate), &siderox509.PEMEncodedCertificateAndKey{
-		Crt: []byte(clientConfig.ClientCertificate),
-		Key: []byte(clientConfig.ClientKey),
-	}), nil
+	return []string{endpoint}
 }
 
 func parseKubeconfig(kubeconfigData string) (*v1alpha1.KubernetesClientConfiguration, error) {
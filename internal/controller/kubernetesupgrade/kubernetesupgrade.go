@@ -0,0 +1,708 @@
+/*
+Copyright 2025 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubernetesupgrade
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/blang/semver/v4"
+	"github.com/cosi-project/runtime/pkg/safe"
+	"github.com/siderolabs/talos/pkg/machinery/api/machine"
+	talosclient "github.com/siderolabs/talos/pkg/machinery/client"
+	talosconfig "github.com/siderolabs/talos/pkg/machinery/config"
+	"github.com/siderolabs/talos/pkg/machinery/config/configpatcher"
+	configresource "github.com/siderolabs/talos/pkg/machinery/resources/config"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/connection"
+	"github.com/crossplane/crossplane-runtime/pkg/controller"
+	"github.com/crossplane/crossplane-runtime/pkg/event"
+	"github.com/crossplane/crossplane-runtime/pkg/feature"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/pkg/ratelimiter"
+	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/crossplane/crossplane-runtime/pkg/statemetrics"
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	ctrlclient "sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane-contrib/provider-talos/apis/cluster/v1alpha1"
+	machinev1alpha1 "github.com/crossplane-contrib/provider-talos/apis/machine/v1alpha1"
+	apisv1alpha1 "github.com/crossplane-contrib/provider-talos/apis/v1alpha1"
+	"github.com/crossplane-contrib/provider-talos/internal/clients"
+	"github.com/crossplane-contrib/provider-talos/internal/controller/clusterhealth"
+	"github.com/crossplane-contrib/provider-talos/internal/features"
+)
+
+const (
+	errNotKubernetesUpgrade = "managed resource is not a KubernetesUpgrade custom resource"
+	errTrackPCUsage         = "cannot track ProviderConfig usage"
+	errGetPC                = "cannot get ProviderConfig"
+	errGetCreds             = "cannot get credentials"
+	errNewClient            = "cannot create new Service"
+
+	// stepSettleTime is how long to wait after upgrading a component before
+	// trusting the cluster health checks again, so that the restarted static
+	// pod or kubelet is the one being checked.
+	stepSettleTime = time.Minute
+)
+
+// Kubernetes components upgraded by a KubernetesUpgrade, in rollout order.
+const (
+	ComponentAPIServer         = "kube-apiserver"
+	ComponentControllerManager = "kube-controller-manager"
+	ComponentScheduler         = "kube-scheduler"
+	ComponentProxy             = "kube-proxy"
+	ComponentKubelet           = "kubelet"
+)
+
+var controlPlaneComponents = []string{ComponentAPIServer, ComponentControllerManager, ComponentScheduler, ComponentProxy}
+
+// KubernetesUpgradePhase describes the progress of a Kubernetes upgrade.
+type KubernetesUpgradePhase string
+
+// KubernetesUpgrade phases.
+const (
+	KubernetesUpgradePhasePending   KubernetesUpgradePhase = "Pending"
+	KubernetesUpgradePhaseUpgrading KubernetesUpgradePhase = "Upgrading"
+	KubernetesUpgradePhaseVerifying KubernetesUpgradePhase = "Verifying"
+	KubernetesUpgradePhaseCompleted KubernetesUpgradePhase = "Completed"
+)
+
+// NoOpService does nothing.
+type NoOpService struct{}
+
+var newNoOpService = func(_ []byte) (interface{}, error) { return &NoOpService{}, nil }
+
+// Setup adds a controller that reconciles KubernetesUpgrade managed resources.
+// Talos API clients are borrowed from the supplied cache.
+func Setup(mgr ctrl.Manager, o controller.Options, talosClients *clients.Cache) error {
+	name := managed.ControllerName(v1alpha1.KubernetesUpgradeGroupKind)
+
+	cps := []managed.ConnectionPublisher{managed.NewAPISecretPublisher(mgr.GetClient(), mgr.GetScheme())}
+	if o.Features.Enabled(features.EnableAlphaExternalSecretStores) {
+		cps = append(cps, connection.NewDetailsManager(mgr.GetClient(), apisv1alpha1.StoreConfigGroupVersionKind))
+	}
+
+	opts := []managed.ReconcilerOption{
+		managed.WithExternalConnecter(&connector{kube: mgr.GetClient(), usage: resource.NewProviderConfigUsageTracker(mgr.GetClient(), &apisv1alpha1.ProviderConfigUsage{}), newServiceFn: newNoOpService, talosClients: talosClients}),
+		managed.WithLogger(o.Logger.WithValues("controller", name)),
+		managed.WithPollInterval(o.PollInterval),
+		managed.WithRecorder(event.NewAPIRecorder(mgr.GetEventRecorderFor(name))),
+		managed.WithConnectionPublishers(cps...),
+		managed.WithManagementPolicies(),
+	}
+
+	if o.Features.Enabled(feature.EnableAlphaChangeLogs) {
+		opts = append(opts, managed.WithChangeLogger(o.ChangeLogOptions.ChangeLogger))
+	}
+	if o.MetricOptions != nil {
+		opts = append(opts, managed.WithMetricRecorder(o.MetricOptions.MRMetrics))
+	}
+	if o.MetricOptions != nil && o.MetricOptions.MRStateMetrics != nil {
+		stateMetricsRecorder := statemetrics.NewMRStateRecorder(mgr.GetClient(), o.Logger, o.MetricOptions.MRStateMetrics, &v1alpha1.KubernetesUpgradeList{}, o.MetricOptions.PollStateMetricInterval)
+		if err := mgr.Add(stateMetricsRecorder); err != nil {
+			return errors.Wrap(err, "cannot register MR state metrics recorder for kind v1alpha1.KubernetesUpgradeList")
+		}
+	}
+
+	r := managed.NewReconciler(mgr, resource.ManagedKind(v1alpha1.KubernetesUpgradeGroupVersionKind), opts...)
+
+	return ctrl.NewControllerManagedBy(mgr).
+		Named(name).
+		WithOptions(o.ForControllerRuntime()).
+		WithEventFilter(resource.DesiredStateChanged()).
+		For(&v1alpha1.KubernetesUpgrade{}).
+		Complete(ratelimiter.NewReconciler(name, r, o.GlobalRateLimiter))
+}
+
+type connector struct {
+	kube         ctrlclient.Client
+	usage        resource.Tracker
+	newServiceFn func(creds []byte) (interface{}, error)
+	talosClients *clients.Cache
+}
+
+func (c *connector) Connect(ctx context.Context, mg resource.Managed) (managed.ExternalClient, error) {
+	cr, ok := mg.(*v1alpha1.KubernetesUpgrade)
+	if !ok {
+		return nil, errors.New(errNotKubernetesUpgrade)
+	}
+	if err := c.usage.Track(ctx, mg); err != nil {
+		return nil, errors.Wrap(err, errTrackPCUsage)
+	}
+	pc := &apisv1alpha1.ProviderConfig{}
+	if err := c.kube.Get(ctx, types.NamespacedName{Name: cr.GetProviderConfigReference().Name}, pc); err != nil {
+		return nil, errors.Wrap(err, errGetPC)
+	}
+	cd := pc.Spec.Credentials
+	data, err := resource.CommonCredentialExtractor(ctx, cd.Source, c.kube, cd.CommonCredentialSelectors)
+	if err != nil {
+		return nil, errors.Wrap(err, errGetCreds)
+	}
+	svc, err := c.newServiceFn(data)
+	if err != nil {
+		return nil, errors.Wrap(err, errNewClient)
+	}
+	return &external{kube: c.kube, service: svc, talosClients: c.talosClients}, nil
+}
+
+type external struct {
+	kube    ctrlclient.Client
+	service interface{}
+	// talosClients shares Talos API clients between reconciles.
+	talosClients *clients.Cache
+	// newUpgradeClientFn allows tests to stub the Talos machine config and
+	// health check APIs.
+	newUpgradeClientFn func(context.Context, *v1alpha1.KubernetesUpgrade) (upgradeClient, error)
+}
+
+// upgradeClient is the subset of the Talos API used to roll out a Kubernetes
+// upgrade.
+type upgradeClient interface {
+	MachineConfig(ctx context.Context, node string) (talosconfig.Provider, error)
+	ApplyConfiguration(ctx context.Context, node string, data []byte) error
+	ClusterHealth(ctx context.Context, controlPlaneNodes, workerNodes []string) (bool, string)
+	Close() error
+}
+
+// upgradeStep is a single component on a single node.
+type upgradeStep struct {
+	component string
+	node      string
+	image     string
+	version   string
+}
+
+func (s upgradeStep) String() string {
+	return s.component + "/" + s.node
+}
+
+func (c *external) Observe(ctx context.Context, mg resource.Managed) (managed.ExternalObservation, error) {
+	cr, ok := mg.(*v1alpha1.KubernetesUpgrade)
+	if !ok {
+		return managed.ExternalObservation{}, errors.New(errNotKubernetesUpgrade)
+	}
+
+	// A Kubernetes upgrade cannot be rolled back by deleting the resource.
+	if meta.WasDeleted(cr) {
+		return managed.ExternalObservation{ResourceExists: false}, nil
+	}
+
+	target, err := parseTargetVersion(cr.Spec.ForProvider.TargetVersion)
+	if err != nil {
+		return managed.ExternalObservation{}, err
+	}
+	cr.Status.AtProvider.TargetVersion = target
+
+	talosClient, err := c.newUpgradeClient(ctx, cr)
+	if err != nil {
+		return managed.ExternalObservation{}, errors.Wrap(err, "failed to create Talos client")
+	}
+	defer talosClient.Close() //nolint:errcheck
+
+	steps, err := observeSteps(ctx, talosClient, cr)
+	if err != nil {
+		return managed.ExternalObservation{}, err
+	}
+	setProgress(cr, steps, target)
+
+	next, pending := nextStep(cr, steps, target)
+	if recentlyStepped(cr, time.Now()) {
+		cr.Status.AtProvider.Phase = string(KubernetesUpgradePhaseVerifying)
+		cr.Status.AtProvider.LastMessage = fmt.Sprintf("waiting for %s to settle", cr.Status.AtProvider.LastStep)
+		cr.SetConditions(xpv1.Unavailable())
+		return managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: true}, nil
+	}
+
+	healthy, message := talosClient.ClusterHealth(ctx, cr.Spec.ForProvider.ControlPlaneNodes, cr.Spec.ForProvider.WorkerNodes)
+	cr.Status.AtProvider.LastMessage = message
+
+	if !pending {
+		if !healthy {
+			cr.Status.AtProvider.Phase = string(KubernetesUpgradePhaseVerifying)
+			cr.SetConditions(xpv1.Unavailable())
+			return managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: true}, nil
+		}
+		cr.Status.AtProvider.Phase = string(KubernetesUpgradePhaseCompleted)
+		cr.SetConditions(xpv1.Available())
+		return managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: true}, nil
+	}
+
+	cr.SetConditions(xpv1.Unavailable())
+	if !healthy {
+		// Never roll the next node onto an unhealthy cluster.
+		if cr.Status.AtProvider.LastStepTime == nil {
+			cr.Status.AtProvider.Phase = string(KubernetesUpgradePhasePending)
+		} else {
+			cr.Status.AtProvider.Phase = string(KubernetesUpgradePhaseVerifying)
+		}
+		return managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: true}, nil
+	}
+
+	if cr.Status.AtProvider.LastStepTime == nil {
+		cr.Status.AtProvider.Phase = string(KubernetesUpgradePhasePending)
+	} else {
+		cr.Status.AtProvider.Phase = string(KubernetesUpgradePhaseUpgrading)
+	}
+
+	return managed.ExternalObservation{
+		ResourceExists:   true,
+		ResourceUpToDate: false,
+		Diff:             fmt.Sprintf("%s on node %s is %s, target %s", next.component, next.node, next.version, target),
+	}, nil
+}
+
+func (c *external) Create(ctx context.Context, mg resource.Managed) (managed.ExternalCreation, error) {
+	cr, ok := mg.(*v1alpha1.KubernetesUpgrade)
+	if !ok {
+		return managed.ExternalCreation{}, errors.New(errNotKubernetesUpgrade)
+	}
+
+	if err := c.upgradeNextStep(ctx, cr); err != nil {
+		return managed.ExternalCreation{}, err
+	}
+
+	return managed.ExternalCreation{ConnectionDetails: managed.ConnectionDetails{}}, nil
+}
+
+func (c *external) Update(ctx context.Context, mg resource.Managed) (managed.ExternalUpdate, error) {
+	cr, ok := mg.(*v1alpha1.KubernetesUpgrade)
+	if !ok {
+		return managed.ExternalUpdate{}, errors.New(errNotKubernetesUpgrade)
+	}
+
+	if err := c.upgradeNextStep(ctx, cr); err != nil {
+		return managed.ExternalUpdate{}, err
+	}
+
+	return managed.ExternalUpdate{ConnectionDetails: managed.ConnectionDetails{}}, nil
+}
+
+func (c *external) Delete(ctx context.Context, mg resource.Managed) (managed.ExternalDelete, error) {
+	cr, ok := mg.(*v1alpha1.KubernetesUpgrade)
+	if !ok {
+		return managed.ExternalDelete{}, errors.New(errNotKubernetesUpgrade)
+	}
+
+	fmt.Printf("Deleting KubernetesUpgrade %s leaves the cluster on its current Kubernetes version\n", cr.Name)
+
+	return managed.ExternalDelete{}, nil
+}
+
+func (c *external) Disconnect(ctx context.Context) error { return nil }
+
+// upgradeNextStep upgrades the next component or kubelet that is not yet on
+// the target version. Only one node is changed per call; Observe gates the
+// following step on cluster health.
+func (c *external) upgradeNextStep(ctx context.Context, cr *v1alpha1.KubernetesUpgrade) error {
+	target, err := parseTargetVersion(cr.Spec.ForProvider.TargetVersion)
+	if err != nil {
+		return err
+	}
+
+	talosClient, err := c.newUpgradeClient(ctx, cr)
+	if err != nil {
+		return errors.Wrap(err, "failed to create Talos client")
+	}
+	defer talosClient.Close() //nolint:errcheck
+
+	steps, err := observeSteps(ctx, talosClient, cr)
+	if err != nil {
+		return err
+	}
+	step, pending := nextStep(cr, steps, target)
+	if !pending {
+		return nil
+	}
+
+	fmt.Printf("Upgrading %s on node %s from %s to %s\n", step.component, step.node, step.version, target)
+
+	cfg, err := talosClient.MachineConfig(ctx, step.node)
+	if err != nil {
+		return errors.Wrapf(err, "failed to read machine configuration from node %s", step.node)
+	}
+	data, err := patchComponentImage(cfg, step, target)
+	if err != nil {
+		return err
+	}
+	if err := c.recordImage(ctx, cr, step, upgradedImage(step, target)); err != nil {
+		return err
+	}
+	if err := talosClient.ApplyConfiguration(ctx, step.node, data); err != nil {
+		return errors.Wrapf(err, "failed to upgrade %s on node %s", step.component, step.node)
+	}
+
+	now := metav1.Now()
+	cr.Status.AtProvider.Phase = string(KubernetesUpgradePhaseUpgrading)
+	cr.Status.AtProvider.LastStep = step.String()
+	cr.Status.AtProvider.LastStepTime = &now
+	cr.Status.AtProvider.LastMessage = fmt.Sprintf("upgraded %s on node %s to %s", step.component, step.node, target)
+	cr.SetConditions(xpv1.Unavailable())
+
+	return nil
+}
+
+// observeSteps reads the machine configuration of every node and returns the
+// configured image of each component in rollout order: each control-plane
+// component across all control-plane nodes, then every kubelet.
+func observeSteps(ctx context.Context, talosClient upgradeClient, cr *v1alpha1.KubernetesUpgrade) ([]upgradeStep, error) {
+	params := cr.Spec.ForProvider
+	configs := map[string]talosconfig.Provider{}
+	for _, node := range allNodes(cr) {
+		if _, ok := configs[node]; ok {
+			continue
+		}
+		cfg, err := talosClient.MachineConfig(ctx, node)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to read machine configuration from node %s", node)
+		}
+		configs[node] = cfg
+	}
+
+	steps := make([]upgradeStep, 0, len(controlPlaneComponents)*len(params.ControlPlaneNodes)+len(configs))
+	for _, component := range controlPlaneComponents {
+		for _, node := range params.ControlPlaneNodes {
+			steps = append(steps, newUpgradeStep(component, node, componentImage(configs[node], component)))
+		}
+	}
+	for _, node := range allNodes(cr) {
+		steps = append(steps, newUpgradeStep(ComponentKubelet, node, componentImage(configs[node], ComponentKubelet)))
+	}
+
+	return steps, nil
+}
+
+func newUpgradeStep(component, node, image string) upgradeStep {
+	version := imageTag(image)
+	if version != "" {
+		version = normalizeVersion(version)
+	}
+
+	return upgradeStep{component: component, node: node, image: image, version: version}
+}
+
+func componentImage(cfg talosconfig.Provider, component string) string {
+	switch component {
+	case ComponentAPIServer:
+		return cfg.Cluster().APIServer().Image()
+	case ComponentControllerManager:
+		return cfg.Cluster().ControllerManager().Image()
+	case ComponentScheduler:
+		return cfg.Cluster().Scheduler().Image()
+	case ComponentProxy:
+		return cfg.Cluster().Proxy().Image()
+	default:
+		return cfg.Machine().Kubelet().Image()
+	}
+}
+
+// nextStep returns the first step not yet on the target version. Kubelets are
+// skipped when spec.forProvider.skipKubeletUpgrade is set.
+func nextStep(cr *v1alpha1.KubernetesUpgrade, steps []upgradeStep, target string) (upgradeStep, bool) {
+	skipKubelet := cr.Spec.ForProvider.SkipKubeletUpgrade != nil && *cr.Spec.ForProvider.SkipKubeletUpgrade
+	for _, step := range steps {
+		if step.component == ComponentKubelet && skipKubelet {
+			continue
+		}
+		if step.version != target {
+			return step, true
+		}
+	}
+
+	return upgradeStep{}, false
+}
+
+// setProgress records per-component and per-node progress and the oldest
+// observed version.
+func setProgress(cr *v1alpha1.KubernetesUpgrade, steps []upgradeStep, target string) {
+	components := []v1alpha1.KubernetesComponentStatus{}
+	nodes := []v1alpha1.KubernetesComponentStatus{}
+	current := ""
+	for _, step := range steps {
+		status := v1alpha1.KubernetesComponentStatus{
+			Component: step.component,
+			Node:      step.node,
+			Version:   step.version,
+			Upgraded:  step.version == target,
+		}
+		if step.component == ComponentKubelet {
+			nodes = append(nodes, status)
+		} else {
+			components = append(components, status)
+		}
+		if current == "" || versionLess(step.version, current) {
+			current = step.version
+		}
+	}
+
+	cr.Status.AtProvider.Components = components
+	cr.Status.AtProvider.Nodes = nodes
+	cr.Status.AtProvider.CurrentVersion = current
+}
+
+func recentlyStepped(cr *v1alpha1.KubernetesUpgrade, now time.Time) bool {
+	stepTime := cr.Status.AtProvider.LastStepTime
+	return stepTime != nil && now.Sub(stepTime.Time) < stepSettleTime
+}
+
+// patchComponentImage returns the node's machine configuration with the image
+// of the step's component switched to the target version. The image
+// repository is kept so that mirrors and custom registries are preserved.
+func patchComponentImage(cfg talosconfig.Provider, step upgradeStep, target string) ([]byte, error) {
+	image := upgradedImage(step, target)
+
+	var patch string
+	switch step.component {
+	case ComponentAPIServer:
+		patch = fmt.Sprintf("cluster:\n  apiServer:\n    image: %s\n", image)
+	case ComponentControllerManager:
+		patch = fmt.Sprintf("cluster:\n  controllerManager:\n    image: %s\n", image)
+	case ComponentScheduler:
+		patch = fmt.Sprintf("cluster:\n  scheduler:\n    image: %s\n", image)
+	case ComponentProxy:
+		patch = fmt.Sprintf("cluster:\n  proxy:\n    image: %s\n", image)
+	default:
+		patch = fmt.Sprintf("machine:\n  kubelet:\n    image: %s\n", image)
+	}
+
+	p, err := configpatcher.LoadPatch([]byte(patch))
+	if err != nil {
+		return nil, errors.Wrapf(err, "cannot load %s image patch", step.component)
+	}
+	patched, err := configpatcher.Apply(configpatcher.WithConfig(cfg), []configpatcher.Patch{p})
+	if err != nil {
+		return nil, errors.Wrapf(err, "cannot patch %s image", step.component)
+	}
+	data, err := patched.Bytes()
+	if err != nil {
+		return nil, errors.Wrap(err, "cannot encode patched machine configuration")
+	}
+
+	return data, nil
+}
+
+// upgradedImage returns the image of the step's component on the target
+// version.
+func upgradedImage(step upgradeStep, target string) string {
+	return imageRepository(step.image) + ":" + target
+}
+
+// recordImage records the image a step rolls out in
+// spec.forProvider.kubernetesImages of the ConfigurationApply that manages
+// the step's node, so that reapplying the node's configuration keeps the
+// upgrade. It is a no-op when spec.forProvider.configurationApplySelector is
+// unset.
+func (c *external) recordImage(ctx context.Context, cr *v1alpha1.KubernetesUpgrade, step upgradeStep, image string) error {
+	if cr.Spec.ForProvider.ConfigurationApplySelector == nil {
+		return nil
+	}
+
+	apply, err := c.configurationApply(ctx, cr, step.node)
+	if err != nil {
+		return err
+	}
+
+	patched := apply.DeepCopy()
+	images := patched.Spec.ForProvider.KubernetesImages
+	if images == nil {
+		images = &machinev1alpha1.KubernetesImages{}
+		patched.Spec.ForProvider.KubernetesImages = images
+	}
+	switch step.component {
+	case ComponentAPIServer:
+		images.APIServer = &image
+	case ComponentControllerManager:
+		images.ControllerManager = &image
+	case ComponentScheduler:
+		images.Scheduler = &image
+	case ComponentProxy:
+		images.Proxy = &image
+	default:
+		images.Kubelet = &image
+	}
+
+	if err := c.kube.Patch(ctx, patched, ctrlclient.MergeFrom(apply)); err != nil {
+		return errors.Wrapf(err, "cannot record %s image on ConfigurationApply %s", step.component, apply.Name)
+	}
+
+	return nil
+}
+
+// configurationApply returns the ConfigurationApply selected by
+// spec.forProvider.configurationApplySelector whose node or observed hostname
+// is the given node.
+func (c *external) configurationApply(ctx context.Context, cr *v1alpha1.KubernetesUpgrade, node string) (*machinev1alpha1.ConfigurationApply, error) {
+	if c.kube == nil {
+		return nil, errors.New("cannot select ConfigurationApplies without Kubernetes client")
+	}
+
+	selector := cr.Spec.ForProvider.ConfigurationApplySelector
+	applies := &machinev1alpha1.ConfigurationApplyList{}
+	if err := c.kube.List(ctx, applies, ctrlclient.MatchingLabels(selector.MatchLabels)); err != nil {
+		return nil, errors.Wrap(err, "cannot list ConfigurationApplies")
+	}
+
+	var found *machinev1alpha1.ConfigurationApply
+	for i := range applies.Items {
+		apply := &applies.Items[i]
+		if selector.MatchControllerRef != nil && *selector.MatchControllerRef && !meta.HaveSameController(cr, apply) {
+			continue
+		}
+		if apply.Spec.ForProvider.Node != node && apply.Status.AtProvider.Hostname != node {
+			continue
+		}
+		if found != nil {
+			return nil, errors.Errorf("ConfigurationApplies %s and %s both manage node %s", found.Name, apply.Name, node)
+		}
+		found = apply
+	}
+	if found == nil {
+		return nil, errors.Errorf("no ConfigurationApply selected by configurationApplySelector manages node %s", node)
+	}
+
+	return found, nil
+}
+
+func allNodes(cr *v1alpha1.KubernetesUpgrade) []string {
+	nodes := append([]string{}, cr.Spec.ForProvider.ControlPlaneNodes...)
+
+	return append(nodes, cr.Spec.ForProvider.WorkerNodes...)
+}
+
+func parseTargetVersion(version string) (string, error) {
+	if strings.TrimSpace(version) == "" {
+		return "", errors.New("targetVersion is required")
+	}
+	if _, err := semver.ParseTolerant(version); err != nil {
+		return "", errors.Wrapf(err, "invalid targetVersion %q", version)
+	}
+
+	return normalizeVersion(version), nil
+}
+
+// versionLess reports whether a is older than b. Versions that cannot be
+// parsed sort first so that they surface as the current version.
+func versionLess(a, b string) bool {
+	av, aErr := semver.ParseTolerant(a)
+	bv, bErr := semver.ParseTolerant(b)
+	switch {
+	case aErr != nil:
+		return bErr == nil
+	case bErr != nil:
+		return false
+	default:
+		return av.LT(bv)
+	}
+}
+
+// imageTag returns the tag of an image reference, ignoring registry ports and digests.
+func imageTag(image string) string {
+	image = strings.SplitN(image, "@", 2)[0]
+	lastColon := strings.LastIndex(image, ":")
+	if lastColon < 0 || lastColon < strings.LastIndex(image, "/") {
+		return ""
+	}
+
+	return image[lastColon+1:]
+}
+
+// imageRepository returns an image reference without its tag or digest.
+func imageRepository(image string) string {
+	image = strings.SplitN(image, "@", 2)[0]
+	lastColon := strings.LastIndex(image, ":")
+	if lastColon < 0 || lastColon < strings.LastIndex(image, "/") {
+		return image
+	}
+
+	return image[:lastColon]
+}
+
+func normalizeVersion(version string) string {
+	return "v" + strings.TrimPrefix(strings.TrimSpace(version), "v")
+}
+
+func (c *external) newUpgradeClient(ctx context.Context, cr *v1alpha1.KubernetesUpgrade) (upgradeClient, error) {
+	if c.newUpgradeClientFn != nil {
+		return c.newUpgradeClientFn(ctx, cr)
+	}
+
+	if len(cr.Spec.ForProvider.Endpoints) == 0 {
+		return nil, errors.New("endpoints is required")
+	}
+	if len(cr.Spec.ForProvider.ControlPlaneNodes) == 0 {
+		return nil, errors.New("controlPlaneNodes is required")
+	}
+	clientConfig, err := clients.ResolveClientConfiguration(ctx, c.kube, clients.ClientConfigurationSource{
+		ClientConfiguration: (*machinev1alpha1.ClientConfiguration)(cr.Spec.ForProvider.ClientConfiguration),
+		SecretRef:           cr.Spec.ForProvider.ClientConfigurationSecretRef,
+		SecretsRef:          cr.Spec.ForProvider.SecretsRef,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if _, err := clients.TalosConfig(clientConfig); err != nil {
+		return nil, err
+	}
+
+	client, err := c.talosClients.Client(ctx, cr.Spec.ForProvider.Endpoints, clientConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	return &talosUpgradeClient{client: client}, nil
+}
+
+// talosUpgradeClient implements upgradeClient on top of the Talos API client.
+type talosUpgradeClient struct {
+	client *clients.Client
+}
+
+func (t *talosUpgradeClient) MachineConfig(ctx context.Context, node string) (talosconfig.Provider, error) {
+	mc, err := safe.StateGetByID[*configresource.MachineConfig](talosclient.WithNode(ctx, node), t.client.COSI, configresource.ActiveID)
+	if err != nil {
+		return nil, err
+	}
+
+	return mc.Provider(), nil
+}
+
+func (t *talosUpgradeClient) ApplyConfiguration(ctx context.Context, node string, data []byte) error {
+	_, err := t.client.ApplyConfiguration(talosclient.WithNode(ctx, node), &machine.ApplyConfigurationRequest{
+		Data: data,
+		Mode: machine.ApplyConfigurationRequest_NO_REBOOT,
+	})
+
+	return err
+}
+
+func (t *talosUpgradeClient) ClusterHealth(ctx context.Context, controlPlaneNodes, workerNodes []string) (bool, string) {
+	checkCtx, cancel := context.WithTimeout(ctx, 15*time.Second)
+	defer cancel()
+
+	return clusterhealth.CheckFullClusterHealth(checkCtx, t.client.Client, controlPlaneNodes, workerNodes)
+}
+
+func (t *talosUpgradeClient) Close() error {
+	return t.client.Close()
+}
@@ -0,0 +1,533 @@
+/*
+Copyright 2025 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubernetesupgrade
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	talosconfig "github.com/siderolabs/talos/pkg/machinery/config"
+	"github.com/siderolabs/talos/pkg/machinery/config/configloader"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/crossplane-contrib/provider-talos/apis/cluster/v1alpha1"
+	machinev1alpha1 "github.com/crossplane-contrib/provider-talos/apis/machine/v1alpha1"
+)
+
+// Unlike many Kubernetes projects Crossplane does not use third party testing
+// libraries, per the common Go test review comments. Crossplane encourages the
+// use of table driven unit tests. The tests of the crossplane-runtime project
+// are representative of the testing style Crossplane encourages.
+//
+// https://github.com/golang/go/wiki/TestComments
+// https://github.com/crossplane/crossplane/blob/master/CONTRIBUTING.md#contributing-code
+
+const (
+	controlPlaneNode = "192.168.1.100"
+	workerNode       = "192.168.1.110"
+)
+
+type fakeUpgradeClient struct {
+	configs       map[string][]byte
+	healthy       bool
+	healthMessage string
+	applyErr      error
+	applied       []string
+}
+
+func (f *fakeUpgradeClient) MachineConfig(_ context.Context, node string) (talosconfig.Provider, error) {
+	data, ok := f.configs[node]
+	if !ok {
+		return nil, errors.Errorf("node %s unreachable", node)
+	}
+
+	return configloader.NewFromBytes(data)
+}
+
+func (f *fakeUpgradeClient) ApplyConfiguration(_ context.Context, node string, data []byte) error {
+	if f.applyErr != nil {
+		return f.applyErr
+	}
+	f.applied = append(f.applied, node)
+	f.configs[node] = data
+
+	return nil
+}
+
+func (f *fakeUpgradeClient) ClusterHealth(context.Context, []string, []string) (bool, string) {
+	return f.healthy, f.healthMessage
+}
+
+func (f *fakeUpgradeClient) Close() error {
+	return nil
+}
+
+func testMachineConfig(machineType, controlPlane, kubelet string) []byte {
+	return []byte(fmt.Sprintf(`version: v1alpha1
+machine:
+  type: %s
+  kubelet:
+    image: ghcr.io/siderolabs/kubelet:%s
+cluster:
+  apiServer:
+    image: registry.k8s.io/kube-apiserver:%s
+  controllerManager:
+    image: registry.k8s.io/kube-controller-manager:%s
+  scheduler:
+    image: registry.k8s.io/kube-scheduler:%s
+  proxy:
+    image: registry.k8s.io/kube-proxy:%s
+`, machineType, kubelet, controlPlane, controlPlane, controlPlane, controlPlane))
+}
+
+func testKubernetesUpgrade(target string) *v1alpha1.KubernetesUpgrade {
+	return &v1alpha1.KubernetesUpgrade{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-kubernetes-upgrade"},
+		Spec: v1alpha1.KubernetesUpgradeSpec{
+			ForProvider: v1alpha1.KubernetesUpgradeParameters{
+				Endpoints:         []string{controlPlaneNode},
+				ControlPlaneNodes: []string{controlPlaneNode},
+				WorkerNodes:       []string{workerNode},
+				TargetVersion:     target,
+				ClientConfiguration: &v1alpha1.ClientConfiguration{
+					CACertificate:     "ca",
+					ClientCertificate: "crt",
+					ClientKey:         "key",
+				},
+			},
+		},
+	}
+}
+
+func newFakeUpgradeClient(controlPlane, kubelet string, healthy bool) *fakeUpgradeClient {
+	return &fakeUpgradeClient{
+		configs: map[string][]byte{
+			controlPlaneNode: testMachineConfig("controlplane", controlPlane, kubelet),
+			workerNode:       testMachineConfig("worker", controlPlane, kubelet),
+		},
+		healthy:       healthy,
+		healthMessage: "cluster is healthy",
+	}
+}
+
+func newTestExternal(fc *fakeUpgradeClient) *external {
+	return &external{
+		newUpgradeClientFn: func(context.Context, *v1alpha1.KubernetesUpgrade) (upgradeClient, error) {
+			return fc, nil
+		},
+	}
+}
+
+func TestObserve(t *testing.T) {
+	recent := metav1.NewTime(time.Now().Add(-10 * time.Second))
+	settled := metav1.NewTime(time.Now().Add(-2 * stepSettleTime))
+
+	type want struct {
+		exists   bool
+		upToDate bool
+		phase    KubernetesUpgradePhase
+		current  string
+		err      bool
+	}
+
+	cases := map[string]struct {
+		reason string
+		client *fakeUpgradeClient
+		mg     func() *v1alpha1.KubernetesUpgrade
+		want   want
+	}{
+		"AlreadyAtTarget": {
+			reason: "A healthy cluster already on the target version should be complete.",
+			client: newFakeUpgradeClient("v1.34.0", "v1.34.0", true),
+			mg: func() *v1alpha1.KubernetesUpgrade {
+				return testKubernetesUpgrade("1.34.0")
+			},
+			want: want{exists: true, upToDate: true, phase: KubernetesUpgradePhaseCompleted, current: "v1.34.0"},
+		},
+		"AtTargetButUnhealthy": {
+			reason: "A cluster on the target version is not complete until it is healthy.",
+			client: newFakeUpgradeClient("v1.34.0", "v1.34.0", false),
+			mg: func() *v1alpha1.KubernetesUpgrade {
+				return testKubernetesUpgrade("v1.34.0")
+			},
+			want: want{exists: true, upToDate: true, phase: KubernetesUpgradePhaseVerifying, current: "v1.34.0"},
+		},
+		"NotStarted": {
+			reason: "A healthy cluster on an older version should trigger the first step.",
+			client: newFakeUpgradeClient("v1.33.4", "v1.33.4", true),
+			mg: func() *v1alpha1.KubernetesUpgrade {
+				return testKubernetesUpgrade("v1.34.0")
+			},
+			want: want{exists: true, upToDate: false, phase: KubernetesUpgradePhasePending, current: "v1.33.4"},
+		},
+		"NotStartedUnhealthy": {
+			reason: "An unhealthy cluster should not be upgraded.",
+			client: newFakeUpgradeClient("v1.33.4", "v1.33.4", false),
+			mg: func() *v1alpha1.KubernetesUpgrade {
+				return testKubernetesUpgrade("v1.34.0")
+			},
+			want: want{exists: true, upToDate: true, phase: KubernetesUpgradePhasePending, current: "v1.33.4"},
+		},
+		"SettlingAfterStep": {
+			reason: "A recent step should settle before the next health check.",
+			client: newFakeUpgradeClient("v1.34.0", "v1.33.4", true),
+			mg: func() *v1alpha1.KubernetesUpgrade {
+				cr := testKubernetesUpgrade("v1.34.0")
+				cr.Status.AtProvider.LastStep = "kube-proxy/" + controlPlaneNode
+				cr.Status.AtProvider.LastStepTime = &recent
+				return cr
+			},
+			want: want{exists: true, upToDate: true, phase: KubernetesUpgradePhaseVerifying, current: "v1.33.4"},
+		},
+		"ContinueAfterHealthyStep": {
+			reason: "A settled, healthy step should trigger the next step.",
+			client: newFakeUpgradeClient("v1.34.0", "v1.33.4", true),
+			mg: func() *v1alpha1.KubernetesUpgrade {
+				cr := testKubernetesUpgrade("v1.34.0")
+				cr.Status.AtProvider.LastStepTime = &settled
+				return cr
+			},
+			want: want{exists: true, upToDate: false, phase: KubernetesUpgradePhaseUpgrading, current: "v1.33.4"},
+		},
+		"WaitForHealthAfterStep": {
+			reason: "An unhealthy cluster after a step should hold the rollout.",
+			client: newFakeUpgradeClient("v1.34.0", "v1.33.4", false),
+			mg: func() *v1alpha1.KubernetesUpgrade {
+				cr := testKubernetesUpgrade("v1.34.0")
+				cr.Status.AtProvider.LastStepTime = &settled
+				return cr
+			},
+			want: want{exists: true, upToDate: true, phase: KubernetesUpgradePhaseVerifying, current: "v1.33.4"},
+		},
+		"SkipKubelet": {
+			reason: "Kubelets on an older version should be ignored when skipKubeletUpgrade is set.",
+			client: newFakeUpgradeClient("v1.34.0", "v1.33.4", true),
+			mg: func() *v1alpha1.KubernetesUpgrade {
+				cr := testKubernetesUpgrade("v1.34.0")
+				skip := true
+				cr.Spec.ForProvider.SkipKubeletUpgrade = &skip
+				return cr
+			},
+			want: want{exists: true, upToDate: true, phase: KubernetesUpgradePhaseCompleted, current: "v1.33.4"},
+		},
+		"NodeUnreachable": {
+			reason: "A node whose machine configuration cannot be read should return an error.",
+			client: &fakeUpgradeClient{configs: map[string][]byte{}},
+			mg: func() *v1alpha1.KubernetesUpgrade {
+				return testKubernetesUpgrade("v1.34.0")
+			},
+			want: want{err: true},
+		},
+		"InvalidTarget": {
+			reason: "An invalid target version should return an error.",
+			client: newFakeUpgradeClient("v1.34.0", "v1.34.0", true),
+			mg: func() *v1alpha1.KubernetesUpgrade {
+				return testKubernetesUpgrade("latest")
+			},
+			want: want{err: true},
+		},
+		"Deleted": {
+			reason: "A deleted KubernetesUpgrade should not touch the cluster.",
+			client: &fakeUpgradeClient{configs: map[string][]byte{}},
+			mg: func() *v1alpha1.KubernetesUpgrade {
+				cr := testKubernetesUpgrade("v1.34.0")
+				meta.SetExternalName(cr, cr.Name)
+				now := metav1.Now()
+				cr.SetDeletionTimestamp(&now)
+				return cr
+			},
+			want: want{exists: false},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			cr := tc.mg()
+			got, err := newTestExternal(tc.client).Observe(context.Background(), cr)
+			if tc.want.err {
+				if err == nil {
+					t.Fatalf("\n%s\ne.Observe(...): expected error", tc.reason)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("\n%s\ne.Observe(...): unexpected error: %v", tc.reason, err)
+			}
+			if got.ResourceExists != tc.want.exists || got.ResourceUpToDate != tc.want.upToDate {
+				t.Errorf("\n%s\ne.Observe(...): got exists=%v upToDate=%v, want exists=%v upToDate=%v", tc.reason, got.ResourceExists, got.ResourceUpToDate, tc.want.exists, tc.want.upToDate)
+			}
+			if diff := cmp.Diff(string(tc.want.phase), cr.Status.AtProvider.Phase); diff != "" {
+				t.Errorf("\n%s\ne.Observe(...): -want phase, +got phase:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.current, cr.Status.AtProvider.CurrentVersion); diff != "" {
+				t.Errorf("\n%s\ne.Observe(...): -want currentVersion, +got currentVersion:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestObserveReportsProgress(t *testing.T) {
+	fc := newFakeUpgradeClient("v1.34.0", "v1.33.4", true)
+	cr := testKubernetesUpgrade("v1.34.0")
+
+	if _, err := newTestExternal(fc).Observe(context.Background(), cr); err != nil {
+		t.Fatalf("Observe(...): unexpected error: %v", err)
+	}
+
+	wantComponents := []v1alpha1.KubernetesComponentStatus{
+		{Component: ComponentAPIServer, Node: controlPlaneNode, Version: "v1.34.0", Upgraded: true},
+		{Component: ComponentControllerManager, Node: controlPlaneNode, Version: "v1.34.0", Upgraded: true},
+		{Component: ComponentScheduler, Node: controlPlaneNode, Version: "v1.34.0", Upgraded: true},
+		{Component: ComponentProxy, Node: controlPlaneNode, Version: "v1.34.0", Upgraded: true},
+	}
+	if diff := cmp.Diff(wantComponents, cr.Status.AtProvider.Components); diff != "" {
+		t.Errorf("Observe(...): -want components, +got components:\n%s", diff)
+	}
+	wantNodes := []v1alpha1.KubernetesComponentStatus{
+		{Component: ComponentKubelet, Node: controlPlaneNode, Version: "v1.33.4"},
+		{Component: ComponentKubelet, Node: workerNode, Version: "v1.33.4"},
+	}
+	if diff := cmp.Diff(wantNodes, cr.Status.AtProvider.Nodes); diff != "" {
+		t.Errorf("Observe(...): -want nodes, +got nodes:\n%s", diff)
+	}
+	if cond := cr.GetCondition(xpv1.TypeReady); cond.Reason != xpv1.ReasonUnavailable {
+		t.Errorf("expected Unavailable condition, got %q", cond.Reason)
+	}
+}
+
+func TestUpdateRollsOneStepAtATime(t *testing.T) {
+	fc := newFakeUpgradeClient("v1.33.4", "v1.33.4", true)
+	fc.configs["192.168.1.101"] = testMachineConfig("controlplane", "v1.33.4", "v1.33.4")
+	cr := testKubernetesUpgrade("v1.34.0")
+	cr.Spec.ForProvider.ControlPlaneNodes = []string{controlPlaneNode, "192.168.1.101"}
+	e := newTestExternal(fc)
+
+	wantSteps := []string{
+		"kube-apiserver/192.168.1.100",
+		"kube-apiserver/192.168.1.101",
+		"kube-controller-manager/192.168.1.100",
+		"kube-controller-manager/192.168.1.101",
+		"kube-scheduler/192.168.1.100",
+		"kube-scheduler/192.168.1.101",
+		"kube-proxy/192.168.1.100",
+		"kube-proxy/192.168.1.101",
+		"kubelet/192.168.1.100",
+		"kubelet/192.168.1.101",
+		"kubelet/192.168.1.110",
+	}
+	for i, want := range wantSteps {
+		if _, err := e.Update(context.Background(), cr); err != nil {
+			t.Fatalf("Update(...) step %d: unexpected error: %v", i, err)
+		}
+		if cr.Status.AtProvider.LastStep != want {
+			t.Errorf("Update(...) step %d: want lastStep %s, got %s", i, want, cr.Status.AtProvider.LastStep)
+		}
+		if len(fc.applied) != i+1 {
+			t.Fatalf("Update(...) step %d: want %d applies, got %d", i, i+1, len(fc.applied))
+		}
+	}
+
+	// Once every component is on the target version there is nothing to do.
+	if _, err := e.Update(context.Background(), cr); err != nil {
+		t.Fatalf("Update(...): unexpected error: %v", err)
+	}
+	if len(fc.applied) != len(wantSteps) {
+		t.Errorf("Update(...): want no further applies, got %d", len(fc.applied)-len(wantSteps))
+	}
+
+	for node, data := range fc.configs {
+		cfg, err := configloader.NewFromBytes(data)
+		if err != nil {
+			t.Fatalf("NewFromBytes(...): %v", err)
+		}
+		if got := cfg.Machine().Kubelet().Image(); got != "ghcr.io/siderolabs/kubelet:v1.34.0" {
+			t.Errorf("node %s: unexpected kubelet image %s", node, got)
+		}
+		if node == workerNode {
+			continue
+		}
+		if got := cfg.Cluster().Proxy().Image(); got != "registry.k8s.io/kube-proxy:v1.34.0" {
+			t.Errorf("node %s: unexpected kube-proxy image %s", node, got)
+		}
+	}
+}
+
+func TestUpdateReturnsApplyError(t *testing.T) {
+	fc := newFakeUpgradeClient("v1.33.4", "v1.33.4", true)
+	fc.applyErr = errors.New("apply rejected")
+	cr := testKubernetesUpgrade("v1.34.0")
+
+	if _, err := newTestExternal(fc).Update(context.Background(), cr); err == nil {
+		t.Fatal("Update(...): expected error")
+	}
+	if cr.Status.AtProvider.LastStepTime != nil {
+		t.Error("expected lastStepTime to remain unset")
+	}
+}
+
+func TestPatchComponentImageKeepsRepository(t *testing.T) {
+	cfg, err := configloader.NewFromBytes([]byte(`version: v1alpha1
+machine:
+  type: controlplane
+cluster:
+  apiServer:
+    image: mirror.local:5000/kube-apiserver:v1.33.4
+`))
+	if err != nil {
+		t.Fatalf("NewFromBytes(...): %v", err)
+	}
+
+	step := newUpgradeStep(ComponentAPIServer, controlPlaneNode, componentImage(cfg, ComponentAPIServer))
+	data, err := patchComponentImage(cfg, step, "v1.34.0")
+	if err != nil {
+		t.Fatalf("patchComponentImage(...): %v", err)
+	}
+	patched, err := configloader.NewFromBytes(data)
+	if err != nil {
+		t.Fatalf("NewFromBytes(...): %v", err)
+	}
+	if got := patched.Cluster().APIServer().Image(); got != "mirror.local:5000/kube-apiserver:v1.34.0" {
+		t.Errorf("patchComponentImage(...): unexpected image %s", got)
+	}
+}
+
+func TestUpdateRecordsImage(t *testing.T) {
+	configurationApply := func(name, node, hostname string, labels map[string]string) *machinev1alpha1.ConfigurationApply {
+		return &machinev1alpha1.ConfigurationApply{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Labels: labels},
+			Spec: machinev1alpha1.ConfigurationApplySpec{
+				ForProvider: machinev1alpha1.ConfigurationApplyParameters{Node: node},
+			},
+			Status: machinev1alpha1.ConfigurationApplyStatus{
+				AtProvider: machinev1alpha1.ConfigurationApplyObservation{Hostname: hostname},
+			},
+		}
+	}
+	cluster := map[string]string{"cluster": "prod"}
+
+	type want struct {
+		err     bool
+		applied int
+		images  map[string]*machinev1alpha1.KubernetesImages
+	}
+
+	cases := map[string]struct {
+		reason   string
+		selector *xpv1.Selector
+		applies  []*machinev1alpha1.ConfigurationApply
+		want     want
+	}{
+		"NoSelector": {
+			reason:  "Without a configurationApplySelector the step should be applied without recording its image.",
+			applies: []*machinev1alpha1.ConfigurationApply{configurationApply("cp-1", controlPlaneNode, "", cluster)},
+			want: want{
+				applied: 1,
+				images:  map[string]*machinev1alpha1.KubernetesImages{"cp-1": nil},
+			},
+		},
+		"MatchedByNode": {
+			reason:   "The image should be recorded on the selected ConfigurationApply of the node.",
+			selector: &xpv1.Selector{MatchLabels: cluster},
+			applies: []*machinev1alpha1.ConfigurationApply{
+				configurationApply("cp-1", controlPlaneNode, "", cluster),
+				configurationApply("other-cluster", controlPlaneNode, "", map[string]string{"cluster": "staging"}),
+			},
+			want: want{
+				applied: 1,
+				images: map[string]*machinev1alpha1.KubernetesImages{
+					"cp-1":          {APIServer: ptr.To("registry.k8s.io/kube-apiserver:v1.34.0")},
+					"other-cluster": nil,
+				},
+			},
+		},
+		"MatchedByHostname": {
+			reason:   "A ConfigurationApply whose observed hostname is the node should be matched.",
+			selector: &xpv1.Selector{MatchLabels: cluster},
+			applies:  []*machinev1alpha1.ConfigurationApply{configurationApply("cp-1", "cp-1.example.com", controlPlaneNode, cluster)},
+			want: want{
+				applied: 1,
+				images: map[string]*machinev1alpha1.KubernetesImages{
+					"cp-1": {APIServer: ptr.To("registry.k8s.io/kube-apiserver:v1.34.0")},
+				},
+			},
+		},
+		"NoConfigurationApply": {
+			reason:   "A node without a selected ConfigurationApply should not be upgraded.",
+			selector: &xpv1.Selector{MatchLabels: cluster},
+			applies:  []*machinev1alpha1.ConfigurationApply{configurationApply("worker-1", workerNode, "", cluster)},
+			want: want{
+				err:    true,
+				images: map[string]*machinev1alpha1.KubernetesImages{"worker-1": nil},
+			},
+		},
+		"AmbiguousConfigurationApply": {
+			reason:   "A node managed by several selected ConfigurationApplies should not be upgraded.",
+			selector: &xpv1.Selector{MatchLabels: cluster},
+			applies: []*machinev1alpha1.ConfigurationApply{
+				configurationApply("cp-1", controlPlaneNode, "", cluster),
+				configurationApply("cp-1-copy", controlPlaneNode, "", cluster),
+			},
+			want: want{
+				err:    true,
+				images: map[string]*machinev1alpha1.KubernetesImages{"cp-1": nil, "cp-1-copy": nil},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			scheme := runtime.NewScheme()
+			if err := machinev1alpha1.SchemeBuilder.AddToScheme(scheme); err != nil {
+				t.Fatalf("machinev1alpha1.AddToScheme(...): %v", err)
+			}
+			builder := fake.NewClientBuilder().WithScheme(scheme)
+			for _, apply := range tc.applies {
+				builder = builder.WithObjects(apply)
+			}
+			kube := builder.Build()
+
+			fc := newFakeUpgradeClient("v1.33.4", "v1.33.4", true)
+			cr := testKubernetesUpgrade("v1.34.0")
+			cr.Spec.ForProvider.ConfigurationApplySelector = tc.selector
+			e := newTestExternal(fc)
+			e.kube = kube
+
+			_, err := e.Update(context.Background(), cr)
+			got := want{err: err != nil, applied: len(fc.applied), images: map[string]*machinev1alpha1.KubernetesImages{}}
+			for _, apply := range tc.applies {
+				recorded := &machinev1alpha1.ConfigurationApply{}
+				if err := kube.Get(context.Background(), types.NamespacedName{Name: apply.Name}, recorded); err != nil {
+					t.Fatalf("kube.Get(...): %v", err)
+				}
+				got.images[apply.Name] = recorded.Spec.ForProvider.KubernetesImages
+			}
+			if diff := cmp.Diff(tc.want, got, cmp.AllowUnexported(want{})); diff != "" {
+				t.Errorf("\n%s\ne.Update(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
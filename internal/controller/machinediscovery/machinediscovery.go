@@ -0,0 +1,427 @@
+/*
+Copyright 2025 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package machinediscovery
+
+import (
+	"context"
+	"net"
+	"net/netip"
+	"strconv"
+	"sync"
+	"time"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	talosclient "github.com/siderolabs/talos/pkg/machinery/client"
+
+	"github.com/crossplane/crossplane-runtime/pkg/feature"
+
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
+	ctrl "sigs.k8s.io/controller-runtime"
+	ctrlclient "sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane/crossplane-runtime/pkg/connection"
+	"github.com/crossplane/crossplane-runtime/pkg/controller"
+	"github.com/crossplane/crossplane-runtime/pkg/event"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/pkg/ratelimiter"
+	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/crossplane/crossplane-runtime/pkg/statemetrics"
+
+	"github.com/crossplane-contrib/provider-talos/apis/machine/v1alpha1"
+	apisv1alpha1 "github.com/crossplane-contrib/provider-talos/apis/v1alpha1"
+	"github.com/crossplane-contrib/provider-talos/internal/clients"
+	"github.com/crossplane-contrib/provider-talos/internal/features"
+)
+
+const (
+	errNotMachineDiscovery = "managed resource is not a MachineDiscovery custom resource"
+	errTrackPCUsage        = "cannot track ProviderConfig usage"
+	errGetPC               = "cannot get ProviderConfig"
+	errTargets             = "cannot determine addresses to scan"
+
+	// defaultPort and defaultConcurrency apply when the API server did not
+	// default the fields.
+	defaultPort        = 50000
+	defaultConcurrency = 32
+
+	// maxAddresses bounds the addresses a single scan probes.
+	maxAddresses = 4096
+
+	// probeTimeout bounds the maintenance-mode check of an address, and
+	// readTimeout the inventory reads of a machine that passed it.
+	probeTimeout = 1 * time.Second
+	readTimeout  = 10 * time.Second
+
+	// scanBudget bounds the probing done by a single Observe, well within the
+	// managed reconciler's timeout. Scans that do not fit continue on the next
+	// poll.
+	scanBudget = 30 * time.Second
+)
+
+// Setup adds a controller that reconciles MachineDiscovery managed resources.
+// Probes use short-lived insecure clients, so that the many unreachable
+// addresses of a scan do not hold cached connections.
+func Setup(mgr ctrl.Manager, o controller.Options) error {
+	name := managed.ControllerName(v1alpha1.MachineDiscoveryGroupKind)
+
+	cps := []managed.ConnectionPublisher{managed.NewAPISecretPublisher(mgr.GetClient(), mgr.GetScheme())}
+	if o.Features.Enabled(features.EnableAlphaExternalSecretStores) {
+		cps = append(cps, connection.NewDetailsManager(mgr.GetClient(), apisv1alpha1.StoreConfigGroupVersionKind))
+	}
+
+	opts := []managed.ReconcilerOption{
+		managed.WithExternalConnecter(&connector{
+			kube:  mgr.GetClient(),
+			usage: resource.NewProviderConfigUsageTracker(mgr.GetClient(), &apisv1alpha1.ProviderConfigUsage{})}),
+		managed.WithLogger(o.Logger.WithValues("controller", name)),
+		managed.WithPollInterval(o.PollInterval),
+		managed.WithRecorder(event.NewAPIRecorder(mgr.GetEventRecorderFor(name))),
+		managed.WithConnectionPublishers(cps...),
+		managed.WithManagementPolicies(),
+	}
+
+	if o.Features.Enabled(feature.EnableAlphaChangeLogs) {
+		opts = append(opts, managed.WithChangeLogger(o.ChangeLogOptions.ChangeLogger))
+	}
+
+	if o.MetricOptions != nil {
+		opts = append(opts, managed.WithMetricRecorder(o.MetricOptions.MRMetrics))
+	}
+
+	if o.MetricOptions != nil && o.MetricOptions.MRStateMetrics != nil {
+		stateMetricsRecorder := statemetrics.NewMRStateRecorder(
+			mgr.GetClient(), o.Logger, o.MetricOptions.MRStateMetrics, &v1alpha1.MachineDiscoveryList{}, o.MetricOptions.PollStateMetricInterval,
+		)
+		if err := mgr.Add(stateMetricsRecorder); err != nil {
+			return errors.Wrap(err, "cannot register MR state metrics recorder for kind v1alpha1.MachineDiscoveryList")
+		}
+	}
+
+	r := managed.NewReconciler(mgr, resource.ManagedKind(v1alpha1.MachineDiscoveryGroupVersionKind), opts...)
+
+	return ctrl.NewControllerManagedBy(mgr).
+		Named(name).
+		WithOptions(o.ForControllerRuntime()).
+		WithEventFilter(resource.DesiredStateChanged()).
+		For(&v1alpha1.MachineDiscovery{}).
+		Complete(ratelimiter.NewReconciler(name, r, o.GlobalRateLimiter))
+}
+
+// A connector is expected to produce an ExternalClient when its Connect method
+// is called.
+type connector struct {
+	kube  ctrlclient.Client
+	usage resource.Tracker
+}
+
+// Connect tracks that the managed resource is using its ProviderConfig and
+// produces an ExternalClient. Probes need no credentials.
+func (c *connector) Connect(ctx context.Context, mg resource.Managed) (managed.ExternalClient, error) {
+	cr, ok := mg.(*v1alpha1.MachineDiscovery)
+	if !ok {
+		return nil, errors.New(errNotMachineDiscovery)
+	}
+
+	if err := c.usage.Track(ctx, mg); err != nil {
+		return nil, errors.Wrap(err, errTrackPCUsage)
+	}
+
+	pc := &apisv1alpha1.ProviderConfig{}
+	if err := c.kube.Get(ctx, types.NamespacedName{Name: cr.GetProviderConfigReference().Name}, pc); err != nil {
+		return nil, errors.Wrap(err, errGetPC)
+	}
+
+	return &external{}, nil
+}
+
+// An ExternalClient observes, then either creates, updates, or deletes an
+// external resource to ensure it reflects the managed resource's desired state.
+type external struct {
+	// probeFn allows tests to stub probing an address.
+	probeFn func(ctx context.Context, address, endpoint string) *v1alpha1.DiscoveredMachine
+	// scanBudget allows tests to shorten the time spent probing per Observe.
+	scanBudget time.Duration
+}
+
+// Observe scans the addresses of a MachineDiscovery and reports the machines
+// in maintenance mode. Each Observe probes addresses for at most the scan
+// budget and records where it stopped, so that a large scan is spread over
+// several polls.
+func (c *external) Observe(ctx context.Context, mg resource.Managed) (managed.ExternalObservation, error) {
+	cr, ok := mg.(*v1alpha1.MachineDiscovery)
+	if !ok {
+		return managed.ExternalObservation{}, errors.New(errNotMachineDiscovery)
+	}
+
+	// A scan leaves nothing behind to delete.
+	if meta.WasDeleted(cr) {
+		return managed.ExternalObservation{ResourceExists: false}, nil
+	}
+
+	addresses, err := targets(cr.Spec.ForProvider)
+	if err != nil {
+		return managed.ExternalObservation{}, errors.Wrap(err, errTargets)
+	}
+
+	offset := cr.Status.AtProvider.ScanOffset
+	if offset < 0 || offset >= len(addresses) {
+		offset = 0
+	}
+	found, next := c.scan(ctx, cr.Spec.ForProvider, addresses, offset)
+	cr.Status.AtProvider.Machines = mergeMachines(cr.Status.AtProvider.Machines, addresses, offset, next, found)
+	cr.Status.AtProvider.ScanOffset = next
+	if next == len(addresses) {
+		now := metav1.Now()
+		cr.Status.AtProvider.ScanOffset = 0
+		cr.Status.AtProvider.ScannedAddresses = len(addresses)
+		cr.Status.AtProvider.LastScanTime = &now
+	}
+
+	cr.SetConditions(xpv1.Available())
+
+	return managed.ExternalObservation{
+		ResourceExists:   true,
+		ResourceUpToDate: true,
+	}, nil
+}
+
+func (c *external) Create(ctx context.Context, mg resource.Managed) (managed.ExternalCreation, error) {
+	_, ok := mg.(*v1alpha1.MachineDiscovery)
+	if !ok {
+		return managed.ExternalCreation{}, errors.New(errNotMachineDiscovery)
+	}
+
+	// Scans run in Observe - Create is a no-op
+	return managed.ExternalCreation{}, nil
+}
+
+func (c *external) Update(ctx context.Context, mg resource.Managed) (managed.ExternalUpdate, error) {
+	_, ok := mg.(*v1alpha1.MachineDiscovery)
+	if !ok {
+		return managed.ExternalUpdate{}, errors.New(errNotMachineDiscovery)
+	}
+
+	return managed.ExternalUpdate{}, nil
+}
+
+func (c *external) Delete(ctx context.Context, mg resource.Managed) (managed.ExternalDelete, error) {
+	_, ok := mg.(*v1alpha1.MachineDiscovery)
+	if !ok {
+		return managed.ExternalDelete{}, errors.New(errNotMachineDiscovery)
+	}
+
+	return managed.ExternalDelete{}, nil
+}
+
+func (c *external) Disconnect(ctx context.Context) error {
+	return nil
+}
+
+// scan probes the addresses from offset on concurrently until the scan budget
+// is spent. It returns the machines found, indexed like the addresses, and the
+// index of the first address whose probe did not complete. Probes cut off by
+// the budget are repeated by the next scan.
+func (c *external) scan(ctx context.Context, p v1alpha1.MachineDiscoveryParameters, addresses []string, offset int) ([]*v1alpha1.DiscoveredMachine, int) {
+	probe := c.probeFn
+	if probe == nil {
+		probe = probeAddress
+	}
+	budget := c.scanBudget
+	if budget <= 0 {
+		budget = scanBudget
+	}
+	port := strconv.Itoa(ptr.Deref(p.Port, defaultPort))
+
+	concurrency := ptr.Deref(p.Concurrency, defaultConcurrency)
+	if concurrency < 1 {
+		concurrency = 1
+	}
+	sem := make(chan struct{}, concurrency)
+
+	scanCtx, cancel := context.WithTimeout(ctx, budget)
+	defer cancel()
+
+	found := make([]*v1alpha1.DiscoveredMachine, len(addresses))
+	probed := make([]bool, len(addresses))
+	var wg sync.WaitGroup
+dispatch:
+	for i := offset; i < len(addresses); i++ {
+		select {
+		case sem <- struct{}{}:
+		case <-scanCtx.Done():
+			break dispatch
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer func() { <-sem }()
+			m := probe(scanCtx, addresses[i], net.JoinHostPort(addresses[i], port))
+			if scanCtx.Err() == nil {
+				found[i], probed[i] = m, true
+			}
+		}()
+	}
+	wg.Wait()
+
+	next := offset
+	for next < len(addresses) && probed[next] {
+		next++
+	}
+
+	return found, next
+}
+
+// mergeMachines returns the known machines with the addresses probed from
+// offset to next replaced by the machines found there, in the order of the
+// addresses. Machines at addresses that are no longer scanned are dropped.
+func mergeMachines(known []v1alpha1.DiscoveredMachine, addresses []string, offset, next int, found []*v1alpha1.DiscoveredMachine) []v1alpha1.DiscoveredMachine {
+	index := make(map[string]int, len(addresses))
+	for i, address := range addresses {
+		index[address] = i
+	}
+
+	byIndex := map[int]v1alpha1.DiscoveredMachine{}
+	for _, m := range known {
+		if i, ok := index[m.Address]; ok && (i < offset || i >= next) {
+			byIndex[i] = m
+		}
+	}
+	for i := offset; i < next; i++ {
+		if found[i] != nil {
+			byIndex[i] = *found[i]
+		}
+	}
+
+	var machines []v1alpha1.DiscoveredMachine
+	for i := range addresses {
+		if m, ok := byIndex[i]; ok {
+			machines = append(machines, m)
+		}
+	}
+
+	return machines
+}
+
+// probeAddress returns the machine answering on endpoint, or nil if there is
+// no machine in maintenance mode. Parts of the inventory that cannot be read
+// are left out.
+func probeAddress(ctx context.Context, address, endpoint string) *v1alpha1.DiscoveredMachine {
+	opts, err := clients.ClientOptions(clients.InsecureClientConfiguration())
+	if err != nil {
+		return nil
+	}
+	talosClient, err := talosclient.New(ctx, append(opts, talosclient.WithEndpoints(endpoint))...)
+	if err != nil {
+		return nil
+	}
+	defer talosClient.Close() //nolint:errcheck
+
+	probeCtx, cancel := context.WithTimeout(ctx, probeTimeout)
+	defer cancel()
+	if !clients.IsMaintenanceMode(probeCtx, talosClient) {
+		return nil
+	}
+
+	readCtx, cancel := context.WithTimeout(ctx, readTimeout)
+	defer cancel()
+
+	m := &v1alpha1.DiscoveredMachine{Address: address}
+	if hw, err := clients.ReadHardware(readCtx, talosClient.COSI); err == nil {
+		m.Hardware = hw
+	}
+	if disks, err := clients.ReadDisks(readCtx, talosClient.COSI); err == nil {
+		m.Disks = disks
+	}
+	if links, err := clients.ReadLinks(readCtx, talosClient.COSI); err == nil {
+		m.Links = links
+		m.MACAddresses = macAddresses(links)
+	}
+
+	return m
+}
+
+func macAddresses(links []v1alpha1.MachineLink) []string {
+	var macs []string
+	for _, l := range links {
+		if l.HardwareAddr != "" {
+			macs = append(macs, l.HardwareAddr)
+		}
+	}
+
+	return macs
+}
+
+// targets returns the addresses to probe: the hosts of each CIDR followed by
+// the explicit addresses, without duplicates.
+func targets(p v1alpha1.MachineDiscoveryParameters) ([]string, error) {
+	seen := map[string]bool{}
+	var addresses []string
+	add := func(address string) error {
+		if seen[address] {
+			return nil
+		}
+		if len(addresses) == maxAddresses {
+			return errors.Errorf("more than %d addresses to scan", maxAddresses)
+		}
+		seen[address] = true
+		addresses = append(addresses, address)
+		return nil
+	}
+
+	for _, cidr := range p.CIDRs {
+		prefix, err := netip.ParsePrefix(cidr)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid CIDR %q", cidr)
+		}
+		prefix = prefix.Masked()
+
+		// Skip the network and broadcast addresses of IPv4 ranges that
+		// have them.
+		first, last := prefix.Addr(), lastAddr(prefix)
+		if prefix.Addr().Is4() && prefix.Bits() < 31 {
+			first, last = first.Next(), last.Prev()
+		}
+		for a := first; a.IsValid() && a.Compare(last) <= 0; a = a.Next() {
+			if err := add(a.String()); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	for _, address := range p.Addresses {
+		if err := add(address); err != nil {
+			return nil, err
+		}
+	}
+
+	return addresses, nil
+}
+
+// lastAddr returns the last address of a masked prefix.
+func lastAddr(prefix netip.Prefix) netip.Addr {
+	b := prefix.Addr().AsSlice()
+	for i := prefix.Bits(); i < len(b)*8; i++ {
+		b[i/8] |= 1 << (7 - uint(i%8))
+	}
+	a, _ := netip.AddrFromSlice(b)
+
+	return a
+}
@@ -0,0 +1,235 @@
+/*
+Copyright 2025 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package machinediscovery
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+
+	"github.com/crossplane-contrib/provider-talos/apis/machine/v1alpha1"
+)
+
+func TestTargets(t *testing.T) {
+	tests := map[string]struct {
+		params  v1alpha1.MachineDiscoveryParameters
+		want    []string
+		wantErr string
+	}{
+		"IPv4RangeSkipsNetworkAndBroadcast": {
+			params: v1alpha1.MachineDiscoveryParameters{CIDRs: []string{"192.168.1.0/30"}},
+			want:   []string{"192.168.1.1", "192.168.1.2"},
+		},
+		"UnmaskedRange": {
+			params: v1alpha1.MachineDiscoveryParameters{CIDRs: []string{"10.0.0.5/31"}},
+			want:   []string{"10.0.0.4", "10.0.0.5"},
+		},
+		"SingleHost": {
+			params: v1alpha1.MachineDiscoveryParameters{CIDRs: []string{"10.0.0.5/32"}},
+			want:   []string{"10.0.0.5"},
+		},
+		"IPv6Range": {
+			params: v1alpha1.MachineDiscoveryParameters{CIDRs: []string{"fd00::/127"}},
+			want:   []string{"fd00::", "fd00::1"},
+		},
+		"AddressesAfterRangesWithoutDuplicates": {
+			params: v1alpha1.MachineDiscoveryParameters{
+				CIDRs:     []string{"192.168.1.0/30"},
+				Addresses: []string{"192.168.1.2", "node-7.example.com"},
+			},
+			want: []string{"192.168.1.1", "192.168.1.2", "node-7.example.com"},
+		},
+		"InvalidCIDR": {
+			params:  v1alpha1.MachineDiscoveryParameters{CIDRs: []string{"192.168.1.0"}},
+			wantErr: "invalid CIDR",
+		},
+		"TooManyAddresses": {
+			params:  v1alpha1.MachineDiscoveryParameters{CIDRs: []string{"10.0.0.0/16"}},
+			wantErr: "more than 4096 addresses",
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, err := targets(tc.params)
+			if tc.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+					t.Fatalf("targets(...): got error %v, want %q", err, tc.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("targets(...): unexpected error: %v", err)
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("targets(...): -want, +got:\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestObserve(t *testing.T) {
+	var mu sync.Mutex
+	var probed []string
+	e := external{probeFn: func(_ context.Context, address, endpoint string) *v1alpha1.DiscoveredMachine {
+		mu.Lock()
+		probed = append(probed, endpoint)
+		mu.Unlock()
+
+		if address != "192.168.1.2" {
+			return nil
+		}
+		return &v1alpha1.DiscoveredMachine{
+			Address:      address,
+			Disks:        []v1alpha1.MachineDisk{{Name: "sda", DevPath: "/dev/sda", Size: 107374182400}},
+			Links:        []v1alpha1.MachineLink{{Name: "eth0", HardwareAddr: "52:54:00:12:34:56"}},
+			MACAddresses: []string{"52:54:00:12:34:56"},
+		}
+	}}
+
+	cr := &v1alpha1.MachineDiscovery{Spec: v1alpha1.MachineDiscoverySpec{ForProvider: v1alpha1.MachineDiscoveryParameters{
+		CIDRs:       []string{"192.168.1.0/29"},
+		Port:        ptr.To(50001),
+		Concurrency: ptr.To(2),
+	}}}
+
+	got, err := e.Observe(context.Background(), cr)
+	if err != nil {
+		t.Fatalf("e.Observe(...): unexpected error: %v", err)
+	}
+	if diff := cmp.Diff(managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: true}, got); diff != "" {
+		t.Errorf("e.Observe(...): -want, +got:\n%s", diff)
+	}
+
+	if len(probed) != 6 || !strings.HasSuffix(probed[0], ":50001") {
+		t.Errorf("e.Observe(...): probed %v, want the 6 hosts of the range on port 50001", probed)
+	}
+	wantMachines := []v1alpha1.DiscoveredMachine{{
+		Address:      "192.168.1.2",
+		Disks:        []v1alpha1.MachineDisk{{Name: "sda", DevPath: "/dev/sda", Size: 107374182400}},
+		Links:        []v1alpha1.MachineLink{{Name: "eth0", HardwareAddr: "52:54:00:12:34:56"}},
+		MACAddresses: []string{"52:54:00:12:34:56"},
+	}}
+	if diff := cmp.Diff(wantMachines, cr.Status.AtProvider.Machines); diff != "" {
+		t.Errorf("e.Observe(...): -want machines, +got machines:\n%s", diff)
+	}
+	if cr.Status.AtProvider.ScannedAddresses != 6 {
+		t.Errorf("e.Observe(...): got %d scanned addresses, want 6", cr.Status.AtProvider.ScannedAddresses)
+	}
+	if cr.Status.AtProvider.LastScanTime == nil {
+		t.Error("e.Observe(...): lastScanTime was not set")
+	}
+	if diff := cmp.Diff(xpv1.Available(), cr.GetCondition(xpv1.TypeReady), test.EquateConditions()); diff != "" {
+		t.Errorf("e.Observe(...): -want Ready condition, +got Ready condition:\n%s", diff)
+	}
+}
+
+func TestObserveDeleted(t *testing.T) {
+	e := external{probeFn: func(context.Context, string, string) *v1alpha1.DiscoveredMachine {
+		t.Error("e.Observe(...): a deleted MachineDiscovery scanned")
+		return nil
+	}}
+
+	cr := &v1alpha1.MachineDiscovery{Spec: v1alpha1.MachineDiscoverySpec{ForProvider: v1alpha1.MachineDiscoveryParameters{
+		Addresses: []string{"192.168.1.2"},
+	}}}
+	cr.SetDeletionTimestamp(&metav1.Time{Time: time.Now()})
+
+	got, err := e.Observe(context.Background(), cr)
+	if err != nil {
+		t.Fatalf("e.Observe(...): unexpected error: %v", err)
+	}
+	if got.ResourceExists {
+		t.Error("e.Observe(...): a deleted MachineDiscovery exists")
+	}
+}
+
+func TestObserveSlowProbes(t *testing.T) {
+	// Every address answers, but each probe takes a fifth of the scan budget,
+	// so a scan spans several Observes.
+	e := external{
+		scanBudget: 100 * time.Millisecond,
+		probeFn: func(ctx context.Context, address, _ string) *v1alpha1.DiscoveredMachine {
+			select {
+			case <-time.After(20 * time.Millisecond):
+				return &v1alpha1.DiscoveredMachine{Address: address}
+			case <-ctx.Done():
+				return nil
+			}
+		},
+	}
+
+	cr := &v1alpha1.MachineDiscovery{Spec: v1alpha1.MachineDiscoverySpec{ForProvider: v1alpha1.MachineDiscoveryParameters{
+		CIDRs:       []string{"192.168.1.0/28"},
+		Concurrency: ptr.To(1),
+	}}}
+
+	observes := 0
+	for cr.Status.AtProvider.LastScanTime == nil {
+		observes++
+		if observes > 14 {
+			t.Fatalf("e.Observe(...): scan did not complete after %d observes", observes-1)
+		}
+
+		start := time.Now()
+		if _, err := e.Observe(context.Background(), cr); err != nil {
+			t.Fatalf("e.Observe(...): unexpected error: %v", err)
+		}
+		if elapsed := time.Since(start); elapsed > time.Second {
+			t.Errorf("e.Observe(...): took %s, want it bounded by the scan budget", elapsed)
+		}
+
+		// Machines found so far are reported while the scan is in progress.
+		if got, want := len(cr.Status.AtProvider.Machines), cr.Status.AtProvider.ScanOffset; cr.Status.AtProvider.LastScanTime == nil && got != want {
+			t.Errorf("e.Observe(...): got %d machines with scanOffset %d, want one per probed address", got, want)
+		}
+	}
+
+	if observes < 2 {
+		t.Errorf("e.Observe(...): scan completed in %d observe, want it spread over several", observes)
+	}
+	if len(cr.Status.AtProvider.Machines) != 14 {
+		t.Errorf("e.Observe(...): got %d machines, want 14", len(cr.Status.AtProvider.Machines))
+	}
+	if cr.Status.AtProvider.ScanOffset != 0 || cr.Status.AtProvider.ScannedAddresses != 14 {
+		t.Errorf("e.Observe(...): got scanOffset %d and %d scanned addresses, want 0 and 14", cr.Status.AtProvider.ScanOffset, cr.Status.AtProvider.ScannedAddresses)
+	}
+}
+
+func TestMergeMachines(t *testing.T) {
+	addresses := []string{"10.0.0.1", "10.0.0.2", "10.0.0.3", "10.0.0.4"}
+	known := []v1alpha1.DiscoveredMachine{{Address: "10.0.0.1"}, {Address: "10.0.0.2"}, {Address: "10.0.0.4"}, {Address: "10.0.0.9"}}
+	found := []*v1alpha1.DiscoveredMachine{nil, nil, {Address: "10.0.0.3"}, nil}
+
+	// 10.0.0.2 and 10.0.0.3 were probed: 10.0.0.2 no longer answers, 10.0.0.3
+	// was found, and 10.0.0.9 is no longer scanned.
+	got := mergeMachines(known, addresses, 1, 3, found)
+	want := []v1alpha1.DiscoveredMachine{{Address: "10.0.0.1"}, {Address: "10.0.0.3"}, {Address: "10.0.0.4"}}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("mergeMachines(...): -want, +got:\n%s", diff)
+	}
+}
@@ -0,0 +1,221 @@
+/*
+Copyright 2025 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package secrets
+
+import (
+	"context"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	clientconfig "github.com/siderolabs/talos/pkg/machinery/client/config"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+
+	"github.com/crossplane-contrib/provider-talos/apis/machine/v1alpha1"
+	"github.com/crossplane-contrib/provider-talos/internal/secrets"
+
+	talossecrets "github.com/siderolabs/talos/pkg/machinery/config/generate/secrets"
+)
+
+// machineSecrets imports the bundle referenced by secretsBundleRef, or
+// generates a new one when it is unset.
+func (c *external) machineSecrets(ctx context.Context, cr *v1alpha1.Secrets) (*GeneratedSecretsResult, error) {
+	if cr.Spec.ForProvider.SecretsBundleRef == nil {
+		generatedSecrets, err := c.generateMachineSecrets(cr.Spec.ForProvider.TalosVersion, clientCertificateTTL(cr))
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to generate machine secrets")
+		}
+		return generatedSecrets, nil
+	}
+
+	importedSecrets, err := c.importMachineSecrets(ctx, cr)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to import machine secrets")
+	}
+	cr.Status.AtProvider.Imported = true
+
+	return importedSecrets, nil
+}
+
+// importMachineSecrets loads an existing secrets bundle, and optionally the
+// client credentials of an existing talosconfig, from Kubernetes Secrets.
+func (c *external) importMachineSecrets(ctx context.Context, cr *v1alpha1.Secrets) (*GeneratedSecretsResult, error) {
+	data, err := c.secretKey(ctx, cr.Spec.ForProvider.SecretsBundleRef)
+	if err != nil {
+		return nil, err
+	}
+
+	bundle, err := secrets.ParseSecretsBundle(data)
+	if err != nil {
+		return nil, err
+	}
+
+	var clientConfiguration *v1alpha1.ClientConfiguration
+	if ref := cr.Spec.ForProvider.TalosconfigRef; ref != nil {
+		data, err := c.secretKey(ctx, ref)
+		if err != nil {
+			return nil, err
+		}
+		clientConfiguration, err = parseTalosconfig(data, bundle)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return secretsResultFromBundle(bundle, clientConfiguration, clientCertificateTTL(cr))
+}
+
+func (c *external) secretKey(ctx context.Context, ref *xpv1.SecretKeySelector) ([]byte, error) {
+	if c.kube == nil {
+		return nil, errors.New("cannot import machine secrets without Kubernetes client")
+	}
+
+	secret := &corev1.Secret{}
+	if err := c.kube.Get(ctx, types.NamespacedName{Namespace: ref.Namespace, Name: ref.Name}, secret); err != nil {
+		return nil, errors.Wrapf(err, "cannot get Secret %s/%s", ref.Namespace, ref.Name)
+	}
+	data, ok := secret.Data[ref.Key]
+	if !ok || len(data) == 0 {
+		return nil, errors.Errorf("Secret %s/%s has no %s key", ref.Namespace, ref.Name, ref.Key)
+	}
+
+	return data, nil
+}
+
+// parseTalosconfig returns the client credentials of the current context of a
+// talosconfig, which must be issued by the bundle's OS CA. Both the base64
+// encoded credentials written by talosctl and the PEM credentials of the
+// talos_config connection detail are accepted.
+func parseTalosconfig(data []byte, bundle *talossecrets.Bundle) (*v1alpha1.ClientConfiguration, error) {
+	cfg, err := clientconfig.FromBytes(data)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to parse talosconfig")
+	}
+	talosContext, ok := cfg.Contexts[cfg.Context]
+	if !ok || talosContext == nil {
+		return nil, errors.Errorf("talosconfig has no context %q", cfg.Context)
+	}
+
+	clientCertificate, err := decodeTalosconfigPEM(talosContext.Crt)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to decode talosconfig client certificate")
+	}
+	clientKey, err := decodeTalosconfigPEM(talosContext.Key)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to decode talosconfig client key")
+	}
+
+	if err := verifyClientCertificate(clientCertificate, bundle.Certs.OS.Crt); err != nil {
+		return nil, err
+	}
+
+	return &v1alpha1.ClientConfiguration{
+		CACertificate:     string(bundle.Certs.OS.Crt),
+		ClientCertificate: string(clientCertificate),
+		ClientKey:         string(clientKey),
+	}, nil
+}
+
+func decodeTalosconfigPEM(value string) ([]byte, error) {
+	if value == "" {
+		return nil, errors.New("value is empty")
+	}
+	if strings.HasPrefix(value, "-----BEGIN") {
+		return []byte(value), nil
+	}
+
+	return base64.StdEncoding.DecodeString(value)
+}
+
+// verifyClientCertificate checks that a client certificate was signed by the
+// OS CA.
+func verifyClientCertificate(clientCertificate, caCertificate []byte) error {
+	cert, err := parsePEMCertificate(clientCertificate)
+	if err != nil {
+		return errors.Wrap(err, "failed to parse talosconfig client certificate")
+	}
+	ca, err := parsePEMCertificate(caCertificate)
+	if err != nil {
+		return errors.Wrap(err, "failed to parse OS CA certificate")
+	}
+
+	if err := cert.CheckSignatureFrom(ca); err != nil {
+		return errors.Wrap(err, "talosconfig client certificate was not issued by the secrets bundle OS CA")
+	}
+
+	return nil
+}
+
+func parsePEMCertificate(data []byte) (*x509.Certificate, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.New("certificate is not PEM encoded")
+	}
+
+	return x509.ParseCertificate(block.Bytes)
+}
+
+// secretsResultFromBundle derives the published secrets from a bundle,
+// issuing a new client certificate unless clientConfiguration is supplied.
+func secretsResultFromBundle(bundle *talossecrets.Bundle, clientConfiguration *v1alpha1.ClientConfiguration, clientCertificateTTL time.Duration) (*GeneratedSecretsResult, error) {
+	bundleJSON, err := json.Marshal(bundle)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to marshal secrets bundle")
+	}
+	machineSecrets, err := secrets.SecretsBundleToMachineSecrets(bundle)
+	if err != nil {
+		return nil, err
+	}
+
+	if clientConfiguration == nil {
+		clientConfiguration, err = secrets.GenerateClientConfiguration(bundle, clientCertificateTTL)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	clusterSecretsJSON, err := marshalClusterSecrets(bundle)
+	if err != nil {
+		return nil, err
+	}
+
+	kubernetesSecretsJSON, err := marshalKubernetesSecrets(bundle)
+	if err != nil {
+		return nil, err
+	}
+
+	trustdInfoJSON, err := marshalTrustdInfo(bundle)
+	if err != nil {
+		return nil, err
+	}
+
+	return &GeneratedSecretsResult{
+		Bundle:              string(bundleJSON),
+		MachineSecrets:      machineSecrets,
+		ClusterSecrets:      clusterSecretsJSON,
+		KubernetesSecrets:   kubernetesSecretsJSON,
+		TrustdInfo:          trustdInfoJSON,
+		ClientConfiguration: clientConfiguration,
+	}, nil
+}
@@ -0,0 +1,289 @@
+/*
+Copyright 2025 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package secrets
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"sort"
+
+	"github.com/pkg/errors"
+	siderox509 "github.com/siderolabs/crypto/x509"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
+
+	"github.com/crossplane-contrib/provider-talos/apis/machine/v1alpha1"
+	"github.com/crossplane-contrib/provider-talos/internal/secrets"
+
+	talossecrets "github.com/siderolabs/talos/pkg/machinery/config/generate/secrets"
+)
+
+// trustOSCAs makes the published client configuration trust the OS CAs
+// returned by secrets.TrustedOSCAs.
+func trustOSCAs(connectionDetails managed.ConnectionDetails, talosconfigOptions secrets.TalosconfigOptions) error {
+	machineSecrets := &v1alpha1.MachineSecrets{}
+	if err := json.Unmarshal(connectionDetails[secrets.ConnectionKeyMachineSecrets], machineSecrets); err != nil {
+		return errors.Wrap(err, "failed to unmarshal machine secrets")
+	}
+	issuing, err := base64.StdEncoding.DecodeString(machineSecrets.Certs.OS.Cert)
+	if err != nil {
+		return errors.Wrap(err, "failed to decode OS CA")
+	}
+	trusted, err := secrets.TrustedOSCAs(issuing, connectionDetails)
+	if err != nil {
+		return err
+	}
+
+	clientConfiguration := secrets.ClientConfigurationFromConnectionDetails(connectionDetails)
+	clientConfiguration.CACertificate = string(trusted)
+
+	return secrets.SetClientConnectionDetails(connectionDetails, clientConfiguration, talosconfigOptions)
+}
+
+// initCARotation records secrets that were just generated or imported as
+// already rotated for the requested generation.
+func initCARotation(cr *v1alpha1.Secrets) {
+	if rotation := cr.Spec.ForProvider.CARotation; rotation != nil && cr.Status.AtProvider.CARotation == nil {
+		now := metav1.Now()
+		cr.Status.AtProvider.CARotation = &v1alpha1.SecretsCARotationStatus{
+			Generation: rotation.Generation,
+			Phase:      v1alpha1.CARotationPhaseCompleted,
+			PhaseTime:  &now,
+		}
+	}
+}
+
+// observeCARotation reports whether a CA rotation should start or advance to
+// its next phase. A phase advances once all dependent resources have rolled
+// out the connection details it published.
+func (c *external) observeCARotation(ctx context.Context, cr *v1alpha1.Secrets, connectionDetails managed.ConnectionDetails) (bool, error) {
+	rotation := cr.Spec.ForProvider.CARotation
+	status := cr.Status.AtProvider.CARotation
+
+	if status == nil || status.Phase == v1alpha1.CARotationPhaseCompleted {
+		return rotation != nil && (status == nil || status.Generation != rotation.Generation), nil
+	}
+
+	pending, err := c.pendingRollout(ctx, cr, connectionDetails)
+	if err != nil {
+		return false, err
+	}
+	status.Pending = pending
+
+	return len(pending) == 0, nil
+}
+
+// pendingRollout returns the dependent resources that have not yet rolled
+// out the current phase of a CA rotation: Configurations referencing the
+// Secrets that were not regenerated from its connection details,
+// ConfigurationApplies of those Configurations whose node does not run the
+// regenerated configuration, and, once the issuing OS CA has been switched,
+// ClientCertificates that were not reissued.
+func (c *external) pendingRollout(ctx context.Context, cr *v1alpha1.Secrets, connectionDetails managed.ConnectionDetails) ([]string, error) {
+	if c.kube == nil {
+		return nil, errors.New("cannot observe CA rotation rollout without Kubernetes client")
+	}
+
+	configurations := &v1alpha1.ConfigurationList{}
+	if err := c.kube.List(ctx, configurations); err != nil {
+		return nil, errors.Wrap(err, "cannot list Configurations")
+	}
+	applies := &v1alpha1.ConfigurationApplyList{}
+	if err := c.kube.List(ctx, applies); err != nil {
+		return nil, errors.Wrap(err, "cannot list ConfigurationApplies")
+	}
+
+	want := secrets.MachineSecretsHash(connectionDetails)
+	pending := []string{}
+	for _, configuration := range configurations.Items {
+		if ref := configuration.Spec.ForProvider.MachineSecretsRef; ref == nil || ref.Name != cr.Name {
+			continue
+		}
+		if configuration.Status.AtProvider.MachineSecretsHash != want {
+			pending = append(pending, v1alpha1.ConfigurationKind+"/"+configuration.Name)
+			continue
+		}
+
+		published := configuration.Spec.WriteConnectionSecretToReference
+		if published == nil {
+			continue
+		}
+		for _, apply := range applies.Items {
+			ref := apply.Spec.ForProvider.MachineConfigurationRef
+			if ref == nil || ref.Name != published.Name || ref.Namespace != published.Namespace {
+				continue
+			}
+			observed := apply.Status.AtProvider
+			if observed.SourceConfigHash != configuration.Status.AtProvider.MachineConfigurationHash ||
+				observed.DesiredConfigHash == "" || observed.DesiredConfigHash != observed.AppliedConfigHash {
+				pending = append(pending, v1alpha1.ConfigurationApplyKind+"/"+apply.Name)
+			}
+		}
+	}
+
+	status := cr.Status.AtProvider.CARotation
+	state, err := secrets.ParseCARotationState(connectionDetails)
+	if err != nil {
+		return nil, err
+	}
+	if status.Phase == v1alpha1.CARotationPhaseSwitchingCA && state.OS != nil {
+		certificates := &v1alpha1.ClientCertificateList{}
+		if err := c.kube.List(ctx, certificates); err != nil {
+			return nil, errors.Wrap(err, "cannot list ClientCertificates")
+		}
+		for _, certificate := range certificates.Items {
+			if certificate.Spec.ForProvider.SecretsRef.Name != cr.Name {
+				continue
+			}
+			if issued := certificate.Status.AtProvider.IssuedTime; issued == nil || issued.Before(status.PhaseTime) {
+				pending = append(pending, v1alpha1.ClientCertificateKind+"/"+certificate.Name)
+			}
+		}
+	}
+
+	sort.Strings(pending)
+	return pending, nil
+}
+
+// advanceCARotation starts a CA rotation or moves it to its next phase, and
+// returns the connection details to publish for that phase.
+func advanceCARotation(cr *v1alpha1.Secrets, connectionDetails managed.ConnectionDetails) (managed.ConnectionDetails, error) {
+	advanced := make(managed.ConnectionDetails, len(connectionDetails)+1)
+	for key, value := range connectionDetails {
+		advanced[key] = value
+	}
+
+	status := cr.Status.AtProvider.CARotation
+	now := metav1.Now()
+	next := &v1alpha1.SecretsCARotationStatus{PhaseTime: &now}
+	if status != nil {
+		next.Generation = status.Generation
+	}
+
+	switch {
+	case status == nil || status.Phase == v1alpha1.CARotationPhaseCompleted:
+		state, err := newCARotationState(cr.Spec.ForProvider.CARotation)
+		if err != nil {
+			return nil, err
+		}
+		if err := secrets.SetCARotationState(advanced, state); err != nil {
+			return nil, err
+		}
+		next.Generation = cr.Spec.ForProvider.CARotation.Generation
+		next.Phase = v1alpha1.CARotationPhaseAcceptingNewCA
+
+	case status.Phase == v1alpha1.CARotationPhaseAcceptingNewCA:
+		if err := switchCAs(cr, advanced); err != nil {
+			return nil, err
+		}
+		cr.Status.AtProvider.ClientCertificateRenewedTime = &now
+		next.Phase = v1alpha1.CARotationPhaseSwitchingCA
+
+	case status.Phase == v1alpha1.CARotationPhaseSwitchingCA:
+		if err := secrets.SetCARotationState(advanced, &secrets.CARotationState{}); err != nil {
+			return nil, err
+		}
+		next.Phase = v1alpha1.CARotationPhaseDroppingOldCA
+
+	case status.Phase == v1alpha1.CARotationPhaseDroppingOldCA:
+		next.Phase = v1alpha1.CARotationPhaseCompleted
+
+	default:
+		return nil, errors.Errorf("unknown CA rotation phase %q", status.Phase)
+	}
+	if err := trustOSCAs(advanced, secrets.SecretsTalosconfigOptions(cr)); err != nil {
+		return nil, err
+	}
+
+	cr.Status.AtProvider.CARotation = next
+	return advanced, nil
+}
+
+// newCARotationState generates the new CAs of a rotation.
+func newCARotationState(rotation *v1alpha1.SecretsCARotation) (*secrets.CARotationState, error) {
+	if rotation == nil {
+		return nil, errors.New("caRotation is not set")
+	}
+
+	state := &secrets.CARotationState{}
+	clock := talossecrets.NewClock()
+	if rotation.OS == nil || *rotation.OS {
+		ca, err := talossecrets.NewTalosCA(clock.Now())
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to generate OS CA")
+		}
+		os := secrets.EncodeCertificateAndKey(siderox509.NewCertificateAndKeyFromCertificateAuthority(ca))
+		state.OS = &os
+	}
+	if rotation.Kubernetes == nil || *rotation.Kubernetes {
+		ca, err := talossecrets.NewKubernetesCA(clock.Now(), nil)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to generate Kubernetes CA")
+		}
+		k8s := secrets.EncodeCertificateAndKey(siderox509.NewCertificateAndKeyFromCertificateAuthority(ca))
+		state.K8s = &k8s
+	}
+	if state.OS == nil && state.K8s == nil {
+		return nil, errors.New("caRotation must rotate at least one of os or kubernetes")
+	}
+
+	return state, nil
+}
+
+// switchCAs makes the accepted new CAs the issuing CAs of the machine
+// secrets, keeps the old CAs accepted, and reissues the client certificate
+// from the OS CA. Clients keep trusting the old OS CA until it is dropped.
+func switchCAs(cr *v1alpha1.Secrets, connectionDetails managed.ConnectionDetails) error {
+	machineSecrets := &v1alpha1.MachineSecrets{}
+	if err := json.Unmarshal(connectionDetails[secrets.ConnectionKeyMachineSecrets], machineSecrets); err != nil {
+		return errors.Wrap(err, "failed to unmarshal machine secrets")
+	}
+	state, err := secrets.ParseCARotationState(connectionDetails)
+	if err != nil {
+		return err
+	}
+
+	old := &secrets.CARotationState{}
+	if state.OS != nil {
+		old.OS = &v1alpha1.MachineSecretsCertificateAndKey{Cert: machineSecrets.Certs.OS.Cert}
+		machineSecrets.Certs.OS = *state.OS
+	}
+	if state.K8s != nil {
+		old.K8s = &v1alpha1.MachineSecretsCertificateAndKey{Cert: machineSecrets.Certs.K8s.Cert}
+		machineSecrets.Certs.K8s = *state.K8s
+	}
+
+	bundle, err := secrets.MachineSecretsToSecretsBundle(machineSecrets)
+	if err != nil {
+		return err
+	}
+	switched, err := secretsResultFromBundle(bundle, nil, clientCertificateTTL(cr))
+	if err != nil {
+		return err
+	}
+	switchedDetails, err := connectionDetailsFromGeneratedSecrets(switched, secrets.SecretsTalosconfigOptions(cr))
+	if err != nil {
+		return err
+	}
+	for key, value := range switchedDetails {
+		connectionDetails[key] = value
+	}
+
+	return secrets.SetCARotationState(connectionDetails, old)
+}
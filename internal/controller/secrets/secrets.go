@@ -17,20 +17,20 @@ limitations under the License.
 package secrets
 
 import (
+	"bytes"
 	"context"
 	"crypto/sha256"
-	"encoding/base64"
+	"crypto/x509"
 	"encoding/hex"
 	"encoding/json"
+	"encoding/pem"
 	"time"
 
 	"github.com/crossplane/crossplane-runtime/pkg/feature"
 
 	"github.com/pkg/errors"
-	siderox509 "github.com/siderolabs/crypto/x509"
 	talosconfig "github.com/siderolabs/talos/pkg/machinery/config"
 	"github.com/siderolabs/talos/pkg/machinery/constants"
-	"github.com/siderolabs/talos/pkg/machinery/role"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
@@ -49,6 +49,7 @@ import (
 	"github.com/crossplane-contrib/provider-talos/apis/machine/v1alpha1"
 	apisv1alpha1 "github.com/crossplane-contrib/provider-talos/apis/v1alpha1"
 	"github.com/crossplane-contrib/provider-talos/internal/features"
+	"github.com/crossplane-contrib/provider-talos/internal/secrets"
 
 	talossecrets "github.com/siderolabs/talos/pkg/machinery/config/generate/secrets"
 )
@@ -61,13 +62,10 @@ const (
 
 	errNewClient = "cannot create new Service"
 
-	connectionKeyMachineSecrets       = "machine_secrets"
-	connectionKeyMachineSecretsBundle = "machine_secrets_bundle"
-	connectionKeyClientConfiguration  = "client_configuration"
-	connectionKeyCACertificate        = "ca_certificate"
-	connectionKeyClientCertificate    = "client_certificate"
-	connectionKeyClientKey            = "client_key"
-	connectionKeyTalosConfig          = "talos_config"
+	// defaultClientCertificateRenewBefore is how long before expiry the client
+	// certificate is reissued when no renewal window is configured. Shorter
+	// certificate lifetimes are renewed after two thirds of their validity.
+	defaultClientCertificateRenewBefore = 30 * 24 * time.Hour
 )
 
 // TalosSecretsService manages Talos machine secrets
@@ -215,23 +213,43 @@ func (c *external) Observe(ctx context.Context, mg resource.Managed) (managed.Ex
 		if cr.Spec.WriteConnectionSecretToReference == nil {
 			return managed.ExternalObservation{}, errors.New("writeConnectionSecretToRef is required to persist generated machine secrets")
 		}
-		generatedSecrets, err := c.generateMachineSecrets(cr.Spec.ForProvider.TalosVersion)
+		generatedSecrets, err := c.machineSecrets(ctx, cr)
 		if err != nil {
-			return managed.ExternalObservation{}, errors.Wrap(err, "failed to generate machine secrets")
+			return managed.ExternalObservation{}, err
 		}
-		connectionDetails, err = connectionDetailsFromGeneratedSecrets(generatedSecrets)
+		connectionDetails, err = connectionDetailsFromGeneratedSecrets(generatedSecrets, secrets.SecretsTalosconfigOptions(cr))
 		if err != nil {
 			return managed.ExternalObservation{}, err
 		}
 		populateStatusMetadata(cr, connectionDetails)
+		initCARotation(cr)
+	}
+
+	renewalTime, err := observeClientCertificate(cr, connectionDetails)
+	if err != nil {
+		return managed.ExternalObservation{}, err
+	}
+
+	rotationDue, err := c.observeCARotation(ctx, cr, connectionDetails)
+	if err != nil {
+		return managed.ExternalObservation{}, err
+	}
+
+	talosConfig, err := secrets.MarshalTalosconfig(secrets.ClientConfigurationFromConnectionDetails(connectionDetails), secrets.SecretsTalosconfigOptions(cr))
+	if err != nil {
+		return managed.ExternalObservation{}, err
 	}
 
 	// Set Ready condition
 	cr.SetConditions(xpv1.Available())
 
+	// Only the client certificate is renewed and the talosconfig rendered
+	// again, and the CAs only change through an explicitly requested
+	// rotation; everything else is immutable.
 	return managed.ExternalObservation{
-		ResourceExists:    true,
-		ResourceUpToDate:  true,
+		ResourceExists: true,
+		ResourceUpToDate: time.Now().Before(renewalTime) && !rotationDue &&
+			bytes.Equal(connectionDetails[secrets.ConnectionKeyTalosConfig], talosConfig),
 		ConnectionDetails: connectionDetails,
 	}, nil
 }
@@ -245,16 +263,21 @@ func (c *external) Create(ctx context.Context, mg resource.Managed) (managed.Ext
 		return managed.ExternalCreation{}, errors.New("writeConnectionSecretToRef is required to persist generated machine secrets")
 	}
 
-	// Generate new machine secrets using Talos SDK
-	generatedSecrets, err := c.generateMachineSecrets(cr.Spec.ForProvider.TalosVersion)
+	// Import the referenced bundle, or generate new machine secrets using
+	// the Talos SDK
+	generatedSecrets, err := c.machineSecrets(ctx, cr)
 	if err != nil {
-		return managed.ExternalCreation{}, errors.Wrap(err, "failed to generate machine secrets")
+		return managed.ExternalCreation{}, err
 	}
-	connectionDetails, err := connectionDetailsFromGeneratedSecrets(generatedSecrets)
+	connectionDetails, err := connectionDetailsFromGeneratedSecrets(generatedSecrets, secrets.SecretsTalosconfigOptions(cr))
 	if err != nil {
 		return managed.ExternalCreation{}, err
 	}
 	populateStatusMetadata(cr, connectionDetails)
+	initCARotation(cr)
+	if _, err := observeClientCertificate(cr, connectionDetails); err != nil {
+		return managed.ExternalCreation{}, err
+	}
 
 	return managed.ExternalCreation{
 		ConnectionDetails: connectionDetails,
@@ -262,14 +285,57 @@ func (c *external) Create(ctx context.Context, mg resource.Managed) (managed.Ext
 }
 
 func (c *external) Update(ctx context.Context, mg resource.Managed) (managed.ExternalUpdate, error) {
-	_, ok := mg.(*v1alpha1.Secrets)
+	cr, ok := mg.(*v1alpha1.Secrets)
 	if !ok {
 		return managed.ExternalUpdate{}, errors.New(errNotSecrets)
 	}
 
-	// MachineSecrets are immutable - no updates allowed
-	// This should not be called since ResourceUpToDate is always true in Observe
-	return managed.ExternalUpdate{}, errors.New("machine secrets are immutable and cannot be updated")
+	// MachineSecrets are immutable; Observe only reports the resource as out
+	// of date when a CA rotation step is due, the client certificate is due
+	// for renewal, or the talosconfig options changed.
+	connectionDetails, err := c.connectionDetailsFromSecret(ctx, cr)
+	if err != nil {
+		return managed.ExternalUpdate{}, err
+	}
+
+	rotationDue, err := c.observeCARotation(ctx, cr, connectionDetails)
+	if err != nil {
+		return managed.ExternalUpdate{}, err
+	}
+	renewalTime, err := observeClientCertificate(cr, connectionDetails)
+	if err != nil {
+		return managed.ExternalUpdate{}, err
+	}
+
+	talosconfigOptions := secrets.SecretsTalosconfigOptions(cr)
+	switch {
+	case rotationDue:
+		connectionDetails, err = advanceCARotation(cr, connectionDetails)
+		if err != nil {
+			return managed.ExternalUpdate{}, err
+		}
+	case !time.Now().Before(renewalTime):
+		connectionDetails, err = renewClientCertificate(connectionDetails, clientCertificateTTL(cr), talosconfigOptions)
+		if err != nil {
+			return managed.ExternalUpdate{}, err
+		}
+		now := metav1.Now()
+		cr.Status.AtProvider.ClientCertificateRenewedTime = &now
+	}
+	talosConfig, err := secrets.MarshalTalosconfig(secrets.ClientConfigurationFromConnectionDetails(connectionDetails), talosconfigOptions)
+	if err != nil {
+		return managed.ExternalUpdate{}, err
+	}
+	connectionDetails[secrets.ConnectionKeyTalosConfig] = talosConfig
+
+	populateStatusMetadata(cr, connectionDetails)
+	if _, err := observeClientCertificate(cr, connectionDetails); err != nil {
+		return managed.ExternalUpdate{}, err
+	}
+
+	return managed.ExternalUpdate{
+		ConnectionDetails: connectionDetails,
+	}, nil
 }
 
 func (c *external) Delete(ctx context.Context, mg resource.Managed) (managed.ExternalDelete, error) {
@@ -301,7 +367,7 @@ type GeneratedSecretsResult struct {
 }
 
 // generateMachineSecrets generates new Talos machine secrets using the Talos SDK
-func (c *external) generateMachineSecrets(talosVersion *string) (*GeneratedSecretsResult, error) {
+func (c *external) generateMachineSecrets(talosVersion *string, clientCertificateTTL time.Duration) (*GeneratedSecretsResult, error) {
 	versionContract, err := parseVersionContract(talosVersion)
 	if err != nil {
 		return nil, err
@@ -313,43 +379,8 @@ func (c *external) generateMachineSecrets(talosVersion *string) (*GeneratedSecre
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to generate secrets bundle")
 	}
-	bundleJSON, err := json.Marshal(secretsBundle)
-	if err != nil {
-		return nil, errors.Wrap(err, "failed to marshal secrets bundle")
-	}
-	machineSecrets, err := SecretsBundleToMachineSecrets(secretsBundle)
-	if err != nil {
-		return nil, err
-	}
 
-	clientConfiguration, err := GenerateClientConfiguration(secretsBundle, constants.TalosAPIDefaultCertificateValidityDuration)
-	if err != nil {
-		return nil, err
-	}
-
-	clusterSecretsJSON, err := marshalClusterSecrets(secretsBundle)
-	if err != nil {
-		return nil, err
-	}
-
-	kubernetesSecretsJSON, err := marshalKubernetesSecrets(secretsBundle)
-	if err != nil {
-		return nil, err
-	}
-
-	trustdInfoJSON, err := marshalTrustdInfo(secretsBundle)
-	if err != nil {
-		return nil, err
-	}
-
-	return &GeneratedSecretsResult{
-		Bundle:              string(bundleJSON),
-		MachineSecrets:      machineSecrets,
-		ClusterSecrets:      clusterSecretsJSON,
-		KubernetesSecrets:   kubernetesSecretsJSON,
-		TrustdInfo:          trustdInfoJSON,
-		ClientConfiguration: clientConfiguration,
-	}, nil
+	return secretsResultFromBundle(secretsBundle, nil, clientCertificateTTL)
 }
 
 func parseVersionContract(talosVersion *string) (*talosconfig.VersionContract, error) {
@@ -410,30 +441,18 @@ func populateStatusMetadata(cr *v1alpha1.Secrets, connectionDetails managed.Conn
 		now := metav1.Now()
 		cr.Status.AtProvider.GeneratedTime = &now
 	}
-	cr.Status.AtProvider.MachineSecretsHash = hashConnectionDetail(connectionDetails, connectionKeyMachineSecretsBundle)
-	cr.Status.AtProvider.ClientConfigurationHash = hashConnectionDetail(connectionDetails, connectionKeyClientConfiguration)
-	cr.Status.AtProvider.TalosConfigHash = hashConnectionDetail(connectionDetails, connectionKeyTalosConfig)
+	cr.Status.AtProvider.MachineSecretsHash = hashConnectionDetail(connectionDetails, secrets.ConnectionKeyMachineSecretsBundle)
+	cr.Status.AtProvider.ClientConfigurationHash = hashConnectionDetail(connectionDetails, secrets.ConnectionKeyClientConfiguration)
+	cr.Status.AtProvider.TalosConfigHash = hashConnectionDetail(connectionDetails, secrets.ConnectionKeyTalosConfig)
 }
 
-func connectionDetailsFromGeneratedSecrets(generatedSecrets *GeneratedSecretsResult) (managed.ConnectionDetails, error) {
+func connectionDetailsFromGeneratedSecrets(generatedSecrets *GeneratedSecretsResult, talosconfigOptions secrets.TalosconfigOptions) (managed.ConnectionDetails, error) {
 	connectionDetails := managed.ConnectionDetails{}
 
 	if generatedSecrets.ClientConfiguration != nil {
-		clientConfigurationJSON, err := marshalBase64ClientConfiguration(generatedSecrets.ClientConfiguration)
-		if err != nil {
-			return nil, err
-		}
-
-		connectionDetails[connectionKeyCACertificate] = []byte(generatedSecrets.ClientConfiguration.CACertificate)
-		connectionDetails[connectionKeyClientCertificate] = []byte(generatedSecrets.ClientConfiguration.ClientCertificate)
-		connectionDetails[connectionKeyClientKey] = []byte(generatedSecrets.ClientConfiguration.ClientKey)
-		connectionDetails[connectionKeyClientConfiguration] = clientConfigurationJSON
-
-		talosConfig, err := marshalTalosConfig(generatedSecrets.ClientConfiguration)
-		if err != nil {
+		if err := secrets.SetClientConnectionDetails(connectionDetails, generatedSecrets.ClientConfiguration, talosconfigOptions); err != nil {
 			return nil, err
 		}
-		connectionDetails[connectionKeyTalosConfig] = talosConfig
 	}
 
 	if generatedSecrets.MachineSecrets != nil {
@@ -441,224 +460,133 @@ func connectionDetailsFromGeneratedSecrets(generatedSecrets *GeneratedSecretsRes
 		if err != nil {
 			return nil, errors.Wrap(err, "failed to marshal structured machine secrets")
 		}
-		connectionDetails[connectionKeyMachineSecrets] = structuredJSON
+		connectionDetails[secrets.ConnectionKeyMachineSecrets] = structuredJSON
 	}
 	if generatedSecrets.Bundle != "" {
-		connectionDetails[connectionKeyMachineSecretsBundle] = []byte(generatedSecrets.Bundle)
+		connectionDetails[secrets.ConnectionKeyMachineSecretsBundle] = []byte(generatedSecrets.Bundle)
 	}
 
 	return connectionDetails, nil
 }
 
-func (c *external) connectionDetailsFromSecret(ctx context.Context, cr *v1alpha1.Secrets) (managed.ConnectionDetails, error) {
-	if cr.Spec.WriteConnectionSecretToReference == nil {
-		return nil, errors.New("generated machine secrets require writeConnectionSecretToRef to reload connection details")
-	}
-	if c.kube == nil {
-		return nil, errors.New("cannot reload generated machine secrets without Kubernetes client")
+// renewClientCertificate reissues the client certificate from the OS CA of
+// the published machine secrets and returns connection details with the
+// client keys replaced. The trusted OS CAs of a CA rotation are kept.
+func renewClientCertificate(connectionDetails managed.ConnectionDetails, ttl time.Duration, talosconfigOptions secrets.TalosconfigOptions) (managed.ConnectionDetails, error) {
+	machineSecrets := &v1alpha1.MachineSecrets{}
+	if err := json.Unmarshal(connectionDetails[secrets.ConnectionKeyMachineSecrets], machineSecrets); err != nil {
+		return nil, errors.Wrap(err, "failed to unmarshal machine secrets")
 	}
 
-	ref := cr.Spec.WriteConnectionSecretToReference
-	secret := &corev1.Secret{}
-	if err := c.kube.Get(ctx, types.NamespacedName{Name: ref.Name, Namespace: ref.Namespace}, secret); err != nil {
-		return nil, errors.Wrapf(err, "cannot get generated machine secrets connection secret %s/%s", ref.Namespace, ref.Name)
+	bundle, err := secrets.MachineSecretsToSecretsBundle(machineSecrets)
+	if err != nil {
+		return nil, err
 	}
 
-	connectionDetails := managed.ConnectionDetails{}
-	for _, key := range requiredConnectionKeys() {
-		value, ok := secret.Data[key]
-		if !ok {
-			return nil, errors.Errorf("generated machine secrets connection secret %s/%s is missing key %q", ref.Namespace, ref.Name, key)
-		}
-		connectionDetails[key] = value
+	clientConfiguration, err := secrets.GenerateClientConfiguration(bundle, ttl)
+	if err != nil {
+		return nil, err
 	}
 
-	return connectionDetails, nil
-}
-
-func requiredConnectionKeys() []string {
-	return []string{
-		connectionKeyMachineSecrets,
-		connectionKeyMachineSecretsBundle,
-		connectionKeyClientConfiguration,
-		connectionKeyCACertificate,
-		connectionKeyClientCertificate,
-		connectionKeyClientKey,
-		connectionKeyTalosConfig,
+	renewed := make(managed.ConnectionDetails, len(connectionDetails))
+	for key, value := range connectionDetails {
+		renewed[key] = value
 	}
-}
-
-func hashConnectionDetail(connectionDetails managed.ConnectionDetails, key string) string {
-	hash := sha256.Sum256(connectionDetails[key])
-	return hex.EncodeToString(hash[:])
-}
-
-func marshalBase64ClientConfiguration(clientConfiguration *v1alpha1.ClientConfiguration) ([]byte, error) {
-	return json.Marshal(v1alpha1.ClientConfiguration{
-		CACertificate:     base64.StdEncoding.EncodeToString([]byte(clientConfiguration.CACertificate)),
-		ClientCertificate: base64.StdEncoding.EncodeToString([]byte(clientConfiguration.ClientCertificate)),
-		ClientKey:         base64.StdEncoding.EncodeToString([]byte(clientConfiguration.ClientKey)),
-	})
-}
-
-func marshalTalosConfig(clientConfiguration *v1alpha1.ClientConfiguration) ([]byte, error) {
-	return json.Marshal(map[string]interface{}{
-		"context": "default",
-		"contexts": map[string]interface{}{
-			"default": map[string]interface{}{
-				"ca":  clientConfiguration.CACertificate,
-				"crt": clientConfiguration.ClientCertificate,
-				"key": clientConfiguration.ClientKey,
-			},
-		},
-	})
-}
-
-// SecretsBundleToMachineSecrets converts a Talos SDK bundle to the public structured contract.
-func SecretsBundleToMachineSecrets(bundle *talossecrets.Bundle) (*v1alpha1.MachineSecrets, error) {
-	if err := validateBundleForStructuredSecrets(bundle); err != nil {
+	if err := secrets.SetClientConnectionDetails(renewed, clientConfiguration, talosconfigOptions); err != nil {
 		return nil, err
 	}
-
-	return &v1alpha1.MachineSecrets{
-		Cluster: v1alpha1.MachineSecretsCluster{
-			ID:     bundle.Cluster.ID,
-			Secret: bundle.Cluster.Secret,
-		},
-		Secrets: v1alpha1.MachineSecretsSecrets{
-			BootstrapToken:            bundle.Secrets.BootstrapToken,
-			SecretboxEncryptionSecret: bundle.Secrets.SecretboxEncryptionSecret,
-			AESCBCEncryptionSecret:    bundle.Secrets.AESCBCEncryptionSecret,
-		},
-		TrustdInfo: v1alpha1.MachineSecretsTrustdInfo{Token: bundle.TrustdInfo.Token},
-		Certs: v1alpha1.MachineSecretsCerts{
-			Etcd:              encodeCertificateAndKey(bundle.Certs.Etcd),
-			K8s:               encodeCertificateAndKey(bundle.Certs.K8s),
-			K8sAggregator:     encodeCertificateAndKey(bundle.Certs.K8sAggregator),
-			K8sServiceAccount: encodeKey(bundle.Certs.K8sServiceAccount),
-			OS:                encodeCertificateAndKey(bundle.Certs.OS),
-		},
-	}, nil
-}
-
-func validateBundleForStructuredSecrets(bundle *talossecrets.Bundle) error {
-	if bundle == nil || bundle.Cluster == nil || bundle.Secrets == nil || bundle.TrustdInfo == nil || bundle.Certs == nil {
-		return errors.New("machine secrets bundle is incomplete")
-	}
-
-	return validateBundleCerts(bundle.Certs)
-}
-
-func validateBundleCerts(certs *talossecrets.Certs) error {
-	if certs.Etcd == nil || certs.K8s == nil || certs.K8sAggregator == nil || certs.K8sServiceAccount == nil || certs.OS == nil {
-		return errors.New("machine secrets bundle certificates are incomplete")
+	if err := trustOSCAs(renewed, talosconfigOptions); err != nil {
+		return nil, err
 	}
 
-	return nil
+	return renewed, nil
 }
 
-// MachineSecretsToSecretsBundle converts the public structured contract back to a Talos SDK bundle.
-func MachineSecretsToSecretsBundle(machineSecrets *v1alpha1.MachineSecrets) (*talossecrets.Bundle, error) {
-	if machineSecrets == nil {
-		return nil, errors.New("machine secrets are nil")
+// observeClientCertificate records the client certificate expiry in status
+// and returns when the certificate is due for renewal.
+func observeClientCertificate(cr *v1alpha1.Secrets, connectionDetails managed.ConnectionDetails) (time.Time, error) {
+	ttl := clientCertificateTTL(cr)
+	renewBefore := clientCertificateRenewBefore(cr)
+	if renewBefore >= ttl {
+		return time.Time{}, errors.Errorf("clientCertificateRenewBefore (%s) must be shorter than clientCertificateTTL (%s)", renewBefore, ttl)
 	}
 
-	etcd, err := decodeCertificateAndKey(machineSecrets.Certs.Etcd)
-	if err != nil {
-		return nil, errors.Wrap(err, "failed to decode etcd certificate and key")
-	}
-	k8s, err := decodeCertificateAndKey(machineSecrets.Certs.K8s)
-	if err != nil {
-		return nil, errors.Wrap(err, "failed to decode k8s certificate and key")
+	block, _ := pem.Decode(connectionDetails[secrets.ConnectionKeyClientCertificate])
+	if block == nil {
+		return time.Time{}, errors.New("client certificate connection detail is not PEM encoded")
 	}
-	k8sAggregator, err := decodeCertificateAndKey(machineSecrets.Certs.K8sAggregator)
+	cert, err := x509.ParseCertificate(block.Bytes)
 	if err != nil {
-		return nil, errors.Wrap(err, "failed to decode k8s aggregator certificate and key")
-	}
-	k8sServiceAccount, err := decodeKey(machineSecrets.Certs.K8sServiceAccount)
-	if err != nil {
-		return nil, errors.Wrap(err, "failed to decode k8s service account key")
-	}
-	os, err := decodeCertificateAndKey(machineSecrets.Certs.OS)
-	if err != nil {
-		return nil, errors.Wrap(err, "failed to decode os certificate and key")
-	}
-
-	bundle := &talossecrets.Bundle{
-		Clock: talossecrets.NewClock(),
-		Cluster: &talossecrets.Cluster{
-			ID:     machineSecrets.Cluster.ID,
-			Secret: machineSecrets.Cluster.Secret,
-		},
-		Secrets: &talossecrets.Secrets{
-			BootstrapToken:            machineSecrets.Secrets.BootstrapToken,
-			AESCBCEncryptionSecret:    machineSecrets.Secrets.AESCBCEncryptionSecret,
-			SecretboxEncryptionSecret: machineSecrets.Secrets.SecretboxEncryptionSecret,
-		},
-		TrustdInfo: &talossecrets.TrustdInfo{Token: machineSecrets.TrustdInfo.Token},
-		Certs: &talossecrets.Certs{
-			Etcd:              etcd,
-			K8s:               k8s,
-			K8sAggregator:     k8sAggregator,
-			K8sServiceAccount: k8sServiceAccount,
-			OS:                os,
-		},
+		return time.Time{}, errors.Wrap(err, "failed to parse client certificate")
 	}
 
-	if err := bundle.Validate(); err != nil {
-		return nil, errors.Wrap(err, "structured machine secrets are invalid")
-	}
+	renewalTime := cert.NotAfter.Add(-renewBefore)
+	notAfter := metav1.NewTime(cert.NotAfter)
+	renewal := metav1.NewTime(renewalTime)
+	cr.Status.AtProvider.ClientCertificateNotAfter = &notAfter
+	cr.Status.AtProvider.ClientCertificateRenewalTime = &renewal
 
-	return bundle, nil
+	return renewalTime, nil
 }
 
-// GenerateClientConfiguration creates a Talos API admin client certificate from the OS CA.
-func GenerateClientConfiguration(bundle *talossecrets.Bundle, ttl time.Duration) (*v1alpha1.ClientConfiguration, error) {
-	if bundle == nil || bundle.Certs == nil || bundle.Certs.OS == nil {
-		return nil, errors.New("machine secrets bundle does not contain an OS CA")
+func clientCertificateTTL(cr *v1alpha1.Secrets) time.Duration {
+	if ttl := cr.Spec.ForProvider.ClientCertificateTTL; ttl != nil && ttl.Duration > 0 {
+		return ttl.Duration
 	}
 
-	clientCertificate, err := bundle.GenerateTalosAPIClientCertificateWithTTL(role.MakeSet(role.Admin), ttl)
-	if err != nil {
-		return nil, errors.Wrap(err, "failed to generate Talos API client certificate")
+	return constants.TalosAPIDefaultCertificateValidityDuration
+}
+
+func clientCertificateRenewBefore(cr *v1alpha1.Secrets) time.Duration {
+	if renewBefore := cr.Spec.ForProvider.ClientCertificateRenewBefore; renewBefore != nil && renewBefore.Duration > 0 {
+		return renewBefore.Duration
 	}
 
-	return &v1alpha1.ClientConfiguration{
-		CACertificate:     string(bundle.Certs.OS.Crt),
-		ClientCertificate: string(clientCertificate.Crt),
-		ClientKey:         string(clientCertificate.Key),
-	}, nil
+	return min(defaultClientCertificateRenewBefore, clientCertificateTTL(cr)/3)
 }
 
-func encodeCertificateAndKey(certificateAndKey *siderox509.PEMEncodedCertificateAndKey) v1alpha1.MachineSecretsCertificateAndKey {
-	return v1alpha1.MachineSecretsCertificateAndKey{
-		Cert: base64.StdEncoding.EncodeToString(certificateAndKey.Crt),
-		Key:  base64.StdEncoding.EncodeToString(certificateAndKey.Key),
+func (c *external) connectionDetailsFromSecret(ctx context.Context, cr *v1alpha1.Secrets) (managed.ConnectionDetails, error) {
+	if cr.Spec.WriteConnectionSecretToReference == nil {
+		return nil, errors.New("generated machine secrets require writeConnectionSecretToRef to reload connection details")
+	}
+	if c.kube == nil {
+		return nil, errors.New("cannot reload generated machine secrets without Kubernetes client")
 	}
-}
 
-func encodeKey(key *siderox509.PEMEncodedKey) v1alpha1.MachineSecretsKey {
-	return v1alpha1.MachineSecretsKey{Key: base64.StdEncoding.EncodeToString(key.Key)}
-}
+	ref := cr.Spec.WriteConnectionSecretToReference
+	secret := &corev1.Secret{}
+	if err := c.kube.Get(ctx, types.NamespacedName{Name: ref.Name, Namespace: ref.Namespace}, secret); err != nil {
+		return nil, errors.Wrapf(err, "cannot get generated machine secrets connection secret %s/%s", ref.Namespace, ref.Name)
+	}
 
-func decodeCertificateAndKey(certificateAndKey v1alpha1.MachineSecretsCertificateAndKey) (*siderox509.PEMEncodedCertificateAndKey, error) {
-	cert, err := base64.StdEncoding.DecodeString(certificateAndKey.Cert)
-	if err != nil {
-		return nil, err
+	connectionDetails := managed.ConnectionDetails{}
+	for _, key := range requiredConnectionKeys() {
+		value, ok := secret.Data[key]
+		if !ok {
+			return nil, errors.Errorf("generated machine secrets connection secret %s/%s is missing key %q", ref.Namespace, ref.Name, key)
+		}
+		connectionDetails[key] = value
 	}
-	key, err := base64.StdEncoding.DecodeString(certificateAndKey.Key)
-	if err != nil {
-		return nil, err
+	if value, ok := secret.Data[secrets.ConnectionKeyCARotation]; ok {
+		connectionDetails[secrets.ConnectionKeyCARotation] = value
 	}
 
-	return &siderox509.PEMEncodedCertificateAndKey{Crt: cert, Key: key}, nil
+	return connectionDetails, nil
 }
 
-func decodeKey(key v1alpha1.MachineSecretsKey) (*siderox509.PEMEncodedKey, error) {
-	decoded, err := base64.StdEncoding.DecodeString(key.Key)
-	if err != nil {
-		return nil, err
+func requiredConnectionKeys() []string {
+	return []string{
+		secrets.ConnectionKeyMachineSecrets,
+		secrets.ConnectionKeyMachineSecretsBundle,
+		secrets.ConnectionKeyClientConfiguration,
+		secrets.ConnectionKeyCACertificate,
+		secrets.ConnectionKeyClientCertificate,
+		secrets.ConnectionKeyClientKey,
+		secrets.ConnectionKeyTalosConfig,
 	}
+}
 
-	return &siderox509.PEMEncodedKey{Key: decoded}, nil
+func hashConnectionDetail(connectionDetails managed.ConnectionDetails, key string) string {
+	hash := sha256.Sum256(connectionDetails[key])
+	return hex.EncodeToString(hash[:])
 }
@@ -24,14 +24,22 @@ import (
 	"encoding/base64"
 	"encoding/json"
 	"encoding/pem"
+	"net"
 	"testing"
 	"time"
 
 	"github.com/google/go-cmp/cmp"
+	siderox509 "github.com/siderolabs/crypto/x509"
+	clientconfig "github.com/siderolabs/talos/pkg/machinery/client/config"
 	talossecrets "github.com/siderolabs/talos/pkg/machinery/config/generate/secrets"
+	"github.com/siderolabs/talos/pkg/machinery/constants"
+	"github.com/siderolabs/talos/pkg/machinery/role"
+	"gopkg.in/yaml.v3"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 
 	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
@@ -40,6 +48,7 @@ import (
 	"github.com/crossplane/crossplane-runtime/pkg/test"
 
 	machinev1alpha1 "github.com/crossplane-contrib/provider-talos/apis/machine/v1alpha1"
+	"github.com/crossplane-contrib/provider-talos/internal/secrets"
 )
 
 // Unlike many Kubernetes projects Crossplane does not use third party testing
@@ -88,106 +97,10 @@ func TestObserve(t *testing.T) {
 	}
 }
 
-func TestSecretsBundleToMachineSecrets(t *testing.T) {
-	t.Parallel()
-
-	bundle, err := talossecrets.NewBundle(talossecrets.NewClock(), nil)
-	if err != nil {
-		t.Fatalf("talossecrets.NewBundle(...): %v", err)
-	}
-
-	machineSecrets, err := SecretsBundleToMachineSecrets(bundle)
-	if err != nil {
-		t.Fatalf("SecretsBundleToMachineSecrets(...): %v", err)
-	}
-
-	if machineSecrets.Cluster.ID != bundle.Cluster.ID {
-		t.Fatalf("expected cluster ID %q, got %q", bundle.Cluster.ID, machineSecrets.Cluster.ID)
-	}
-	if machineSecrets.Secrets.BootstrapToken != bundle.Secrets.BootstrapToken {
-		t.Fatalf("expected bootstrap token %q, got %q", bundle.Secrets.BootstrapToken, machineSecrets.Secrets.BootstrapToken)
-	}
-	if machineSecrets.TrustdInfo.Token != bundle.TrustdInfo.Token {
-		t.Fatalf("expected trustd token %q, got %q", bundle.TrustdInfo.Token, machineSecrets.TrustdInfo.Token)
-	}
-
-	for name, value := range map[string]string{
-		"certs.etcd.cert":              machineSecrets.Certs.Etcd.Cert,
-		"certs.etcd.key":               machineSecrets.Certs.Etcd.Key,
-		"certs.k8s.cert":               machineSecrets.Certs.K8s.Cert,
-		"certs.k8s.key":                machineSecrets.Certs.K8s.Key,
-		"certs.k8s_aggregator.cert":    machineSecrets.Certs.K8sAggregator.Cert,
-		"certs.k8s_aggregator.key":     machineSecrets.Certs.K8sAggregator.Key,
-		"certs.k8s_serviceaccount.key": machineSecrets.Certs.K8sServiceAccount.Key,
-		"certs.os.cert":                machineSecrets.Certs.OS.Cert,
-		"certs.os.key":                 machineSecrets.Certs.OS.Key,
-	} {
-		decoded, err := base64.StdEncoding.DecodeString(value)
-		if err != nil {
-			t.Fatalf("expected %s to be base64 encoded: %v", name, err)
-		}
-		if block, _ := pem.Decode(decoded); block == nil {
-			t.Fatalf("expected %s to decode to PEM", name)
-		}
-	}
-}
-
-func TestMachineSecretsToSecretsBundle(t *testing.T) {
-	t.Parallel()
-
-	bundle, err := talossecrets.NewBundle(talossecrets.NewClock(), nil)
-	if err != nil {
-		t.Fatalf("talossecrets.NewBundle(...): %v", err)
-	}
-	machineSecrets, err := SecretsBundleToMachineSecrets(bundle)
-	if err != nil {
-		t.Fatalf("SecretsBundleToMachineSecrets(...): %v", err)
-	}
-
-	got, err := MachineSecretsToSecretsBundle(machineSecrets)
-	if err != nil {
-		t.Fatalf("MachineSecretsToSecretsBundle(...): %v", err)
-	}
-
-	if got.Cluster.ID != bundle.Cluster.ID || got.Cluster.Secret != bundle.Cluster.Secret {
-		t.Fatal("expected cluster fields to round trip")
-	}
-	if !bytes.Equal(got.Certs.OS.Crt, bundle.Certs.OS.Crt) || !bytes.Equal(got.Certs.OS.Key, bundle.Certs.OS.Key) {
-		t.Fatal("expected OS CA to round trip")
-	}
-}
-
-func TestGenerateClientConfiguration(t *testing.T) {
-	t.Parallel()
-
-	bundle, err := talossecrets.NewBundle(talossecrets.NewClock(), nil)
-	if err != nil {
-		t.Fatalf("talossecrets.NewBundle(...): %v", err)
-	}
-
-	clientConfiguration, err := GenerateClientConfiguration(bundle, time.Hour)
-	if err != nil {
-		t.Fatalf("GenerateClientConfiguration(...): %v", err)
-	}
-
-	if clientConfiguration.ClientCertificate == string(bundle.Certs.OS.Crt) {
-		t.Fatal("expected client certificate to differ from OS CA certificate")
-	}
-	if _, err := tls.X509KeyPair([]byte(clientConfiguration.ClientCertificate), []byte(clientConfiguration.ClientKey)); err != nil {
-		t.Fatalf("expected client certificate and key to match: %v", err)
-	}
-
-	ca := parseCertificate(t, []byte(clientConfiguration.CACertificate))
-	client := parseCertificate(t, []byte(clientConfiguration.ClientCertificate))
-	if err := client.CheckSignatureFrom(ca); err != nil {
-		t.Fatalf("expected client certificate to be signed by OS CA: %v", err)
-	}
-}
-
 func TestGenerateMachineSecretsUsesAdminClientCertificate(t *testing.T) {
 	t.Parallel()
 
-	generated, err := (&external{}).generateMachineSecrets(nil)
+	generated, err := (&external{}).generateMachineSecrets(nil, constants.TalosAPIDefaultCertificateValidityDuration)
 	if err != nil {
 		t.Fatalf("generateMachineSecrets(...): unexpected error: %v", err)
 	}
@@ -218,32 +131,26 @@ func TestGenerateMachineSecretsUsesAdminClientCertificate(t *testing.T) {
 		t.Fatalf("ClientCertificate did not verify against CACertificate: %v", err)
 	}
 
-	details, err := connectionDetailsFromGeneratedSecrets(generated)
+	details, err := connectionDetailsFromGeneratedSecrets(generated, secrets.TalosconfigOptions{})
 	if err != nil {
 		t.Fatalf("connectionDetailsFromGeneratedSecrets(...): %v", err)
 	}
 
-	var talosConfig struct {
-		Contexts map[string]struct {
-			CA  string `json:"ca"`
-			Crt string `json:"crt"`
-			Key string `json:"key"`
-		} `json:"contexts"`
-	}
-	if err := json.Unmarshal(details[connectionKeyTalosConfig], &talosConfig); err != nil {
-		t.Fatalf("TalosConfig JSON unmarshal failed: %v", err)
+	talosConfig, err := clientconfig.FromBytes(details[secrets.ConnectionKeyTalosConfig])
+	if err != nil {
+		t.Fatalf("clientconfig.FromBytes(...): %v", err)
 	}
 	ctx, ok := talosConfig.Contexts["default"]
-	if !ok {
+	if !ok || talosConfig.Context != "default" {
 		t.Fatal("TalosConfig missing default context")
 	}
-	if diff := cmp.Diff(clientConfiguration.CACertificate, ctx.CA); diff != "" {
+	if diff := cmp.Diff(base64.StdEncoding.EncodeToString([]byte(clientConfiguration.CACertificate)), ctx.CA); diff != "" {
 		t.Errorf("TalosConfig CA: -want, +got:\n%s", diff)
 	}
-	if diff := cmp.Diff(clientConfiguration.ClientCertificate, ctx.Crt); diff != "" {
+	if diff := cmp.Diff(base64.StdEncoding.EncodeToString([]byte(clientConfiguration.ClientCertificate)), ctx.Crt); diff != "" {
 		t.Errorf("TalosConfig client certificate: -want, +got:\n%s", diff)
 	}
-	if diff := cmp.Diff(clientConfiguration.ClientKey, ctx.Key); diff != "" {
+	if diff := cmp.Diff(base64.StdEncoding.EncodeToString([]byte(clientConfiguration.ClientKey)), ctx.Key); diff != "" {
 		t.Errorf("TalosConfig client key: -want, +got:\n%s", diff)
 	}
 }
@@ -255,13 +162,13 @@ func TestConnectionDetailsFromGeneratedSecrets(t *testing.T) {
 	if err != nil {
 		t.Fatalf("talossecrets.NewBundle(...): %v", err)
 	}
-	machineSecrets, err := SecretsBundleToMachineSecrets(bundle)
+	machineSecrets, err := secrets.SecretsBundleToMachineSecrets(bundle)
 	if err != nil {
-		t.Fatalf("SecretsBundleToMachineSecrets(...): %v", err)
+		t.Fatalf("secrets.SecretsBundleToMachineSecrets(...): %v", err)
 	}
-	clientConfiguration, err := GenerateClientConfiguration(bundle, time.Hour)
+	clientConfiguration, err := secrets.GenerateClientConfiguration(bundle, time.Hour)
 	if err != nil {
-		t.Fatalf("GenerateClientConfiguration(...): %v", err)
+		t.Fatalf("secrets.GenerateClientConfiguration(...): %v", err)
 	}
 	bundleJSON, err := json.Marshal(bundle)
 	if err != nil {
@@ -272,26 +179,40 @@ func TestConnectionDetailsFromGeneratedSecrets(t *testing.T) {
 		Bundle:              string(bundleJSON),
 		MachineSecrets:      machineSecrets,
 		ClientConfiguration: clientConfiguration,
-	})
+	}, secrets.TalosconfigOptions{Context: "example", Endpoints: []string{"10.0.0.1", "10.0.0.2"}, Nodes: []string{"10.0.0.1"}})
 	if err != nil {
 		t.Fatalf("connectionDetailsFromGeneratedSecrets(...): %v", err)
 	}
 
-	if bytes.Equal(details[connectionKeyMachineSecrets], bundleJSON) {
+	if bytes.Equal(details[secrets.ConnectionKeyMachineSecrets], bundleJSON) {
 		t.Fatal("expected machine_secrets to contain structured JSON, not raw bundle JSON")
 	}
-	if !bytes.Equal(details[connectionKeyMachineSecretsBundle], bundleJSON) {
+	if !bytes.Equal(details[secrets.ConnectionKeyMachineSecretsBundle], bundleJSON) {
 		t.Fatal("expected machine_secrets_bundle to contain raw bundle JSON")
 	}
-	if string(details[connectionKeyClientCertificate]) != clientConfiguration.ClientCertificate {
+	if string(details[secrets.ConnectionKeyClientCertificate]) != clientConfiguration.ClientCertificate {
 		t.Fatal("expected top-level client_certificate to remain raw PEM")
 	}
-	if !json.Valid(details[connectionKeyTalosConfig]) {
-		t.Fatal("expected talos_config to contain JSON")
+	talosConfig, err := clientconfig.FromBytes(details[secrets.ConnectionKeyTalosConfig])
+	if err != nil {
+		t.Fatalf("expected talos_config to be a talosconfig: %v", err)
+	}
+	want := &clientconfig.Context{
+		Endpoints: []string{"10.0.0.1", "10.0.0.2"},
+		Nodes:     []string{"10.0.0.1"},
+		CA:        base64.StdEncoding.EncodeToString([]byte(clientConfiguration.CACertificate)),
+		Crt:       base64.StdEncoding.EncodeToString([]byte(clientConfiguration.ClientCertificate)),
+		Key:       base64.StdEncoding.EncodeToString([]byte(clientConfiguration.ClientKey)),
+	}
+	if diff := cmp.Diff("example", talosConfig.Context); diff != "" {
+		t.Errorf("talos_config context: -want, +got:\n%s", diff)
+	}
+	if diff := cmp.Diff(want, talosConfig.Contexts["example"]); diff != "" {
+		t.Errorf("talos_config context example: -want, +got:\n%s", diff)
 	}
 
 	var connectionClientConfiguration machinev1alpha1.ClientConfiguration
-	if err := json.Unmarshal(details[connectionKeyClientConfiguration], &connectionClientConfiguration); err != nil {
+	if err := json.Unmarshal(details[secrets.ConnectionKeyClientConfiguration], &connectionClientConfiguration); err != nil {
 		t.Fatalf("json.Unmarshal(...): %v", err)
 	}
 	decodedClientCertificate, err := base64.StdEncoding.DecodeString(connectionClientConfiguration.ClientCertificate)
@@ -347,11 +268,11 @@ func TestObserveWritesMetadataOnlyStatus(t *testing.T) {
 func TestObserveLoadsConnectionDetailsFromSecret(t *testing.T) {
 	t.Parallel()
 
-	generated, err := (&external{}).generateMachineSecrets(nil)
+	generated, err := (&external{}).generateMachineSecrets(nil, constants.TalosAPIDefaultCertificateValidityDuration)
 	if err != nil {
 		t.Fatalf("generateMachineSecrets(...): %v", err)
 	}
-	details, err := connectionDetailsFromGeneratedSecrets(generated)
+	details, err := connectionDetailsFromGeneratedSecrets(generated, secrets.TalosconfigOptions{})
 	if err != nil {
 		t.Fatalf("connectionDetailsFromGeneratedSecrets(...): %v", err)
 	}
@@ -399,3 +320,537 @@ func hasClientAuthUsage(cert *x509.Certificate) bool {
 	}
 	return false
 }
+
+func TestObserveClientCertificateRenewal(t *testing.T) {
+	t.Parallel()
+
+	cases := map[string]struct {
+		reason      string
+		ttl         *metav1.Duration
+		renewBefore *metav1.Duration
+		upToDate    bool
+	}{
+		"NotDue": {
+			reason:   "A client certificate outside its renewal window should be up to date.",
+			upToDate: true,
+		},
+		"Due": {
+			reason:      "A client certificate inside its renewal window should be renewed.",
+			ttl:         &metav1.Duration{Duration: 2 * time.Hour},
+			renewBefore: &metav1.Duration{Duration: 90 * time.Minute},
+			upToDate:    false,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			cr, kube, _ := testGeneratedSecrets(t, time.Hour)
+			if tc.ttl != nil {
+				cr.Spec.ForProvider.ClientCertificateTTL = tc.ttl
+			}
+			cr.Spec.ForProvider.ClientCertificateRenewBefore = tc.renewBefore
+
+			got, err := (&external{kube: kube}).Observe(context.Background(), cr)
+			if err != nil {
+				t.Fatalf("\n%s\nObserve(...): %v", tc.reason, err)
+			}
+			if got.ResourceUpToDate != tc.upToDate {
+				t.Errorf("\n%s\nObserve(...): ResourceUpToDate = %t, want %t", tc.reason, got.ResourceUpToDate, tc.upToDate)
+			}
+
+			notAfter := cr.Status.AtProvider.ClientCertificateNotAfter
+			if notAfter == nil {
+				t.Fatalf("\n%s\nexpected ClientCertificateNotAfter", tc.reason)
+			}
+			if d := time.Until(notAfter.Time); d <= 0 || d > time.Hour {
+				t.Errorf("\n%s\nClientCertificateNotAfter %s is not within the 1h TTL", tc.reason, notAfter)
+			}
+			if cr.Status.AtProvider.ClientCertificateRenewalTime == nil {
+				t.Errorf("\n%s\nexpected ClientCertificateRenewalTime", tc.reason)
+			}
+		})
+	}
+}
+
+func TestObserveRejectsRenewalWindowLongerThanTTL(t *testing.T) {
+	t.Parallel()
+
+	cr, kube, _ := testGeneratedSecrets(t, time.Hour)
+	cr.Spec.ForProvider.ClientCertificateTTL = &metav1.Duration{Duration: time.Hour}
+	cr.Spec.ForProvider.ClientCertificateRenewBefore = &metav1.Duration{Duration: 2 * time.Hour}
+
+	if _, err := (&external{kube: kube}).Observe(context.Background(), cr); err == nil {
+		t.Fatal("Observe(...): expected error for renewal window longer than the certificate TTL")
+	}
+}
+
+func TestUpdateRenewsClientCertificate(t *testing.T) {
+	t.Parallel()
+
+	cr, kube, details := testGeneratedSecrets(t, time.Hour)
+	cr.Spec.ForProvider.ClientCertificateTTL = &metav1.Duration{Duration: 48 * time.Hour}
+
+	got, err := (&external{kube: kube}).Update(context.Background(), cr)
+	if err != nil {
+		t.Fatalf("Update(...): %v", err)
+	}
+
+	renewed := got.ConnectionDetails
+	for _, key := range []string{secrets.ConnectionKeyMachineSecrets, secrets.ConnectionKeyMachineSecretsBundle, secrets.ConnectionKeyCACertificate} {
+		if !bytes.Equal(details[key], renewed[key]) {
+			t.Errorf("Update(...): %s changed, want unchanged", key)
+		}
+	}
+	for _, key := range []string{secrets.ConnectionKeyClientCertificate, secrets.ConnectionKeyClientKey, secrets.ConnectionKeyClientConfiguration, secrets.ConnectionKeyTalosConfig} {
+		if bytes.Equal(details[key], renewed[key]) {
+			t.Errorf("Update(...): %s unchanged, want reissued", key)
+		}
+	}
+
+	if _, err := tls.X509KeyPair(renewed[secrets.ConnectionKeyClientCertificate], renewed[secrets.ConnectionKeyClientKey]); err != nil {
+		t.Fatalf("renewed client certificate and key do not match: %v", err)
+	}
+	cert := parseCertificate(t, renewed[secrets.ConnectionKeyClientCertificate])
+	roots := x509.NewCertPool()
+	roots.AppendCertsFromPEM(renewed[secrets.ConnectionKeyCACertificate])
+	if _, err := cert.Verify(x509.VerifyOptions{Roots: roots, KeyUsages: []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth}}); err != nil {
+		t.Fatalf("renewed client certificate is not issued by the OS CA: %v", err)
+	}
+	if d := time.Until(cert.NotAfter); d < 47*time.Hour {
+		t.Errorf("renewed client certificate expires in %s, want about 48h", d)
+	}
+
+	if cr.Status.AtProvider.ClientCertificateRenewedTime == nil {
+		t.Error("expected ClientCertificateRenewedTime")
+	}
+	if cr.Status.AtProvider.ClientCertificateNotAfter == nil || !cr.Status.AtProvider.ClientCertificateNotAfter.Time.Equal(cert.NotAfter) {
+		t.Errorf("ClientCertificateNotAfter = %v, want %s", cr.Status.AtProvider.ClientCertificateNotAfter, cert.NotAfter)
+	}
+	if cr.Status.AtProvider.ClientConfigurationHash != hashConnectionDetail(renewed, secrets.ConnectionKeyClientConfiguration) {
+		t.Error("ClientConfigurationHash does not match the renewed client configuration")
+	}
+}
+
+func TestUpdateRendersTalosconfig(t *testing.T) {
+	t.Parallel()
+
+	cr, kube, details := testGeneratedSecrets(t, 48*time.Hour)
+	cr.Spec.ForProvider.TalosconfigContext = ptr.To("example")
+	cr.Spec.ForProvider.Endpoints = []string{"10.0.0.1"}
+
+	got, err := (&external{kube: kube}).Observe(context.Background(), cr)
+	if err != nil {
+		t.Fatalf("Observe(...): %v", err)
+	}
+	if got.ResourceUpToDate {
+		t.Fatal("Observe(...): want not up to date after the talosconfig options changed")
+	}
+
+	updated, err := (&external{kube: kube}).Update(context.Background(), cr)
+	if err != nil {
+		t.Fatalf("Update(...): %v", err)
+	}
+	for _, key := range []string{secrets.ConnectionKeyClientCertificate, secrets.ConnectionKeyClientKey, secrets.ConnectionKeyClientConfiguration} {
+		if !bytes.Equal(details[key], updated.ConnectionDetails[key]) {
+			t.Errorf("Update(...): %s changed, want unchanged", key)
+		}
+	}
+	talosConfig, err := clientconfig.FromBytes(updated.ConnectionDetails[secrets.ConnectionKeyTalosConfig])
+	if err != nil {
+		t.Fatalf("clientconfig.FromBytes(...): %v", err)
+	}
+	if diff := cmp.Diff("example", talosConfig.Context); diff != "" {
+		t.Errorf("talos_config context: -want, +got:\n%s", diff)
+	}
+	if diff := cmp.Diff([]string{"10.0.0.1"}, talosConfig.Contexts["example"].Endpoints); diff != "" {
+		t.Errorf("talos_config endpoints: -want, +got:\n%s", diff)
+	}
+	if cr.Status.AtProvider.ClientCertificateRenewedTime != nil {
+		t.Error("Update(...): client certificate renewed, want only the talosconfig rendered")
+	}
+}
+
+// testGeneratedSecrets returns a generated Secrets resource whose connection
+// details, including a client certificate valid for ttl, are stored in a fake
+// connection secret.
+func testGeneratedSecrets(t *testing.T, ttl time.Duration) (*machinev1alpha1.Secrets, client.Client, managed.ConnectionDetails) {
+	t.Helper()
+
+	generated, err := (&external{}).generateMachineSecrets(nil, ttl)
+	if err != nil {
+		t.Fatalf("generateMachineSecrets(...): %v", err)
+	}
+	details, err := connectionDetailsFromGeneratedSecrets(generated, secrets.TalosconfigOptions{})
+	if err != nil {
+		t.Fatalf("connectionDetailsFromGeneratedSecrets(...): %v", err)
+	}
+
+	scheme := runtime.NewScheme()
+	if err := corev1.AddToScheme(scheme); err != nil {
+		t.Fatalf("corev1.AddToScheme(...): %v", err)
+	}
+	secret := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "example-connection", Namespace: "default"}, Data: map[string][]byte(details)}
+	cr := &machinev1alpha1.Secrets{
+		ObjectMeta: metav1.ObjectMeta{Name: "example-secrets"},
+		Spec: machinev1alpha1.SecretsSpec{
+			ResourceSpec: xpv1.ResourceSpec{WriteConnectionSecretToReference: &xpv1.SecretReference{Name: secret.Name, Namespace: secret.Namespace}},
+			ForProvider:  machinev1alpha1.SecretsParameters{ClientCertificateTTL: &metav1.Duration{Duration: ttl}},
+		},
+		Status: machinev1alpha1.SecretsStatus{AtProvider: machinev1alpha1.SecretsObservation{Generated: true}},
+	}
+
+	return cr, fake.NewClientBuilder().WithScheme(scheme).WithObjects(secret).Build(), details
+}
+
+func TestCreateImportsSecretsBundle(t *testing.T) {
+	t.Parallel()
+
+	bundle, err := talossecrets.NewBundle(talossecrets.NewClock(), nil)
+	if err != nil {
+		t.Fatalf("talossecrets.NewBundle(...): %v", err)
+	}
+	secretsYAML, err := yaml.Marshal(bundle)
+	if err != nil {
+		t.Fatalf("yaml.Marshal(...): %v", err)
+	}
+	admin, err := bundle.GenerateTalosAPIClientCertificateWithTTL(role.MakeSet(role.Admin), time.Hour)
+	if err != nil {
+		t.Fatalf("GenerateTalosAPIClientCertificateWithTTL(...): %v", err)
+	}
+	talosconfig, err := clientconfig.NewConfig("existing", []string{"10.0.0.2"}, bundle.Certs.OS.Crt, admin).Bytes()
+	if err != nil {
+		t.Fatalf("talosconfig Bytes(): %v", err)
+	}
+	other, err := talossecrets.NewBundle(talossecrets.NewClock(), nil)
+	if err != nil {
+		t.Fatalf("talossecrets.NewBundle(...): %v", err)
+	}
+	foreign, err := other.GenerateTalosAPIClientCertificateWithTTL(role.MakeSet(role.Admin), time.Hour)
+	if err != nil {
+		t.Fatalf("GenerateTalosAPIClientCertificateWithTTL(...): %v", err)
+	}
+	foreignTalosconfig, err := clientconfig.NewConfig("foreign", []string{"10.0.0.2"}, other.Certs.OS.Crt, foreign).Bytes()
+	if err != nil {
+		t.Fatalf("talosconfig Bytes(): %v", err)
+	}
+
+	scheme := runtime.NewScheme()
+	if err := corev1.AddToScheme(scheme); err != nil {
+		t.Fatalf("corev1.AddToScheme(...): %v", err)
+	}
+	existing := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "existing", Namespace: "default"},
+		Data: map[string][]byte{
+			"secrets.yaml":        secretsYAML,
+			"talosconfig":         talosconfig,
+			"foreign-talosconfig": foreignTalosconfig,
+		},
+	}
+	kube := fake.NewClientBuilder().WithScheme(scheme).WithObjects(existing).Build()
+
+	cases := map[string]struct {
+		reason         string
+		talosconfigKey string
+		wantClientCert []byte
+		wantErr        bool
+	}{
+		"BundleOnly": {
+			reason: "An imported bundle should be published with a newly issued client certificate.",
+		},
+		"WithTalosconfig": {
+			reason:         "The client credentials of an imported talosconfig should be published as is.",
+			talosconfigKey: "talosconfig",
+			wantClientCert: admin.Crt,
+		},
+		"ForeignTalosconfig": {
+			reason:         "A talosconfig issued by another CA should be rejected.",
+			talosconfigKey: "foreign-talosconfig",
+			wantErr:        true,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			cr := &machinev1alpha1.Secrets{
+				ObjectMeta: metav1.ObjectMeta{Name: "example-secrets"},
+				Spec: machinev1alpha1.SecretsSpec{
+					ResourceSpec: xpv1.ResourceSpec{WriteConnectionSecretToReference: &xpv1.SecretReference{Name: "example-connection", Namespace: "default"}},
+					ForProvider: machinev1alpha1.SecretsParameters{
+						SecretsBundleRef: &xpv1.SecretKeySelector{
+							SecretReference: xpv1.SecretReference{Name: existing.Name, Namespace: existing.Namespace},
+							Key:             "secrets.yaml",
+						},
+					},
+				},
+			}
+			if tc.talosconfigKey != "" {
+				cr.Spec.ForProvider.TalosconfigRef = &xpv1.SecretKeySelector{
+					SecretReference: xpv1.SecretReference{Name: existing.Name, Namespace: existing.Namespace},
+					Key:             tc.talosconfigKey,
+				}
+			}
+
+			got, err := (&external{kube: kube}).Create(context.Background(), cr)
+			if tc.wantErr {
+				if err == nil {
+					t.Fatalf("\n%s\nCreate(...): expected error", tc.reason)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("\n%s\nCreate(...): %v", tc.reason, err)
+			}
+
+			imported := &machinev1alpha1.MachineSecrets{}
+			if err := json.Unmarshal(got.ConnectionDetails[secrets.ConnectionKeyMachineSecrets], imported); err != nil {
+				t.Fatalf("\n%s\njson.Unmarshal(machine_secrets): %v", tc.reason, err)
+			}
+			if imported.Cluster.ID != bundle.Cluster.ID {
+				t.Errorf("\n%s\nCreate(...): cluster ID = %q, want %q", tc.reason, imported.Cluster.ID, bundle.Cluster.ID)
+			}
+			if !bytes.Equal(got.ConnectionDetails[secrets.ConnectionKeyCACertificate], bundle.Certs.OS.Crt) {
+				t.Errorf("\n%s\nCreate(...): ca_certificate is not the imported OS CA", tc.reason)
+			}
+			if tc.wantClientCert != nil && !bytes.Equal(got.ConnectionDetails[secrets.ConnectionKeyClientCertificate], tc.wantClientCert) {
+				t.Errorf("\n%s\nCreate(...): client_certificate is not the imported certificate", tc.reason)
+			}
+			for _, key := range requiredConnectionKeys() {
+				if len(got.ConnectionDetails[key]) == 0 {
+					t.Errorf("\n%s\nCreate(...): missing connection detail %q", tc.reason, key)
+				}
+			}
+			if !cr.Status.AtProvider.Imported {
+				t.Errorf("\n%s\nCreate(...): Imported = false, want true", tc.reason)
+			}
+		})
+	}
+}
+
+// testNode is a node running the machine configuration generated from the
+// connection details of a Secrets it last applied. It serves a certificate
+// from its issuing OS CA and accepts clients of the issuing and accepted OS
+// CAs.
+type testNode struct {
+	issuing  *siderox509.PEMEncodedCertificateAndKey
+	accepted [][]byte
+}
+
+func newTestNode(t *testing.T, connectionDetails map[string][]byte) testNode {
+	t.Helper()
+
+	machineSecrets := &machinev1alpha1.MachineSecrets{}
+	if err := json.Unmarshal(connectionDetails[secrets.ConnectionKeyMachineSecrets], machineSecrets); err != nil {
+		t.Fatalf("json.Unmarshal(...): %v", err)
+	}
+	bundle, err := secrets.MachineSecretsToSecretsBundle(machineSecrets)
+	if err != nil {
+		t.Fatalf("secrets.MachineSecretsToSecretsBundle(...): %v", err)
+	}
+	accepted, err := secrets.ParseAcceptedCAs(connectionDetails)
+	if err != nil {
+		t.Fatalf("secrets.ParseAcceptedCAs(...): %v", err)
+	}
+
+	return testNode{issuing: bundle.Certs.OS, accepted: accepted.OS}
+}
+
+// verifyReachable checks that a client using the published connection
+// details and the node accept each other's certificates.
+func (n testNode) verifyReachable(t *testing.T, connectionDetails map[string][]byte) {
+	t.Helper()
+
+	ca, err := siderox509.NewCertificateAuthorityFromCertificateAndKey(n.issuing)
+	if err != nil {
+		t.Fatalf("NewCertificateAuthorityFromCertificateAndKey(...): %v", err)
+	}
+	server, err := siderox509.NewKeyPair(ca,
+		siderox509.IPAddresses([]net.IP{net.ParseIP("10.0.0.1")}),
+		siderox509.ExtKeyUsage([]x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth}),
+	)
+	if err != nil {
+		t.Fatalf("NewKeyPair(...): %v", err)
+	}
+	clientRoots := x509.NewCertPool()
+	clientRoots.AppendCertsFromPEM(connectionDetails[secrets.ConnectionKeyCACertificate])
+	if _, err := parseCertificate(t, server.CrtPEM).Verify(x509.VerifyOptions{Roots: clientRoots, KeyUsages: []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth}}); err != nil {
+		t.Errorf("published ca_certificate does not trust the node: %v", err)
+	}
+
+	nodeRoots := x509.NewCertPool()
+	nodeRoots.AppendCertsFromPEM(n.issuing.Crt)
+	for _, accepted := range n.accepted {
+		nodeRoots.AppendCertsFromPEM(accepted)
+	}
+	if _, err := parseCertificate(t, connectionDetails[secrets.ConnectionKeyClientCertificate]).Verify(x509.VerifyOptions{Roots: nodeRoots, KeyUsages: []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth}}); err != nil {
+		t.Errorf("node does not accept the published client certificate: %v", err)
+	}
+}
+
+func TestCARotation(t *testing.T) {
+	t.Parallel()
+
+	cr, _, details := testGeneratedSecrets(t, 48*time.Hour)
+	cr.Spec.ForProvider.CARotation = &machinev1alpha1.SecretsCARotation{Generation: 1}
+
+	scheme := runtime.NewScheme()
+	if err := corev1.AddToScheme(scheme); err != nil {
+		t.Fatalf("corev1.AddToScheme(...): %v", err)
+	}
+	if err := machinev1alpha1.SchemeBuilder.AddToScheme(scheme); err != nil {
+		t.Fatalf("machinev1alpha1.AddToScheme(...): %v", err)
+	}
+	secret := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "example-connection", Namespace: "default"}, Data: map[string][]byte(details)}
+	configuration := &machinev1alpha1.Configuration{
+		ObjectMeta: metav1.ObjectMeta{Name: "example-configuration"},
+		Spec: machinev1alpha1.ConfigurationSpec{
+			ResourceSpec: xpv1.ResourceSpec{WriteConnectionSecretToReference: &xpv1.SecretReference{Name: "example-configuration", Namespace: "default"}},
+			ForProvider:  machinev1alpha1.ConfigurationParameters{MachineSecretsRef: &xpv1.Reference{Name: cr.Name}},
+		},
+	}
+	apply := &machinev1alpha1.ConfigurationApply{
+		ObjectMeta: metav1.ObjectMeta{Name: "example-apply"},
+		Spec: machinev1alpha1.ConfigurationApplySpec{
+			ForProvider: machinev1alpha1.ConfigurationApplyParameters{
+				Node:                    "10.0.0.1",
+				MachineConfigurationRef: &machinev1alpha1.SecretKeyReference{Name: "example-configuration", Namespace: "default", Key: "machine_configuration"},
+			},
+		},
+	}
+	kube := fake.NewClientBuilder().WithScheme(scheme).WithObjects(secret, configuration, apply).WithStatusSubresource(configuration, apply).Build()
+	e := &external{kube: kube}
+	node := newTestNode(t, details)
+
+	// step publishes the connection details of an Update, as the managed
+	// reconciler would. When rolledOut is set, the Configuration is first
+	// regenerated from the published connection details and the node applies
+	// it, which requires the published credentials to reach the node.
+	step := func(t *testing.T, rolledOut bool) managed.ExternalObservation {
+		t.Helper()
+		ctx := context.Background()
+
+		if rolledOut {
+			current := &corev1.Secret{}
+			if err := kube.Get(ctx, client.ObjectKeyFromObject(secret), current); err != nil {
+				t.Fatalf("Get(...): %v", err)
+			}
+			node.verifyReachable(t, current.Data)
+			node = newTestNode(t, current.Data)
+
+			hash := secrets.MachineSecretsHash(current.Data)
+			if err := kube.Get(ctx, client.ObjectKeyFromObject(configuration), configuration); err != nil {
+				t.Fatalf("Get(...): %v", err)
+			}
+			configuration.Status.AtProvider.MachineSecretsHash = hash
+			configuration.Status.AtProvider.MachineConfigurationHash = hash
+			if err := kube.Status().Update(ctx, configuration); err != nil {
+				t.Fatalf("Status().Update(...): %v", err)
+			}
+			if err := kube.Get(ctx, client.ObjectKeyFromObject(apply), apply); err != nil {
+				t.Fatalf("Get(...): %v", err)
+			}
+			apply.Status.AtProvider.SourceConfigHash = hash
+			apply.Status.AtProvider.DesiredConfigHash = hash
+			apply.Status.AtProvider.AppliedConfigHash = hash
+			if err := kube.Status().Update(ctx, apply); err != nil {
+				t.Fatalf("Status().Update(...): %v", err)
+			}
+		}
+
+		got, err := e.Observe(ctx, cr)
+		if err != nil {
+			t.Fatalf("Observe(...): %v", err)
+		}
+		if got.ResourceUpToDate {
+			return got
+		}
+
+		updated, err := e.Update(ctx, cr)
+		if err != nil {
+			t.Fatalf("Update(...): %v", err)
+		}
+		current := &corev1.Secret{}
+		if err := kube.Get(ctx, client.ObjectKeyFromObject(secret), current); err != nil {
+			t.Fatalf("Get(...): %v", err)
+		}
+		current.Data = map[string][]byte(updated.ConnectionDetails)
+		if err := kube.Update(ctx, current); err != nil {
+			t.Fatalf("Update(...): %v", err)
+		}
+
+		return managed.ExternalObservation{ConnectionDetails: updated.ConnectionDetails}
+	}
+	phase := func() string {
+		if cr.Status.AtProvider.CARotation == nil {
+			return ""
+		}
+		return cr.Status.AtProvider.CARotation.Phase
+	}
+
+	accepting := step(t, false).ConnectionDetails
+	if diff := cmp.Diff(machinev1alpha1.CARotationPhaseAcceptingNewCA, phase()); diff != "" {
+		t.Fatalf("phase: -want, +got:\n%s", diff)
+	}
+	accepted, err := secrets.ParseAcceptedCAs(accepting)
+	if err != nil {
+		t.Fatalf("secrets.ParseAcceptedCAs(...): %v", err)
+	}
+	if len(accepted.OS) != 1 || len(accepted.Kubernetes) != 1 {
+		t.Fatalf("secrets.ParseAcceptedCAs(...): got %d OS and %d Kubernetes CAs, want 1 each", len(accepted.OS), len(accepted.Kubernetes))
+	}
+	newOSCA := accepted.OS[0]
+	if !bytes.Equal(details[secrets.ConnectionKeyMachineSecrets], accepting[secrets.ConnectionKeyMachineSecrets]) {
+		t.Error("machine_secrets changed while accepting the new CAs")
+	}
+
+	if got := step(t, false); !got.ResourceUpToDate {
+		t.Fatal("Observe(...): rotation advanced before the Configuration was regenerated")
+	}
+	if diff := cmp.Diff([]string{machinev1alpha1.ConfigurationKind + "/" + configuration.Name}, cr.Status.AtProvider.CARotation.Pending); diff != "" {
+		t.Errorf("Pending: -want, +got:\n%s", diff)
+	}
+
+	switching := step(t, true).ConnectionDetails
+	if diff := cmp.Diff(machinev1alpha1.CARotationPhaseSwitchingCA, phase()); diff != "" {
+		t.Fatalf("phase: -want, +got:\n%s", diff)
+	}
+	if !bytes.Contains(switching[secrets.ConnectionKeyCACertificate], newOSCA) || !bytes.Contains(switching[secrets.ConnectionKeyCACertificate], details[secrets.ConnectionKeyCACertificate]) {
+		t.Error("ca_certificate does not hold both the new and the old OS CA while switching CAs")
+	}
+	roots := x509.NewCertPool()
+	roots.AppendCertsFromPEM(newOSCA)
+	cert := parseCertificate(t, switching[secrets.ConnectionKeyClientCertificate])
+	if _, err := cert.Verify(x509.VerifyOptions{Roots: roots, KeyUsages: []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth}}); err != nil {
+		t.Errorf("client certificate is not issued by the new OS CA: %v", err)
+	}
+	accepted, err = secrets.ParseAcceptedCAs(switching)
+	if err != nil {
+		t.Fatalf("secrets.ParseAcceptedCAs(...): %v", err)
+	}
+	if len(accepted.OS) != 1 || !bytes.Equal(details[secrets.ConnectionKeyCACertificate], accepted.OS[0]) {
+		t.Error("old OS CA is not accepted while switching CAs")
+	}
+
+	dropping := step(t, true).ConnectionDetails
+	if diff := cmp.Diff(machinev1alpha1.CARotationPhaseDroppingOldCA, phase()); diff != "" {
+		t.Fatalf("phase: -want, +got:\n%s", diff)
+	}
+	if diff := cmp.Diff("{}", string(dropping[secrets.ConnectionKeyCARotation])); diff != "" {
+		t.Errorf("ca_rotation: -want, +got:\n%s", diff)
+	}
+	if !bytes.Equal(newOSCA, dropping[secrets.ConnectionKeyCACertificate]) {
+		t.Error("ca_certificate is not only the new OS CA once the old CA is dropped")
+	}
+
+	step(t, true)
+	if diff := cmp.Diff(machinev1alpha1.CARotationPhaseCompleted, phase()); diff != "" {
+		t.Fatalf("phase: -want, +got:\n%s", diff)
+	}
+	if got := step(t, false); !got.ResourceUpToDate {
+		t.Error("Observe(...): want up to date once the rotation completed")
+	}
+	node.verifyReachable(t, dropping)
+}
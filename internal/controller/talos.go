@@ -20,32 +20,52 @@ import (
 	"github.com/crossplane/crossplane-runtime/pkg/controller"
 	ctrl "sigs.k8s.io/controller-runtime"
 
+	"github.com/crossplane-contrib/provider-talos/internal/clients"
 	"github.com/crossplane-contrib/provider-talos/internal/controller/bootstrap"
+	"github.com/crossplane-contrib/provider-talos/internal/controller/clientcertificate"
 	"github.com/crossplane-contrib/provider-talos/internal/controller/clusterhealth"
 	"github.com/crossplane-contrib/provider-talos/internal/controller/config"
 	"github.com/crossplane-contrib/provider-talos/internal/controller/configuration"
 	"github.com/crossplane-contrib/provider-talos/internal/controller/configurationapply"
+	"github.com/crossplane-contrib/provider-talos/internal/controller/etcdmember"
+	"github.com/crossplane-contrib/provider-talos/internal/controller/etcdsnapshot"
 	"github.com/crossplane-contrib/provider-talos/internal/controller/factoryschematic"
 	"github.com/crossplane-contrib/provider-talos/internal/controller/kubeconfig"
+	"github.com/crossplane-contrib/provider-talos/internal/controller/kubernetesupgrade"
+	"github.com/crossplane-contrib/provider-talos/internal/controller/machinediscovery"
 	"github.com/crossplane-contrib/provider-talos/internal/controller/secrets"
+	"github.com/crossplane-contrib/provider-talos/internal/controller/upgrade"
 )
 
 // Setup creates all Talos controllers with the supplied logger and adds them to
-// the supplied manager.
-func Setup(mgr ctrl.Manager, o controller.Options) error {
+// the supplied manager. Controllers that talk to the Talos API share clients
+// through the supplied cache, which the manager runs alongside them.
+func Setup(mgr ctrl.Manager, o controller.Options, talosClients *clients.Cache) error {
 	for _, setup := range []func(ctrl.Manager, controller.Options) error{
 		config.Setup,
 		secrets.Setup,
+		clientcertificate.Setup,
 		configuration.Setup,
+		factoryschematic.Setup,
+		machinediscovery.Setup,
+	} {
+		if err := setup(mgr, o); err != nil {
+			return err
+		}
+	}
+	for _, setup := range []func(ctrl.Manager, controller.Options, *clients.Cache) error{
 		configurationapply.Setup,
 		bootstrap.Setup,
+		upgrade.Setup,
+		etcdsnapshot.Setup,
+		etcdmember.Setup,
 		clusterhealth.Setup,
+		kubernetesupgrade.Setup,
 		kubeconfig.Setup,
-		factoryschematic.Setup,
 	} {
-		if err := setup(mgr, o); err != nil {
+		if err := setup(mgr, o, talosClients); err != nil {
 			return err
 		}
 	}
-	return nil
+	return mgr.Add(talosClients)
 }
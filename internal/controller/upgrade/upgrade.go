@@ -0,0 +1,555 @@
+/*
+Copyright 2025 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package upgrade
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/siderolabs/talos/pkg/machinery/api/machine"
+	talosclient "github.com/siderolabs/talos/pkg/machinery/client"
+	"google.golang.org/grpc"
+
+	"github.com/crossplane/crossplane-runtime/pkg/feature"
+
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
+	ctrl "sigs.k8s.io/controller-runtime"
+	ctrlclient "sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane/crossplane-runtime/pkg/connection"
+	"github.com/crossplane/crossplane-runtime/pkg/controller"
+	"github.com/crossplane/crossplane-runtime/pkg/event"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/pkg/ratelimiter"
+	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/crossplane/crossplane-runtime/pkg/statemetrics"
+
+	imagev1alpha1 "github.com/crossplane-contrib/provider-talos/apis/image/v1alpha1"
+	"github.com/crossplane-contrib/provider-talos/apis/machine/v1alpha1"
+	apisv1alpha1 "github.com/crossplane-contrib/provider-talos/apis/v1alpha1"
+	"github.com/crossplane-contrib/provider-talos/internal/clients"
+	"github.com/crossplane-contrib/provider-talos/internal/features"
+	"github.com/crossplane-contrib/provider-talos/internal/imagefactory"
+)
+
+const (
+	errNotUpgrade   = "managed resource is not an Upgrade custom resource"
+	errTrackPCUsage = "cannot track ProviderConfig usage"
+	errGetPC        = "cannot get ProviderConfig"
+	errGetCreds     = "cannot get credentials"
+
+	errNewClient = "cannot create new Service"
+
+	// upgradeTimeout bounds how long a non-staged upgrade may take before the
+	// request is considered failed and re-issued.
+	upgradeTimeout = 30 * time.Minute
+)
+
+// UpgradePhase describes the progress of an upgrade.
+type UpgradePhase string
+
+// Upgrade phases.
+const (
+	UpgradePhasePending   UpgradePhase = "Pending"
+	UpgradePhaseRequested UpgradePhase = "Requested"
+	UpgradePhaseRebooting UpgradePhase = "Rebooting"
+	UpgradePhaseCompleted UpgradePhase = "Completed"
+	UpgradePhaseFailed    UpgradePhase = "Failed"
+)
+
+// A NoOpService does nothing.
+type NoOpService struct{}
+
+var (
+	newNoOpService = func(_ []byte) (interface{}, error) { return &NoOpService{}, nil }
+)
+
+// Setup adds a controller that reconciles Upgrade managed resources.
+// Talos API clients are borrowed from the supplied cache.
+func Setup(mgr ctrl.Manager, o controller.Options, talosClients *clients.Cache) error {
+	name := managed.ControllerName(v1alpha1.UpgradeGroupKind)
+
+	cps := []managed.ConnectionPublisher{managed.NewAPISecretPublisher(mgr.GetClient(), mgr.GetScheme())}
+	if o.Features.Enabled(features.EnableAlphaExternalSecretStores) {
+		cps = append(cps, connection.NewDetailsManager(mgr.GetClient(), apisv1alpha1.StoreConfigGroupVersionKind))
+	}
+
+	opts := []managed.ReconcilerOption{
+		managed.WithExternalConnecter(&connector{
+			kube:         mgr.GetClient(),
+			usage:        resource.NewProviderConfigUsageTracker(mgr.GetClient(), &apisv1alpha1.ProviderConfigUsage{}),
+			newServiceFn: newNoOpService,
+			talosClients: talosClients}),
+		managed.WithLogger(o.Logger.WithValues("controller", name)),
+		managed.WithPollInterval(o.PollInterval),
+		managed.WithRecorder(event.NewAPIRecorder(mgr.GetEventRecorderFor(name))),
+		managed.WithConnectionPublishers(cps...),
+		managed.WithManagementPolicies(),
+	}
+
+	if o.Features.Enabled(feature.EnableAlphaChangeLogs) {
+		opts = append(opts, managed.WithChangeLogger(o.ChangeLogOptions.ChangeLogger))
+	}
+
+	if o.MetricOptions != nil {
+		opts = append(opts, managed.WithMetricRecorder(o.MetricOptions.MRMetrics))
+	}
+
+	if o.MetricOptions != nil && o.MetricOptions.MRStateMetrics != nil {
+		stateMetricsRecorder := statemetrics.NewMRStateRecorder(
+			mgr.GetClient(), o.Logger, o.MetricOptions.MRStateMetrics, &v1alpha1.UpgradeList{}, o.MetricOptions.PollStateMetricInterval,
+		)
+		if err := mgr.Add(stateMetricsRecorder); err != nil {
+			return errors.Wrap(err, "cannot register MR state metrics recorder for kind v1alpha1.UpgradeList")
+		}
+	}
+
+	r := managed.NewReconciler(mgr, resource.ManagedKind(v1alpha1.UpgradeGroupVersionKind), opts...)
+
+	return ctrl.NewControllerManagedBy(mgr).
+		Named(name).
+		WithOptions(o.ForControllerRuntime()).
+		WithEventFilter(resource.DesiredStateChanged()).
+		For(&v1alpha1.Upgrade{}).
+		Complete(ratelimiter.NewReconciler(name, r, o.GlobalRateLimiter))
+}
+
+// A connector is expected to produce an ExternalClient when its Connect method
+// is called.
+type connector struct {
+	kube         ctrlclient.Client
+	usage        resource.Tracker
+	newServiceFn func(creds []byte) (interface{}, error)
+	talosClients *clients.Cache
+}
+
+// Connect typically produces an ExternalClient by:
+// 1. Tracking that the managed resource is using a ProviderConfig.
+// 2. Getting the managed resource's ProviderConfig.
+// 3. Getting the credentials specified by the ProviderConfig.
+// 4. Using the credentials to form a client.
+func (c *connector) Connect(ctx context.Context, mg resource.Managed) (managed.ExternalClient, error) {
+	cr, ok := mg.(*v1alpha1.Upgrade)
+	if !ok {
+		return nil, errors.New(errNotUpgrade)
+	}
+
+	if err := c.usage.Track(ctx, mg); err != nil {
+		return nil, errors.Wrap(err, errTrackPCUsage)
+	}
+
+	pc := &apisv1alpha1.ProviderConfig{}
+	if err := c.kube.Get(ctx, types.NamespacedName{Name: cr.GetProviderConfigReference().Name}, pc); err != nil {
+		return nil, errors.Wrap(err, errGetPC)
+	}
+
+	cd := pc.Spec.Credentials
+	data, err := resource.CommonCredentialExtractor(ctx, cd.Source, c.kube, cd.CommonCredentialSelectors)
+	if err != nil {
+		return nil, errors.Wrap(err, errGetCreds)
+	}
+
+	svc, err := c.newServiceFn(data)
+	if err != nil {
+		return nil, errors.Wrap(err, errNewClient)
+	}
+
+	factoryURL := ""
+	if pc.Spec.ImageFactory != nil {
+		factoryURL = pc.Spec.ImageFactory.URL
+	}
+
+	return &external{kube: c.kube, service: svc, talosClients: c.talosClients, factory: imagefactory.NewClient(factoryURL)}, nil
+}
+
+// An ExternalClient observes, then either creates, updates, or deletes an
+// external resource to ensure it reflects the managed resource's desired state.
+type external struct {
+	kube ctrlclient.Client
+	// A 'client' used to connect to the external resource API. In practice this
+	// would be something like an AWS SDK client.
+	service interface{}
+	// talosClients shares Talos API clients between reconciles.
+	talosClients *clients.Cache
+	// factory is the Image Factory of the ProviderConfig, used to resolve
+	// installer images of FactorySchematics.
+	factory *imagefactory.Client
+	// newUpgradeClientFn allows tests to stub the Talos version and upgrade APIs.
+	newUpgradeClientFn func(context.Context, *v1alpha1.Upgrade) (upgradeClient, error)
+}
+
+type upgradeClient interface {
+	Version(context.Context, ...grpc.CallOption) (*machine.VersionResponse, error)
+	UpgradeWithOptions(context.Context, ...talosclient.UpgradeOption) (*machine.UpgradeResponse, error)
+	Close() error
+}
+
+// upgradeTarget is the resolved installer image and Talos version of an Upgrade.
+type upgradeTarget struct {
+	image   string
+	version string
+}
+
+func (c *external) Observe(ctx context.Context, mg resource.Managed) (managed.ExternalObservation, error) {
+	cr, ok := mg.(*v1alpha1.Upgrade)
+	if !ok {
+		return managed.ExternalObservation{}, errors.New(errNotUpgrade)
+	}
+
+	fmt.Printf("Observing Upgrade: %s\n", cr.Name)
+
+	// An upgrade cannot be rolled back by deleting the resource.
+	if meta.WasDeleted(cr) {
+		return managed.ExternalObservation{ResourceExists: false}, nil
+	}
+
+	target, err := c.resolveUpgradeTarget(ctx, cr)
+	if err != nil {
+		return managed.ExternalObservation{}, err
+	}
+	cr.Status.AtProvider.TargetVersion = target.version
+
+	requested := cr.Status.AtProvider.UpgradeRequestedTime != nil
+	currentVersion, err := c.getNodeVersion(ctx, cr)
+	if err != nil {
+		if requested && cr.Status.AtProvider.Image == target.image && !upgradeCompleted(cr) {
+			// The node is expected to be unreachable while it reboots into the
+			// new version; wait rather than re-issuing the upgrade.
+			fmt.Printf("Node %s is unreachable during upgrade: %v\n", cr.Spec.ForProvider.Node, err)
+			cr.Status.AtProvider.Phase = string(UpgradePhaseRebooting)
+			cr.SetConditions(xpv1.Unavailable())
+			return managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: true}, nil
+		}
+
+		return managed.ExternalObservation{}, errors.Wrap(err, "failed to get Talos version")
+	}
+	cr.Status.AtProvider.CurrentVersion = currentVersion
+
+	if versionsEqual(currentVersion, target.version) {
+		markCompleted(cr, metav1.Now())
+		fmt.Printf("Upgrade node %s is running target version %s\n", cr.Spec.ForProvider.Node, target.version)
+		return managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: true}, nil
+	}
+
+	exists := requested || hasSuccessfulExternalCreate(cr)
+	if !exists {
+		cr.Status.AtProvider.Phase = string(UpgradePhasePending)
+		cr.SetConditions(xpv1.Unavailable())
+		return managed.ExternalObservation{ResourceExists: false}, nil
+	}
+
+	cr.SetConditions(xpv1.Unavailable())
+	if upgradeInProgress(cr, target, time.Now()) {
+		return managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: true}, nil
+	}
+	if requested && cr.Status.AtProvider.Image == target.image {
+		cr.Status.AtProvider.Phase = string(UpgradePhaseFailed)
+	} else {
+		cr.Status.AtProvider.Phase = string(UpgradePhasePending)
+	}
+
+	fmt.Printf("Upgrade node %s running %s, target %s\n", cr.Spec.ForProvider.Node, currentVersion, target.version)
+
+	return managed.ExternalObservation{
+		ResourceExists:   true,
+		ResourceUpToDate: false,
+		Diff:             fmt.Sprintf("talos version %s does not match target %s", currentVersion, target.version),
+	}, nil
+}
+
+func (c *external) Create(ctx context.Context, mg resource.Managed) (managed.ExternalCreation, error) {
+	cr, ok := mg.(*v1alpha1.Upgrade)
+	if !ok {
+		return managed.ExternalCreation{}, errors.New(errNotUpgrade)
+	}
+
+	if err := c.upgrade(ctx, cr); err != nil {
+		return managed.ExternalCreation{}, err
+	}
+
+	return managed.ExternalCreation{
+		ConnectionDetails: managed.ConnectionDetails{},
+	}, nil
+}
+
+func (c *external) Update(ctx context.Context, mg resource.Managed) (managed.ExternalUpdate, error) {
+	cr, ok := mg.(*v1alpha1.Upgrade)
+	if !ok {
+		return managed.ExternalUpdate{}, errors.New(errNotUpgrade)
+	}
+
+	if err := c.upgrade(ctx, cr); err != nil {
+		return managed.ExternalUpdate{}, err
+	}
+
+	return managed.ExternalUpdate{
+		ConnectionDetails: managed.ConnectionDetails{},
+	}, nil
+}
+
+func (c *external) Delete(ctx context.Context, mg resource.Managed) (managed.ExternalDelete, error) {
+	cr, ok := mg.(*v1alpha1.Upgrade)
+	if !ok {
+		return managed.ExternalDelete{}, errors.New(errNotUpgrade)
+	}
+
+	fmt.Printf("Deleting Upgrade %s leaves node %s on its current version\n", cr.Name, cr.Spec.ForProvider.Node)
+
+	return managed.ExternalDelete{}, nil
+}
+
+func (c *external) Disconnect(ctx context.Context) error {
+	return nil
+}
+
+// upgrade requests a Talos upgrade of the node to the resolved target image.
+func (c *external) upgrade(ctx context.Context, cr *v1alpha1.Upgrade) error {
+	target, err := c.resolveUpgradeTarget(ctx, cr)
+	if err != nil {
+		return err
+	}
+
+	talosClient, err := c.newUpgradeClient(ctx, cr)
+	if err != nil {
+		return errors.Wrap(err, "failed to create Talos client")
+	}
+	defer talosClient.Close() //nolint:errcheck
+
+	fmt.Printf("Upgrading node %s to %s\n", cr.Spec.ForProvider.Node, target.image)
+
+	resp, err := talosClient.UpgradeWithOptions(talosclient.WithNode(ctx, cr.Spec.ForProvider.Node), getUpgradeOptions(cr, target.image)...)
+	if err != nil {
+		return errors.Wrap(err, "failed to upgrade Talos node")
+	}
+
+	now := metav1.Now()
+	cr.Status.AtProvider.Phase = string(UpgradePhaseRequested)
+	cr.Status.AtProvider.Image = target.image
+	cr.Status.AtProvider.TargetVersion = target.version
+	cr.Status.AtProvider.UpgradeRequestedTime = &now
+	cr.Status.AtProvider.UpgradeCompletedTime = nil
+	cr.Status.AtProvider.ActorID = ""
+	for _, message := range resp.GetMessages() {
+		if message.GetActorId() != "" {
+			cr.Status.AtProvider.ActorID = message.GetActorId()
+			break
+		}
+	}
+	cr.SetConditions(xpv1.Unavailable())
+
+	return nil
+}
+
+func getUpgradeOptions(cr *v1alpha1.Upgrade, image string) []talosclient.UpgradeOption {
+	params := cr.Spec.ForProvider
+
+	return []talosclient.UpgradeOption{
+		talosclient.WithUpgradeImage(image),
+		talosclient.WithUpgradeRebootMode(machine.UpgradeRequest_DEFAULT),
+		talosclient.WithUpgradeStage(boolValue(params.Stage)),
+		talosclient.WithUpgradePreserve(boolValue(params.Preserve)),
+		talosclient.WithUpgradeForce(boolValue(params.Force)),
+	}
+}
+
+// resolveUpgradeTarget resolves the installer image and Talos version from
+// either the explicit image or the referenced FactorySchematic.
+func (c *external) resolveUpgradeTarget(ctx context.Context, cr *v1alpha1.Upgrade) (upgradeTarget, error) {
+	params := cr.Spec.ForProvider
+	version := ""
+	if params.TalosVersion != nil {
+		version = *params.TalosVersion
+	}
+
+	image := ""
+	switch {
+	case params.Image != nil && *params.Image != "":
+		image = *params.Image
+	case params.FactorySchematicRef != nil:
+		if version == "" {
+			return upgradeTarget{}, errors.New("talosVersion is required when factorySchematicRef is set")
+		}
+		schematic, err := c.getFactorySchematic(ctx, params.FactorySchematicRef.Name)
+		if err != nil {
+			return upgradeTarget{}, err
+		}
+		image = c.factoryInstallerImage(schematic, version, boolValue(params.SecureBoot))
+	default:
+		return upgradeTarget{}, errors.New("image or factorySchematicRef is required")
+	}
+
+	if version == "" {
+		version = imageTag(image)
+	}
+	if version == "" {
+		return upgradeTarget{}, errors.Errorf("talosVersion is required when image %q has no version tag", image)
+	}
+
+	return upgradeTarget{image: image, version: normalizeVersion(version)}, nil
+}
+
+func (c *external) getFactorySchematic(ctx context.Context, name string) (*imagev1alpha1.FactorySchematic, error) {
+	if c.kube == nil {
+		return nil, errors.New("cannot resolve factorySchematicRef without Kubernetes client")
+	}
+
+	schematic := &imagev1alpha1.FactorySchematic{}
+	if err := c.kube.Get(ctx, types.NamespacedName{Name: name}, schematic); err != nil {
+		return nil, errors.Wrap(err, "cannot get referenced factory schematic")
+	}
+	if schematic.Status.AtProvider.ID == "" {
+		return nil, errors.Errorf("referenced factory schematic %s has no schematic ID yet", name)
+	}
+
+	return schematic, nil
+}
+
+// factoryInstallerImage returns the installer image of a FactorySchematic for
+// a Talos version. The artifacts published by the FactorySchematic are used
+// when they are for that version, so that the installer comes from the
+// factory the schematic was uploaded to. Otherwise the installer is resolved
+// for the schematic's platform and architecture from the Image Factory of the
+// ProviderConfig.
+func (c *external) factoryInstallerImage(schematic *imagev1alpha1.FactorySchematic, version string, secureBoot bool) string {
+	var installer, secureBootInstaller string
+	if a := schematic.Status.AtProvider.Artifacts; a != nil && normalizeVersion(a.TalosVersion) == normalizeVersion(version) {
+		installer, secureBootInstaller = a.InstallerImage, a.SecureBootInstallerImage
+	} else {
+		p := schematic.Spec.ForProvider
+		a := c.factory.Artifacts(schematic.Status.AtProvider.ID, version, ptr.Deref(p.Platform, ""), ptr.Deref(p.Arch, ""))
+		installer, secureBootInstaller = a.InstallerImage, a.SecureBootInstallerImage
+	}
+
+	if secureBoot {
+		return secureBootInstaller
+	}
+
+	return installer
+}
+
+func (c *external) getNodeVersion(ctx context.Context, cr *v1alpha1.Upgrade) (string, error) {
+	checkCtx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	talosClient, err := c.newUpgradeClient(checkCtx, cr)
+	if err != nil {
+		return "", err
+	}
+	defer talosClient.Close() //nolint:errcheck
+
+	resp, err := talosClient.Version(talosclient.WithNode(checkCtx, cr.Spec.ForProvider.Node))
+	if err != nil {
+		return "", err
+	}
+	for _, message := range resp.GetMessages() {
+		if tag := message.GetVersion().GetTag(); tag != "" {
+			return tag, nil
+		}
+	}
+
+	return "", errors.New("node did not report a Talos version")
+}
+
+func (c *external) newUpgradeClient(ctx context.Context, cr *v1alpha1.Upgrade) (upgradeClient, error) {
+	if c.newUpgradeClientFn != nil {
+		return c.newUpgradeClientFn(ctx, cr)
+	}
+
+	clientConfig, err := clients.ResolveClientConfiguration(ctx, c.kube, clients.ClientConfigurationSource{
+		ClientConfiguration: cr.Spec.ForProvider.ClientConfiguration,
+		SecretRef:           cr.Spec.ForProvider.ClientConfigurationSecretRef,
+		SecretsRef:          cr.Spec.ForProvider.SecretsRef,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return c.talosClients.Client(ctx, []string{getUpgradeEndpoint(cr)}, clientConfig)
+}
+
+func hasSuccessfulExternalCreate(cr *v1alpha1.Upgrade) bool {
+	return cr.GetAnnotations()[meta.AnnotationKeyExternalCreateSucceeded] != ""
+}
+
+func upgradeCompleted(cr *v1alpha1.Upgrade) bool {
+	return cr.Status.AtProvider.Phase == string(UpgradePhaseCompleted)
+}
+
+// upgradeInProgress reports whether an upgrade to the target image was already
+// requested and is still expected to finish. Staged upgrades wait for the next
+// reboot indefinitely; other upgrades are retried after upgradeTimeout.
+func upgradeInProgress(cr *v1alpha1.Upgrade, target upgradeTarget, now time.Time) bool {
+	requestedTime := cr.Status.AtProvider.UpgradeRequestedTime
+	if requestedTime == nil || cr.Status.AtProvider.Image != target.image {
+		return false
+	}
+	if cr.Status.AtProvider.Phase == string(UpgradePhaseFailed) {
+		return false
+	}
+	if boolValue(cr.Spec.ForProvider.Stage) {
+		return true
+	}
+
+	return now.Sub(requestedTime.Time) < upgradeTimeout
+}
+
+func markCompleted(cr *v1alpha1.Upgrade, when metav1.Time) {
+	cr.Status.AtProvider.Phase = string(UpgradePhaseCompleted)
+	if cr.Status.AtProvider.UpgradeCompletedTime == nil {
+		cr.Status.AtProvider.UpgradeCompletedTime = &when
+	}
+	cr.SetConditions(xpv1.Available())
+}
+
+// imageTag returns the tag of an image reference, ignoring registry ports and digests.
+func imageTag(image string) string {
+	image = strings.SplitN(image, "@", 2)[0]
+	lastColon := strings.LastIndex(image, ":")
+	if lastColon < 0 || lastColon < strings.LastIndex(image, "/") {
+		return ""
+	}
+
+	return image[lastColon+1:]
+}
+
+func normalizeVersion(version string) string {
+	return "v" + strings.TrimPrefix(strings.TrimSpace(version), "v")
+}
+
+func versionsEqual(a, b string) bool {
+	return normalizeVersion(a) == normalizeVersion(b)
+}
+
+func boolValue(v *bool) bool {
+	return v != nil && *v
+}
+
+func getUpgradeEndpoint(cr *v1alpha1.Upgrade) string {
+	endpoint := cr.Spec.ForProvider.Node + ":50000"
+	if cr.Spec.ForProvider.Endpoint != nil && *cr.Spec.ForProvider.Endpoint != "" {
+		endpoint = *cr.Spec.ForProvider.Endpoint
+	}
+
+	return endpoint
+}
@@ -0,0 +1,424 @@
+/*
+Copyright 2025 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package upgrade
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	machineapi "github.com/siderolabs/talos/pkg/machinery/api/machine"
+	talosclient "github.com/siderolabs/talos/pkg/machinery/client"
+	"google.golang.org/grpc"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	imagev1alpha1 "github.com/crossplane-contrib/provider-talos/apis/image/v1alpha1"
+	"github.com/crossplane-contrib/provider-talos/apis/machine/v1alpha1"
+	"github.com/crossplane-contrib/provider-talos/internal/imagefactory"
+)
+
+// Unlike many Kubernetes projects Crossplane does not use third party testing
+// libraries, per the common Go test review comments. Crossplane encourages the
+// use of table driven unit tests. The tests of the crossplane-runtime project
+// are representative of the testing style Crossplane encourages.
+//
+// https://github.com/golang/go/wiki/TestComments
+// https://github.com/crossplane/crossplane/blob/master/CONTRIBUTING.md#contributing-code
+
+type fakeUpgradeClient struct {
+	version    string
+	versionErr error
+	upgradeErr error
+	upgrades   []*machineapi.UpgradeRequest
+}
+
+func (f *fakeUpgradeClient) Version(context.Context, ...grpc.CallOption) (*machineapi.VersionResponse, error) {
+	if f.versionErr != nil {
+		return nil, f.versionErr
+	}
+
+	return &machineapi.VersionResponse{Messages: []*machineapi.Version{{Version: &machineapi.VersionInfo{Tag: f.version}}}}, nil
+}
+
+func (f *fakeUpgradeClient) UpgradeWithOptions(_ context.Context, opts ...talosclient.UpgradeOption) (*machineapi.UpgradeResponse, error) {
+	if f.upgradeErr != nil {
+		return nil, f.upgradeErr
+	}
+
+	var options talosclient.UpgradeOptions
+	for _, opt := range opts {
+		opt(&options)
+	}
+	f.upgrades = append(f.upgrades, &machineapi.UpgradeRequest{
+		Image:    options.Request.Image,
+		Stage:    options.Request.Stage,
+		Preserve: options.Request.Preserve,
+		Force:    options.Request.Force,
+	})
+
+	return &machineapi.UpgradeResponse{Messages: []*machineapi.Upgrade{{Ack: "Upgrade request received", ActorId: "actor-1"}}}, nil
+}
+
+func (f *fakeUpgradeClient) Close() error {
+	return nil
+}
+
+func testUpgrade(image string) *v1alpha1.Upgrade {
+	return &v1alpha1.Upgrade{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-upgrade"},
+		Spec: v1alpha1.UpgradeSpec{
+			ForProvider: v1alpha1.UpgradeParameters{
+				Node:  "192.168.1.100",
+				Image: &image,
+				ClientConfiguration: &v1alpha1.ClientConfiguration{
+					CACertificate:     "ca",
+					ClientCertificate: "crt",
+					ClientKey:         "key",
+				},
+			},
+		},
+	}
+}
+
+func newTestExternal(fc *fakeUpgradeClient) *external {
+	return &external{
+		newUpgradeClientFn: func(context.Context, *v1alpha1.Upgrade) (upgradeClient, error) {
+			return fc, nil
+		},
+	}
+}
+
+func TestObserve(t *testing.T) {
+	recent := metav1.NewTime(time.Now().Add(-time.Minute))
+	stale := metav1.NewTime(time.Now().Add(-2 * upgradeTimeout))
+
+	type want struct {
+		exists   bool
+		upToDate bool
+		phase    UpgradePhase
+		err      bool
+	}
+
+	cases := map[string]struct {
+		reason string
+		client *fakeUpgradeClient
+		mg     func() *v1alpha1.Upgrade
+		want   want
+	}{
+		"AlreadyAtTarget": {
+			reason: "A node already running the target version should be complete.",
+			client: &fakeUpgradeClient{version: "v1.11.0"},
+			mg: func() *v1alpha1.Upgrade {
+				return testUpgrade("ghcr.io/siderolabs/installer:v1.11.0")
+			},
+			want: want{exists: true, upToDate: true, phase: UpgradePhaseCompleted},
+		},
+		"NotYetRequested": {
+			reason: "A node on an older version without a prior request should trigger Create.",
+			client: &fakeUpgradeClient{version: "v1.10.6"},
+			mg: func() *v1alpha1.Upgrade {
+				return testUpgrade("ghcr.io/siderolabs/installer:v1.11.0")
+			},
+			want: want{exists: false, phase: UpgradePhasePending},
+		},
+		"RebootingAfterRequest": {
+			reason: "An unreachable node after an upgrade request should be treated as rebooting.",
+			client: &fakeUpgradeClient{versionErr: errors.New("connection refused")},
+			mg: func() *v1alpha1.Upgrade {
+				cr := testUpgrade("ghcr.io/siderolabs/installer:v1.11.0")
+				cr.Status.AtProvider.Phase = string(UpgradePhaseRequested)
+				cr.Status.AtProvider.Image = "ghcr.io/siderolabs/installer:v1.11.0"
+				cr.Status.AtProvider.UpgradeRequestedTime = &recent
+				return cr
+			},
+			want: want{exists: true, upToDate: true, phase: UpgradePhaseRebooting},
+		},
+		"UnreachableWithoutRequest": {
+			reason: "An unreachable node without an upgrade request should return an error.",
+			client: &fakeUpgradeClient{versionErr: errors.New("connection refused")},
+			mg: func() *v1alpha1.Upgrade {
+				return testUpgrade("ghcr.io/siderolabs/installer:v1.11.0")
+			},
+			want: want{err: true},
+		},
+		"WaitingForRequestedUpgrade": {
+			reason: "A recent request for the same image should not be re-issued.",
+			client: &fakeUpgradeClient{version: "v1.10.6"},
+			mg: func() *v1alpha1.Upgrade {
+				cr := testUpgrade("ghcr.io/siderolabs/installer:v1.11.0")
+				cr.Status.AtProvider.Phase = string(UpgradePhaseRequested)
+				cr.Status.AtProvider.Image = "ghcr.io/siderolabs/installer:v1.11.0"
+				cr.Status.AtProvider.UpgradeRequestedTime = &recent
+				return cr
+			},
+			want: want{exists: true, upToDate: true, phase: UpgradePhaseRequested},
+		},
+		"TimedOutRequest": {
+			reason: "A stale request for the same image should be marked failed and re-issued.",
+			client: &fakeUpgradeClient{version: "v1.10.6"},
+			mg: func() *v1alpha1.Upgrade {
+				cr := testUpgrade("ghcr.io/siderolabs/installer:v1.11.0")
+				cr.Status.AtProvider.Phase = string(UpgradePhaseRequested)
+				cr.Status.AtProvider.Image = "ghcr.io/siderolabs/installer:v1.11.0"
+				cr.Status.AtProvider.UpgradeRequestedTime = &stale
+				return cr
+			},
+			want: want{exists: true, upToDate: false, phase: UpgradePhaseFailed},
+		},
+		"TargetChanged": {
+			reason: "A new target image after a completed upgrade should trigger Update.",
+			client: &fakeUpgradeClient{version: "v1.11.0"},
+			mg: func() *v1alpha1.Upgrade {
+				cr := testUpgrade("ghcr.io/siderolabs/installer:v1.11.1")
+				cr.Status.AtProvider.Phase = string(UpgradePhaseCompleted)
+				cr.Status.AtProvider.Image = "ghcr.io/siderolabs/installer:v1.11.0"
+				cr.Status.AtProvider.UpgradeRequestedTime = &recent
+				return cr
+			},
+			want: want{exists: true, upToDate: false, phase: UpgradePhasePending},
+		},
+		"Deleted": {
+			reason: "A deleted Upgrade should not touch the node.",
+			client: &fakeUpgradeClient{versionErr: errors.New("should not be called")},
+			mg: func() *v1alpha1.Upgrade {
+				cr := testUpgrade("ghcr.io/siderolabs/installer:v1.11.0")
+				meta.SetExternalName(cr, cr.Name)
+				now := metav1.Now()
+				cr.SetDeletionTimestamp(&now)
+				return cr
+			},
+			want: want{exists: false},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			cr := tc.mg()
+			got, err := newTestExternal(tc.client).Observe(context.Background(), cr)
+			if tc.want.err {
+				if err == nil {
+					t.Fatalf("\n%s\ne.Observe(...): expected error", tc.reason)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("\n%s\ne.Observe(...): unexpected error: %v", tc.reason, err)
+			}
+			if got.ResourceExists != tc.want.exists || got.ResourceUpToDate != tc.want.upToDate {
+				t.Errorf("\n%s\ne.Observe(...): got exists=%v upToDate=%v, want exists=%v upToDate=%v", tc.reason, got.ResourceExists, got.ResourceUpToDate, tc.want.exists, tc.want.upToDate)
+			}
+			if diff := cmp.Diff(string(tc.want.phase), cr.Status.AtProvider.Phase); diff != "" {
+				t.Errorf("\n%s\ne.Observe(...): -want phase, +got phase:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestObserveMarksCompletedAfterRequest(t *testing.T) {
+	requested := metav1.NewTime(time.Now().Add(-time.Minute))
+	cr := testUpgrade("ghcr.io/siderolabs/installer:v1.11.0")
+	cr.Status.AtProvider.Phase = string(UpgradePhaseRebooting)
+	cr.Status.AtProvider.Image = "ghcr.io/siderolabs/installer:v1.11.0"
+	cr.Status.AtProvider.UpgradeRequestedTime = &requested
+
+	if _, err := newTestExternal(&fakeUpgradeClient{version: "v1.11.0"}).Observe(context.Background(), cr); err != nil {
+		t.Fatalf("Observe(...): unexpected error: %v", err)
+	}
+	if cr.Status.AtProvider.UpgradeCompletedTime == nil {
+		t.Fatal("expected upgradeCompletedTime to be set")
+	}
+	if cr.Status.AtProvider.CurrentVersion != "v1.11.0" {
+		t.Errorf("expected currentVersion v1.11.0, got %q", cr.Status.AtProvider.CurrentVersion)
+	}
+	if cond := cr.GetCondition(xpv1.TypeReady); cond.Reason != xpv1.ReasonAvailable {
+		t.Errorf("expected Available condition, got %q", cond.Reason)
+	}
+}
+
+func TestCreateRequestsUpgradeWithOptions(t *testing.T) {
+	cr := testUpgrade("ghcr.io/siderolabs/installer:v1.11.0")
+	stage, preserve, force := true, true, false
+	cr.Spec.ForProvider.Stage = &stage
+	cr.Spec.ForProvider.Preserve = &preserve
+	cr.Spec.ForProvider.Force = &force
+
+	fc := &fakeUpgradeClient{version: "v1.10.6"}
+	if _, err := newTestExternal(fc).Create(context.Background(), cr); err != nil {
+		t.Fatalf("Create(...): unexpected error: %v", err)
+	}
+
+	if len(fc.upgrades) != 1 {
+		t.Fatalf("expected one upgrade request, got %d", len(fc.upgrades))
+	}
+	got := fc.upgrades[0]
+	if got.Image != "ghcr.io/siderolabs/installer:v1.11.0" || !got.Stage || !got.Preserve || got.Force {
+		t.Errorf("unexpected upgrade request: image=%s stage=%v preserve=%v force=%v", got.Image, got.Stage, got.Preserve, got.Force)
+	}
+	if cr.Status.AtProvider.Phase != string(UpgradePhaseRequested) {
+		t.Errorf("expected phase %s, got %s", UpgradePhaseRequested, cr.Status.AtProvider.Phase)
+	}
+	if cr.Status.AtProvider.UpgradeRequestedTime == nil {
+		t.Error("expected upgradeRequestedTime to be set")
+	}
+	if cr.Status.AtProvider.ActorID != "actor-1" {
+		t.Errorf("expected actorID actor-1, got %q", cr.Status.AtProvider.ActorID)
+	}
+}
+
+func TestCreateReturnsUpgradeError(t *testing.T) {
+	cr := testUpgrade("ghcr.io/siderolabs/installer:v1.11.0")
+	fc := &fakeUpgradeClient{upgradeErr: errors.New("upgrade rejected")}
+
+	if _, err := newTestExternal(fc).Create(context.Background(), cr); err == nil {
+		t.Fatal("Create(...): expected error")
+	}
+	if cr.Status.AtProvider.UpgradeRequestedTime != nil {
+		t.Error("expected upgradeRequestedTime to remain unset")
+	}
+}
+
+func TestResolveUpgradeTarget(t *testing.T) {
+	scheme := runtime.NewScheme()
+	if err := imagev1alpha1.SchemeBuilder.AddToScheme(scheme); err != nil {
+		t.Fatalf("AddToScheme(...): %v", err)
+	}
+	schematic := &imagev1alpha1.FactorySchematic{
+		ObjectMeta: metav1.ObjectMeta{Name: "custom"},
+		Status: imagev1alpha1.FactorySchematicStatus{
+			AtProvider: imagev1alpha1.FactorySchematicObservation{ID: "376567988ad370138ad8b2698212367b8edcb69b5fd68c80be1f2ec7d603b4ba"},
+		},
+	}
+	pending := &imagev1alpha1.FactorySchematic{ObjectMeta: metav1.ObjectMeta{Name: "pending"}}
+	aws := "aws"
+	cloud := &imagev1alpha1.FactorySchematic{
+		ObjectMeta: metav1.ObjectMeta{Name: "cloud"},
+		Spec: imagev1alpha1.FactorySchematicSpec{
+			ForProvider: imagev1alpha1.FactorySchematicParameters{Platform: &aws},
+		},
+		Status: imagev1alpha1.FactorySchematicStatus{
+			AtProvider: imagev1alpha1.FactorySchematicObservation{ID: "abc"},
+		},
+	}
+	published := &imagev1alpha1.FactorySchematic{
+		ObjectMeta: metav1.ObjectMeta{Name: "published"},
+		Status: imagev1alpha1.FactorySchematicStatus{
+			AtProvider: imagev1alpha1.FactorySchematicObservation{
+				ID: "abc",
+				Artifacts: &imagev1alpha1.FactorySchematicArtifacts{
+					TalosVersion:             "v1.11.0",
+					InstallerImage:           "factory.example.com/installer/abc:v1.11.0",
+					SecureBootInstallerImage: "factory.example.com/installer-secureboot/abc:v1.11.0",
+				},
+			},
+		},
+	}
+	kube := fake.NewClientBuilder().WithScheme(scheme).WithObjects(schematic, pending, cloud, published).Build()
+
+	str := func(s string) *string { return &s }
+	secureBoot := true
+
+	cases := map[string]struct {
+		reason string
+		params v1alpha1.UpgradeParameters
+		want   upgradeTarget
+		err    bool
+	}{
+		"ImageTag": {
+			reason: "The target version should default to the image tag.",
+			params: v1alpha1.UpgradeParameters{Image: str("ghcr.io/siderolabs/installer:v1.11.0")},
+			want:   upgradeTarget{image: "ghcr.io/siderolabs/installer:v1.11.0", version: "v1.11.0"},
+		},
+		"RegistryPortWithoutTag": {
+			reason: "A registry port is not a version tag.",
+			params: v1alpha1.UpgradeParameters{Image: str("registry.local:5000/installer")},
+			err:    true,
+		},
+		"ExplicitVersion": {
+			reason: "An explicit talosVersion should override the image tag.",
+			params: v1alpha1.UpgradeParameters{Image: str("registry.local:5000/installer@sha256:abc"), TalosVersion: str("1.11.0")},
+			want:   upgradeTarget{image: "registry.local:5000/installer@sha256:abc", version: "v1.11.0"},
+		},
+		"FactorySchematic": {
+			reason: "A FactorySchematic reference should resolve to the Image Factory installer.",
+			params: v1alpha1.UpgradeParameters{FactorySchematicRef: &xpv1.Reference{Name: "custom"}, TalosVersion: str("v1.11.0")},
+			want: upgradeTarget{
+				image:   "factory.talos.dev/installer/376567988ad370138ad8b2698212367b8edcb69b5fd68c80be1f2ec7d603b4ba:v1.11.0",
+				version: "v1.11.0",
+			},
+		},
+		"FactorySchematicPlatform": {
+			reason: "A FactorySchematic for a cloud platform should resolve to the platform installer.",
+			params: v1alpha1.UpgradeParameters{FactorySchematicRef: &xpv1.Reference{Name: "cloud"}, TalosVersion: str("v1.11.0")},
+			want:   upgradeTarget{image: "factory.talos.dev/aws-installer/abc:v1.11.0", version: "v1.11.0"},
+		},
+		"FactorySchematicSecureBoot": {
+			reason: "secureBoot should select the SecureBoot installer of the FactorySchematic.",
+			params: v1alpha1.UpgradeParameters{FactorySchematicRef: &xpv1.Reference{Name: "cloud"}, TalosVersion: str("v1.11.0"), SecureBoot: &secureBoot},
+			want:   upgradeTarget{image: "factory.talos.dev/aws-installer-secureboot/abc:v1.11.0", version: "v1.11.0"},
+		},
+		"FactorySchematicPublishedArtifacts": {
+			reason: "Artifacts published by the FactorySchematic for the target version should be used.",
+			params: v1alpha1.UpgradeParameters{FactorySchematicRef: &xpv1.Reference{Name: "published"}, TalosVersion: str("1.11.0"), SecureBoot: &secureBoot},
+			want:   upgradeTarget{image: "factory.example.com/installer-secureboot/abc:v1.11.0", version: "v1.11.0"},
+		},
+		"FactorySchematicPublishedArtifactsOtherVersion": {
+			reason: "Artifacts published for another version should not be used.",
+			params: v1alpha1.UpgradeParameters{FactorySchematicRef: &xpv1.Reference{Name: "published"}, TalosVersion: str("v1.11.1")},
+			want:   upgradeTarget{image: "factory.talos.dev/installer/abc:v1.11.1", version: "v1.11.1"},
+		},
+		"FactorySchematicWithoutID": {
+			reason: "A FactorySchematic without an ID cannot be resolved yet.",
+			params: v1alpha1.UpgradeParameters{FactorySchematicRef: &xpv1.Reference{Name: "pending"}, TalosVersion: str("v1.11.0")},
+			err:    true,
+		},
+		"FactorySchematicWithoutVersion": {
+			reason: "A FactorySchematic reference requires talosVersion.",
+			params: v1alpha1.UpgradeParameters{FactorySchematicRef: &xpv1.Reference{Name: "custom"}},
+			err:    true,
+		},
+		"NoImage": {
+			reason: "Either image or factorySchematicRef is required.",
+			params: v1alpha1.UpgradeParameters{},
+			err:    true,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			cr := &v1alpha1.Upgrade{Spec: v1alpha1.UpgradeSpec{ForProvider: tc.params}}
+			got, err := (&external{kube: kube, factory: imagefactory.NewClient("")}).resolveUpgradeTarget(context.Background(), cr)
+			if tc.err {
+				if err == nil {
+					t.Fatalf("\n%s\nresolveUpgradeTarget(...): expected error", tc.reason)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("\n%s\nresolveUpgradeTarget(...): unexpected error: %v", tc.reason, err)
+			}
+			if diff := cmp.Diff(tc.want, got, cmp.AllowUnexported(upgradeTarget{})); diff != "" {
+				t.Errorf("\n%s\nresolveUpgradeTarget(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
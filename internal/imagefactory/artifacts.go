@@ -0,0 +1,77 @@
+/*
+Copyright 2025 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package imagefactory
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// DefaultPlatform and DefaultArch select metal amd64 artifacts.
+const (
+	DefaultPlatform = "metal"
+	DefaultArch     = "amd64"
+)
+
+// Artifacts are references to the images the Image Factory builds for a
+// schematic.
+type Artifacts struct {
+	InstallerImage           string
+	SecureBootInstallerImage string
+	ISO                      string
+	DiskImage                string
+	Kernel                   string
+	Initramfs                string
+}
+
+// Artifacts returns the references to the artifacts of a schematic for a
+// Talos version, platform and architecture. The installer images are served
+// by the registry on the Image Factory's host.
+func (c *Client) Artifacts(id, talosVersion, platform, arch string) Artifacts {
+	if platform == "" {
+		platform = DefaultPlatform
+	}
+	if arch == "" {
+		arch = DefaultArch
+	}
+	version := "v" + strings.TrimPrefix(strings.TrimSpace(talosVersion), "v")
+
+	registry := c.baseURL
+	if u, err := url.Parse(c.baseURL); err == nil && u.Host != "" {
+		registry = u.Host + strings.TrimSuffix(u.Path, "/")
+	}
+
+	// The metal installer keeps the unprefixed repository name.
+	installer := "installer"
+	if platform != DefaultPlatform {
+		installer = platform + "-installer"
+	}
+
+	image := func(file string) string {
+		return fmt.Sprintf("%s/image/%s/%s/%s", c.baseURL, id, version, file)
+	}
+
+	return Artifacts{
+		InstallerImage:           fmt.Sprintf("%s/%s/%s:%s", registry, installer, id, version),
+		SecureBootInstallerImage: fmt.Sprintf("%s/%s-secureboot/%s:%s", registry, installer, id, version),
+		ISO:                      image(fmt.Sprintf("metal-%s.iso", arch)),
+		DiskImage:                image(fmt.Sprintf("%s-%s.raw.xz", platform, arch)),
+		Kernel:                   image("kernel-" + arch),
+		Initramfs:                image(fmt.Sprintf("initramfs-%s.xz", arch)),
+	}
+}
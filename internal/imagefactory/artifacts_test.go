@@ -0,0 +1,68 @@
+/*
+Copyright 2025 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package imagefactory
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestArtifacts(t *testing.T) {
+	tests := map[string]struct {
+		baseURL  string
+		version  string
+		platform string
+		arch     string
+		want     Artifacts
+	}{
+		"MetalDefaults": {
+			version: "v1.11.0",
+			want: Artifacts{
+				InstallerImage:           "factory.talos.dev/installer/abc:v1.11.0",
+				SecureBootInstallerImage: "factory.talos.dev/installer-secureboot/abc:v1.11.0",
+				ISO:                      "https://factory.talos.dev/image/abc/v1.11.0/metal-amd64.iso",
+				DiskImage:                "https://factory.talos.dev/image/abc/v1.11.0/metal-amd64.raw.xz",
+				Kernel:                   "https://factory.talos.dev/image/abc/v1.11.0/kernel-amd64",
+				Initramfs:                "https://factory.talos.dev/image/abc/v1.11.0/initramfs-amd64.xz",
+			},
+		},
+		"SelfHostedCloudPlatform": {
+			baseURL:  "https://factory.example.com:8443/",
+			version:  "1.11.0",
+			platform: "aws",
+			arch:     "arm64",
+			want: Artifacts{
+				InstallerImage:           "factory.example.com:8443/aws-installer/abc:v1.11.0",
+				SecureBootInstallerImage: "factory.example.com:8443/aws-installer-secureboot/abc:v1.11.0",
+				ISO:                      "https://factory.example.com:8443/image/abc/v1.11.0/metal-arm64.iso",
+				DiskImage:                "https://factory.example.com:8443/image/abc/v1.11.0/aws-arm64.raw.xz",
+				Kernel:                   "https://factory.example.com:8443/image/abc/v1.11.0/kernel-arm64",
+				Initramfs:                "https://factory.example.com:8443/image/abc/v1.11.0/initramfs-arm64.xz",
+			},
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			got := NewClient(tc.baseURL).Artifacts("abc", tc.version, tc.platform, tc.arch)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("Artifacts(...): -want, +got:\n%s", diff)
+			}
+		})
+	}
+}
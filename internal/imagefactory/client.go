@@ -0,0 +1,91 @@
+/*
+Copyright 2025 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package imagefactory
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// DefaultURL is the base URL of the public Talos Image Factory.
+const DefaultURL = "https://factory.talos.dev"
+
+const maxErrorBody = 4096
+
+// A Client talks to the HTTP API of an Image Factory.
+type Client struct {
+	baseURL string
+	http    *http.Client
+}
+
+// NewClient returns a Client for the Image Factory at baseURL, or at
+// DefaultURL if baseURL is empty.
+func NewClient(baseURL string) *Client {
+	if baseURL == "" {
+		baseURL = DefaultURL
+	}
+
+	return &Client{
+		baseURL: strings.TrimSuffix(baseURL, "/"),
+		http:    &http.Client{Timeout: 30 * time.Second},
+	}
+}
+
+// CreateSchematic uploads the schematic to the Image Factory and returns the
+// ID it was assigned. Uploading an existing schematic returns its ID.
+func (c *Client) CreateSchematic(ctx context.Context, s *Schematic) (string, error) {
+	body, err := s.Marshal()
+	if err != nil {
+		return "", errors.Wrap(err, "cannot marshal schematic")
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.baseURL+"/schematics", bytes.NewReader(body))
+	if err != nil {
+		return "", errors.Wrap(err, "cannot build schematic request")
+	}
+	req.Header.Set("Content-Type", "application/yaml")
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return "", errors.Wrap(err, "cannot upload schematic")
+	}
+	defer resp.Body.Close() //nolint:errcheck
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, maxErrorBody))
+		return "", errors.Errorf("image factory rejected schematic: %s: %s", resp.Status, strings.TrimSpace(string(msg)))
+	}
+
+	var created struct {
+		ID string `json:"id"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&created); err != nil {
+		return "", errors.Wrap(err, "cannot decode schematic response")
+	}
+	if created.ID == "" {
+		return "", errors.New("image factory returned no schematic ID")
+	}
+
+	return created.ID, nil
+}
@@ -0,0 +1,83 @@
+/*
+Copyright 2025 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package imagefactory
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestCreateSchematic(t *testing.T) {
+	tests := map[string]struct {
+		handler http.HandlerFunc
+		want    string
+		wantErr bool
+	}{
+		"Created": {
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				if r.Method != http.MethodPost || r.URL.Path != "/schematics" {
+					http.NotFound(w, r)
+					return
+				}
+				body, _ := io.ReadAll(r.Body)
+				if string(body) != "customization: {}\n" {
+					http.Error(w, "unexpected schematic "+string(body), http.StatusBadRequest)
+					return
+				}
+				w.WriteHeader(http.StatusCreated)
+				w.Write([]byte(`{"id":"` + vanillaID + `"}`)) //nolint:errcheck
+			},
+			want: vanillaID,
+		},
+		"Rejected": {
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				http.Error(w, "unknown extension", http.StatusBadRequest)
+			},
+			wantErr: true,
+		},
+		"NoID": {
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				w.Write([]byte(`{}`)) //nolint:errcheck
+			},
+			wantErr: true,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			srv := httptest.NewServer(tc.handler)
+			defer srv.Close()
+
+			got, err := NewClient(srv.URL+"/").CreateSchematic(context.Background(), &Schematic{})
+			if tc.wantErr {
+				if err == nil {
+					t.Fatal("CreateSchematic(...): expected error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("CreateSchematic(...): unexpected error: %v", err)
+			}
+			if got != tc.want {
+				t.Errorf("CreateSchematic(...): got %q, want %q", got, tc.want)
+			}
+		})
+	}
+}
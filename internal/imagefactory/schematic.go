@@ -0,0 +1,147 @@
+/*
+Copyright 2025 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package imagefactory creates and identifies Talos image schematics through
+// the Talos Image Factory.
+package imagefactory
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"regexp"
+	"strings"
+
+	"github.com/pkg/errors"
+	"gopkg.in/yaml.v3"
+)
+
+// officialExtension matches the name of an official system extension.
+var officialExtension = regexp.MustCompile(`^siderolabs/[a-z0-9]([a-z0-9-]*[a-z0-9])?$`)
+
+// A Schematic describes a customized Talos image. Its fields and their order
+// mirror the Image Factory, so that a marshaled Schematic hashes to the ID the
+// Image Factory assigns to it.
+type Schematic struct {
+	// Overlay selects an overlay for single-board computers.
+	Overlay Overlay `yaml:"overlay,omitempty"`
+	// Customization customizes the Talos image.
+	Customization Customization `yaml:"customization"`
+}
+
+// An Overlay selects an overlay image and its options.
+type Overlay struct {
+	Image   string         `yaml:"image,omitempty"`
+	Name    string         `yaml:"name,omitempty"`
+	Options map[string]any `yaml:"options,omitempty"`
+}
+
+// Customization customizes the Talos image.
+type Customization struct {
+	ExtraKernelArgs  []string                `yaml:"extraKernelArgs,omitempty"`
+	Meta             []MetaValue             `yaml:"meta,omitempty"`
+	SystemExtensions SystemExtensions        `yaml:"systemExtensions,omitempty"`
+	SecureBoot       SecureBootCustomization `yaml:"secureboot,omitempty"`
+}
+
+// A MetaValue is written to the META partition of the image.
+type MetaValue struct {
+	Key   uint8  `yaml:"key"`
+	Value string `yaml:"value"`
+}
+
+// SystemExtensions lists the system extensions included in the image.
+type SystemExtensions struct {
+	OfficialExtensions []string `yaml:"officialExtensions,omitempty"`
+}
+
+// SecureBootCustomization customizes SecureBoot images.
+type SecureBootCustomization struct {
+	IncludeWellKnownCertificates bool `yaml:"includeWellKnownCertificates,omitempty"`
+}
+
+// ParseSchematic parses a schematic YAML document. Unknown fields are
+// rejected, as they are by the Image Factory. An empty document is the
+// vanilla schematic.
+func ParseSchematic(data []byte) (*Schematic, error) {
+	s := &Schematic{}
+
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	dec.KnownFields(true)
+	if err := dec.Decode(s); err != nil && !errors.Is(err, io.EOF) {
+		return nil, errors.Wrap(err, "cannot parse schematic")
+	}
+
+	return s, nil
+}
+
+// Validate reports whether the schematic is well-formed: official extensions
+// are named siderolabs/<name> and listed once, META keys are unique, kernel
+// arguments contain no whitespace and an overlay has both a name and an image.
+func (s *Schematic) Validate() error {
+	extensions := map[string]bool{}
+	for _, name := range s.Customization.SystemExtensions.OfficialExtensions {
+		if !officialExtension.MatchString(name) {
+			return errors.Errorf("invalid official extension name %q: expected siderolabs/<name>", name)
+		}
+		if extensions[name] {
+			return errors.Errorf("official extension %q is listed more than once", name)
+		}
+		extensions[name] = true
+	}
+
+	for _, arg := range s.Customization.ExtraKernelArgs {
+		if arg == "" || strings.ContainsAny(arg, " \t\n") {
+			return errors.Errorf("invalid kernel argument %q", arg)
+		}
+	}
+
+	keys := map[uint8]bool{}
+	for _, m := range s.Customization.Meta {
+		if keys[m.Key] {
+			return errors.Errorf("META key %#x is set more than once", m.Key)
+		}
+		keys[m.Key] = true
+	}
+
+	if (s.Overlay.Name == "") != (s.Overlay.Image == "") {
+		return errors.New("overlay requires both a name and an image")
+	}
+	if len(s.Overlay.Options) > 0 && s.Overlay.Name == "" {
+		return errors.New("overlay options require an overlay name and image")
+	}
+
+	return nil
+}
+
+// Marshal returns the canonical YAML form of the schematic.
+func (s *Schematic) Marshal() ([]byte, error) {
+	return yaml.Marshal(s)
+}
+
+// ID returns the content-addressed ID of the schematic: the SHA-256 of its
+// canonical YAML form.
+func (s *Schematic) ID() (string, error) {
+	data, err := s.Marshal()
+	if err != nil {
+		return "", errors.Wrap(err, "cannot marshal schematic")
+	}
+
+	hash := sha256.Sum256(data)
+
+	return hex.EncodeToString(hash[:]), nil
+}
@@ -0,0 +1,131 @@
+/*
+Copyright 2025 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package imagefactory
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"testing"
+)
+
+// vanillaID is the ID the Image Factory assigns to the empty schematic.
+const vanillaID = "376567988ad370138ad8b2698212367b8edcb69b5fd68c80be1f2ec7d603b4ba"
+
+func TestSchematicID(t *testing.T) {
+	sum := func(s string) string {
+		hash := sha256.Sum256([]byte(s))
+		return hex.EncodeToString(hash[:])
+	}
+
+	tests := map[string]struct {
+		schematic string
+		want      string
+		wantErr   bool
+	}{
+		"Empty": {
+			schematic: "",
+			want:      vanillaID,
+		},
+		"EmptyCustomization": {
+			schematic: "customization: {}\n",
+			want:      vanillaID,
+		},
+		"NormalizesFormatting": {
+			schematic: "customization:\n  extraKernelArgs: [net.ifnames=0]\n  meta:\n  - {key: 0xa, value: production}\n",
+			want:      sum("customization:\n    extraKernelArgs:\n        - net.ifnames=0\n    meta:\n        - key: 10\n          value: production\n"),
+		},
+		"OverlayBeforeCustomization": {
+			schematic: "customization:\n  systemExtensions:\n    officialExtensions: [siderolabs/gvisor]\noverlay:\n  name: rpi_generic\n  image: siderolabs/sbc-raspberrypi\n",
+			want:      sum("overlay:\n    image: siderolabs/sbc-raspberrypi\n    name: rpi_generic\ncustomization:\n    systemExtensions:\n        officialExtensions:\n            - siderolabs/gvisor\n"),
+		},
+		"UnknownField": {
+			schematic: "customization:\n  systemExtension: {}\n",
+			wantErr:   true,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			s, err := ParseSchematic([]byte(tc.schematic))
+			if tc.wantErr {
+				if err == nil {
+					t.Fatal("ParseSchematic(...): expected error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseSchematic(...): unexpected error: %v", err)
+			}
+
+			got, err := s.ID()
+			if err != nil {
+				t.Fatalf("s.ID(): unexpected error: %v", err)
+			}
+			if got != tc.want {
+				t.Errorf("s.ID(): got %q, want %q", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestSchematicValidate(t *testing.T) {
+	tests := map[string]struct {
+		schematic Schematic
+		wantErr   bool
+	}{
+		"Vanilla": {},
+		"Valid": {
+			schematic: Schematic{
+				Overlay: Overlay{Name: "rpi_generic", Image: "siderolabs/sbc-raspberrypi"},
+				Customization: Customization{
+					ExtraKernelArgs:  []string{"console=ttyS0"},
+					Meta:             []MetaValue{{Key: 1, Value: "a"}, {Key: 2, Value: "b"}},
+					SystemExtensions: SystemExtensions{OfficialExtensions: []string{"siderolabs/gvisor"}},
+				},
+			},
+		},
+		"ExtensionWithoutPrefix": {
+			schematic: Schematic{Customization: Customization{SystemExtensions: SystemExtensions{OfficialExtensions: []string{"gvisor"}}}},
+			wantErr:   true,
+		},
+		"DuplicateExtension": {
+			schematic: Schematic{Customization: Customization{SystemExtensions: SystemExtensions{OfficialExtensions: []string{"siderolabs/gvisor", "siderolabs/gvisor"}}}},
+			wantErr:   true,
+		},
+		"KernelArgWithSpace": {
+			schematic: Schematic{Customization: Customization{ExtraKernelArgs: []string{"console=ttyS0 quiet"}}},
+			wantErr:   true,
+		},
+		"DuplicateMetaKey": {
+			schematic: Schematic{Customization: Customization{Meta: []MetaValue{{Key: 1, Value: "a"}, {Key: 1, Value: "b"}}}},
+			wantErr:   true,
+		},
+		"OverlayWithoutImage": {
+			schematic: Schematic{Overlay: Overlay{Name: "rpi_generic"}},
+			wantErr:   true,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			err := tc.schematic.Validate()
+			if tc.wantErr != (err != nil) {
+				t.Errorf("s.Validate(): got error %v, want error %v", err, tc.wantErr)
+			}
+		})
+	}
+}
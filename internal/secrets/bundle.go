@@ -0,0 +1,214 @@
+/*
+Copyright 2025 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package secrets converts Talos machine secrets between the Talos SDK bundle
+// and the structured contract published by Secrets, and derives the client
+// credentials and talosconfigs issued from them.
+package secrets
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"time"
+
+	"github.com/pkg/errors"
+	siderox509 "github.com/siderolabs/crypto/x509"
+	"github.com/siderolabs/talos/pkg/machinery/role"
+	"sigs.k8s.io/yaml"
+
+	"github.com/crossplane-contrib/provider-talos/apis/machine/v1alpha1"
+
+	talossecrets "github.com/siderolabs/talos/pkg/machinery/config/generate/secrets"
+)
+
+// ParseSecretsBundle parses an existing secrets bundle. It accepts the YAML
+// written by talosctl gen secrets as well as the machine_secrets and
+// machine_secrets_bundle connection details published by a Secrets. The
+// bundle is validated by round-tripping it through the structured contract.
+func ParseSecretsBundle(data []byte) (*talossecrets.Bundle, error) {
+	machineSecrets := &v1alpha1.MachineSecrets{}
+	if err := json.Unmarshal(data, machineSecrets); err != nil || machineSecrets.Certs.OS.Cert == "" {
+		// Not the structured contract. The talosctl YAML and the SDK's JSON
+		// encoding differ only in key case, which the JSON decoder ignores.
+		bundle := &talossecrets.Bundle{Clock: talossecrets.NewClock()}
+		if err := yaml.Unmarshal(data, bundle); err != nil {
+			return nil, errors.Wrap(err, "failed to parse secrets bundle")
+		}
+		machineSecrets, err = SecretsBundleToMachineSecrets(bundle)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return MachineSecretsToSecretsBundle(machineSecrets)
+}
+
+// SecretsBundleToMachineSecrets converts a Talos SDK bundle to the public structured contract.
+func SecretsBundleToMachineSecrets(bundle *talossecrets.Bundle) (*v1alpha1.MachineSecrets, error) {
+	if err := validateBundleForStructuredSecrets(bundle); err != nil {
+		return nil, err
+	}
+
+	return &v1alpha1.MachineSecrets{
+		Cluster: v1alpha1.MachineSecretsCluster{
+			ID:     bundle.Cluster.ID,
+			Secret: bundle.Cluster.Secret,
+		},
+		Secrets: v1alpha1.MachineSecretsSecrets{
+			BootstrapToken:            bundle.Secrets.BootstrapToken,
+			SecretboxEncryptionSecret: bundle.Secrets.SecretboxEncryptionSecret,
+			AESCBCEncryptionSecret:    bundle.Secrets.AESCBCEncryptionSecret,
+		},
+		TrustdInfo: v1alpha1.MachineSecretsTrustdInfo{Token: bundle.TrustdInfo.Token},
+		Certs: v1alpha1.MachineSecretsCerts{
+			Etcd:              EncodeCertificateAndKey(bundle.Certs.Etcd),
+			K8s:               EncodeCertificateAndKey(bundle.Certs.K8s),
+			K8sAggregator:     EncodeCertificateAndKey(bundle.Certs.K8sAggregator),
+			K8sServiceAccount: encodeKey(bundle.Certs.K8sServiceAccount),
+			OS:                EncodeCertificateAndKey(bundle.Certs.OS),
+		},
+	}, nil
+}
+
+func validateBundleForStructuredSecrets(bundle *talossecrets.Bundle) error {
+	if bundle == nil || bundle.Cluster == nil || bundle.Secrets == nil || bundle.TrustdInfo == nil || bundle.Certs == nil {
+		return errors.New("machine secrets bundle is incomplete")
+	}
+
+	return validateBundleCerts(bundle.Certs)
+}
+
+func validateBundleCerts(certs *talossecrets.Certs) error {
+	if certs.Etcd == nil || certs.K8s == nil || certs.K8sAggregator == nil || certs.K8sServiceAccount == nil || certs.OS == nil {
+		return errors.New("machine secrets bundle certificates are incomplete")
+	}
+
+	return nil
+}
+
+// MachineSecretsToSecretsBundle converts the public structured contract back to a Talos SDK bundle.
+func MachineSecretsToSecretsBundle(machineSecrets *v1alpha1.MachineSecrets) (*talossecrets.Bundle, error) {
+	if machineSecrets == nil {
+		return nil, errors.New("machine secrets are nil")
+	}
+
+	etcd, err := decodeCertificateAndKey(machineSecrets.Certs.Etcd)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to decode etcd certificate and key")
+	}
+	k8s, err := decodeCertificateAndKey(machineSecrets.Certs.K8s)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to decode k8s certificate and key")
+	}
+	k8sAggregator, err := decodeCertificateAndKey(machineSecrets.Certs.K8sAggregator)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to decode k8s aggregator certificate and key")
+	}
+	k8sServiceAccount, err := decodeKey(machineSecrets.Certs.K8sServiceAccount)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to decode k8s service account key")
+	}
+	os, err := decodeCertificateAndKey(machineSecrets.Certs.OS)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to decode os certificate and key")
+	}
+
+	bundle := &talossecrets.Bundle{
+		Clock: talossecrets.NewClock(),
+		Cluster: &talossecrets.Cluster{
+			ID:     machineSecrets.Cluster.ID,
+			Secret: machineSecrets.Cluster.Secret,
+		},
+		Secrets: &talossecrets.Secrets{
+			BootstrapToken:            machineSecrets.Secrets.BootstrapToken,
+			AESCBCEncryptionSecret:    machineSecrets.Secrets.AESCBCEncryptionSecret,
+			SecretboxEncryptionSecret: machineSecrets.Secrets.SecretboxEncryptionSecret,
+		},
+		TrustdInfo: &talossecrets.TrustdInfo{Token: machineSecrets.TrustdInfo.Token},
+		Certs: &talossecrets.Certs{
+			Etcd:              etcd,
+			K8s:               k8s,
+			K8sAggregator:     k8sAggregator,
+			K8sServiceAccount: k8sServiceAccount,
+			OS:                os,
+		},
+	}
+
+	if err := bundle.Validate(); err != nil {
+		return nil, errors.Wrap(err, "structured machine secrets are invalid")
+	}
+
+	return bundle, nil
+}
+
+// GenerateClientConfiguration creates a Talos API admin client certificate from the OS CA.
+func GenerateClientConfiguration(bundle *talossecrets.Bundle, ttl time.Duration) (*v1alpha1.ClientConfiguration, error) {
+	return GenerateRoleClientConfiguration(bundle, role.MakeSet(role.Admin), ttl)
+}
+
+// GenerateRoleClientConfiguration creates a Talos API client certificate with
+// the supplied roles from the OS CA.
+func GenerateRoleClientConfiguration(bundle *talossecrets.Bundle, roles role.Set, ttl time.Duration) (*v1alpha1.ClientConfiguration, error) {
+	if bundle == nil || bundle.Certs == nil || bundle.Certs.OS == nil {
+		return nil, errors.New("machine secrets bundle does not contain an OS CA")
+	}
+
+	clientCertificate, err := bundle.GenerateTalosAPIClientCertificateWithTTL(roles, ttl)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to generate Talos API client certificate")
+	}
+
+	return &v1alpha1.ClientConfiguration{
+		CACertificate:     string(bundle.Certs.OS.Crt),
+		ClientCertificate: string(clientCertificate.Crt),
+		ClientKey:         string(clientCertificate.Key),
+	}, nil
+}
+
+// EncodeCertificateAndKey encodes a certificate and key in the structured
+// contract.
+func EncodeCertificateAndKey(certificateAndKey *siderox509.PEMEncodedCertificateAndKey) v1alpha1.MachineSecretsCertificateAndKey {
+	return v1alpha1.MachineSecretsCertificateAndKey{
+		Cert: base64.StdEncoding.EncodeToString(certificateAndKey.Crt),
+		Key:  base64.StdEncoding.EncodeToString(certificateAndKey.Key),
+	}
+}
+
+func encodeKey(key *siderox509.PEMEncodedKey) v1alpha1.MachineSecretsKey {
+	return v1alpha1.MachineSecretsKey{Key: base64.StdEncoding.EncodeToString(key.Key)}
+}
+
+func decodeCertificateAndKey(certificateAndKey v1alpha1.MachineSecretsCertificateAndKey) (*siderox509.PEMEncodedCertificateAndKey, error) {
+	cert, err := base64.StdEncoding.DecodeString(certificateAndKey.Cert)
+	if err != nil {
+		return nil, err
+	}
+	key, err := base64.StdEncoding.DecodeString(certificateAndKey.Key)
+	if err != nil {
+		return nil, err
+	}
+
+	return &siderox509.PEMEncodedCertificateAndKey{Crt: cert, Key: key}, nil
+}
+
+func decodeKey(key v1alpha1.MachineSecretsKey) (*siderox509.PEMEncodedKey, error) {
+	decoded, err := base64.StdEncoding.DecodeString(key.Key)
+	if err != nil {
+		return nil, err
+	}
+
+	return &siderox509.PEMEncodedKey{Key: decoded}, nil
+}
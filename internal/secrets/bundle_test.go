@@ -0,0 +1,215 @@
+/*
+Copyright 2025 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package secrets
+
+import (
+	"bytes"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	talossecrets "github.com/siderolabs/talos/pkg/machinery/config/generate/secrets"
+	"gopkg.in/yaml.v3"
+)
+
+func TestSecretsBundleToMachineSecrets(t *testing.T) {
+	t.Parallel()
+
+	bundle, err := talossecrets.NewBundle(talossecrets.NewClock(), nil)
+	if err != nil {
+		t.Fatalf("talossecrets.NewBundle(...): %v", err)
+	}
+
+	machineSecrets, err := SecretsBundleToMachineSecrets(bundle)
+	if err != nil {
+		t.Fatalf("SecretsBundleToMachineSecrets(...): %v", err)
+	}
+
+	if machineSecrets.Cluster.ID != bundle.Cluster.ID {
+		t.Fatalf("expected cluster ID %q, got %q", bundle.Cluster.ID, machineSecrets.Cluster.ID)
+	}
+	if machineSecrets.Secrets.BootstrapToken != bundle.Secrets.BootstrapToken {
+		t.Fatalf("expected bootstrap token %q, got %q", bundle.Secrets.BootstrapToken, machineSecrets.Secrets.BootstrapToken)
+	}
+	if machineSecrets.TrustdInfo.Token != bundle.TrustdInfo.Token {
+		t.Fatalf("expected trustd token %q, got %q", bundle.TrustdInfo.Token, machineSecrets.TrustdInfo.Token)
+	}
+
+	for name, value := range map[string]string{
+		"certs.etcd.cert":              machineSecrets.Certs.Etcd.Cert,
+		"certs.etcd.key":               machineSecrets.Certs.Etcd.Key,
+		"certs.k8s.cert":               machineSecrets.Certs.K8s.Cert,
+		"certs.k8s.key":                machineSecrets.Certs.K8s.Key,
+		"certs.k8s_aggregator.cert":    machineSecrets.Certs.K8sAggregator.Cert,
+		"certs.k8s_aggregator.key":     machineSecrets.Certs.K8sAggregator.Key,
+		"certs.k8s_serviceaccount.key": machineSecrets.Certs.K8sServiceAccount.Key,
+		"certs.os.cert":                machineSecrets.Certs.OS.Cert,
+		"certs.os.key":                 machineSecrets.Certs.OS.Key,
+	} {
+		decoded, err := base64.StdEncoding.DecodeString(value)
+		if err != nil {
+			t.Fatalf("expected %s to be base64 encoded: %v", name, err)
+		}
+		if block, _ := pem.Decode(decoded); block == nil {
+			t.Fatalf("expected %s to decode to PEM", name)
+		}
+	}
+}
+
+func TestMachineSecretsToSecretsBundle(t *testing.T) {
+	t.Parallel()
+
+	bundle, err := talossecrets.NewBundle(talossecrets.NewClock(), nil)
+	if err != nil {
+		t.Fatalf("talossecrets.NewBundle(...): %v", err)
+	}
+	machineSecrets, err := SecretsBundleToMachineSecrets(bundle)
+	if err != nil {
+		t.Fatalf("SecretsBundleToMachineSecrets(...): %v", err)
+	}
+
+	got, err := MachineSecretsToSecretsBundle(machineSecrets)
+	if err != nil {
+		t.Fatalf("MachineSecretsToSecretsBundle(...): %v", err)
+	}
+
+	if got.Cluster.ID != bundle.Cluster.ID || got.Cluster.Secret != bundle.Cluster.Secret {
+		t.Fatal("expected cluster fields to round trip")
+	}
+	if !bytes.Equal(got.Certs.OS.Crt, bundle.Certs.OS.Crt) || !bytes.Equal(got.Certs.OS.Key, bundle.Certs.OS.Key) {
+		t.Fatal("expected OS CA to round trip")
+	}
+}
+
+func TestGenerateClientConfiguration(t *testing.T) {
+	t.Parallel()
+
+	bundle, err := talossecrets.NewBundle(talossecrets.NewClock(), nil)
+	if err != nil {
+		t.Fatalf("talossecrets.NewBundle(...): %v", err)
+	}
+
+	clientConfiguration, err := GenerateClientConfiguration(bundle, time.Hour)
+	if err != nil {
+		t.Fatalf("GenerateClientConfiguration(...): %v", err)
+	}
+
+	if clientConfiguration.ClientCertificate == string(bundle.Certs.OS.Crt) {
+		t.Fatal("expected client certificate to differ from OS CA certificate")
+	}
+	if _, err := tls.X509KeyPair([]byte(clientConfiguration.ClientCertificate), []byte(clientConfiguration.ClientKey)); err != nil {
+		t.Fatalf("expected client certificate and key to match: %v", err)
+	}
+
+	ca := parseCertificate(t, []byte(clientConfiguration.CACertificate))
+	client := parseCertificate(t, []byte(clientConfiguration.ClientCertificate))
+	if err := client.CheckSignatureFrom(ca); err != nil {
+		t.Fatalf("expected client certificate to be signed by OS CA: %v", err)
+	}
+}
+
+func TestParseSecretsBundle(t *testing.T) {
+	t.Parallel()
+
+	bundle, err := talossecrets.NewBundle(talossecrets.NewClock(), nil)
+	if err != nil {
+		t.Fatalf("talossecrets.NewBundle(...): %v", err)
+	}
+	talosctlYAML, err := yaml.Marshal(bundle)
+	if err != nil {
+		t.Fatalf("yaml.Marshal(...): %v", err)
+	}
+	bundleJSON, err := json.Marshal(bundle)
+	if err != nil {
+		t.Fatalf("json.Marshal(...): %v", err)
+	}
+	machineSecrets, err := SecretsBundleToMachineSecrets(bundle)
+	if err != nil {
+		t.Fatalf("SecretsBundleToMachineSecrets(...): %v", err)
+	}
+	machineSecretsJSON, err := json.Marshal(machineSecrets)
+	if err != nil {
+		t.Fatalf("json.Marshal(...): %v", err)
+	}
+
+	cases := map[string]struct {
+		reason  string
+		data    []byte
+		wantErr bool
+	}{
+		"TalosctlYAML": {
+			reason: "The secrets.yaml written by talosctl gen secrets should be imported.",
+			data:   talosctlYAML,
+		},
+		"BundleJSON": {
+			reason: "The machine_secrets_bundle connection detail should be imported.",
+			data:   bundleJSON,
+		},
+		"MachineSecretsJSON": {
+			reason: "The machine_secrets connection detail should be imported.",
+			data:   machineSecretsJSON,
+		},
+		"Incomplete": {
+			reason:  "A bundle without certificates should be rejected.",
+			data:    []byte("cluster:\n  id: abc\n  secret: def\n"),
+			wantErr: true,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := ParseSecretsBundle(tc.data)
+			if tc.wantErr {
+				if err == nil {
+					t.Fatalf("\n%s\nParseSecretsBundle(...): expected error", tc.reason)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("\n%s\nParseSecretsBundle(...): %v", tc.reason, err)
+			}
+			if diff := cmp.Diff(bundle.Cluster, got.Cluster); diff != "" {
+				t.Errorf("\n%s\nParseSecretsBundle(...): Cluster -want, +got:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(bundle.Certs.OS, got.Certs.OS); diff != "" {
+				t.Errorf("\n%s\nParseSecretsBundle(...): Certs.OS -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func parseCertificate(t *testing.T, pemBytes []byte) *x509.Certificate {
+	t.Helper()
+
+	block, _ := pem.Decode(pemBytes)
+	if block == nil {
+		t.Fatal("expected PEM certificate")
+	}
+	cert, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		t.Fatalf("x509.ParseCertificate(...): %v", err)
+	}
+
+	return cert
+}
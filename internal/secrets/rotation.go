@@ -0,0 +1,126 @@
+/*
+Copyright 2025 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package secrets
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+
+	"github.com/pkg/errors"
+
+	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
+
+	"github.com/crossplane-contrib/provider-talos/apis/machine/v1alpha1"
+)
+
+// ConnectionKeyCARotation holds the CAs accepted alongside the issuing CAs of
+// machine_secrets while a CA rotation is in progress.
+const ConnectionKeyCARotation = "ca_rotation"
+
+// CARotationState is the ca_rotation connection detail. The new CAs are kept
+// with their keys until they become the issuing CAs; the old CAs are kept
+// without keys until they are dropped.
+type CARotationState struct {
+	OS  *v1alpha1.MachineSecretsCertificateAndKey `json:"os,omitempty"`
+	K8s *v1alpha1.MachineSecretsCertificateAndKey `json:"k8s,omitempty"`
+}
+
+// AcceptedCAs are PEM encoded CA certificates that machine configuration must
+// accept in addition to the issuing CAs of the machine secrets.
+type AcceptedCAs struct {
+	OS         [][]byte
+	Kubernetes [][]byte
+}
+
+// ParseAcceptedCAs returns the CAs accepted during a CA rotation from the
+// connection details of a Secrets. It returns no CAs when no rotation is in
+// progress.
+func ParseAcceptedCAs(connectionDetails map[string][]byte) (*AcceptedCAs, error) {
+	state, err := ParseCARotationState(connectionDetails)
+	if err != nil {
+		return nil, err
+	}
+
+	accepted := &AcceptedCAs{}
+	if state.OS != nil {
+		crt, err := base64.StdEncoding.DecodeString(state.OS.Cert)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to decode accepted OS CA")
+		}
+		accepted.OS = append(accepted.OS, crt)
+	}
+	if state.K8s != nil {
+		crt, err := base64.StdEncoding.DecodeString(state.K8s.Cert)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to decode accepted Kubernetes CA")
+		}
+		accepted.Kubernetes = append(accepted.Kubernetes, crt)
+	}
+
+	return accepted, nil
+}
+
+// TrustedOSCAs returns the PEM encoded OS CAs Talos API clients must trust:
+// the issuing OS CA and, while a CA rotation is in progress, the accepted OS
+// CA. Nodes keep serving certificates issued by the old OS CA until they run
+// configuration from the new one, so clients trust both until the old CA is
+// dropped.
+func TrustedOSCAs(issuing []byte, connectionDetails map[string][]byte) ([]byte, error) {
+	accepted, err := ParseAcceptedCAs(connectionDetails)
+	if err != nil {
+		return nil, err
+	}
+
+	return bytes.Join(append([][]byte{issuing}, accepted.OS...), nil), nil
+}
+
+// MachineSecretsHash identifies the machine secrets and accepted CAs published
+// by a Secrets. Configurations record it so a CA rotation can tell when they
+// have been regenerated.
+func MachineSecretsHash(connectionDetails map[string][]byte) string {
+	h := sha256.New()
+	h.Write(connectionDetails[ConnectionKeyMachineSecrets]) //nolint:errcheck
+	h.Write([]byte{0})                                      //nolint:errcheck
+	h.Write(connectionDetails[ConnectionKeyCARotation])     //nolint:errcheck
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// ParseCARotationState returns the ca_rotation connection detail of a Secrets.
+func ParseCARotationState(connectionDetails map[string][]byte) (*CARotationState, error) {
+	state := &CARotationState{}
+	if data := connectionDetails[ConnectionKeyCARotation]; len(data) > 0 {
+		if err := json.Unmarshal(data, state); err != nil {
+			return nil, errors.Wrap(err, "failed to unmarshal CA rotation state")
+		}
+	}
+
+	return state, nil
+}
+
+// SetCARotationState sets the ca_rotation connection detail.
+func SetCARotationState(connectionDetails managed.ConnectionDetails, state *CARotationState) error {
+	data, err := json.Marshal(state)
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal CA rotation state")
+	}
+	connectionDetails[ConnectionKeyCARotation] = data
+
+	return nil
+}
@@ -0,0 +1,141 @@
+/*
+Copyright 2025 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package secrets
+
+import (
+	"encoding/base64"
+	"encoding/json"
+
+	"github.com/pkg/errors"
+	siderox509 "github.com/siderolabs/crypto/x509"
+	clientconfig "github.com/siderolabs/talos/pkg/machinery/client/config"
+
+	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
+
+	"github.com/crossplane-contrib/provider-talos/apis/machine/v1alpha1"
+)
+
+// Connection detail keys published by Secrets and ClientCertificates.
+const (
+	ConnectionKeyMachineSecrets       = "machine_secrets"
+	ConnectionKeyMachineSecretsBundle = "machine_secrets_bundle"
+	ConnectionKeyClientConfiguration  = "client_configuration"
+	ConnectionKeyCACertificate        = "ca_certificate"
+	ConnectionKeyClientCertificate    = "client_certificate"
+	ConnectionKeyClientKey            = "client_key"
+	ConnectionKeyTalosConfig          = "talos_config"
+)
+
+const defaultTalosconfigContext = "default"
+
+// ClientConnectionDetails returns the ca_certificate, client_certificate,
+// client_key, client_configuration and talos_config connection details of a
+// client configuration.
+func ClientConnectionDetails(clientConfiguration *v1alpha1.ClientConfiguration, talosconfigOptions TalosconfigOptions) (managed.ConnectionDetails, error) {
+	connectionDetails := managed.ConnectionDetails{}
+	if err := SetClientConnectionDetails(connectionDetails, clientConfiguration, talosconfigOptions); err != nil {
+		return nil, err
+	}
+
+	return connectionDetails, nil
+}
+
+// SetClientConnectionDetails sets the connection details derived from a client
+// configuration.
+func SetClientConnectionDetails(connectionDetails managed.ConnectionDetails, clientConfiguration *v1alpha1.ClientConfiguration, talosconfigOptions TalosconfigOptions) error {
+	clientConfigurationJSON, err := marshalBase64ClientConfiguration(clientConfiguration)
+	if err != nil {
+		return err
+	}
+
+	connectionDetails[ConnectionKeyCACertificate] = []byte(clientConfiguration.CACertificate)
+	connectionDetails[ConnectionKeyClientCertificate] = []byte(clientConfiguration.ClientCertificate)
+	connectionDetails[ConnectionKeyClientKey] = []byte(clientConfiguration.ClientKey)
+	connectionDetails[ConnectionKeyClientConfiguration] = clientConfigurationJSON
+
+	talosConfig, err := MarshalTalosconfig(clientConfiguration, talosconfigOptions)
+	if err != nil {
+		return err
+	}
+	connectionDetails[ConnectionKeyTalosConfig] = talosConfig
+
+	return nil
+}
+
+func marshalBase64ClientConfiguration(clientConfiguration *v1alpha1.ClientConfiguration) ([]byte, error) {
+	return json.Marshal(v1alpha1.ClientConfiguration{
+		CACertificate:     base64.StdEncoding.EncodeToString([]byte(clientConfiguration.CACertificate)),
+		ClientCertificate: base64.StdEncoding.EncodeToString([]byte(clientConfiguration.ClientCertificate)),
+		ClientKey:         base64.StdEncoding.EncodeToString([]byte(clientConfiguration.ClientKey)),
+	})
+}
+
+// TalosconfigOptions configure the context of a published talosconfig.
+type TalosconfigOptions struct {
+	// Context is the context name. Defaults to "default".
+	Context string
+	// Endpoints are the Talos API endpoints of the context.
+	Endpoints []string
+	// Nodes are the default target nodes of the context.
+	Nodes []string
+}
+
+// SecretsTalosconfigOptions returns the options of the talosconfig published
+// by a Secrets.
+func SecretsTalosconfigOptions(cr *v1alpha1.Secrets) TalosconfigOptions {
+	options := TalosconfigOptions{
+		Endpoints: cr.Spec.ForProvider.Endpoints,
+		Nodes:     cr.Spec.ForProvider.Nodes,
+	}
+	if cr.Spec.ForProvider.TalosconfigContext != nil {
+		options.Context = *cr.Spec.ForProvider.TalosconfigContext
+	}
+
+	return options
+}
+
+// MarshalTalosconfig returns a talosconfig in the format read by talosctl
+// with a single context holding the client configuration.
+func MarshalTalosconfig(clientConfiguration *v1alpha1.ClientConfiguration, options TalosconfigOptions) ([]byte, error) {
+	contextName := options.Context
+	if contextName == "" {
+		contextName = defaultTalosconfigContext
+	}
+
+	cfg := clientconfig.NewConfig(contextName, options.Endpoints, []byte(clientConfiguration.CACertificate), &siderox509.PEMEncodedCertificateAndKey{
+		Crt: []byte(clientConfiguration.ClientCertificate),
+		Key: []byte(clientConfiguration.ClientKey),
+	})
+	cfg.Contexts[contextName].Nodes = options.Nodes
+
+	data, err := cfg.Bytes()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to marshal talosconfig")
+	}
+
+	return data, nil
+}
+
+// ClientConfigurationFromConnectionDetails returns the published client
+// configuration.
+func ClientConfigurationFromConnectionDetails(connectionDetails managed.ConnectionDetails) *v1alpha1.ClientConfiguration {
+	return &v1alpha1.ClientConfiguration{
+		CACertificate:     string(connectionDetails[ConnectionKeyCACertificate]),
+		ClientCertificate: string(connectionDetails[ConnectionKeyClientCertificate]),
+		ClientKey:         string(connectionDetails[ConnectionKeyClientKey]),
+	}
+}
@@ -0,0 +1,457 @@
+/*
+Copyright 2025 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package snapshotstore persists etcd snapshots taken by EtcdSnapshots to the
+// Secret, volume or S3 storage configured in their spec.
+package snapshotstore
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrlclient "sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane-contrib/provider-talos/apis/machine/v1alpha1"
+)
+
+const (
+	defaultSecretKey = "snapshot.db"
+	defaultS3Region  = "us-east-1"
+
+	// maxSecretSnapshotSize leaves headroom below the 1MiB object size limit
+	// for the Secret's metadata.
+	maxSecretSnapshotSize = 1000 * 1024
+
+	s3AccessKeyIDKey     = "accessKeyID"
+	s3SecretAccessKeyKey = "secretAccessKey"
+	s3SessionTokenKey    = "sessionToken"
+
+	s3Service          = "s3"
+	s3TimeFormat       = "20060102T150405Z"
+	s3DateFormat       = "20060102"
+	s3UnsignedPayload  = "UNSIGNED-PAYLOAD"
+	s3SigningAlgorithm = "AWS4-HMAC-SHA256"
+
+	// s3Timeout bounds a single S3 request, including streaming the snapshot
+	// body, so that an unresponsive endpoint cannot hang a reconcile.
+	s3Timeout = 5 * time.Minute
+
+	labelEtcdSnapshot = "talos.crossplane.io/etcd-snapshot"
+)
+
+// A Store persists etcd snapshots. Put returns the location recorded in
+// status, which is later passed to Get and Delete.
+type Store interface {
+	Put(ctx context.Context, name string, r io.Reader, size int64) (string, error)
+	Get(ctx context.Context, location string) (io.ReadCloser, error)
+	Delete(ctx context.Context, location string) error
+}
+
+// Open opens a snapshot taken by the supplied EtcdSnapshot from the store
+// configured in its spec. The caller must close the returned reader.
+func Open(ctx context.Context, kube ctrlclient.Client, cr *v1alpha1.EtcdSnapshot, location string) (io.ReadCloser, error) {
+	store, err := New(ctx, kube, cr)
+	if err != nil {
+		return nil, err
+	}
+
+	return store.Get(ctx, location)
+}
+
+// New returns the store configured in the resource's spec.
+func New(ctx context.Context, kube ctrlclient.Client, cr *v1alpha1.EtcdSnapshot) (Store, error) {
+	storage := cr.Spec.ForProvider.Storage
+	switch {
+	case storage.Secret != nil:
+		key := defaultSecretKey
+		if storage.Secret.Key != nil && *storage.Secret.Key != "" {
+			key = *storage.Secret.Key
+		}
+		return &secretStore{kube: kube, owner: cr.Name, namespace: storage.Secret.Namespace, key: key}, nil
+	case storage.Volume != nil:
+		return &volumeStore{path: storage.Volume.Path}, nil
+	case storage.S3 != nil:
+		return newS3Store(ctx, kube, storage.S3)
+	default:
+		return nil, errors.New("storage must set one of secret, volume or s3")
+	}
+}
+
+// A secretStore writes each snapshot to its own Secret.
+type secretStore struct {
+	kube      ctrlclient.Client
+	owner     string
+	namespace string
+	key       string
+}
+
+func (s *secretStore) Put(ctx context.Context, name string, r io.Reader, size int64) (string, error) {
+	if size > maxSecretSnapshotSize {
+		return "", errors.Errorf("snapshot is %d bytes, which exceeds the %d byte limit of Secret storage", size, maxSecretSnapshotSize)
+	}
+
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return "", errors.Wrap(err, "cannot read snapshot")
+	}
+
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: s.namespace,
+			Labels:    map[string]string{labelEtcdSnapshot: s.owner},
+		},
+		Type: corev1.SecretTypeOpaque,
+		Data: map[string][]byte{s.key: data},
+	}
+	if err := s.kube.Create(ctx, secret); err != nil {
+		return "", errors.Wrapf(err, "cannot create snapshot Secret %s/%s", s.namespace, name)
+	}
+
+	return s.namespace + "/" + name, nil
+}
+
+func (s *secretStore) Get(ctx context.Context, location string) (io.ReadCloser, error) {
+	namespace, name, ok := strings.Cut(location, "/")
+	if !ok {
+		return nil, errors.Errorf("invalid Secret location %q", location)
+	}
+
+	secret := &corev1.Secret{}
+	if err := s.kube.Get(ctx, types.NamespacedName{Namespace: namespace, Name: name}, secret); err != nil {
+		return nil, errors.Wrapf(err, "cannot get snapshot Secret %s", location)
+	}
+
+	data, ok := secret.Data[s.key]
+	if !ok {
+		return nil, errors.Errorf("snapshot Secret %s has no %s key", location, s.key)
+	}
+
+	return io.NopCloser(bytes.NewReader(data)), nil
+}
+
+func (s *secretStore) Delete(ctx context.Context, location string) error {
+	namespace, name, ok := strings.Cut(location, "/")
+	if !ok {
+		return errors.Errorf("invalid Secret location %q", location)
+	}
+
+	secret := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace}}
+	if err := s.kube.Delete(ctx, secret); err != nil && !kerrors.IsNotFound(err) {
+		return errors.Wrapf(err, "cannot delete snapshot Secret %s", location)
+	}
+
+	return nil
+}
+
+// A volumeStore writes snapshots as files into a directory.
+type volumeStore struct {
+	path string
+}
+
+func (s *volumeStore) Put(_ context.Context, name string, r io.Reader, _ int64) (string, error) {
+	if err := os.MkdirAll(s.path, 0o750); err != nil {
+		return "", errors.Wrapf(err, "cannot create snapshot directory %s", s.path)
+	}
+
+	path := filepath.Join(s.path, name+".db")
+	// Write to a temporary file first so that a partial snapshot never
+	// appears under its final name.
+	tmp := path + ".partial"
+	file, err := os.OpenFile(tmp, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o600) //nolint:gosec // Path is built from the spec and resource name.
+	if err != nil {
+		return "", errors.Wrapf(err, "cannot create snapshot file %s", tmp)
+	}
+	if _, err := io.Copy(file, r); err != nil {
+		file.Close()   //nolint:errcheck
+		os.Remove(tmp) //nolint:errcheck
+		return "", errors.Wrapf(err, "cannot write snapshot file %s", tmp)
+	}
+	if err := file.Close(); err != nil {
+		os.Remove(tmp) //nolint:errcheck
+		return "", errors.Wrapf(err, "cannot write snapshot file %s", tmp)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		os.Remove(tmp) //nolint:errcheck
+		return "", errors.Wrapf(err, "cannot rename snapshot file to %s", path)
+	}
+
+	return path, nil
+}
+
+func (s *volumeStore) Get(_ context.Context, location string) (io.ReadCloser, error) {
+	file, err := os.Open(location) //nolint:gosec // Location was recorded by Put.
+	if err != nil {
+		return nil, errors.Wrapf(err, "cannot open snapshot file %s", location)
+	}
+
+	return file, nil
+}
+
+func (s *volumeStore) Delete(_ context.Context, location string) error {
+	if err := os.Remove(location); err != nil && !os.IsNotExist(err) {
+		return errors.Wrapf(err, "cannot delete snapshot file %s", location)
+	}
+
+	return nil
+}
+
+// An s3Store uploads snapshots to an S3-compatible bucket using path-style
+// requests signed with AWS Signature Version 4.
+type s3Store struct {
+	httpClient *http.Client
+
+	endpoint *url.URL
+	bucket   string
+	prefix   string
+	region   string
+
+	accessKeyID     string
+	secretAccessKey string
+	sessionToken    string
+
+	// now allows tests to fix the signing time.
+	now func() time.Time
+}
+
+func newS3Store(ctx context.Context, kube ctrlclient.Client, cfg *v1alpha1.EtcdSnapshotS3Storage) (*s3Store, error) {
+	endpoint, err := url.Parse(cfg.Endpoint)
+	if err != nil || endpoint.Scheme == "" || endpoint.Host == "" {
+		return nil, errors.Errorf("invalid S3 endpoint %q", cfg.Endpoint)
+	}
+
+	ref := cfg.CredentialsSecretRef
+	secret := &corev1.Secret{}
+	if err := kube.Get(ctx, types.NamespacedName{Namespace: ref.Namespace, Name: ref.Name}, secret); err != nil {
+		return nil, errors.Wrapf(err, "cannot get S3 credentials Secret %s/%s", ref.Namespace, ref.Name)
+	}
+
+	accessKeyID := string(secret.Data[s3AccessKeyIDKey])
+	secretAccessKey := string(secret.Data[s3SecretAccessKeyKey])
+	if accessKeyID == "" || secretAccessKey == "" {
+		return nil, errors.Errorf("S3 credentials Secret %s/%s must contain %s and %s", ref.Namespace, ref.Name, s3AccessKeyIDKey, s3SecretAccessKeyKey)
+	}
+
+	region := defaultS3Region
+	if cfg.Region != nil && *cfg.Region != "" {
+		region = *cfg.Region
+	}
+
+	prefix := ""
+	if cfg.Prefix != nil {
+		prefix = *cfg.Prefix
+	}
+
+	return &s3Store{
+		httpClient:      &http.Client{Timeout: s3Timeout},
+		endpoint:        endpoint,
+		bucket:          cfg.Bucket,
+		prefix:          prefix,
+		region:          region,
+		accessKeyID:     accessKeyID,
+		secretAccessKey: secretAccessKey,
+		sessionToken:    string(secret.Data[s3SessionTokenKey]),
+		now:             time.Now,
+	}, nil
+}
+
+func (s *s3Store) Put(ctx context.Context, name string, r io.Reader, size int64) (string, error) {
+	key := s.prefix + name + ".db"
+
+	req, err := s.newRequest(ctx, http.MethodPut, key, r)
+	if err != nil {
+		return "", err
+	}
+	req.ContentLength = size
+	req.Header.Set("Content-Type", "application/octet-stream")
+
+	if err := s.do(req); err != nil {
+		return "", errors.Wrapf(err, "cannot upload s3://%s/%s", s.bucket, key)
+	}
+
+	return fmt.Sprintf("s3://%s/%s", s.bucket, key), nil
+}
+
+func (s *s3Store) Get(ctx context.Context, location string) (io.ReadCloser, error) {
+	key, err := s.key(location)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := s.newRequest(ctx, http.MethodGet, key, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	body, err := s.send(req)
+	if err != nil {
+		return nil, errors.Wrapf(err, "cannot download %s", location)
+	}
+
+	return body, nil
+}
+
+func (s *s3Store) Delete(ctx context.Context, location string) error {
+	key, err := s.key(location)
+	if err != nil {
+		return err
+	}
+
+	req, err := s.newRequest(ctx, http.MethodDelete, key, nil)
+	if err != nil {
+		return err
+	}
+
+	if err := s.do(req); err != nil {
+		return errors.Wrapf(err, "cannot delete %s", location)
+	}
+
+	return nil
+}
+
+func (s *s3Store) key(location string) (string, error) {
+	key, ok := strings.CutPrefix(location, fmt.Sprintf("s3://%s/", s.bucket))
+	if !ok {
+		return "", errors.Errorf("snapshot %s is not in bucket %s", location, s.bucket)
+	}
+
+	return key, nil
+}
+
+func (s *s3Store) do(req *http.Request) error {
+	body, err := s.send(req)
+	if err != nil {
+		return err
+	}
+
+	return body.Close()
+}
+
+// send signs and sends the request, returning the response body of a
+// successful request.
+func (s *s3Store) send(req *http.Request) (io.ReadCloser, error) {
+	s.sign(req)
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	// DELETE of a missing object returns 204 as well, so any 2xx is success.
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		defer resp.Body.Close() //nolint:errcheck
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return nil, errors.Errorf("S3 returned %s: %s", resp.Status, strings.TrimSpace(string(body)))
+	}
+
+	return resp.Body, nil
+}
+
+func (s *s3Store) newRequest(ctx context.Context, method, key string, body io.Reader) (*http.Request, error) {
+	u := *s.endpoint
+	u.Path = strings.TrimSuffix(u.Path, "/") + "/" + s.bucket + "/" + key
+	u.RawPath = ""
+
+	req, err := http.NewRequestWithContext(ctx, method, u.String(), body)
+	if err != nil {
+		return nil, errors.Wrap(err, "cannot build S3 request")
+	}
+
+	return req, nil
+}
+
+// sign adds AWS Signature Version 4 headers to the request. The payload is
+// sent unsigned so that snapshots can be streamed without hashing them twice;
+// TLS protects the body in transit.
+func (s *s3Store) sign(req *http.Request) {
+	now := s.now().UTC()
+	amzDate := now.Format(s3TimeFormat)
+	date := now.Format(s3DateFormat)
+
+	req.Header.Set("Host", req.URL.Host)
+	req.Header.Set("X-Amz-Date", amzDate)
+	req.Header.Set("X-Amz-Content-Sha256", s3UnsignedPayload)
+	if s.sessionToken != "" {
+		req.Header.Set("X-Amz-Security-Token", s.sessionToken)
+	}
+
+	signedHeaders := []string{"host", "x-amz-content-sha256", "x-amz-date"}
+	if s.sessionToken != "" {
+		signedHeaders = append(signedHeaders, "x-amz-security-token")
+	}
+
+	var canonicalHeaders strings.Builder
+	for _, h := range signedHeaders {
+		value := req.Header.Get(h)
+		if h == "host" {
+			value = req.URL.Host
+		}
+		canonicalHeaders.WriteString(h + ":" + strings.TrimSpace(value) + "\n")
+	}
+
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		req.URL.EscapedPath(),
+		req.URL.RawQuery,
+		canonicalHeaders.String(),
+		strings.Join(signedHeaders, ";"),
+		s3UnsignedPayload,
+	}, "\n")
+
+	scope := strings.Join([]string{date, s.region, s3Service, "aws4_request"}, "/")
+	stringToSign := strings.Join([]string{
+		s3SigningAlgorithm,
+		amzDate,
+		scope,
+		hexSHA256([]byte(canonicalRequest)),
+	}, "\n")
+
+	key := hmacSHA256([]byte("AWS4"+s.secretAccessKey), date)
+	key = hmacSHA256(key, s.region)
+	key = hmacSHA256(key, s3Service)
+	key = hmacSHA256(key, "aws4_request")
+	signature := hex.EncodeToString(hmacSHA256(key, stringToSign))
+
+	req.Header.Set("Authorization", fmt.Sprintf("%s Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		s3SigningAlgorithm, s.accessKeyID, scope, strings.Join(signedHeaders, ";"), signature))
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	h := hmac.New(sha256.New, key)
+	h.Write([]byte(data))
+	return h.Sum(nil)
+}
+
+func hexSHA256(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
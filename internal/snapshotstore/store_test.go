@@ -0,0 +1,165 @@
+/*
+Copyright 2025 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package snapshotstore
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+
+	"github.com/crossplane-contrib/provider-talos/apis/machine/v1alpha1"
+)
+
+// Unlike many Kubernetes projects Crossplane does not use third party testing
+// libraries, per the common Go test review comments. Crossplane encourages the
+// use of table driven unit tests. The tests of the crossplane-runtime project
+// are representative of the testing style Crossplane encourages.
+//
+// https://github.com/golang/go/wiki/TestComments
+// https://github.com/crossplane/crossplane/blob/master/CONTRIBUTING.md#contributing-code
+
+func TestSecretStore(t *testing.T) {
+	scheme := runtime.NewScheme()
+	if err := corev1.AddToScheme(scheme); err != nil {
+		t.Fatalf("add core scheme: %v", err)
+	}
+	kube := fake.NewClientBuilder().WithScheme(scheme).Build()
+	store := &secretStore{kube: kube, owner: "test", namespace: "backups", key: defaultSecretKey}
+
+	location, err := store.Put(context.Background(), "test-20250101-000000", strings.NewReader("snapshot"), 8)
+	if err != nil {
+		t.Fatalf("store.Put(...): unexpected error: %v", err)
+	}
+	if location != "backups/test-20250101-000000" {
+		t.Errorf("store.Put(...): location: want backups/test-20250101-000000, got %s", location)
+	}
+
+	secret := &corev1.Secret{}
+	if err := kube.Get(context.Background(), types.NamespacedName{Namespace: "backups", Name: "test-20250101-000000"}, secret); err != nil {
+		t.Fatalf("get snapshot Secret: %v", err)
+	}
+	if got := string(secret.Data[defaultSecretKey]); got != "snapshot" {
+		t.Errorf("store.Put(...): data: want snapshot, got %s", got)
+	}
+
+	if _, err := store.Put(context.Background(), "too-large", strings.NewReader(""), maxSecretSnapshotSize+1); err == nil {
+		t.Error("store.Put(...): expected error for snapshot exceeding the Secret size limit")
+	}
+
+	if err := store.Delete(context.Background(), location); err != nil {
+		t.Fatalf("store.Delete(...): unexpected error: %v", err)
+	}
+	if err := store.Delete(context.Background(), location); err != nil {
+		t.Errorf("store.Delete(...): deleting a missing snapshot should succeed: %v", err)
+	}
+}
+
+func TestS3Store(t *testing.T) {
+	type request struct {
+		method string
+		path   string
+		body   string
+	}
+	var requests []request
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		auth := r.Header.Get("Authorization")
+		if !strings.HasPrefix(auth, "AWS4-HMAC-SHA256 Credential=AKID/20250101/eu-west-1/s3/aws4_request, SignedHeaders=host;x-amz-content-sha256;x-amz-date, Signature=") {
+			http.Error(w, "bad signature: "+auth, http.StatusForbidden)
+			return
+		}
+		body, _ := io.ReadAll(r.Body)
+		requests = append(requests, request{method: r.Method, path: r.URL.Path, body: string(body)})
+		if r.Method == http.MethodGet {
+			_, _ = w.Write([]byte("snapshot"))
+		}
+	}))
+	defer server.Close()
+
+	scheme := runtime.NewScheme()
+	if err := corev1.AddToScheme(scheme); err != nil {
+		t.Fatalf("add core scheme: %v", err)
+	}
+	kube := fake.NewClientBuilder().WithScheme(scheme).WithObjects(&corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "s3", Namespace: "crossplane-system"},
+		Data: map[string][]byte{
+			s3AccessKeyIDKey:     []byte("AKID"),
+			s3SecretAccessKeyKey: []byte("secret"),
+		},
+	}).Build()
+
+	store, err := newS3Store(context.Background(), kube, &v1alpha1.EtcdSnapshotS3Storage{
+		Endpoint: server.URL,
+		Bucket:   "backups",
+		Prefix:   ptr.To("prod/"),
+		Region:   ptr.To("eu-west-1"),
+		CredentialsSecretRef: xpv1.SecretReference{
+			Name:      "s3",
+			Namespace: "crossplane-system",
+		},
+	})
+	if err != nil {
+		t.Fatalf("newS3Store(...): unexpected error: %v", err)
+	}
+	if store.httpClient.Timeout != s3Timeout {
+		t.Errorf("newS3Store(...): HTTP client timeout: want %s, got %s", s3Timeout, store.httpClient.Timeout)
+	}
+	store.now = func() time.Time { return time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC) }
+
+	location, err := store.Put(context.Background(), "test", strings.NewReader("snapshot"), 8)
+	if err != nil {
+		t.Fatalf("store.Put(...): unexpected error: %v", err)
+	}
+	if location != "s3://backups/prod/test.db" {
+		t.Errorf("store.Put(...): location: want s3://backups/prod/test.db, got %s", location)
+	}
+	body, err := store.Get(context.Background(), location)
+	if err != nil {
+		t.Fatalf("store.Get(...): unexpected error: %v", err)
+	}
+	data, _ := io.ReadAll(body)
+	body.Close() //nolint:errcheck
+	if string(data) != "snapshot" {
+		t.Errorf("store.Get(...): want snapshot, got %s", data)
+	}
+	if err := store.Delete(context.Background(), location); err != nil {
+		t.Fatalf("store.Delete(...): unexpected error: %v", err)
+	}
+
+	want := []request{
+		{method: http.MethodPut, path: "/backups/prod/test.db", body: "snapshot"},
+		{method: http.MethodGet, path: "/backups/prod/test.db"},
+		{method: http.MethodDelete, path: "/backups/prod/test.db"},
+	}
+	if diff := cmp.Diff(want, requests, cmp.AllowUnexported(request{})); diff != "" {
+		t.Errorf("S3 requests: -want, +got:\n%s\n", diff)
+	}
+}